@@ -155,3 +155,33 @@ type TranscriptChunker interface {
 	// Handles format-specific reassembly (JSONL concatenation, JSON message merging).
 	ReassembleTranscript(chunks [][]byte) ([]byte, error)
 }
+
+// MCPServerSupport is implemented by agents that load MCP servers from a
+// project-level config file. It lets `entire enable --mcp` register the
+// `entire mcp` server so the agent can query checkpoint history.
+type MCPServerSupport interface {
+	Agent
+
+	// InstallMCPServer registers the Entire MCP server in the agent's project MCP config.
+	// If localDev is true, the server runs from the local development build.
+	// Returns true if the config was changed, false if it was already registered.
+	InstallMCPServer(localDev bool) (bool, error)
+
+	// UninstallMCPServer removes the Entire MCP server from the agent's MCP config.
+	UninstallMCPServer() error
+
+	// IsMCPServerInstalled reports whether the Entire MCP server is registered.
+	IsMCPServerInstalled() bool
+}
+
+// ContextFileSupport is implemented by agents that load project memory files
+// (such as CLAUDE.md or GEMINI.md) into new sessions. `entire handoff` uses it
+// so a fresh session picks up where the previous one left off.
+type ContextFileSupport interface {
+	Agent
+
+	// WriteHandoffContext writes content to a file the agent loads at session
+	// start, adding an import to the agent's memory file if needed.
+	// Returns the repo-relative paths of the written file and of the memory file importing it.
+	WriteHandoffContext(content []byte) (path string, importedFrom string, err error)
+}
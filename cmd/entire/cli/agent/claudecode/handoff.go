@@ -0,0 +1,128 @@
+package claudecode
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/entireio/cli/cmd/entire/cli/agent"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/transcript"
+)
+
+// ClaudeLocalMemoryFileName is Claude Code's per-user project memory file.
+// It is loaded alongside CLAUDE.md but is meant to stay out of version control,
+// which makes it the right place to import a handoff from.
+const ClaudeLocalMemoryFileName = "CLAUDE.local.md"
+
+// WriteHandoffContext writes the handoff to .claude/entire-handoff.md and
+// imports it from CLAUDE.local.md so new sessions load it automatically.
+func (c *ClaudeCodeAgent) WriteHandoffContext(content []byte) (string, string, error) {
+	repoRoot, err := paths.RepoRoot()
+	if err != nil {
+		// Fallback to CWD if not in a git repo (e.g., during tests)
+		repoRoot, err = os.Getwd() //nolint:forbidigo // Intentional fallback when RepoRoot() fails (tests run outside git repos)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to get current directory: %w", err)
+		}
+	}
+
+	relPath := filepath.ToSlash(filepath.Join(".claude", agent.HandoffFileName))
+	if err := agent.WriteContextImport(
+		filepath.Join(repoRoot, ".claude", agent.HandoffFileName), content,
+		filepath.Join(repoRoot, ClaudeLocalMemoryFileName), "@"+relPath,
+	); err != nil {
+		return "", "", err //nolint:wrapcheck // Already descriptive
+	}
+	return relPath, ClaudeLocalMemoryFileName, nil
+}
+
+// toolUseBlock is an assistant content block with the ID needed to match tool results.
+type toolUseBlock struct {
+	Type  string          `json:"type"`
+	ID    string          `json:"id"`
+	Name  string          `json:"name"`
+	Input json.RawMessage `json:"input"`
+}
+
+// toolResultWithError is a tool_result block including its error flag and output.
+type toolResultWithError struct {
+	Type      string          `json:"type"`
+	ToolUseID string          `json:"tool_use_id"`
+	IsError   bool            `json:"is_error"`
+	Content   json.RawMessage `json:"content"`
+}
+
+// ExtractFailedCommands returns the Bash commands whose tool results were
+// flagged as errors, in transcript order.
+func ExtractFailedCommands(lines []TranscriptLine) []agent.FailedCommand {
+	commands := make(map[string]string) // tool_use_id -> command
+	var failed []agent.FailedCommand
+
+	for _, line := range lines {
+		switch line.Type {
+		case transcript.TypeAssistant:
+			var msg struct {
+				Content []toolUseBlock `json:"content"`
+			}
+			if err := json.Unmarshal(line.Message, &msg); err != nil {
+				continue
+			}
+			for _, block := range msg.Content {
+				if block.Type != transcript.ContentTypeToolUse || block.Name != "Bash" {
+					continue
+				}
+				var input transcript.ToolInput
+				if err := json.Unmarshal(block.Input, &input); err == nil && input.Command != "" {
+					commands[block.ID] = input.Command
+				}
+			}
+		case transcript.TypeUser:
+			var msg struct {
+				Content []toolResultWithError `json:"content"`
+			}
+			if err := json.Unmarshal(line.Message, &msg); err != nil {
+				continue // Plain-text prompts have string content
+			}
+			for _, block := range msg.Content {
+				if block.Type != "tool_result" || !block.IsError {
+					continue
+				}
+				command, ok := commands[block.ToolUseID]
+				if !ok {
+					continue
+				}
+				failed = append(failed, agent.FailedCommand{
+					Command: command,
+					Output:  agent.TruncateCommandOutput(toolResultText(block.Content)),
+				})
+			}
+		}
+	}
+	return failed
+}
+
+// toolResultText extracts text from tool_result content, which is either a
+// string or an array of text blocks.
+func toolResultText(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	var blocks []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(raw, &blocks); err != nil {
+		return ""
+	}
+	var texts []string
+	for _, b := range blocks {
+		if b.Type == transcript.ContentTypeText && b.Text != "" {
+			texts = append(texts, b.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
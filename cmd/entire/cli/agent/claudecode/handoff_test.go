@@ -0,0 +1,62 @@
+package claudecode
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/agent"
+	"github.com/entireio/cli/cmd/entire/cli/transcript"
+)
+
+func TestExtractFailedCommands(t *testing.T) {
+	t.Parallel()
+
+	data := `{"type":"user","uuid":"u1","message":{"content":"run the tests"}}
+{"type":"assistant","uuid":"a1","message":{"content":[{"type":"tool_use","id":"t1","name":"Bash","input":{"command":"go test ./..."}},{"type":"tool_use","id":"t2","name":"Bash","input":{"command":"ls"}}]}}
+{"type":"user","uuid":"u2","message":{"content":[{"type":"tool_result","tool_use_id":"t1","is_error":true,"content":"FAIL: TestFoo"},{"type":"tool_result","tool_use_id":"t2","content":"main.go"}]}}
+{"type":"assistant","uuid":"a2","message":{"content":[{"type":"tool_use","id":"t3","name":"Bash","input":{"command":"make lint"}}]}}
+{"type":"user","uuid":"u3","message":{"content":[{"type":"tool_result","tool_use_id":"t3","is_error":true,"content":[{"type":"text","text":"lint failed"}]}]}}
+`
+	lines, err := transcript.ParseFromBytes([]byte(data))
+	if err != nil {
+		t.Fatalf("ParseFromBytes() error = %v", err)
+	}
+
+	got := ExtractFailedCommands(lines)
+	want := []agent.FailedCommand{
+		{Command: "go test ./...", Output: "FAIL: TestFoo"},
+		{Command: "make lint", Output: "lint failed"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d failed commands, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("failed[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestWriteHandoffContext(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Chdir(tempDir)
+
+	ag := &ClaudeCodeAgent{}
+	path, importedFrom, err := ag.WriteHandoffContext([]byte("# Session handoff\n"))
+	if err != nil {
+		t.Fatalf("WriteHandoffContext() error = %v", err)
+	}
+	if path != ".claude/"+agent.HandoffFileName || importedFrom != ClaudeLocalMemoryFileName {
+		t.Errorf("paths = %q, %q", path, importedFrom)
+	}
+
+	memory, err := os.ReadFile(filepath.Join(tempDir, ClaudeLocalMemoryFileName))
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", ClaudeLocalMemoryFileName, err)
+	}
+	if !strings.Contains(string(memory), "@.claude/"+agent.HandoffFileName) {
+		t.Errorf("%s missing import: %q", ClaudeLocalMemoryFileName, memory)
+	}
+}
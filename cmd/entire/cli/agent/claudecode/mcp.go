@@ -0,0 +1,68 @@
+package claudecode
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/entireio/cli/cmd/entire/cli/agent"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+)
+
+// Ensure ClaudeCodeAgent implements MCPServerSupport
+var _ agent.MCPServerSupport = (*ClaudeCodeAgent)(nil)
+
+// ClaudeMCPConfigFileName is Claude Code's project-scoped MCP config file at the repo root.
+const ClaudeMCPConfigFileName = ".mcp.json"
+
+// InstallMCPServer registers `entire mcp` in the project's .mcp.json.
+func (c *ClaudeCodeAgent) InstallMCPServer(localDev bool) (bool, error) {
+	configPath, err := claudeMCPConfigPath()
+	if err != nil {
+		return false, err
+	}
+
+	server := agent.MCPServerConfig{Command: "entire", Args: []string{"mcp"}}
+	if localDev {
+		server = agent.MCPServerConfig{Command: "go", Args: []string{"run", "${CLAUDE_PROJECT_DIR}/cmd/entire/main.go", "mcp"}}
+	}
+
+	changed, err := agent.WriteMCPServerEntry(configPath, agent.MCPServerName, server)
+	if err != nil {
+		return false, fmt.Errorf("failed to register MCP server: %w", err)
+	}
+	return changed, nil
+}
+
+// UninstallMCPServer removes `entire mcp` from the project's .mcp.json.
+func (c *ClaudeCodeAgent) UninstallMCPServer() error {
+	configPath, err := claudeMCPConfigPath()
+	if err != nil {
+		return err
+	}
+	if err := agent.RemoveMCPServerEntry(configPath, agent.MCPServerName); err != nil {
+		return fmt.Errorf("failed to remove MCP server: %w", err)
+	}
+	return nil
+}
+
+// IsMCPServerInstalled reports whether .mcp.json registers the Entire MCP server.
+func (c *ClaudeCodeAgent) IsMCPServerInstalled() bool {
+	configPath, err := claudeMCPConfigPath()
+	if err != nil {
+		return false
+	}
+	return agent.HasMCPServerEntry(configPath, agent.MCPServerName)
+}
+
+func claudeMCPConfigPath() (string, error) {
+	repoRoot, err := paths.RepoRoot()
+	if err != nil {
+		// Fallback to CWD if not in a git repo (e.g., during tests)
+		repoRoot, err = os.Getwd() //nolint:forbidigo // Intentional fallback when RepoRoot() fails (tests run outside git repos)
+		if err != nil {
+			return "", fmt.Errorf("failed to get current directory: %w", err)
+		}
+	}
+	return filepath.Join(repoRoot, ClaudeMCPConfigFileName), nil
+}
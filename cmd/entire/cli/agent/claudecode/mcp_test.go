@@ -0,0 +1,78 @@
+package claudecode
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/agent"
+)
+
+func TestInstallMCPServer(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Chdir(tempDir)
+
+	ag := &ClaudeCodeAgent{}
+	if ag.IsMCPServerInstalled() {
+		t.Fatal("IsMCPServerInstalled() = true before install")
+	}
+
+	changed, err := ag.InstallMCPServer(false)
+	if err != nil {
+		t.Fatalf("InstallMCPServer() error = %v", err)
+	}
+	if !changed {
+		t.Error("first install should report a change")
+	}
+	if !ag.IsMCPServerInstalled() {
+		t.Error("IsMCPServerInstalled() = false after install")
+	}
+
+	data, err := os.ReadFile(filepath.Join(tempDir, ClaudeMCPConfigFileName))
+	if err != nil {
+		t.Fatalf("failed to read .mcp.json: %v", err)
+	}
+	var config struct {
+		MCPServers map[string]agent.MCPServerConfig `json:"mcpServers"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		t.Fatalf("failed to parse .mcp.json: %v", err)
+	}
+	server := config.MCPServers[agent.MCPServerName]
+	if server.Command != "entire" || len(server.Args) != 1 || server.Args[0] != "mcp" {
+		t.Errorf("server = %+v, want entire mcp", server)
+	}
+
+	if err := ag.UninstallMCPServer(); err != nil {
+		t.Fatalf("UninstallMCPServer() error = %v", err)
+	}
+	if ag.IsMCPServerInstalled() {
+		t.Error("IsMCPServerInstalled() = true after uninstall")
+	}
+}
+
+func TestInstallMCPServer_LocalDev(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Chdir(tempDir)
+
+	ag := &ClaudeCodeAgent{}
+	if _, err := ag.InstallMCPServer(true); err != nil {
+		t.Fatalf("InstallMCPServer() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(tempDir, ClaudeMCPConfigFileName))
+	if err != nil {
+		t.Fatalf("failed to read .mcp.json: %v", err)
+	}
+	var config struct {
+		MCPServers map[string]agent.MCPServerConfig `json:"mcpServers"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		t.Fatalf("failed to parse .mcp.json: %v", err)
+	}
+	server := config.MCPServers[agent.MCPServerName]
+	if server.Command != "go" {
+		t.Errorf("local dev command = %q, want go", server.Command)
+	}
+}
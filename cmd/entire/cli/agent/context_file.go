@@ -0,0 +1,54 @@
+package agent
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// HandoffFileName is the name of the file `entire handoff` writes into an
+// agent's config directory.
+const HandoffFileName = "entire-handoff.md"
+
+// WriteContextImport writes content to contextPath and makes sure memoryPath
+// contains importLine (e.g. "@.claude/entire-handoff.md") on a line of its own.
+// memoryPath is created if it doesn't exist; other content is left untouched.
+func WriteContextImport(contextPath string, content []byte, memoryPath, importLine string) error {
+	if err := os.MkdirAll(filepath.Dir(contextPath), 0o750); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", filepath.Base(contextPath), err)
+	}
+	if err := os.WriteFile(contextPath, content, 0o600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(contextPath), err)
+	}
+
+	existing, err := os.ReadFile(memoryPath) //nolint:gosec // path is constructed from repo root + fixed path
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read %s: %w", filepath.Base(memoryPath), err)
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(existing))
+	for scanner.Scan() {
+		if strings.TrimSpace(scanner.Text()) == importLine {
+			return nil
+		}
+	}
+
+	var updated bytes.Buffer
+	updated.Write(existing)
+	if len(existing) > 0 && !bytes.HasSuffix(existing, []byte("\n")) {
+		updated.WriteByte('\n')
+	}
+	if len(existing) > 0 {
+		updated.WriteByte('\n')
+	}
+	updated.WriteString(importLine + "\n")
+
+	if err := os.WriteFile(memoryPath, updated.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(memoryPath), err)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package agent
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteContextImport(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	contextPath := filepath.Join(dir, ".claude", HandoffFileName)
+	memoryPath := filepath.Join(dir, "CLAUDE.local.md")
+	if err := os.WriteFile(memoryPath, []byte("# My notes"), 0o600); err != nil {
+		t.Fatalf("failed to write memory file: %v", err)
+	}
+
+	for range 2 {
+		if err := WriteContextImport(contextPath, []byte("handoff"), memoryPath, "@.claude/"+HandoffFileName); err != nil {
+			t.Fatalf("WriteContextImport() error = %v", err)
+		}
+	}
+
+	content, err := os.ReadFile(contextPath)
+	if err != nil || string(content) != "handoff" {
+		t.Errorf("context file = %q, %v; want handoff", content, err)
+	}
+	memory, err := os.ReadFile(memoryPath)
+	if err != nil {
+		t.Fatalf("failed to read memory file: %v", err)
+	}
+	if got, want := string(memory), "# My notes\n\n@.claude/"+HandoffFileName+"\n"; got != want {
+		t.Errorf("memory file = %q, want %q", got, want)
+	}
+}
+
+func TestWriteContextImport_CreatesMemoryFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	memoryPath := filepath.Join(dir, "GEMINI.md")
+	if err := WriteContextImport(filepath.Join(dir, "ctx.md"), []byte("x"), memoryPath, "@ctx.md"); err != nil {
+		t.Fatalf("WriteContextImport() error = %v", err)
+	}
+	memory, err := os.ReadFile(memoryPath)
+	if err != nil || string(memory) != "@ctx.md\n" {
+		t.Errorf("memory file = %q, %v; want just the import", memory, err)
+	}
+}
+
+func TestTruncateCommandOutput(t *testing.T) {
+	t.Parallel()
+
+	if got := TruncateCommandOutput("  short\n"); got != "short" {
+		t.Errorf("TruncateCommandOutput(short) = %q", got)
+	}
+	long := strings.Repeat("noise line\n", 500) + "error: the real failure"
+	got := TruncateCommandOutput(long)
+	if len(got) > maxFailedCommandOutput+4 {
+		t.Errorf("output not truncated: %d bytes", len(got))
+	}
+	if !strings.HasPrefix(got, "...\n") || !strings.HasSuffix(got, "error: the real failure") {
+		t.Errorf("truncation should keep the tail at a line boundary, got %q...", got[:20])
+	}
+}
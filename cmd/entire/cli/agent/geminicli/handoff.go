@@ -0,0 +1,71 @@
+package geminicli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/entireio/cli/cmd/entire/cli/agent"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+)
+
+// GeminiMemoryFileName is Gemini CLI's project context file.
+const GeminiMemoryFileName = "GEMINI.md"
+
+// ToolRunShellCommand is Gemini CLI's shell tool.
+const ToolRunShellCommand = "run_shell_command"
+
+// toolCallStatusError is the status Gemini CLI records for failed tool calls.
+const toolCallStatusError = "error"
+
+// WriteHandoffContext writes the handoff to .gemini/entire-handoff.md and
+// imports it from GEMINI.md so new sessions load it automatically.
+func (g *GeminiCLIAgent) WriteHandoffContext(content []byte) (string, string, error) {
+	repoRoot, err := paths.RepoRoot()
+	if err != nil {
+		// Fallback to CWD if not in a git repo (e.g., during tests)
+		repoRoot, err = os.Getwd() //nolint:forbidigo // Intentional fallback when RepoRoot() fails (tests run outside git repos)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to get current directory: %w", err)
+		}
+	}
+
+	relPath := filepath.ToSlash(filepath.Join(".gemini", agent.HandoffFileName))
+	if err := agent.WriteContextImport(
+		filepath.Join(repoRoot, ".gemini", agent.HandoffFileName), content,
+		filepath.Join(repoRoot, GeminiMemoryFileName), "@"+relPath,
+	); err != nil {
+		return "", "", err //nolint:wrapcheck // Already descriptive
+	}
+	return relPath, GeminiMemoryFileName, nil
+}
+
+// ExtractFailedCommands returns the shell commands that Gemini CLI recorded
+// with an error status, in transcript order.
+func ExtractFailedCommands(transcript *GeminiTranscript) []agent.FailedCommand {
+	var failed []agent.FailedCommand
+	for _, msg := range transcript.Messages {
+		if msg.Type != MessageTypeGemini {
+			continue
+		}
+		for _, call := range msg.ToolCalls {
+			if call.Name != ToolRunShellCommand || call.Status != toolCallStatusError {
+				continue
+			}
+			command, ok := call.Args["command"].(string)
+			if !ok || command == "" {
+				continue
+			}
+			var output string
+			if len(call.ResultDisplay) > 0 {
+				_ = json.Unmarshal(call.ResultDisplay, &output) //nolint:errcheck // Non-string displays carry no command output
+			}
+			failed = append(failed, agent.FailedCommand{
+				Command: command,
+				Output:  agent.TruncateCommandOutput(output),
+			})
+		}
+	}
+	return failed
+}
@@ -0,0 +1,30 @@
+package geminicli
+
+import (
+	"testing"
+)
+
+func TestExtractFailedCommands(t *testing.T) {
+	t.Parallel()
+
+	data := `{"messages":[
+{"type":"user","content":[{"text":"run the tests"}]},
+{"type":"gemini","content":"Running tests","toolCalls":[
+ {"id":"1","name":"run_shell_command","args":{"command":"npm test"},"status":"error","resultDisplay":"1 test failed"},
+ {"id":"2","name":"run_shell_command","args":{"command":"ls"},"status":"success","resultDisplay":"package.json"},
+ {"id":"3","name":"write_file","args":{"file_path":"a.js"},"status":"error","resultDisplay":{"fileDiff":"..."}}
+]}
+]}`
+	transcript, err := ParseTranscript([]byte(data))
+	if err != nil {
+		t.Fatalf("ParseTranscript() error = %v", err)
+	}
+
+	got := ExtractFailedCommands(transcript)
+	if len(got) != 1 {
+		t.Fatalf("got %d failed commands, want 1: %+v", len(got), got)
+	}
+	if got[0].Command != "npm test" || got[0].Output != "1 test failed" {
+		t.Errorf("failed command = %+v", got[0])
+	}
+}
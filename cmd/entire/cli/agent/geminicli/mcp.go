@@ -0,0 +1,65 @@
+package geminicli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/entireio/cli/cmd/entire/cli/agent"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+)
+
+// Ensure GeminiCLIAgent implements MCPServerSupport
+var _ agent.MCPServerSupport = (*GeminiCLIAgent)(nil)
+
+// InstallMCPServer registers `entire mcp` under mcpServers in .gemini/settings.json.
+func (g *GeminiCLIAgent) InstallMCPServer(localDev bool) (bool, error) {
+	configPath, err := geminiSettingsPath()
+	if err != nil {
+		return false, err
+	}
+
+	server := agent.MCPServerConfig{Command: "entire", Args: []string{"mcp"}}
+	if localDev {
+		server = agent.MCPServerConfig{Command: "go", Args: []string{"run", "${GEMINI_PROJECT_DIR}/cmd/entire/main.go", "mcp"}}
+	}
+
+	changed, err := agent.WriteMCPServerEntry(configPath, agent.MCPServerName, server)
+	if err != nil {
+		return false, fmt.Errorf("failed to register MCP server: %w", err)
+	}
+	return changed, nil
+}
+
+// UninstallMCPServer removes `entire mcp` from .gemini/settings.json.
+func (g *GeminiCLIAgent) UninstallMCPServer() error {
+	configPath, err := geminiSettingsPath()
+	if err != nil {
+		return err
+	}
+	if err := agent.RemoveMCPServerEntry(configPath, agent.MCPServerName); err != nil {
+		return fmt.Errorf("failed to remove MCP server: %w", err)
+	}
+	return nil
+}
+
+// IsMCPServerInstalled reports whether .gemini/settings.json registers the Entire MCP server.
+func (g *GeminiCLIAgent) IsMCPServerInstalled() bool {
+	configPath, err := geminiSettingsPath()
+	if err != nil {
+		return false
+	}
+	return agent.HasMCPServerEntry(configPath, agent.MCPServerName)
+}
+
+func geminiSettingsPath() (string, error) {
+	repoRoot, err := paths.RepoRoot()
+	if err != nil {
+		// Fallback to CWD if not in a git repo (e.g., during tests)
+		repoRoot, err = os.Getwd() //nolint:forbidigo // Intentional fallback when RepoRoot() fails (tests run outside git repos)
+		if err != nil {
+			return "", fmt.Errorf("failed to get current directory: %w", err)
+		}
+	}
+	return filepath.Join(repoRoot, ".gemini", GeminiSettingsFileName), nil
+}
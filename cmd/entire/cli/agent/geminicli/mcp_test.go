@@ -0,0 +1,57 @@
+package geminicli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/agent"
+)
+
+func TestInstallMCPServer_PreservesHooks(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Chdir(tempDir)
+
+	ag := &GeminiCLIAgent{}
+	if _, err := ag.InstallHooks(false, false); err != nil {
+		t.Fatalf("InstallHooks() error = %v", err)
+	}
+	changed, err := ag.InstallMCPServer(false)
+	if err != nil {
+		t.Fatalf("InstallMCPServer() error = %v", err)
+	}
+	if !changed {
+		t.Error("first install should report a change")
+	}
+	if !ag.IsMCPServerInstalled() {
+		t.Error("IsMCPServerInstalled() = false after install")
+	}
+	if !ag.AreHooksInstalled() {
+		t.Error("registering the MCP server should keep existing hooks")
+	}
+
+	data, err := os.ReadFile(filepath.Join(tempDir, ".gemini", GeminiSettingsFileName))
+	if err != nil {
+		t.Fatalf("failed to read settings.json: %v", err)
+	}
+	var settings struct {
+		MCPServers map[string]agent.MCPServerConfig `json:"mcpServers"`
+	}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		t.Fatalf("failed to parse settings.json: %v", err)
+	}
+	if server := settings.MCPServers[agent.MCPServerName]; server.Command != "entire" {
+		t.Errorf("server = %+v, want entire mcp", server)
+	}
+
+	if err := ag.UninstallMCPServer(); err != nil {
+		t.Fatalf("UninstallMCPServer() error = %v", err)
+	}
+	if ag.IsMCPServerInstalled() {
+		t.Error("IsMCPServerInstalled() = true after uninstall")
+	}
+	if !ag.AreHooksInstalled() {
+		t.Error("removing the MCP server should keep existing hooks")
+	}
+}
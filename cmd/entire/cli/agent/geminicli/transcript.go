@@ -86,6 +86,9 @@ type GeminiToolCall struct {
 	Name   string                 `json:"name"`
 	Args   map[string]interface{} `json:"args"`
 	Status string                 `json:"status,omitempty"`
+	// ResultDisplay is the output shown to the user: a string for shell
+	// commands, an object for file diffs.
+	ResultDisplay json.RawMessage `json:"resultDisplay,omitempty"`
 }
 
 // ParseTranscript parses raw JSON content into a transcript structure
@@ -0,0 +1,131 @@
+package agent
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+
+	"github.com/entireio/cli/cmd/entire/cli/jsonutil"
+)
+
+// MCPServerName is the name the Entire MCP server reports to clients and the
+// key under which it is registered in an agent's "mcpServers" config map.
+const MCPServerName = "entire"
+
+// MCPServerConfig is a stdio MCP server entry in an agent config file.
+// Claude Code (.mcp.json) and Gemini CLI (.gemini/settings.json) share this shape.
+type MCPServerConfig struct {
+	Command string   `json:"command"`
+	Args    []string `json:"args,omitempty"`
+}
+
+// WriteMCPServerEntry adds or updates the named server in the "mcpServers"
+// map of the JSON config file at configPath, preserving all other content.
+// Creates the file (and its directory) if needed.
+// Returns true if the file was changed, false if the entry was already up to date.
+func WriteMCPServerEntry(configPath, name string, server MCPServerConfig) (bool, error) {
+	rawConfig, rawServers, err := readMCPConfig(configPath)
+	if err != nil {
+		return false, err
+	}
+
+	if existing, ok := rawServers[name]; ok {
+		var current MCPServerConfig
+		if json.Unmarshal(existing, &current) == nil && reflect.DeepEqual(current, server) {
+			return false, nil
+		}
+	}
+
+	serverJSON, err := json.Marshal(server)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal MCP server config: %w", err)
+	}
+	rawServers[name] = serverJSON
+
+	if err := writeMCPConfig(configPath, rawConfig, rawServers); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RemoveMCPServerEntry removes the named server from the config file at configPath.
+// A missing file or entry is not an error. The "mcpServers" key is dropped when it
+// becomes empty; the file itself is left in place.
+func RemoveMCPServerEntry(configPath, name string) error {
+	if _, err := os.Stat(configPath); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	rawConfig, rawServers, err := readMCPConfig(configPath)
+	if err != nil {
+		return err
+	}
+	if _, ok := rawServers[name]; !ok {
+		return nil
+	}
+	delete(rawServers, name)
+
+	return writeMCPConfig(configPath, rawConfig, rawServers)
+}
+
+// HasMCPServerEntry reports whether the config file at configPath registers the named server.
+func HasMCPServerEntry(configPath, name string) bool {
+	_, rawServers, err := readMCPConfig(configPath)
+	if err != nil {
+		return false
+	}
+	_, ok := rawServers[name]
+	return ok
+}
+
+// readMCPConfig reads a JSON config file, returning the top-level object and its
+// "mcpServers" map. A missing file yields empty maps.
+func readMCPConfig(configPath string) (map[string]json.RawMessage, map[string]json.RawMessage, error) {
+	rawConfig := make(map[string]json.RawMessage)
+	rawServers := make(map[string]json.RawMessage)
+
+	data, err := os.ReadFile(configPath) //nolint:gosec // path is constructed from repo root + fixed path
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return rawConfig, rawServers, nil
+		}
+		return nil, nil, fmt.Errorf("failed to read %s: %w", filepath.Base(configPath), err)
+	}
+
+	if err := json.Unmarshal(data, &rawConfig); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse %s: %w", filepath.Base(configPath), err)
+	}
+	if serversRaw, ok := rawConfig["mcpServers"]; ok {
+		if err := json.Unmarshal(serversRaw, &rawServers); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse mcpServers in %s: %w", filepath.Base(configPath), err)
+		}
+	}
+	return rawConfig, rawServers, nil
+}
+
+func writeMCPConfig(configPath string, rawConfig, rawServers map[string]json.RawMessage) error {
+	if len(rawServers) == 0 {
+		delete(rawConfig, "mcpServers")
+	} else {
+		serversJSON, err := json.Marshal(rawServers)
+		if err != nil {
+			return fmt.Errorf("failed to marshal mcpServers: %w", err)
+		}
+		rawConfig["mcpServers"] = serversJSON
+	}
+
+	if err := os.MkdirAll(filepath.Dir(configPath), 0o750); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	output, err := jsonutil.MarshalIndentWithNewline(rawConfig, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", filepath.Base(configPath), err)
+	}
+	if err := os.WriteFile(configPath, output, 0o600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(configPath), err)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package agent
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func readJSONFile(t *testing.T, path string) map[string]any {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", path, err)
+	}
+	var out map[string]any
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("failed to parse %s: %v", path, err)
+	}
+	return out
+}
+
+func TestWriteMCPServerEntry_CreatesFile(t *testing.T) {
+	t.Parallel()
+
+	configPath := filepath.Join(t.TempDir(), "nested", ".mcp.json")
+	server := MCPServerConfig{Command: "entire", Args: []string{"mcp"}}
+
+	changed, err := WriteMCPServerEntry(configPath, MCPServerName, server)
+	if err != nil {
+		t.Fatalf("WriteMCPServerEntry() error = %v", err)
+	}
+	if !changed {
+		t.Error("first write should report a change")
+	}
+
+	config := readJSONFile(t, configPath)
+	entry, ok := config["mcpServers"].(map[string]any)[MCPServerName].(map[string]any)
+	if !ok {
+		t.Fatalf("mcpServers.%s missing: %v", MCPServerName, config)
+	}
+	if entry["command"] != "entire" {
+		t.Errorf("command = %v, want entire", entry["command"])
+	}
+
+	changed, err = WriteMCPServerEntry(configPath, MCPServerName, server)
+	if err != nil {
+		t.Fatalf("second WriteMCPServerEntry() error = %v", err)
+	}
+	if changed {
+		t.Error("identical write should be a no-op")
+	}
+	if !HasMCPServerEntry(configPath, MCPServerName) {
+		t.Error("HasMCPServerEntry() = false after write")
+	}
+}
+
+func TestWriteMCPServerEntry_PreservesOtherContent(t *testing.T) {
+	t.Parallel()
+
+	configPath := filepath.Join(t.TempDir(), "settings.json")
+	existing := `{"theme":"dark","mcpServers":{"other":{"command":"other-server"}}}`
+	if err := os.WriteFile(configPath, []byte(existing), 0o600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	if _, err := WriteMCPServerEntry(configPath, MCPServerName, MCPServerConfig{Command: "entire", Args: []string{"mcp"}}); err != nil {
+		t.Fatalf("WriteMCPServerEntry() error = %v", err)
+	}
+
+	config := readJSONFile(t, configPath)
+	if config["theme"] != "dark" {
+		t.Errorf("unrelated setting lost: %v", config)
+	}
+	servers := config["mcpServers"].(map[string]any)
+	if _, ok := servers["other"]; !ok {
+		t.Errorf("existing MCP server lost: %v", servers)
+	}
+
+	if err := RemoveMCPServerEntry(configPath, MCPServerName); err != nil {
+		t.Fatalf("RemoveMCPServerEntry() error = %v", err)
+	}
+	config = readJSONFile(t, configPath)
+	servers = config["mcpServers"].(map[string]any)
+	if _, ok := servers[MCPServerName]; ok {
+		t.Error("entry should be removed")
+	}
+	if _, ok := servers["other"]; !ok {
+		t.Error("remove should keep other servers")
+	}
+}
+
+func TestRemoveMCPServerEntry_DropsEmptyMap(t *testing.T) {
+	t.Parallel()
+
+	configPath := filepath.Join(t.TempDir(), ".mcp.json")
+	if _, err := WriteMCPServerEntry(configPath, MCPServerName, MCPServerConfig{Command: "entire"}); err != nil {
+		t.Fatalf("WriteMCPServerEntry() error = %v", err)
+	}
+	if err := RemoveMCPServerEntry(configPath, MCPServerName); err != nil {
+		t.Fatalf("RemoveMCPServerEntry() error = %v", err)
+	}
+	if _, ok := readJSONFile(t, configPath)["mcpServers"]; ok {
+		t.Error("empty mcpServers map should be removed")
+	}
+}
+
+func TestRemoveMCPServerEntry_MissingFile(t *testing.T) {
+	t.Parallel()
+
+	configPath := filepath.Join(t.TempDir(), ".mcp.json")
+	if err := RemoveMCPServerEntry(configPath, MCPServerName); err != nil {
+		t.Errorf("RemoveMCPServerEntry() on missing file error = %v", err)
+	}
+	if _, err := os.Stat(configPath); !os.IsNotExist(err) {
+		t.Error("remove should not create the file")
+	}
+	if HasMCPServerEntry(configPath, MCPServerName) {
+		t.Error("HasMCPServerEntry() = true for missing file")
+	}
+}
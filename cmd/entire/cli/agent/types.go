@@ -1,6 +1,9 @@
 package agent
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 // HookType represents agent lifecycle events
 type HookType string
@@ -59,3 +62,29 @@ type TokenUsage struct {
 	// SubagentTokens contains token usage from spawned subagents (if any)
 	SubagentTokens *TokenUsage `json:"subagent_tokens,omitempty"`
 }
+
+// FailedCommand is a shell command the agent ran that reported an error,
+// extracted from a transcript.
+type FailedCommand struct {
+	// Command is the shell command as the agent issued it
+	Command string
+	// Output is the error output returned to the agent (may be empty or truncated)
+	Output string
+}
+
+// maxFailedCommandOutput bounds the error output kept per failed command.
+const maxFailedCommandOutput = 2000
+
+// TruncateCommandOutput trims command output to a bounded size, keeping the
+// tail, where errors usually are.
+func TruncateCommandOutput(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) <= maxFailedCommandOutput {
+		return s
+	}
+	cut := s[len(s)-maxFailedCommandOutput:]
+	if idx := strings.IndexByte(cut, '\n'); idx >= 0 && idx < len(cut)-1 {
+		cut = cut[idx+1:]
+	}
+	return "...\n" + cut
+}
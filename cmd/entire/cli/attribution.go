@@ -0,0 +1,534 @@
+package cli
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/jsonutil"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/spf13/cobra"
+)
+
+const (
+	attributionFormatText = "text"
+	attributionFormatJSON = "json"
+	attributionFormatCSV  = "csv"
+
+	// attributionHumanAgent is the agent bucket for commits without a checkpoint.
+	attributionHumanAgent = "(none)"
+
+	// attributionUnknownDirectory collects lines from checkpoints recorded before
+	// per-file attribution existed.
+	attributionUnknownDirectory = "(unknown)"
+
+	attributionDateLayout = "2006-01-02"
+)
+
+func newAttributionCmd() *cobra.Command {
+	var formatFlag string
+	var outputFlag string
+	var sinceFlag string
+	var untilFlag string
+	var depthFlag int
+
+	cmd := &cobra.Command{
+		Use:   "attribution [<range>]",
+		Short: "Report agent vs human line attribution over a commit range",
+		Long: `Aggregate line attribution across the commits in a range to answer questions
+like "what share of the code merged to main this quarter came from agents".
+
+The range is either base..head (commits in head that aren't in base) or a
+single revision, which covers its full history like 'git log <revision>'.
+It defaults to HEAD. Merge commits are skipped.
+
+Attribution comes from the checkpoint linked to each commit. Commits without a
+checkpoint count as fully human-written.
+
+Results are broken down by author, agent, directory, and month.
+
+Formats:
+  text  Human-readable tables (default)
+  json  Structured JSON
+  csv   One row per breakdown entry`,
+		Example: `  entire attribution main --since 2026-07-01 --until 2026-09-30
+  entire attribution v1.2.0..v1.3.0 --format csv -o attribution.csv`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if checkDisabledGuard(cmd.OutOrStdout()) {
+				return nil
+			}
+			if !slices.Contains([]string{attributionFormatText, attributionFormatJSON, attributionFormatCSV}, formatFlag) {
+				return fmt.Errorf("invalid format %q: must be one of text, json, csv", formatFlag)
+			}
+			if depthFlag < 1 {
+				return fmt.Errorf("invalid depth %d: must be at least 1", depthFlag)
+			}
+
+			opts := attributionOptions{Range: "HEAD", DirectoryDepth: depthFlag}
+			if len(args) > 0 {
+				opts.Range = args[0]
+			}
+			var err error
+			if opts.Since, err = parseAttributionDate(sinceFlag, false); err != nil {
+				return err
+			}
+			if opts.Until, err = parseAttributionDate(untilFlag, true); err != nil {
+				return err
+			}
+
+			return writeOutput(cmd.OutOrStdout(), outputFlag, func(w io.Writer) error {
+				return runAttribution(cmd.Context(), w, cmd.ErrOrStderr(), opts, formatFlag)
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&formatFlag, "format", "f", attributionFormatText, "Output format: text, json, or csv")
+	cmd.Flags().StringVarP(&outputFlag, "output", "o", "", "Write to a file instead of stdout")
+	cmd.Flags().StringVar(&sinceFlag, "since", "", "Only include commits authored on or after this date (YYYY-MM-DD)")
+	cmd.Flags().StringVar(&untilFlag, "until", "", "Only include commits authored on or before this date (YYYY-MM-DD)")
+	cmd.Flags().IntVar(&depthFlag, "depth", 1, "Number of path components used for the directory breakdown")
+
+	return cmd
+}
+
+// attributionOptions selects the commits to aggregate.
+type attributionOptions struct {
+	Range          string
+	Since          time.Time // Zero means unbounded
+	Until          time.Time // Zero means unbounded
+	DirectoryDepth int
+}
+
+// parseAttributionDate parses a YYYY-MM-DD flag value in UTC. For an end date,
+// the returned time is the last instant of that day so the day is inclusive.
+func parseAttributionDate(value string, endOfDay bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(attributionDateLayout, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q: use YYYY-MM-DD", value)
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return t, nil
+}
+
+// attributionReport is the aggregated attribution for a commit range.
+type attributionReport struct {
+	Range              string              `json:"range"`
+	Since              string              `json:"since,omitempty"`
+	Until              string              `json:"until,omitempty"`
+	Commits            int                 `json:"commits"`
+	CheckpointCommits  int                 `json:"checkpoint_commits"`
+	MissingCheckpoints int                 `json:"missing_checkpoints,omitempty"` // Linked commits whose checkpoint had no readable attribution
+	Total              attributionTotals   `json:"total"`
+	ByAuthor           []attributionBucket `json:"by_author"`
+	ByAgent            []attributionBucket `json:"by_agent"`
+	ByDirectory        []attributionBucket `json:"by_directory"`
+	ByMonth            []attributionBucket `json:"by_month"`
+}
+
+// attributionTotals holds line counts for one slice of the report.
+type attributionTotals struct {
+	Commits         int     `json:"commits"`
+	AgentLines      int     `json:"agent_lines"`
+	HumanAdded      int     `json:"human_added"`
+	HumanModified   int     `json:"human_modified"`
+	HumanRemoved    int     `json:"human_removed"`
+	TotalLines      int     `json:"total_lines"`
+	AgentPercentage float64 `json:"agent_percentage"`
+}
+
+// attributionBucket is one entry of a breakdown (an author, agent, directory, or month).
+type attributionBucket struct {
+	Key string `json:"key"`
+	attributionTotals
+}
+
+func (t *attributionTotals) add(a *checkpoint.InitialAttribution) {
+	t.AgentLines += a.AgentLines
+	t.HumanAdded += a.HumanAdded
+	t.HumanModified += a.HumanModified
+	t.HumanRemoved += a.HumanRemoved
+	t.TotalLines += a.TotalCommitted
+	if t.TotalLines > 0 {
+		t.AgentPercentage = float64(t.AgentLines) / float64(t.TotalLines) * 100
+	}
+}
+
+func runAttribution(ctx context.Context, w, errW io.Writer, opts attributionOptions, format string) error {
+	repo, err := openRepository()
+	if err != nil {
+		return fmt.Errorf("not a git repository: %w", err)
+	}
+
+	report, err := buildAttributionReport(ctx, repo, opts, errW)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case attributionFormatJSON:
+		data, err := jsonutil.MarshalIndentWithNewline(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal report: %w", err)
+		}
+		if _, err := w.Write(data); err != nil {
+			return fmt.Errorf("failed to write report: %w", err)
+		}
+		return nil
+	case attributionFormatCSV:
+		return writeAttributionCSV(w, report)
+	default:
+		if _, err := io.WriteString(w, formatAttributionReport(report)); err != nil {
+			return fmt.Errorf("failed to write report: %w", err)
+		}
+		return nil
+	}
+}
+
+// buildAttributionReport walks the commits selected by opts and aggregates
+// their checkpoint attribution. Each checkpoint is counted once, even when it
+// is linked to several commits in the range (e.g. a rebased copy).
+func buildAttributionReport(ctx context.Context, repo *git.Repository, opts attributionOptions, errW io.Writer) (*attributionReport, error) {
+	commits, err := attributionCommits(repo, opts.Range)
+	if err != nil {
+		return nil, err
+	}
+
+	store := checkpoint.NewGitStore(repo)
+	links, err := strategy.LoadCommitLinkIndex(ctx, store)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // Already wrapped by the strategy package
+	}
+
+	report := &attributionReport{Range: opts.Range}
+	if !opts.Since.IsZero() {
+		report.Since = opts.Since.Format(attributionDateLayout)
+	}
+	if !opts.Until.IsZero() {
+		report.Until = opts.Until.Format(attributionDateLayout)
+	}
+
+	byAuthor := make(map[string]*attributionTotals)
+	byAgent := make(map[string]*attributionTotals)
+	byDirectory := make(map[string]*attributionTotals)
+	byMonth := make(map[string]*attributionTotals)
+	counted := make(map[id.CheckpointID]bool)
+
+	for _, c := range commits {
+		when := c.Author.When.UTC()
+		if (!opts.Since.IsZero() && when.Before(opts.Since)) || (!opts.Until.IsZero() && when.After(opts.Until)) {
+			continue
+		}
+
+		cpIDs := links.CheckpointsForCommit(c)
+		attr, agentType, files, hasCheckpoint, err := commitAttribution(ctx, store, c, cpIDs, counted, errW)
+		if err != nil {
+			return nil, err
+		}
+		if attr == nil {
+			continue // Every checkpoint on this commit was already counted
+		}
+
+		report.Commits++
+		if hasCheckpoint {
+			report.CheckpointCommits++
+		} else if len(cpIDs) > 0 {
+			report.MissingCheckpoints++
+		}
+
+		author := fmt.Sprintf("%s <%s>", c.Author.Name, c.Author.Email)
+		for _, totals := range []*attributionTotals{
+			&report.Total,
+			bucket(byAuthor, author),
+			bucket(byAgent, agentType),
+			bucket(byMonth, when.Format("2006-01")),
+		} {
+			totals.Commits++
+			totals.add(attr)
+		}
+
+		seenDirs := make(map[string]bool)
+		for filePath, fa := range files {
+			dir := attributionDirectory(filePath, opts.DirectoryDepth)
+			totals := bucket(byDirectory, dir)
+			if !seenDirs[dir] {
+				seenDirs[dir] = true
+				totals.Commits++
+			}
+			totals.add(&checkpoint.InitialAttribution{
+				AgentLines:     fa.AgentLines,
+				HumanAdded:     fa.HumanAdded,
+				HumanModified:  fa.HumanModified,
+				HumanRemoved:   fa.HumanRemoved,
+				TotalCommitted: fa.AgentLines + fa.HumanAdded,
+			})
+		}
+		if len(files) == 0 && attr.TotalCommitted+attr.HumanRemoved > 0 {
+			totals := bucket(byDirectory, attributionUnknownDirectory)
+			totals.Commits++
+			totals.add(attr)
+		}
+	}
+
+	report.ByAuthor = sortedBuckets(byAuthor, false)
+	report.ByAgent = sortedBuckets(byAgent, false)
+	report.ByDirectory = sortedBuckets(byDirectory, false)
+	report.ByMonth = sortedBuckets(byMonth, true)
+	return report, nil
+}
+
+// commitAttribution returns the attribution for a single commit. Commits whose
+// checkpoints can't be read count as fully human, using the commit's own diff.
+// Returns a nil attribution when every checkpoint on the commit was already counted.
+func commitAttribution(ctx context.Context, store *checkpoint.GitStore, c *object.Commit, cpIDs []id.CheckpointID, counted map[id.CheckpointID]bool, errW io.Writer) (*checkpoint.InitialAttribution, string, map[string]checkpoint.FileAttribution, bool, error) {
+	var total *checkpoint.InitialAttribution
+	agentType := ""
+	files := make(map[string]checkpoint.FileAttribution)
+	alreadyCounted := len(cpIDs) > 0
+
+	for _, cpID := range cpIDs {
+		if counted[cpID] {
+			continue
+		}
+		alreadyCounted = false
+		counted[cpID] = true
+
+		summary, err := store.ReadCommitted(ctx, cpID)
+		if err != nil {
+			return nil, "", nil, false, fmt.Errorf("failed to read checkpoint %s: %w", cpID, err)
+		}
+		if summary == nil || len(summary.Sessions) == 0 {
+			fmt.Fprintf(errW, "Warning: checkpoint %s (commit %s) not found; counting it as human\n", cpID, c.Hash.String()[:7])
+			continue
+		}
+		// Attribution is calculated per commit, so the latest session has the final numbers
+		content, err := store.ReadSessionMetadataAndPrompts(ctx, cpID, len(summary.Sessions)-1)
+		if err != nil || content.Metadata.InitialAttribution == nil {
+			continue
+		}
+		attr := content.Metadata.InitialAttribution
+		total = addAttribution(total, attr)
+		if agentType == "" {
+			agentType = string(content.Metadata.Agent)
+		}
+		for filePath, fa := range attr.Files {
+			existing := files[filePath]
+			existing.AgentLines += fa.AgentLines
+			existing.HumanAdded += fa.HumanAdded
+			existing.HumanModified += fa.HumanModified
+			existing.HumanRemoved += fa.HumanRemoved
+			files[filePath] = existing
+		}
+	}
+	if alreadyCounted {
+		return nil, "", nil, false, nil
+	}
+	if total != nil {
+		if agentType == "" {
+			agentType = "unknown"
+		}
+		return total, agentType, files, true, nil
+	}
+
+	// No usable checkpoint: every added and removed line is human.
+	human, humanFiles, err := humanCommitAttribution(c)
+	if err != nil {
+		return nil, "", nil, false, err
+	}
+	return human, attributionHumanAgent, humanFiles, false, nil
+}
+
+// humanCommitAttribution attributes a commit's full diff against its first parent to the author.
+func humanCommitAttribution(c *object.Commit) (*checkpoint.InitialAttribution, map[string]checkpoint.FileAttribution, error) {
+	stats, err := c.Stats()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to diff commit %s: %w", c.Hash.String()[:7], err)
+	}
+	attr := &checkpoint.InitialAttribution{}
+	files := make(map[string]checkpoint.FileAttribution, len(stats))
+	for _, stat := range stats {
+		attr.HumanAdded += stat.Addition
+		attr.HumanRemoved += stat.Deletion
+		files[stat.Name] = checkpoint.FileAttribution{HumanAdded: stat.Addition, HumanRemoved: stat.Deletion}
+	}
+	attr.TotalCommitted = attr.HumanAdded
+	return attr, files, nil
+}
+
+// attributionCommits returns the non-merge commits selected by rangeArg, newest first.
+// "base..head" selects commits in head but not base; a single revision selects its history.
+func attributionCommits(repo *git.Repository, rangeArg string) ([]*object.Commit, error) {
+	var commits []*object.Commit
+	if strings.Contains(rangeArg, "..") {
+		base, head, err := parseRevisionRange(rangeArg)
+		if err != nil {
+			return nil, err
+		}
+		commits, err = commitsInRange(repo, base, head)
+		if err != nil {
+			return nil, err
+		}
+		slices.Reverse(commits)
+	} else {
+		start, err := resolveCommit(repo, rangeArg)
+		if err != nil {
+			return nil, err
+		}
+		iter, err := repo.Log(&git.LogOptions{From: start.Hash, Order: git.LogOrderCommitterTime})
+		if err != nil {
+			return nil, fmt.Errorf("failed to walk %s: %w", rangeArg, err)
+		}
+		if err := iter.ForEach(func(c *object.Commit) error {
+			commits = append(commits, c)
+			return nil
+		}); err != nil {
+			return nil, fmt.Errorf("failed to walk %s: %w", rangeArg, err)
+		}
+	}
+
+	return slices.DeleteFunc(commits, func(c *object.Commit) bool {
+		return c.NumParents() > 1
+	}), nil
+}
+
+// attributionDirectory returns the first depth directory components of filePath,
+// or "." for files at the repository root.
+func attributionDirectory(filePath string, depth int) string {
+	dir := path.Dir(filePath)
+	if dir == "." {
+		return dir
+	}
+	parts := strings.Split(dir, "/")
+	if len(parts) > depth {
+		parts = parts[:depth]
+	}
+	return strings.Join(parts, "/") + "/"
+}
+
+func bucket(m map[string]*attributionTotals, key string) *attributionTotals {
+	if t, ok := m[key]; ok {
+		return t
+	}
+	t := &attributionTotals{}
+	m[key] = t
+	return t
+}
+
+// sortedBuckets flattens a breakdown. Chronological breakdowns sort by key;
+// others put the largest contributors first.
+func sortedBuckets(m map[string]*attributionTotals, byKey bool) []attributionBucket {
+	buckets := make([]attributionBucket, 0, len(m))
+	for key, totals := range m {
+		buckets = append(buckets, attributionBucket{Key: key, attributionTotals: *totals})
+	}
+	sort.Slice(buckets, func(i, j int) bool {
+		if !byKey && buckets[i].TotalLines != buckets[j].TotalLines {
+			return buckets[i].TotalLines > buckets[j].TotalLines
+		}
+		return buckets[i].Key < buckets[j].Key
+	})
+	return buckets
+}
+
+// writeAttributionCSV writes one row for the total and one per breakdown entry.
+func writeAttributionCSV(w io.Writer, report *attributionReport) error {
+	cw := csv.NewWriter(w)
+	rows := [][]string{{"dimension", "key", "commits", "agent_lines", "human_added", "human_modified", "human_removed", "total_lines", "agent_percentage"}}
+	row := func(dimension, key string, t attributionTotals) []string {
+		return []string{
+			dimension, key,
+			strconv.Itoa(t.Commits),
+			strconv.Itoa(t.AgentLines),
+			strconv.Itoa(t.HumanAdded),
+			strconv.Itoa(t.HumanModified),
+			strconv.Itoa(t.HumanRemoved),
+			strconv.Itoa(t.TotalLines),
+			strconv.FormatFloat(t.AgentPercentage, 'f', 1, 64),
+		}
+	}
+	rows = append(rows, row("total", "", report.Total))
+	for _, dim := range []struct {
+		name    string
+		buckets []attributionBucket
+	}{
+		{"author", report.ByAuthor},
+		{"agent", report.ByAgent},
+		{"directory", report.ByDirectory},
+		{"month", report.ByMonth},
+	} {
+		for _, b := range dim.buckets {
+			rows = append(rows, row(dim.name, b.Key, b.attributionTotals))
+		}
+	}
+	if err := cw.WriteAll(rows); err != nil {
+		return fmt.Errorf("failed to write csv: %w", err)
+	}
+	return nil
+}
+
+// formatAttributionReport renders the report as plain-text tables.
+func formatAttributionReport(report *attributionReport) string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "Attribution for %s", report.Range)
+	if report.Since != "" || report.Until != "" {
+		fmt.Fprintf(&sb, " (%s to %s)", orDefault(report.Since, "start"), orDefault(report.Until, "now"))
+	}
+	sb.WriteString("\n\n")
+	fmt.Fprintf(&sb, "Commits: %d (%d with checkpoints)\n", report.Commits, report.CheckpointCommits)
+	t := report.Total
+	fmt.Fprintf(&sb, "Lines: %d total, %d agent (%.1f%%), %d human added, %d modified, %d removed\n",
+		t.TotalLines, t.AgentLines, t.AgentPercentage, t.HumanAdded, t.HumanModified, t.HumanRemoved)
+	if report.MissingCheckpoints > 0 {
+		fmt.Fprintf(&sb, "Commits without checkpoint attribution: %d (counted as human)\n", report.MissingCheckpoints)
+	}
+
+	for _, dim := range []struct {
+		title   string
+		buckets []attributionBucket
+	}{
+		{"Author", report.ByAuthor},
+		{"Agent", report.ByAgent},
+		{"Directory", report.ByDirectory},
+		{"Month", report.ByMonth},
+	} {
+		if len(dim.buckets) == 0 {
+			continue
+		}
+		width := len(dim.title)
+		for _, b := range dim.buckets {
+			width = max(width, len(b.Key))
+		}
+		sb.WriteString("\n")
+		fmt.Fprintf(&sb, "%-*s  %7s  %7s  %7s  %7s\n", width, dim.title, "Commits", "Agent", "Human", "Agent %")
+		for _, b := range dim.buckets {
+			fmt.Fprintf(&sb, "%-*s  %7d  %7d  %7d  %6.1f%%\n", width, b.Key, b.Commits, b.AgentLines, b.HumanAdded, b.AgentPercentage)
+		}
+	}
+
+	return sb.String()
+}
+
+func orDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
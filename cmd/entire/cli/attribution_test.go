@@ -0,0 +1,234 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/agent"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/trailers"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// setupAttributionTestRepo extends the serve test repo with a hand-written commit
+// from another author in February 2026 and a second commit reusing the checkpoint.
+func setupAttributionTestRepo(t *testing.T) (*git.Repository, id.CheckpointID) {
+	t.Helper()
+
+	repo, cpID := setupServeTestRepo(t)
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("failed to get worktree: %v", err)
+	}
+	root := wt.Filesystem.Root()
+
+	commit := func(name, content, message string, sig *object.Signature) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(root, name)), 0o755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+		if _, err := wt.Add(name); err != nil {
+			t.Fatalf("failed to add %s: %v", name, err)
+		}
+		if _, err := wt.Commit(message, &git.CommitOptions{Author: sig}); err != nil {
+			t.Fatalf("failed to commit: %v", err)
+		}
+	}
+
+	alice := &object.Signature{Name: "Alice", Email: "alice@example.com", When: time.Date(2026, 2, 15, 12, 0, 0, 0, time.UTC)}
+	commit("docs/guide.md", "one\ntwo\nthree\n", "Write guide", alice)
+
+	// A rebased copy of the checkpoint commit must not be counted twice.
+	test := &object.Signature{Name: "Test", Email: "test@example.com", When: time.Now()}
+	commit("hello.txt", "hello world!\n", "Add greeting (rebased)\n\n"+trailers.CheckpointTrailerKey+": "+cpID.String()+"\n", test)
+
+	return repo, cpID
+}
+
+func findBucket(buckets []attributionBucket, key string) *attributionBucket {
+	for i := range buckets {
+		if buckets[i].Key == key {
+			return &buckets[i]
+		}
+	}
+	return nil
+}
+
+func TestBuildAttributionReport(t *testing.T) {
+	repo, _ := setupAttributionTestRepo(t)
+
+	report, err := buildAttributionReport(context.Background(), repo, attributionOptions{Range: "HEAD", DirectoryDepth: 1}, io.Discard)
+	if err != nil {
+		t.Fatalf("buildAttributionReport() error = %v", err)
+	}
+
+	// README (human), hello.txt (checkpoint), docs/guide.md (human); the rebased copy is skipped.
+	if report.Commits != 3 || report.CheckpointCommits != 1 {
+		t.Errorf("commits = %d (%d with checkpoints), want 3 (1)", report.Commits, report.CheckpointCommits)
+	}
+	if tot := report.Total; tot.AgentLines != 1 || tot.HumanAdded != 4 || tot.TotalLines != 5 {
+		t.Errorf("total = %+v, want 1 agent and 4 human lines of 5", tot)
+	}
+
+	if b := findBucket(report.ByAgent, string(agent.AgentTypeClaudeCode)); b == nil || b.AgentLines != 1 || b.Commits != 1 {
+		t.Errorf("Claude Code bucket = %+v", b)
+	}
+	if b := findBucket(report.ByAgent, attributionHumanAgent); b == nil || b.HumanAdded != 4 || b.Commits != 2 {
+		t.Errorf("human bucket = %+v", b)
+	}
+	if b := findBucket(report.ByAuthor, "Alice <alice@example.com>"); b == nil || b.HumanAdded != 3 {
+		t.Errorf("Alice bucket = %+v", b)
+	}
+	if b := findBucket(report.ByDirectory, "docs/"); b == nil || b.HumanAdded != 3 {
+		t.Errorf("docs/ bucket = %+v", b)
+	}
+	// The checkpoint predates per-file attribution.
+	if b := findBucket(report.ByDirectory, attributionUnknownDirectory); b == nil || b.AgentLines != 1 {
+		t.Errorf("unknown directory bucket = %+v", b)
+	}
+	if b := findBucket(report.ByMonth, "2026-02"); b == nil || b.Commits != 1 {
+		t.Errorf("2026-02 bucket = %+v", b)
+	}
+	if report.ByMonth[0].Key != "2026-02" {
+		t.Errorf("months should be chronological, got %+v", report.ByMonth)
+	}
+}
+
+func TestBuildAttributionReport_DateFilter(t *testing.T) {
+	repo, _ := setupAttributionTestRepo(t)
+
+	since, err := parseAttributionDate("2026-02-01", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	until, err := parseAttributionDate("2026-02-15", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := buildAttributionReport(context.Background(), repo, attributionOptions{Range: "HEAD", Since: since, Until: until, DirectoryDepth: 1}, io.Discard)
+	if err != nil {
+		t.Fatalf("buildAttributionReport() error = %v", err)
+	}
+	if report.Commits != 1 || len(report.ByAuthor) != 1 || report.ByAuthor[0].Key != "Alice <alice@example.com>" {
+		t.Errorf("report = %+v, want only Alice's commit", report)
+	}
+	if report.Since != "2026-02-01" || report.Until != "2026-02-15" {
+		t.Errorf("since/until = %q/%q", report.Since, report.Until)
+	}
+}
+
+func TestRunAttribution_Formats(t *testing.T) {
+	setupAttributionTestRepo(t)
+	opts := attributionOptions{Range: "HEAD~2..HEAD", DirectoryDepth: 1}
+
+	var jsonOut bytes.Buffer
+	if err := runAttribution(context.Background(), &jsonOut, io.Discard, opts, attributionFormatJSON); err != nil {
+		t.Fatalf("json error = %v", err)
+	}
+	var report attributionReport
+	if err := json.Unmarshal(jsonOut.Bytes(), &report); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	// The range holds Alice's commit and the rebased copy of an out-of-range checkpoint.
+	if report.Range != "HEAD~2..HEAD" || report.Commits != 2 {
+		t.Errorf("report = %+v", report)
+	}
+
+	var csvOut bytes.Buffer
+	if err := runAttribution(context.Background(), &csvOut, io.Discard, opts, attributionFormatCSV); err != nil {
+		t.Fatalf("csv error = %v", err)
+	}
+	rows, err := csv.NewReader(&csvOut).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid csv: %v", err)
+	}
+	if len(rows) < 2 || rows[0][0] != "dimension" || rows[1][0] != "total" {
+		t.Fatalf("csv rows = %v", rows)
+	}
+
+	var text bytes.Buffer
+	if err := runAttribution(context.Background(), &text, io.Discard, opts, attributionFormatText); err != nil {
+		t.Fatalf("text error = %v", err)
+	}
+	for _, want := range []string{"Attribution for HEAD~2..HEAD", "Commits: 2", "Alice <alice@example.com>", "docs/"} {
+		if !strings.Contains(text.String(), want) {
+			t.Errorf("text report missing %q:\n%s", want, text.String())
+		}
+	}
+}
+
+func TestAttributionDirectory(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		path  string
+		depth int
+		want  string
+	}{
+		{"README.md", 1, "."},
+		{"cmd/entire/main.go", 1, "cmd/"},
+		{"cmd/entire/main.go", 2, "cmd/entire/"},
+		{"cmd/entire/main.go", 5, "cmd/entire/"},
+	}
+	for _, tt := range tests {
+		if got := attributionDirectory(tt.path, tt.depth); got != tt.want {
+			t.Errorf("attributionDirectory(%q, %d) = %q, want %q", tt.path, tt.depth, got, tt.want)
+		}
+	}
+}
+
+func TestWriteOutput(t *testing.T) {
+	t.Parallel()
+
+	var stdout bytes.Buffer
+	if err := writeOutput(&stdout, "-", func(w io.Writer) error {
+		_, err := io.WriteString(w, "report")
+		return err
+	}); err != nil {
+		t.Fatalf("writeOutput to stdout: %v", err)
+	}
+	if stdout.String() != "report" {
+		t.Errorf("stdout = %q, want report", stdout.String())
+	}
+
+	path := filepath.Join(t.TempDir(), "report.txt")
+	if err := writeOutput(&stdout, path, func(w io.Writer) error {
+		_, err := io.WriteString(w, "file report")
+		return err
+	}); err != nil {
+		t.Fatalf("writeOutput to file: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	if string(data) != "file report" {
+		t.Errorf("file = %q, want file report", data)
+	}
+
+	// A failing close must surface, so an incomplete report doesn't exit 0.
+	err = writeOutput(&stdout, path, func(w io.Writer) error {
+		f, ok := w.(*os.File)
+		if !ok {
+			t.Fatalf("writer is %T, want *os.File", w)
+		}
+		return f.Close()
+	})
+	if err == nil || !strings.Contains(err.Error(), "failed to write output file") {
+		t.Errorf("writeOutput close error = %v, want failed to write output file", err)
+	}
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/entireio/cli/cmd/entire/cli/agent"
 	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/session"
 
 	"github.com/go-git/go-git/v5/plumbing"
 )
@@ -22,6 +23,10 @@ var (
 	// ErrCheckpointNotFound is returned when a checkpoint ID doesn't exist.
 	ErrCheckpointNotFound = errors.New("checkpoint not found")
 
+	// ErrSessionNotFound is returned when a checkpoint exists but has no
+	// session with the requested ID.
+	ErrSessionNotFound = errors.New("session not found in checkpoint")
+
 	// ErrNoTranscript is returned when a checkpoint exists but has no transcript.
 	ErrNoTranscript = errors.New("no transcript found for checkpoint")
 )
@@ -290,6 +295,9 @@ type WriteCommittedOptions struct {
 	// Persisted in CommittedMetadata so restore can write the transcript back to
 	// the correct location without reconstructing agent-specific paths.
 	SessionTranscriptPath string
+
+	// Annotations are the user's title, tags, and notes for the session
+	Annotations session.Annotations
 }
 
 // UpdateCommittedOptions contains options for updating an existing committed checkpoint.
@@ -345,6 +353,12 @@ type CommittedInfo struct {
 	// Multi-session support
 	SessionCount int      // Number of sessions (1 if single session)
 	SessionIDs   []string // All session IDs that contributed
+
+	// LinkedCommits are rewritten commits that resolve to this checkpoint without a trailer
+	LinkedCommits []LinkedCommit
+
+	// Annotations are the title, tags, and notes of the most recent session
+	Annotations session.Annotations
 }
 
 // SessionContent contains the actual content for a session.
@@ -407,6 +421,10 @@ type CommittedMetadata struct {
 	// Persisted so restore can write the transcript back to the correct location
 	// without needing to reconstruct agent-specific paths (e.g. SHA-256 hashed dirs for Gemini).
 	TranscriptPath string `json:"transcript_path,omitempty"`
+
+	// Annotations are the user's title, tags, and notes for the session.
+	// Updated in place by "entire session rename/tag/note" after condensation.
+	session.Annotations
 }
 
 // GetTranscriptStart returns the transcript line offset at which this checkpoint's data begins.
@@ -458,6 +476,35 @@ type CheckpointSummary struct {
 	FilesTouched     []string           `json:"files_touched"`
 	Sessions         []SessionFilePaths `json:"sessions"`
 	TokenUsage       *agent.TokenUsage  `json:"token_usage,omitempty"`
+	LinkedCommits    []LinkedCommit     `json:"linked_commits,omitempty"`
+}
+
+// HasLinkedCommit reports whether the given commit SHA is already linked to the checkpoint.
+func (s *CheckpointSummary) HasLinkedCommit(sha string) bool {
+	for _, link := range s.LinkedCommits {
+		if link.SHA == sha {
+			return true
+		}
+	}
+	return false
+}
+
+// Reasons a rewritten commit was linked to a checkpoint.
+const (
+	LinkReasonAmend   = "amend"    // git commit --amend (post-rewrite hook)
+	LinkReasonRebase  = "rebase"   // git rebase (post-rewrite hook)
+	LinkReasonPatchID = "patch-id" // matched by `git patch-id` against an original commit
+)
+
+// LinkedCommit is a commit that was rewritten from one of a checkpoint's original
+// commits and lost its Entire-Checkpoint trailer along the way (e.g. a fixup
+// during an interactive rebase, or a squash-merge). It resolves to the checkpoint
+// just like a commit with the trailer, so the checkpoint's attribution carries over.
+type LinkedCommit struct {
+	SHA         string    `json:"sha"`
+	OriginalSHA string    `json:"original_sha"`
+	Reason      string    `json:"reason"`
+	LinkedAt    time.Time `json:"linked_at"`
 }
 
 // Summary contains AI-generated summary of a checkpoint.
@@ -467,6 +514,10 @@ type Summary struct {
 	Learnings LearningsSummary `json:"learnings"`  // Categorized learnings
 	Friction  []string         `json:"friction"`   // Problems/annoyances encountered
 	OpenItems []string         `json:"open_items"` // Tech debt, unfinished work
+
+	// Extensions holds the extra fields defined by the repository's summary
+	// template (.entire/summary-template.json). Values are strings or string lists.
+	Extensions map[string]any `json:"extensions,omitempty"`
 }
 
 // LearningsSummary contains learnings grouped by scope.
@@ -506,6 +557,46 @@ type InitialAttribution struct {
 	HumanRemoved    int       `json:"human_removed"`    // Lines removed by human (excluding modifications)
 	TotalCommitted  int       `json:"total_committed"`  // Net additions in commit (agent + human new lines, not total file size)
 	AgentPercentage float64   `json:"agent_percentage"` // agent_lines / total_committed * 100 (0 for deletion-only commits)
+
+	// Files breaks the totals down per file path. Each entry is calculated
+	// independently, so per-file values don't always sum exactly to the totals
+	// (modifications are estimated per file rather than across the commit).
+	Files map[string]FileAttribution `json:"files,omitempty"`
+
+	// Prompts breaks the agent lines down per prompt, in the order the prompts
+	// were made, along with the human edits made before the next prompt.
+	Prompts []PromptAttribution `json:"prompts,omitempty"`
+
+	// Subagents attributes agent lines to the subagents that wrote them, based on
+	// the files each subagent modified. Lines not listed here came from the main agent.
+	Subagents []SubagentAttribution `json:"subagents,omitempty"`
+}
+
+// FileAttribution is the line-level attribution for a single file in a commit.
+type FileAttribution struct {
+	AgentLines    int `json:"agent_lines"`    // Agent lines remaining in the file
+	HumanAdded    int `json:"human_added"`    // Lines added by human (excluding modifications)
+	HumanModified int `json:"human_modified"` // Lines modified by human (estimate: min(added, removed))
+	HumanRemoved  int `json:"human_removed"`  // Lines removed by human (excluding modifications)
+}
+
+// PromptAttribution is the line-level attribution for a single prompt in a session.
+// Human edits are those made after the agent responded and before the next prompt
+// (or, for the last prompt, before the commit).
+type PromptAttribution struct {
+	Checkpoint   int    `json:"checkpoint"`       // Session checkpoint the prompt produced (1-indexed)
+	Prompt       string `json:"prompt,omitempty"` // User prompt (truncated)
+	AgentLines   int    `json:"agent_lines"`      // Lines added by the agent in response to the prompt
+	HumanAdded   int    `json:"human_added"`      // Lines added by human before the next prompt
+	HumanRemoved int    `json:"human_removed"`    // Lines removed by human before the next prompt
+}
+
+// SubagentAttribution is the share of a commit's agent lines written by one subagent.
+type SubagentAttribution struct {
+	AgentID      string   `json:"agent_id,omitempty"`      // Subagent ID
+	SubagentType string   `json:"subagent_type,omitempty"` // Subagent type (e.g., "dev", "reviewer")
+	AgentLines   int      `json:"agent_lines"`             // Agent lines in files the subagent modified
+	Files        []string `json:"files,omitempty"`         // Files the lines were counted from
 }
 
 // Info provides summary information for listing checkpoints.
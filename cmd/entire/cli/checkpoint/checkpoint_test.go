@@ -8,6 +8,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"testing"
@@ -17,6 +18,7 @@ import (
 	"github.com/entireio/cli/cmd/entire/cli/buildinfo"
 	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
 	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/session"
 	"github.com/entireio/cli/cmd/entire/cli/trailers"
 
 	"github.com/go-git/go-git/v5"
@@ -676,6 +678,112 @@ func TestUpdateSummary_NotFound(t *testing.T) {
 	}
 }
 
+// TestUpdateSessionSummary verifies that UpdateSessionSummary writes the summary
+// to the named session rather than the latest one.
+func TestUpdateSessionSummary(t *testing.T) {
+	repo, _ := setupBranchTestRepo(t)
+	store := NewGitStore(repo)
+	checkpointID := id.MustCheckpointID("a1b2c3d4e5f6")
+
+	for _, sessionID := range []string{"session-one", "session-two"} {
+		if err := store.WriteCommitted(context.Background(), WriteCommittedOptions{
+			CheckpointID: checkpointID,
+			SessionID:    sessionID,
+			Strategy:     "manual-commit",
+			Transcript:   []byte("transcript for " + sessionID),
+			AuthorName:   "Test Author",
+			AuthorEmail:  "test@example.com",
+		}); err != nil {
+			t.Fatalf("WriteCommitted(%s) error = %v", sessionID, err)
+		}
+	}
+
+	summary := &Summary{Intent: "First session intent", Outcome: "Done"}
+	if err := store.UpdateSessionSummary(context.Background(), checkpointID, "session-one", summary); err != nil {
+		t.Fatalf("UpdateSessionSummary() error = %v", err)
+	}
+
+	first, err := store.ReadSessionContentByID(context.Background(), checkpointID, "session-one")
+	if err != nil {
+		t.Fatalf("ReadSessionContentByID() error = %v", err)
+	}
+	if first.Metadata.Summary == nil || first.Metadata.Summary.Intent != "First session intent" {
+		t.Errorf("session-one summary = %+v, want the updated summary", first.Metadata.Summary)
+	}
+	if latest := readLatestSessionMetadata(t, repo, checkpointID); latest.Summary != nil {
+		t.Errorf("latest session summary = %+v, want untouched", latest.Summary)
+	}
+
+	if err := store.UpdateSessionSummary(context.Background(), checkpointID, "missing", summary); err == nil {
+		t.Error("UpdateSessionSummary() should return error for an unknown session")
+	}
+}
+
+func TestUpdateSessionAnnotations(t *testing.T) {
+	repo, _ := setupBranchTestRepo(t)
+	store := NewGitStore(repo)
+	first := id.MustCheckpointID("a1b2c3d4e5f6")
+	second := id.MustCheckpointID("b1b2c3d4e5f6")
+
+	for _, cp := range []struct {
+		checkpointID id.CheckpointID
+		sessionID    string
+	}{
+		{first, "session-one"},
+		{first, "session-two"},
+		{second, "session-one"},
+	} {
+		if err := store.WriteCommitted(context.Background(), WriteCommittedOptions{
+			CheckpointID: cp.checkpointID,
+			SessionID:    cp.sessionID,
+			Strategy:     "manual-commit",
+			Transcript:   []byte("transcript for " + cp.sessionID),
+			AuthorName:   "Test Author",
+			AuthorEmail:  "test@example.com",
+			Annotations:  session.Annotations{Tags: []string{"initial"}},
+		}); err != nil {
+			t.Fatalf("WriteCommitted(%s) error = %v", cp.sessionID, err)
+		}
+	}
+
+	updated, err := store.UpdateSessionAnnotations(context.Background(), "session-one", func(a *session.Annotations) {
+		a.Title = "Login refactor"
+		a.AddTag("auth")
+	})
+	if err != nil {
+		t.Fatalf("UpdateSessionAnnotations() error = %v", err)
+	}
+	if updated != 2 {
+		t.Errorf("UpdateSessionAnnotations() updated %d checkpoints, want 2", updated)
+	}
+
+	for _, checkpointID := range []id.CheckpointID{first, second} {
+		content, err := store.ReadSessionContentByID(context.Background(), checkpointID, "session-one")
+		if err != nil {
+			t.Fatalf("ReadSessionContentByID() error = %v", err)
+		}
+		got := content.Metadata.Annotations
+		if got.Title != "Login refactor" || !slices.Equal(got.Tags, []string{"initial", "auth"}) {
+			t.Errorf("checkpoint %s annotations = %+v, want the update applied", checkpointID, got)
+		}
+	}
+	other, err := store.ReadSessionContentByID(context.Background(), first, "session-two")
+	if err != nil {
+		t.Fatalf("ReadSessionContentByID() error = %v", err)
+	}
+	if other.Metadata.Title != "" {
+		t.Errorf("session-two title = %q, want untouched", other.Metadata.Title)
+	}
+
+	// Applying the same update again changes nothing
+	updated, err = store.UpdateSessionAnnotations(context.Background(), "session-one", func(a *session.Annotations) {
+		a.AddTag("auth")
+	})
+	if err != nil || updated != 0 {
+		t.Errorf("UpdateSessionAnnotations() = %d, %v, want 0, nil", updated, err)
+	}
+}
+
 // TestListCommitted_FallsBackToRemote verifies that ListCommitted can find
 // checkpoints when only origin/entire/checkpoints/v1 exists (simulating post-clone state).
 func TestListCommitted_FallsBackToRemote(t *testing.T) {
@@ -1273,6 +1381,9 @@ func TestReadSessionContentByID_NotFound(t *testing.T) {
 	if !strings.Contains(err.Error(), "not found") {
 		t.Errorf("error should mention 'not found', got: %v", err)
 	}
+	if !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("error should wrap ErrSessionNotFound, got: %v", err)
+	}
 }
 
 // TestListCommitted_MultiSessionInfo verifies that ListCommitted returns correct
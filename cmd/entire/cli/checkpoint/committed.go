@@ -10,6 +10,7 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"runtime"
 	"sort"
 	"strconv"
 	"strings"
@@ -21,15 +22,18 @@ import (
 	"github.com/entireio/cli/cmd/entire/cli/jsonutil"
 	"github.com/entireio/cli/cmd/entire/cli/logging"
 	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/session"
 	"github.com/entireio/cli/cmd/entire/cli/trailers"
 	"github.com/entireio/cli/cmd/entire/cli/validation"
 	"github.com/entireio/cli/redact"
 
+	"github.com/go-git/go-billy/v5"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/filemode"
 	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage"
 	"github.com/go-git/go-git/v5/utils/binary"
 )
 
@@ -64,6 +68,13 @@ func (s *GitStore) WriteCommitted(ctx context.Context, opts WriteCommittedOption
 		return fmt.Errorf("failed to ensure sessions branch: %w", err)
 	}
 
+	return s.updateSessionsBranch(func() error {
+		return s.writeCommitted(opts)
+	})
+}
+
+// writeCommitted adds a checkpoint on top of the current sessions branch tip.
+func (s *GitStore) writeCommitted(opts WriteCommittedOptions) error {
 	// Get current branch tip and flatten tree
 	ref, entries, err := s.getSessionsBranchEntries()
 	if err != nil {
@@ -101,13 +112,7 @@ func (s *GitStore) WriteCommitted(ctx context.Context, opts WriteCommittedOption
 		return err
 	}
 
-	refName := plumbing.NewBranchReferenceName(paths.MetadataBranchName)
-	newRef := plumbing.NewHashReference(refName, newCommitHash)
-	if err := s.repo.Storer.SetReference(newRef); err != nil {
-		return fmt.Errorf("failed to set branch reference: %w", err)
-	}
-
-	return nil
+	return s.setSessionsBranchRef(ref, newCommitHash)
 }
 
 // getSessionsBranchEntries returns the sessions branch reference and flattened tree entries.
@@ -136,6 +141,70 @@ func (s *GitStore) getSessionsBranchEntries() (*plumbing.Reference, map[string]o
 	return ref, entries, nil
 }
 
+// sessionsBranchLockFile is the lock file, in the git directory, that
+// serializes writers of the sessions branch across processes.
+const sessionsBranchLockFile = "entire-checkpoints.lock"
+
+// maxBranchUpdateAttempts bounds how often a sessions branch update is retried
+// when the branch was moved by a writer that doesn't take the lock.
+const maxBranchUpdateAttempts = 5
+
+// lockSessionsBranch blocks until no other process is writing the sessions
+// branch and returns a function that releases the lock. Agent hooks, git hooks,
+// and the background summary worker all write the branch concurrently; without
+// the lock, one writer could read the tip while another is replacing it.
+func (s *GitStore) lockSessionsBranch() (func(), error) {
+	fsStorer, ok := s.repo.Storer.(interface{ Filesystem() billy.Filesystem })
+	if !ok {
+		return func() {}, nil // In-memory repositories aren't shared between processes
+	}
+	f, err := fsStorer.Filesystem().OpenFile(sessionsBranchLockFile, os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sessions branch lock: %w", err)
+	}
+	if err := f.Lock(); err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("failed to lock sessions branch: %w", err)
+	}
+	return func() {
+		_ = f.Unlock()
+		_ = f.Close()
+	}, nil
+}
+
+// updateSessionsBranch runs update, which reads the sessions branch tip and
+// commits on top of it, while holding the sessions branch lock. If the branch
+// still moved in between (e.g. by a fetch), update runs again from the new tip.
+func (s *GitStore) updateSessionsBranch(update func() error) error {
+	var err error
+	for range maxBranchUpdateAttempts {
+		unlock, lockErr := s.lockSessionsBranch()
+		if lockErr != nil {
+			return lockErr
+		}
+		err = update()
+		unlock()
+		if !errors.Is(err, storage.ErrReferenceHasChanged) {
+			return err
+		}
+	}
+	return fmt.Errorf("sessions branch kept changing concurrently: %w", err)
+}
+
+// setSessionsBranchRef moves the sessions branch from old to newCommit. It
+// fails with storage.ErrReferenceHasChanged if the branch moved since old was
+// read, so another writer's commit is never silently overwritten.
+func (s *GitStore) setSessionsBranchRef(old *plumbing.Reference, newCommit plumbing.Hash) error {
+	newRef := plumbing.NewHashReference(old.Name(), newCommit)
+	if err := s.repo.Storer.CheckAndSetReference(newRef, old); err != nil {
+		if errors.Is(err, storage.ErrReferenceHasChanged) {
+			return err //nolint:wrapcheck // Sentinel checked by updateSessionsBranch
+		}
+		return fmt.Errorf("failed to set branch reference: %w", err)
+	}
+	return nil
+}
+
 // writeTaskCheckpointEntries writes task-specific checkpoint entries and returns the task metadata path.
 func (s *GitStore) writeTaskCheckpointEntries(opts WriteCommittedOptions, basePath string, entries map[string]object.TreeEntry) (string, error) {
 	taskPath := basePath + "tasks/" + opts.ToolUseID + "/"
@@ -278,7 +347,7 @@ func (s *GitStore) writeStandardCheckpointEntries(opts WriteCommittedOptions, ba
 	sessions[sessionIndex] = sessionFilePaths
 
 	// Update root metadata.json with CheckpointSummary
-	return s.writeCheckpointSummary(opts, basePath, entries, sessions)
+	return s.writeCheckpointSummary(opts, basePath, entries, sessions, existingSummary)
 }
 
 // writeSessionToSubdirectory writes a single session's files to a numbered subdirectory.
@@ -294,16 +363,23 @@ func (s *GitStore) writeSessionToSubdirectory(opts WriteCommittedOptions, sessio
 		}
 	}
 
+	// Count what redaction removes from each file for redactions.json
+	report := &RedactionReport{}
+	counts := redact.Counts{}
+
 	// Write transcript
-	if err := s.writeTranscript(opts, sessionPath, entries); err != nil {
+	if err := s.writeTranscript(opts, sessionPath, entries, redact.Default().WithCounts(counts)); err != nil {
 		return filePaths, err
 	}
+	report.setFile(paths.TranscriptFileName, counts)
 	filePaths.Transcript = "/" + sessionPath + paths.TranscriptFileName
 	filePaths.ContentHash = "/" + sessionPath + paths.ContentHashFileName
 
 	// Write prompts
 	if len(opts.Prompts) > 0 {
-		promptContent := redact.String(strings.Join(opts.Prompts, "\n\n---\n\n"))
+		counts := redact.Counts{}
+		promptContent := redact.Default().WithCounts(counts).String(strings.Join(opts.Prompts, "\n\n---\n\n"))
+		report.setFile(paths.PromptFileName, counts)
 		blobHash, err := CreateBlobFromContent(s.repo, []byte(promptContent))
 		if err != nil {
 			return filePaths, err
@@ -318,7 +394,10 @@ func (s *GitStore) writeSessionToSubdirectory(opts WriteCommittedOptions, sessio
 
 	// Write context
 	if len(opts.Context) > 0 {
-		blobHash, err := CreateBlobFromContent(s.repo, redact.Bytes(opts.Context))
+		counts := redact.Counts{}
+		contextContent := redact.Default().WithCounts(counts).Bytes(opts.Context)
+		report.setFile(paths.ContextFileName, counts)
+		blobHash, err := CreateBlobFromContent(s.repo, contextContent)
 		if err != nil {
 			return filePaths, err
 		}
@@ -330,6 +409,10 @@ func (s *GitStore) writeSessionToSubdirectory(opts WriteCommittedOptions, sessio
 		filePaths.Context = "/" + sessionPath + paths.ContextFileName
 	}
 
+	if err := s.writeRedactionReport(report, sessionPath, entries); err != nil {
+		return filePaths, err
+	}
+
 	// Write session-level metadata.json (CommittedMetadata with all fields including initial_attribution)
 	sessionMetadata := CommittedMetadata{
 		CheckpointID:                opts.CheckpointID,
@@ -351,6 +434,7 @@ func (s *GitStore) writeSessionToSubdirectory(opts WriteCommittedOptions, sessio
 		Summary:                     opts.Summary,
 		CLIVersion:                  buildinfo.Version,
 		TranscriptPath:              opts.SessionTranscriptPath,
+		Annotations:                 opts.Annotations,
 	}
 
 	metadataJSON, err := jsonutil.MarshalIndentWithNewline(sessionMetadata, "", "  ")
@@ -373,7 +457,7 @@ func (s *GitStore) writeSessionToSubdirectory(opts WriteCommittedOptions, sessio
 
 // writeCheckpointSummary writes the root-level CheckpointSummary with aggregated statistics.
 // sessions is the complete sessions array (already built by the caller).
-func (s *GitStore) writeCheckpointSummary(opts WriteCommittedOptions, basePath string, entries map[string]object.TreeEntry, sessions []SessionFilePaths) error {
+func (s *GitStore) writeCheckpointSummary(opts WriteCommittedOptions, basePath string, entries map[string]object.TreeEntry, sessions []SessionFilePaths, existingSummary *CheckpointSummary) error {
 	checkpointsCount, filesTouched, tokenUsage, err :=
 		s.reaggregateFromEntries(basePath, len(sessions), entries)
 	if err != nil {
@@ -390,6 +474,10 @@ func (s *GitStore) writeCheckpointSummary(opts WriteCommittedOptions, basePath s
 		Sessions:         sessions,
 		TokenUsage:       tokenUsage,
 	}
+	// Links to rewritten commits outlive session rewrites
+	if existingSummary != nil {
+		summary.LinkedCommits = existingSummary.LinkedCommits
+	}
 
 	metadataJSON, err := jsonutil.MarshalIndentWithNewline(summary, "", "  ")
 	if err != nil {
@@ -510,26 +598,26 @@ func aggregateTokenUsage(a, b *agent.TokenUsage) *agent.TokenUsage {
 
 // writeTranscript writes the transcript file from in-memory content or file path.
 // If the transcript exceeds MaxChunkSize, it's split into multiple chunk files.
-func (s *GitStore) writeTranscript(opts WriteCommittedOptions, basePath string, entries map[string]object.TreeEntry) error {
-	transcript := opts.Transcript
-	if len(transcript) == 0 && opts.TranscriptPath != "" {
-		var readErr error
-		transcript, readErr = os.ReadFile(opts.TranscriptPath)
-		if readErr != nil {
-			// Non-fatal: transcript may not exist yet
-			transcript = nil
+func (s *GitStore) writeTranscript(opts WriteCommittedOptions, basePath string, entries map[string]object.TreeEntry, redactor *redact.Redactor) error {
+	// Redact secrets before chunking so content hash reflects redacted content
+	var transcript []byte
+	if len(opts.Transcript) > 0 {
+		redacted, err := redactor.JSONLBytes(opts.Transcript)
+		if err != nil {
+			return fmt.Errorf("failed to redact transcript secrets: %w", err)
+		}
+		transcript = redacted
+	} else if opts.TranscriptPath != "" {
+		redacted, err := redactTranscriptFile(opts.TranscriptPath, redactor)
+		if err != nil {
+			return err
 		}
+		transcript = redacted
 	}
 	if len(transcript) == 0 {
 		return nil
 	}
 
-	// Redact secrets before chunking so content hash reflects redacted content
-	transcript, err := redact.JSONLBytes(transcript)
-	if err != nil {
-		return fmt.Errorf("failed to redact transcript secrets: %w", err)
-	}
-
 	// Chunk the transcript if it's too large
 	chunks, err := agent.ChunkTranscript(transcript, opts.Agent)
 	if err != nil {
@@ -691,56 +779,80 @@ func (s *GitStore) ReadCommitted(ctx context.Context, checkpointID id.Checkpoint
 func (s *GitStore) ReadSessionContent(ctx context.Context, checkpointID id.CheckpointID, sessionIndex int) (*SessionContent, error) {
 	_ = ctx // Reserved for future use
 
+	sessionTree, err := s.getSessionTree(checkpointID, sessionIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	result := readSessionMetadataAndPrompts(sessionTree)
+
+	// Read transcript
+	if transcript, transcriptErr := readTranscriptFromTree(sessionTree, result.Metadata.Agent); transcriptErr == nil && transcript != nil {
+		result.Transcript = transcript
+	}
+
+	// Read context
+	if file, fileErr := sessionTree.File(paths.ContextFileName); fileErr == nil {
+		if content, contentErr := file.Contents(); contentErr == nil {
+			result.Context = content
+		}
+	}
+
+	return result, nil
+}
+
+// ReadSessionMetadataAndPrompts reads a session's metadata and prompts without
+// loading its transcript or context. Use this when scanning many checkpoints,
+// where reading full transcripts would be needlessly expensive.
+// Returns ErrCheckpointNotFound if the checkpoint doesn't exist.
+func (s *GitStore) ReadSessionMetadataAndPrompts(ctx context.Context, checkpointID id.CheckpointID, sessionIndex int) (*SessionContent, error) {
+	_ = ctx // Reserved for future use
+
+	sessionTree, err := s.getSessionTree(checkpointID, sessionIndex)
+	if err != nil {
+		return nil, err
+	}
+	return readSessionMetadataAndPrompts(sessionTree), nil
+}
+
+// getSessionTree returns the tree for a session subdirectory within a checkpoint.
+func (s *GitStore) getSessionTree(checkpointID id.CheckpointID, sessionIndex int) (*object.Tree, error) {
 	tree, err := s.getSessionsBranchTree()
 	if err != nil {
 		return nil, ErrCheckpointNotFound
 	}
 
-	checkpointPath := checkpointID.Path()
-	checkpointTree, err := tree.Tree(checkpointPath)
+	checkpointTree, err := tree.Tree(checkpointID.Path())
 	if err != nil {
 		return nil, ErrCheckpointNotFound
 	}
 
-	// Get the session subdirectory
-	sessionDir := strconv.Itoa(sessionIndex)
-	sessionTree, err := checkpointTree.Tree(sessionDir)
+	sessionTree, err := checkpointTree.Tree(strconv.Itoa(sessionIndex))
 	if err != nil {
 		return nil, fmt.Errorf("session %d not found: %w", sessionIndex, err)
 	}
+	return sessionTree, nil
+}
 
+// readSessionMetadataAndPrompts reads metadata.json and prompt.txt from a session tree.
+// Missing or unparseable files leave the corresponding fields empty.
+func readSessionMetadataAndPrompts(sessionTree *object.Tree) *SessionContent {
 	result := &SessionContent{}
 
-	// Read session-specific metadata
-	var agentType agent.AgentType
 	if metadataFile, fileErr := sessionTree.File(paths.MetadataFileName); fileErr == nil {
 		if content, contentErr := metadataFile.Contents(); contentErr == nil {
-			if jsonErr := json.Unmarshal([]byte(content), &result.Metadata); jsonErr == nil {
-				agentType = result.Metadata.Agent
-			}
+			//nolint:errcheck,gosec // Unparseable metadata leaves Metadata empty, matching missing-file behavior
+			json.Unmarshal([]byte(content), &result.Metadata)
 		}
 	}
 
-	// Read transcript
-	if transcript, transcriptErr := readTranscriptFromTree(sessionTree, agentType); transcriptErr == nil && transcript != nil {
-		result.Transcript = transcript
-	}
-
-	// Read prompts
 	if file, fileErr := sessionTree.File(paths.PromptFileName); fileErr == nil {
 		if content, contentErr := file.Contents(); contentErr == nil {
 			result.Prompts = content
 		}
 	}
 
-	// Read context
-	if file, fileErr := sessionTree.File(paths.ContextFileName); fileErr == nil {
-		if content, contentErr := file.Contents(); contentErr == nil {
-			result.Context = content
-		}
-	}
-
-	return result, nil
+	return result
 }
 
 // ReadLatestSessionContent is a convenience method that reads the latest session's content.
@@ -785,7 +897,7 @@ func (s *GitStore) ReadSessionContentByID(ctx context.Context, checkpointID id.C
 		}
 	}
 
-	return nil, fmt.Errorf("session %q not found in checkpoint %s", sessionID, checkpointID)
+	return nil, fmt.Errorf("%w: session %q, checkpoint %s", ErrSessionNotFound, sessionID, checkpointID)
 }
 
 // ListCommitted lists all committed checkpoints from the entire/checkpoints/v1 branch.
@@ -848,6 +960,7 @@ func (s *GitStore) ListCommitted(ctx context.Context) ([]CommittedInfo, error) {
 						info.CheckpointsCount = summary.CheckpointsCount
 						info.FilesTouched = summary.FilesTouched
 						info.SessionCount = len(summary.Sessions)
+						info.LinkedCommits = summary.LinkedCommits
 
 						// Read session metadata from latest session to get Agent, SessionID, CreatedAt
 						if len(summary.Sessions) > 0 {
@@ -861,6 +974,7 @@ func (s *GitStore) ListCommitted(ctx context.Context) ([]CommittedInfo, error) {
 											info.Agent = sessionMetadata.Agent
 											info.SessionID = sessionMetadata.SessionID
 											info.CreatedAt = sessionMetadata.CreatedAt
+											info.Annotations = sessionMetadata.Annotations
 										}
 									}
 								}
@@ -931,12 +1045,34 @@ func LookupSessionLog(cpID id.CheckpointID) ([]byte, string, error) {
 // Returns ErrCheckpointNotFound if the checkpoint doesn't exist.
 func (s *GitStore) UpdateSummary(ctx context.Context, checkpointID id.CheckpointID, summary *Summary) error {
 	_ = ctx // Reserved for future use
+	return s.updateSessionSummary(checkpointID, "", summary)
+}
+
+// UpdateSessionSummary updates the summary field in the metadata of the session
+// with the given ID. This is used when several sessions were condensed into the
+// same checkpoint and the summary belongs to one that may not be the latest.
+// Returns ErrCheckpointNotFound if the checkpoint doesn't exist.
+func (s *GitStore) UpdateSessionSummary(ctx context.Context, checkpointID id.CheckpointID, sessionID string, summary *Summary) error {
+	_ = ctx // Reserved for future use
+	return s.updateSessionSummary(checkpointID, sessionID, summary)
+}
 
+// updateSessionSummary writes summary into a session's metadata. An empty
+// sessionID selects the latest session.
+func (s *GitStore) updateSessionSummary(checkpointID id.CheckpointID, sessionID string, summary *Summary) error {
 	// Ensure sessions branch exists
 	if err := s.ensureSessionsBranch(); err != nil {
 		return fmt.Errorf("failed to ensure sessions branch: %w", err)
 	}
 
+	return s.updateSessionsBranch(func() error {
+		return s.writeSessionSummary(checkpointID, sessionID, summary)
+	})
+}
+
+// writeSessionSummary writes summary into a session's metadata on top of the
+// current sessions branch tip.
+func (s *GitStore) writeSessionSummary(checkpointID id.CheckpointID, sessionID string, summary *Summary) error {
 	// Get current branch tip and flatten tree
 	ref, entries, err := s.getSessionsBranchEntries()
 	if err != nil {
@@ -956,18 +1092,39 @@ func (s *GitStore) UpdateSummary(ctx context.Context, checkpointID id.Checkpoint
 		return fmt.Errorf("failed to read checkpoint summary: %w", err)
 	}
 
-	// Find the latest session's metadata path (0-based indexing)
-	latestIndex := len(checkpointSummary.Sessions) - 1
-	sessionMetadataPath := fmt.Sprintf("%s%d/%s", basePath, latestIndex, paths.MetadataFileName)
-	sessionEntry, exists := entries[sessionMetadataPath]
-	if !exists {
-		return fmt.Errorf("session metadata not found at %s", sessionMetadataPath)
-	}
+	var sessionMetadataPath string
+	var existingMetadata *CommittedMetadata
+	if sessionID == "" {
+		// Find the latest session's metadata path (0-based indexing)
+		latestIndex := len(checkpointSummary.Sessions) - 1
+		sessionMetadataPath = fmt.Sprintf("%s%d/%s", basePath, latestIndex, paths.MetadataFileName)
+		sessionEntry, exists := entries[sessionMetadataPath]
+		if !exists {
+			return fmt.Errorf("session metadata not found at %s", sessionMetadataPath)
+		}
 
-	// Read and update session metadata
-	existingMetadata, err := s.readMetadataFromBlob(sessionEntry.Hash)
-	if err != nil {
-		return fmt.Errorf("failed to read session metadata: %w", err)
+		// Read and update session metadata
+		existingMetadata, err = s.readMetadataFromBlob(sessionEntry.Hash)
+		if err != nil {
+			return fmt.Errorf("failed to read session metadata: %w", err)
+		}
+	} else {
+		for i := range len(checkpointSummary.Sessions) {
+			path := fmt.Sprintf("%s%d/%s", basePath, i, paths.MetadataFileName)
+			sessionEntry, exists := entries[path]
+			if !exists {
+				continue
+			}
+			metadata, readErr := s.readMetadataFromBlob(sessionEntry.Hash)
+			if readErr != nil || metadata.SessionID != sessionID {
+				continue
+			}
+			sessionMetadataPath, existingMetadata = path, metadata
+			break
+		}
+		if existingMetadata == nil {
+			return fmt.Errorf("%w: session %q, checkpoint %s", ErrSessionNotFound, sessionID, checkpointID)
+		}
 	}
 
 	// Update the summary
@@ -1001,13 +1158,167 @@ func (s *GitStore) UpdateSummary(ctx context.Context, checkpointID id.Checkpoint
 		return err
 	}
 
-	refName := plumbing.NewBranchReferenceName(paths.MetadataBranchName)
-	newRef := plumbing.NewHashReference(refName, newCommitHash)
-	if err := s.repo.Storer.SetReference(newRef); err != nil {
-		return fmt.Errorf("failed to set branch reference: %w", err)
+	return s.setSessionsBranchRef(ref, newCommitHash)
+}
+
+// LinkCommits records commits that were rewritten from this checkpoint's original
+// commits (amend, rebase, squash) and no longer carry its Entire-Checkpoint trailer.
+// Links whose SHA is already recorded are skipped. Returns the number of links added.
+//
+// Returns ErrCheckpointNotFound if the checkpoint doesn't exist.
+func (s *GitStore) LinkCommits(ctx context.Context, checkpointID id.CheckpointID, links []LinkedCommit) (int, error) {
+	_ = ctx // Reserved for future use
+
+	if err := s.ensureSessionsBranch(); err != nil {
+		return 0, fmt.Errorf("failed to ensure sessions branch: %w", err)
 	}
 
-	return nil
+	var added int
+	err := s.updateSessionsBranch(func() error {
+		var linkErr error
+		added, linkErr = s.linkCommits(checkpointID, links)
+		return linkErr
+	})
+	return added, err
+}
+
+// linkCommits records links on top of the current sessions branch tip.
+func (s *GitStore) linkCommits(checkpointID id.CheckpointID, links []LinkedCommit) (int, error) {
+	ref, entries, err := s.getSessionsBranchEntries()
+	if err != nil {
+		return 0, err
+	}
+
+	rootMetadataPath := checkpointID.Path() + "/" + paths.MetadataFileName
+	entry, exists := entries[rootMetadataPath]
+	if !exists {
+		return 0, ErrCheckpointNotFound
+	}
+
+	checkpointSummary, err := s.readSummaryFromBlob(entry.Hash)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read checkpoint summary: %w", err)
+	}
+
+	added := 0
+	for _, link := range links {
+		if checkpointSummary.HasLinkedCommit(link.SHA) {
+			continue
+		}
+		checkpointSummary.LinkedCommits = append(checkpointSummary.LinkedCommits, link)
+		added++
+	}
+	if added == 0 {
+		return 0, nil
+	}
+
+	metadataJSON, err := jsonutil.MarshalIndentWithNewline(checkpointSummary, "", "  ")
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal checkpoint summary: %w", err)
+	}
+	metadataHash, err := CreateBlobFromContent(s.repo, metadataJSON)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create metadata blob: %w", err)
+	}
+	entries[rootMetadataPath] = object.TreeEntry{
+		Name: rootMetadataPath,
+		Mode: filemode.Regular,
+		Hash: metadataHash,
+	}
+
+	newTreeHash, err := BuildTreeFromEntries(s.repo, entries)
+	if err != nil {
+		return 0, err
+	}
+
+	authorName, authorEmail := GetGitAuthorFromRepo(s.repo)
+	commitMsg := fmt.Sprintf("Link %d rewritten commit(s) to checkpoint %s", added, checkpointID)
+	newCommitHash, err := s.createCommit(newTreeHash, ref.Hash(), commitMsg, authorName, authorEmail)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := s.setSessionsBranchRef(ref, newCommitHash); err != nil {
+		return 0, err
+	}
+
+	return added, nil
+}
+
+// UpdateSessionAnnotations applies update to the annotations of every committed
+// checkpoint of the session, in a single commit on the metadata branch.
+// Returns the number of checkpoints updated; 0 if the session has none.
+func (s *GitStore) UpdateSessionAnnotations(ctx context.Context, sessionID string, update func(*session.Annotations)) (int, error) {
+	_ = ctx // Reserved for future use
+
+	var updated int
+	err := s.updateSessionsBranch(func() error {
+		var updateErr error
+		updated, updateErr = s.updateSessionAnnotations(sessionID, update)
+		return updateErr
+	})
+	return updated, err
+}
+
+// updateSessionAnnotations applies update on top of the current sessions
+// branch tip. update may be called again for the same checkpoint on retry.
+func (s *GitStore) updateSessionAnnotations(sessionID string, update func(*session.Annotations)) (int, error) {
+	ref, entries, err := s.getSessionsBranchEntries()
+	if err != nil {
+		return 0, nil //nolint:nilerr // No sessions branch means no committed checkpoints
+	}
+
+	updated := 0
+	for path, entry := range entries {
+		// Session metadata lives at <id[:2]>/<id[2:]>/<index>/metadata.json
+		if !strings.HasSuffix(path, "/"+paths.MetadataFileName) || strings.Count(path, "/") != 3 {
+			continue
+		}
+		metadata, readErr := s.readMetadataFromBlob(entry.Hash)
+		if readErr != nil || metadata.SessionID != sessionID {
+			continue
+		}
+		update(&metadata.Annotations)
+
+		metadataJSON, err := jsonutil.MarshalIndentWithNewline(metadata, "", "  ")
+		if err != nil {
+			return 0, fmt.Errorf("failed to marshal metadata: %w", err)
+		}
+		metadataHash, err := CreateBlobFromContent(s.repo, metadataJSON)
+		if err != nil {
+			return 0, fmt.Errorf("failed to create metadata blob: %w", err)
+		}
+		if metadataHash == entry.Hash {
+			continue
+		}
+		entries[path] = object.TreeEntry{
+			Name: path,
+			Mode: filemode.Regular,
+			Hash: metadataHash,
+		}
+		updated++
+	}
+	if updated == 0 {
+		return 0, nil
+	}
+
+	newTreeHash, err := BuildTreeFromEntries(s.repo, entries)
+	if err != nil {
+		return 0, err
+	}
+
+	authorName, authorEmail := GetGitAuthorFromRepo(s.repo)
+	commitMsg := fmt.Sprintf("Update annotations for session %s", sessionID)
+	newCommitHash, err := s.createCommit(newTreeHash, ref.Hash(), commitMsg, authorName, authorEmail)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := s.setSessionsBranchRef(ref, newCommitHash); err != nil {
+		return 0, err
+	}
+
+	return updated, nil
 }
 
 // UpdateCommitted replaces the transcript, prompts, and context for an existing
@@ -1028,6 +1339,14 @@ func (s *GitStore) UpdateCommitted(ctx context.Context, opts UpdateCommittedOpti
 		return fmt.Errorf("failed to ensure sessions branch: %w", err)
 	}
 
+	return s.updateSessionsBranch(func() error {
+		return s.updateCommitted(ctx, opts)
+	})
+}
+
+// updateCommitted replaces the checkpoint's content on top of the current
+// sessions branch tip.
+func (s *GitStore) updateCommitted(ctx context.Context, opts UpdateCommittedOptions) error {
 	// Get current branch tip and flatten tree
 	ref, entries, err := s.getSessionsBranchEntries()
 	if err != nil {
@@ -1073,11 +1392,14 @@ func (s *GitStore) UpdateCommitted(ctx context.Context, opts UpdateCommittedOpti
 	}
 
 	sessionPath := fmt.Sprintf("%s%d/", basePath, sessionIndex)
+	report := s.readRedactionReportEntry(sessionPath, entries)
 
 	// Replace transcript (full replace, not append)
 	// Apply redaction as safety net (caller should redact, but we ensure it here)
 	if len(opts.Transcript) > 0 {
-		transcript, err := redact.JSONLBytes(opts.Transcript)
+		counts := redact.Counts{}
+		transcript, err := redact.Default().WithCounts(counts).JSONLBytes(opts.Transcript)
+		report.setFile(paths.TranscriptFileName, counts)
 		if err != nil {
 			return fmt.Errorf("failed to redact transcript secrets: %w", err)
 		}
@@ -1088,7 +1410,9 @@ func (s *GitStore) UpdateCommitted(ctx context.Context, opts UpdateCommittedOpti
 
 	// Replace prompts (apply redaction as safety net)
 	if len(opts.Prompts) > 0 {
-		promptContent := redact.String(strings.Join(opts.Prompts, "\n\n---\n\n"))
+		counts := redact.Counts{}
+		promptContent := redact.Default().WithCounts(counts).String(strings.Join(opts.Prompts, "\n\n---\n\n"))
+		report.setFile(paths.PromptFileName, counts)
 		blobHash, err := CreateBlobFromContent(s.repo, []byte(promptContent))
 		if err != nil {
 			return fmt.Errorf("failed to create prompt blob: %w", err)
@@ -1102,7 +1426,10 @@ func (s *GitStore) UpdateCommitted(ctx context.Context, opts UpdateCommittedOpti
 
 	// Replace context (apply redaction as safety net)
 	if len(opts.Context) > 0 {
-		contextBlob, err := CreateBlobFromContent(s.repo, redact.Bytes(opts.Context))
+		counts := redact.Counts{}
+		contextContent := redact.Default().WithCounts(counts).Bytes(opts.Context)
+		report.setFile(paths.ContextFileName, counts)
+		contextBlob, err := CreateBlobFromContent(s.repo, contextContent)
 		if err != nil {
 			return fmt.Errorf("failed to create context blob: %w", err)
 		}
@@ -1113,6 +1440,10 @@ func (s *GitStore) UpdateCommitted(ctx context.Context, opts UpdateCommittedOpti
 		}
 	}
 
+	if err := s.writeRedactionReport(report, sessionPath, entries); err != nil {
+		return err
+	}
+
 	// Build and commit
 	newTreeHash, err := BuildTreeFromEntries(s.repo, entries)
 	if err != nil {
@@ -1126,13 +1457,7 @@ func (s *GitStore) UpdateCommitted(ctx context.Context, opts UpdateCommittedOpti
 		return err
 	}
 
-	refName := plumbing.NewBranchReferenceName(paths.MetadataBranchName)
-	newRef := plumbing.NewHashReference(refName, newCommitHash)
-	if err := s.repo.Storer.SetReference(newRef); err != nil {
-		return fmt.Errorf("failed to set branch reference: %w", err)
-	}
-
-	return nil
+	return s.setSessionsBranchRef(ref, newCommitHash)
 }
 
 // replaceTranscript writes the full transcript content, replacing any existing transcript.
@@ -1185,11 +1510,21 @@ func (s *GitStore) replaceTranscript(transcript []byte, agentType agent.AgentTyp
 // ensureSessionsBranch ensures the entire/checkpoints/v1 branch exists.
 func (s *GitStore) ensureSessionsBranch() error {
 	refName := plumbing.NewBranchReferenceName(paths.MetadataBranchName)
-	_, err := s.repo.Reference(refName, true)
-	if err == nil {
+	if _, err := s.repo.Reference(refName, true); err == nil {
 		return nil // Branch exists
 	}
 
+	// Check again under the lock, so a branch another writer is updating
+	// isn't mistaken for a missing one and replaced
+	unlock, err := s.lockSessionsBranch()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	if _, err := s.repo.Reference(refName, true); err == nil {
+		return nil
+	}
+
 	// Create orphan branch with empty tree
 	emptyTreeHash, err := BuildTreeFromEntries(s.repo, make(map[string]object.TreeEntry))
 	if err != nil {
@@ -1333,24 +1668,9 @@ func createRedactedBlobFromFile(repo *git.Repository, filePath, treePath string)
 		return plumbing.ZeroHash, 0, fmt.Errorf("failed to read file: %w", err)
 	}
 
-	// Skip redaction for binary files — they can't contain text secrets and
-	// running string replacement on them would corrupt the data.
-	isBin, binErr := binary.IsBinary(bytes.NewReader(content))
-	if binErr != nil || isBin {
-		hash, err := CreateBlobFromContent(repo, content)
-		if err != nil {
-			return plumbing.ZeroHash, 0, fmt.Errorf("failed to create blob: %w", err)
-		}
-		return hash, mode, nil
-	}
-
-	if strings.HasSuffix(treePath, ".jsonl") {
-		content, err = redact.JSONLBytes(content)
-		if err != nil {
-			return plumbing.ZeroHash, 0, fmt.Errorf("failed to redact secrets: %w", err)
-		}
-	} else {
-		content = redact.Bytes(content)
+	content, err = RedactFileContent(redact.Default(), treePath, content)
+	if err != nil {
+		return plumbing.ZeroHash, 0, err
 	}
 
 	hash, err := CreateBlobFromContent(repo, content)
@@ -1360,6 +1680,49 @@ func createRedactedBlobFromFile(repo *git.Repository, filePath, treePath string)
 	return hash, mode, nil
 }
 
+// redactTranscriptFile streams a transcript file through the redactor, so the
+// raw transcript is never held in memory alongside the redacted one.
+// A missing file yields no transcript.
+func redactTranscriptFile(path string, redactor *redact.Redactor) ([]byte, error) {
+	f, err := os.Open(path) //nolint:gosec // Path comes from the agent's session
+	if err != nil {
+		// Non-fatal: transcript may not exist yet
+		return nil, nil //nolint:nilerr // A missing transcript is expected
+	}
+	defer f.Close()
+
+	var buf bytes.Buffer
+	if info, statErr := f.Stat(); statErr == nil {
+		buf.Grow(int(info.Size()))
+	}
+	opts := redact.StreamOptions{Workers: runtime.GOMAXPROCS(0)}
+	if err := redactor.JSONLStream(&buf, f, opts); err != nil {
+		return nil, fmt.Errorf("failed to redact transcript secrets: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// RedactFileContent redacts a metadata file's content. JSONL files, including
+// transcript chunks, get JSONL-aware redaction; other text files get plain
+// string redaction. Binary files are returned unchanged — they can't contain
+// text secrets and running string replacement on them would corrupt the data.
+func RedactFileContent(redactor *redact.Redactor, treePath string, content []byte) ([]byte, error) {
+	isBin, binErr := binary.IsBinary(bytes.NewReader(content))
+	if binErr != nil || isBin {
+		return content, nil
+	}
+
+	name := filepath.Base(treePath)
+	if strings.HasSuffix(name, ".jsonl") || agent.ParseChunkIndex(name, paths.TranscriptFileName) > 0 {
+		redacted, err := redactor.JSONLBytes(content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to redact secrets: %w", err)
+		}
+		return redacted, nil
+	}
+	return redactor.Bytes(content), nil
+}
+
 // GetGitAuthorFromRepo retrieves the git user.name and user.email,
 // checking both the repository-local config and the global ~/.gitconfig.
 func GetGitAuthorFromRepo(repo *git.Repository) (name, email string) {
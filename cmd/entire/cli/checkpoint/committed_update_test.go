@@ -3,13 +3,16 @@ package checkpoint
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 
 	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
 	"github.com/entireio/cli/cmd/entire/cli/paths"
 
+	"github.com/go-git/go-billy/v5"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
@@ -611,3 +614,86 @@ func TestGetGitAuthorFromRepo_NoConfig(t *testing.T) {
 
 // Verify go-git config import is used (compile-time check).
 var _ = config.GlobalScope
+
+func TestCommittedWriters_ConcurrentUpdatesAreNotLost(t *testing.T) {
+	t.Parallel()
+	repo, _, cpID := setupRepoForUpdate(t)
+	repoDir := filepath.Dir(repo.Storer.(interface{ Filesystem() billy.Filesystem }).Filesystem().Root())
+
+	// Each writer opens its own repository, like hooks and the summary worker
+	// running in separate processes.
+	const writers, checkpointsPerWriter = 4, 5
+	var cpIDs []id.CheckpointID
+	errs := make(chan error, writers+1)
+	var wg sync.WaitGroup
+	for w := range writers {
+		ids := make([]id.CheckpointID, checkpointsPerWriter)
+		for i := range ids {
+			ids[i] = id.MustCheckpointID(fmt.Sprintf("b1b2c3d4e%d%02d", w, i))
+		}
+		cpIDs = append(cpIDs, ids...)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			writerRepo, err := git.PlainOpen(repoDir)
+			if err != nil {
+				errs <- err
+				return
+			}
+			store := NewGitStore(writerRepo)
+			for _, cpID := range ids {
+				if err := store.WriteCommitted(context.Background(), WriteCommittedOptions{
+					CheckpointID: cpID,
+					SessionID:    fmt.Sprintf("session-%d", w),
+					Strategy:     "manual-commit",
+					Transcript:   []byte("line\n"),
+					AuthorName:   "Test",
+					AuthorEmail:  "test@test.com",
+				}); err != nil {
+					errs <- err
+					return
+				}
+			}
+			errs <- nil
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		writerRepo, err := git.PlainOpen(repoDir)
+		if err != nil {
+			errs <- err
+			return
+		}
+		store := NewGitStore(writerRepo)
+		for i := range checkpointsPerWriter {
+			if err := store.UpdateSummary(context.Background(), cpID, &Summary{Intent: fmt.Sprintf("summary %d", i)}); err != nil {
+				errs <- err
+				return
+			}
+		}
+		errs <- nil
+	}()
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("concurrent write error = %v", err)
+		}
+	}
+
+	store := NewGitStore(repo)
+	for _, written := range cpIDs {
+		if _, err := store.ReadCommitted(context.Background(), written); err != nil {
+			t.Errorf("checkpoint %s lost: %v", written, err)
+		}
+	}
+	content, err := store.ReadLatestSessionContent(context.Background(), cpID)
+	if err != nil {
+		t.Fatalf("ReadLatestSessionContent() error = %v", err)
+	}
+	want := fmt.Sprintf("summary %d", checkpointsPerWriter-1)
+	if content.Metadata.Summary == nil || content.Metadata.Summary.Intent != want {
+		t.Errorf("summary = %+v, want the last concurrent update kept", content.Metadata.Summary)
+	}
+}
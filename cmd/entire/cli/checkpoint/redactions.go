@@ -0,0 +1,114 @@
+package checkpoint
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/jsonutil"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/redact"
+
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// RedactionReport is the redactions.json written next to a session's files.
+// It records how many values each rule redacted when the session was written,
+// never the values themselves.
+type RedactionReport struct {
+	// Total is the number of redactions across all files.
+	Total int `json:"total"`
+
+	// Rules counts redactions by rule ID across all files.
+	Rules map[string]int `json:"rules"`
+
+	// Files counts redactions by rule ID for each file, keyed by file name.
+	Files map[string]map[string]int `json:"files"`
+}
+
+// setFile replaces the counts recorded for a file and recomputes the totals.
+func (r *RedactionReport) setFile(name string, counts redact.Counts) {
+	if r.Files == nil {
+		r.Files = make(map[string]map[string]int)
+	}
+	if counts.Total() == 0 {
+		delete(r.Files, name)
+	} else {
+		r.Files[name] = counts
+	}
+
+	r.Total = 0
+	r.Rules = make(map[string]int)
+	for _, fileCounts := range r.Files {
+		for rule, n := range fileCounts {
+			r.Rules[rule] += n
+			r.Total += n
+		}
+	}
+}
+
+// writeRedactionReport stores the report in the session directory, or removes
+// it when nothing was redacted.
+func (s *GitStore) writeRedactionReport(report *RedactionReport, sessionPath string, entries map[string]object.TreeEntry) error {
+	reportPath := sessionPath + paths.RedactionsFileName
+	if report.Total == 0 {
+		delete(entries, reportPath)
+		return nil
+	}
+
+	data, err := jsonutil.MarshalIndentWithNewline(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal redaction report: %w", err)
+	}
+	blobHash, err := CreateBlobFromContent(s.repo, data)
+	if err != nil {
+		return err
+	}
+	entries[reportPath] = object.TreeEntry{
+		Name: reportPath,
+		Mode: filemode.Regular,
+		Hash: blobHash,
+	}
+	return nil
+}
+
+// readRedactionReportEntry reads an existing redactions.json from the flattened
+// tree entries. A missing or unreadable report yields an empty one.
+func (s *GitStore) readRedactionReportEntry(sessionPath string, entries map[string]object.TreeEntry) *RedactionReport {
+	entry, ok := entries[sessionPath+paths.RedactionsFileName]
+	if !ok {
+		return &RedactionReport{}
+	}
+	report, err := readJSONFromBlob[RedactionReport](s.repo, entry.Hash)
+	if err != nil {
+		return &RedactionReport{}
+	}
+	return report
+}
+
+// ReadRedactionReport reads the redactions.json of a session.
+// Returns nil without an error if nothing was redacted when the session was
+// written, or if it was written before redaction reports existed.
+func (s *GitStore) ReadRedactionReport(ctx context.Context, checkpointID id.CheckpointID, sessionIndex int) (*RedactionReport, error) {
+	_ = ctx // Reserved for future use
+
+	sessionTree, err := s.getSessionTree(checkpointID, sessionIndex)
+	if err != nil {
+		return nil, err
+	}
+	file, err := sessionTree.File(paths.RedactionsFileName)
+	if err != nil {
+		return nil, nil //nolint:nilnil // A missing report means nothing was redacted
+	}
+	content, err := file.Contents()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read redaction report: %w", err)
+	}
+	var report RedactionReport
+	if err := json.Unmarshal([]byte(content), &report); err != nil {
+		return nil, fmt.Errorf("failed to parse redaction report: %w", err)
+	}
+	return &report, nil
+}
@@ -0,0 +1,104 @@
+package checkpoint
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/redact"
+)
+
+// testSecret has enough entropy to be redacted by the default rules.
+const testSecret = "sk-ant-REDACTED"
+
+func TestWriteCommitted_RedactionReport(t *testing.T) {
+	t.Parallel()
+	_, store, cpID := setupRepoForUpdate(t)
+
+	// The setup checkpoint has nothing to redact, so it has no report
+	report, err := store.ReadRedactionReport(context.Background(), cpID, 0)
+	if err != nil {
+		t.Fatalf("ReadRedactionReport() error = %v", err)
+	}
+	if report != nil {
+		t.Errorf("expected no report for a checkpoint without redactions, got %+v", report)
+	}
+
+	err = store.WriteCommitted(context.Background(), WriteCommittedOptions{
+		CheckpointID: cpID,
+		SessionID:    "session-002",
+		Strategy:     "manual-commit",
+		Transcript:   []byte(`{"type":"user","message":"use ` + testSecret + `"}` + "\n"),
+		Prompts:      []string{"key is " + testSecret, "again " + testSecret},
+		Context:      []byte("no secrets here"),
+		AuthorName:   "Test",
+		AuthorEmail:  "test@test.com",
+	})
+	if err != nil {
+		t.Fatalf("WriteCommitted() error = %v", err)
+	}
+
+	report, err = store.ReadRedactionReport(context.Background(), cpID, 1)
+	if err != nil {
+		t.Fatalf("ReadRedactionReport() error = %v", err)
+	}
+	if report == nil {
+		t.Fatal("expected a redaction report")
+	}
+	if report.Total != 3 || report.Rules[redact.EntropyRuleID] != 3 {
+		t.Errorf("report totals = %d %v, want 3 entropy redactions", report.Total, report.Rules)
+	}
+	if report.Files[paths.TranscriptFileName][redact.EntropyRuleID] != 1 || report.Files[paths.PromptFileName][redact.EntropyRuleID] != 2 {
+		t.Errorf("report files = %v", report.Files)
+	}
+	if _, ok := report.Files[paths.ContextFileName]; ok {
+		t.Errorf("context had nothing redacted but is in the report: %v", report.Files)
+	}
+
+	content, err := store.ReadSessionContent(context.Background(), cpID, 1)
+	if err != nil {
+		t.Fatalf("ReadSessionContent() error = %v", err)
+	}
+	if strings.Contains(content.Prompts, testSecret) {
+		t.Error("prompt secret was not redacted")
+	}
+}
+
+func TestUpdateCommitted_RedactionReport(t *testing.T) {
+	t.Parallel()
+	_, store, cpID := setupRepoForUpdate(t)
+
+	err := store.UpdateCommitted(context.Background(), UpdateCommittedOptions{
+		CheckpointID: cpID,
+		SessionID:    "session-001",
+		Context:      []byte("token " + testSecret),
+	})
+	if err != nil {
+		t.Fatalf("UpdateCommitted() error = %v", err)
+	}
+	report, err := store.ReadRedactionReport(context.Background(), cpID, 0)
+	if err != nil || report == nil {
+		t.Fatalf("ReadRedactionReport() = %v, %v", report, err)
+	}
+	if report.Total != 1 || report.Files[paths.ContextFileName][redact.EntropyRuleID] != 1 {
+		t.Errorf("report = %+v", report)
+	}
+
+	// Replacing the transcript keeps the context counts
+	err = store.UpdateCommitted(context.Background(), UpdateCommittedOptions{
+		CheckpointID: cpID,
+		SessionID:    "session-001",
+		Transcript:   []byte(`{"message":"` + testSecret + `"}` + "\n"),
+	})
+	if err != nil {
+		t.Fatalf("UpdateCommitted() error = %v", err)
+	}
+	report, err = store.ReadRedactionReport(context.Background(), cpID, 0)
+	if err != nil || report == nil {
+		t.Fatalf("ReadRedactionReport() = %v, %v", report, err)
+	}
+	if report.Total != 2 || len(report.Files) != 2 {
+		t.Errorf("report = %+v, want context and transcript counts", report)
+	}
+}
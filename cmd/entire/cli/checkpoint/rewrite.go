@@ -0,0 +1,362 @@
+package checkpoint
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/entireio/cli/cmd/entire/cli/agent"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage"
+)
+
+// RewriteFunc returns the rewritten content of the blob at treePath, or the
+// content unchanged to keep the blob. Commit messages are passed with an
+// empty treePath.
+type RewriteFunc func(treePath string, content []byte) ([]byte, error)
+
+// RewriteResult describes the rewrite of one branch.
+type RewriteResult struct {
+	Branch  string
+	OldHash plumbing.Hash
+	NewHash plumbing.Hash
+
+	// Commits is the number of commits whose hash changed.
+	Commits int
+
+	// Blobs is the number of distinct blobs whose content changed.
+	Blobs int
+}
+
+// Changed reports whether the branch history was rewritten.
+func (r *RewriteResult) Changed() bool {
+	return r.OldHash != r.NewHash
+}
+
+// RewriteBranch rewrites every commit reachable from a local branch, passing
+// each blob and commit message through rewrite and rebuilding the trees and
+// commits above them. When a transcript changes, the directory's
+// content_hash.txt is recomputed from the rewritten transcript.
+//
+// Author, committer, and dates are kept. Commit signatures are dropped, since
+// they no longer match. With dryRun, nothing is written to the repository and
+// the branch isn't moved, but the result reports what would change.
+func RewriteBranch(repo *git.Repository, branch string, rewrite RewriteFunc, dryRun bool) (*RewriteResult, error) {
+	refName := plumbing.NewBranchReferenceName(branch)
+	ref, err := repo.Reference(refName, true)
+	if err != nil {
+		return nil, fmt.Errorf("branch %s not found: %w", branch, err)
+	}
+
+	rw := &historyRewriter{
+		repo:    repo,
+		rewrite: rewrite,
+		dryRun:  dryRun,
+		blobs:   make(map[string]plumbing.Hash),
+		trees:   make(map[string]plumbing.Hash),
+		commits: make(map[plumbing.Hash]plumbing.Hash),
+		changed: make(map[plumbing.Hash]bool),
+	}
+
+	newHash, err := rw.rewriteHistory(ref.Hash())
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RewriteResult{Branch: branch, OldHash: ref.Hash(), NewHash: newHash, Blobs: len(rw.changed)}
+	for oldHash, newHash := range rw.commits {
+		if oldHash != newHash {
+			result.Commits++
+		}
+	}
+
+	if !dryRun && result.Changed() {
+		// Fail rather than drop commits written to the branch during the rewrite
+		if err := repo.Storer.CheckAndSetReference(plumbing.NewHashReference(refName, newHash), ref); err != nil {
+			if errors.Is(err, storage.ErrReferenceHasChanged) {
+				return nil, fmt.Errorf("branch %s changed during the rewrite, run it again: %w", branch, err)
+			}
+			return nil, fmt.Errorf("failed to update branch %s: %w", branch, err)
+		}
+	}
+	return result, nil
+}
+
+// historyRewriter holds the state of a RewriteBranch call. Results are
+// memoized by object hash and path, since most commits share most of their tree.
+type historyRewriter struct {
+	repo    *git.Repository
+	rewrite RewriteFunc
+	dryRun  bool
+
+	blobs   map[string]plumbing.Hash
+	trees   map[string]plumbing.Hash
+	commits map[plumbing.Hash]plumbing.Hash
+
+	// changed records the original hashes of blobs whose content changed.
+	changed map[plumbing.Hash]bool
+}
+
+// rewriteHistory rewrites the commits reachable from tip, parents first.
+func (rw *historyRewriter) rewriteHistory(tip plumbing.Hash) (plumbing.Hash, error) {
+	// Iterative post-order walk: deep histories would overflow a recursive one
+	type frame struct {
+		hash     plumbing.Hash
+		expanded bool
+	}
+	stack := []frame{{hash: tip}}
+	for len(stack) > 0 {
+		top := &stack[len(stack)-1]
+		if _, done := rw.commits[top.hash]; done {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		commit, err := rw.repo.CommitObject(top.hash)
+		if err != nil {
+			return plumbing.ZeroHash, fmt.Errorf("failed to read commit %s: %w", top.hash, err)
+		}
+		if !top.expanded {
+			top.expanded = true
+			for _, parent := range commit.ParentHashes {
+				if _, done := rw.commits[parent]; !done {
+					stack = append(stack, frame{hash: parent})
+				}
+			}
+			continue
+		}
+		stack = stack[:len(stack)-1]
+
+		newHash, err := rw.rewriteCommit(commit)
+		if err != nil {
+			return plumbing.ZeroHash, err
+		}
+		rw.commits[commit.Hash] = newHash
+	}
+	return rw.commits[tip], nil
+}
+
+func (rw *historyRewriter) rewriteCommit(commit *object.Commit) (plumbing.Hash, error) {
+	treeHash, err := rw.rewriteTree(commit.TreeHash, "")
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	message, err := rw.rewrite("", []byte(commit.Message))
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to rewrite message of commit %s: %w", commit.Hash, err)
+	}
+
+	parents := make([]plumbing.Hash, len(commit.ParentHashes))
+	changed := treeHash != commit.TreeHash || string(message) != commit.Message
+	for i, parent := range commit.ParentHashes {
+		parents[i] = rw.commits[parent]
+		changed = changed || parents[i] != parent
+	}
+	if !changed {
+		return commit.Hash, nil
+	}
+
+	newCommit := &object.Commit{
+		Author:       commit.Author,
+		Committer:    commit.Committer,
+		Message:      string(message),
+		TreeHash:     treeHash,
+		ParentHashes: parents,
+	}
+	obj := rw.repo.Storer.NewEncodedObject()
+	if err := newCommit.Encode(obj); err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to encode commit: %w", err)
+	}
+	return rw.store(obj)
+}
+
+// rewriteTree rewrites the tree at dir ("" for the root, otherwise ending in "/").
+func (rw *historyRewriter) rewriteTree(hash plumbing.Hash, dir string) (plumbing.Hash, error) {
+	key := hash.String() + ":" + dir
+	if newHash, ok := rw.trees[key]; ok {
+		return newHash, nil
+	}
+
+	tree, err := rw.repo.TreeObject(hash)
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to read tree %s: %w", dir, err)
+	}
+
+	entries := make([]object.TreeEntry, len(tree.Entries))
+	changed := false
+	transcriptChanged := false
+	for i, entry := range tree.Entries {
+		entries[i] = entry
+		switch entry.Mode {
+		case filemode.Dir:
+			entries[i].Hash, err = rw.rewriteTree(entry.Hash, dir+entry.Name+"/")
+		case filemode.Regular, filemode.Executable:
+			if entry.Name == paths.ContentHashFileName {
+				continue // Recomputed below
+			}
+			entries[i].Hash, err = rw.rewriteBlob(entry.Hash, dir+entry.Name)
+			if entries[i].Hash != entry.Hash && agent.ParseChunkIndex(entry.Name, paths.TranscriptFileName) >= 0 {
+				transcriptChanged = true
+			}
+		default:
+			continue // Symlinks and submodules are kept as-is
+		}
+		if err != nil {
+			return plumbing.ZeroHash, err
+		}
+		changed = changed || entries[i].Hash != entry.Hash
+	}
+
+	newHash := hash
+	if changed {
+		newHash, err = rw.storeTree(entries)
+		if err != nil {
+			return plumbing.ZeroHash, err
+		}
+		// The transcript is reassembled from the stored tree, so a dry run
+		// leaves the old content hash
+		if transcriptChanged && !rw.dryRun {
+			newHash, err = rw.updateContentHash(newHash, entries)
+			if err != nil {
+				return plumbing.ZeroHash, err
+			}
+		}
+	}
+	rw.trees[key] = newHash
+	return newHash, nil
+}
+
+func (rw *historyRewriter) rewriteBlob(hash plumbing.Hash, treePath string) (plumbing.Hash, error) {
+	key := hash.String() + ":" + treePath
+	if newHash, ok := rw.blobs[key]; ok {
+		return newHash, nil
+	}
+
+	content, err := rw.readBlob(hash)
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to read %s: %w", treePath, err)
+	}
+	rewritten, err := rw.rewrite(treePath, content)
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to rewrite %s: %w", treePath, err)
+	}
+
+	newHash := hash
+	if string(rewritten) != string(content) {
+		obj := rw.repo.Storer.NewEncodedObject()
+		obj.SetType(plumbing.BlobObject)
+		w, err := obj.Writer()
+		if err != nil {
+			return plumbing.ZeroHash, fmt.Errorf("failed to create blob writer: %w", err)
+		}
+		if _, err := w.Write(rewritten); err != nil {
+			return plumbing.ZeroHash, fmt.Errorf("failed to write blob: %w", err)
+		}
+		if err := w.Close(); err != nil {
+			return plumbing.ZeroHash, fmt.Errorf("failed to close blob writer: %w", err)
+		}
+		newHash, err = rw.store(obj)
+		if err != nil {
+			return plumbing.ZeroHash, err
+		}
+		rw.changed[hash] = true
+	}
+	rw.blobs[key] = newHash
+	return newHash, nil
+}
+
+// updateContentHash recomputes content_hash.txt from the rewritten transcript
+// in the tree, the way writeTranscript computes it when a checkpoint is
+// written. Returns the hash of the updated tree.
+func (rw *historyRewriter) updateContentHash(treeHash plumbing.Hash, entries []object.TreeEntry) (plumbing.Hash, error) {
+	hashIndex := -1
+	var agentType agent.AgentType
+	for i, entry := range entries {
+		switch entry.Name {
+		case paths.ContentHashFileName:
+			hashIndex = i
+		case paths.MetadataFileName:
+			if data, err := rw.readBlob(entry.Hash); err == nil {
+				var meta CommittedMetadata
+				if json.Unmarshal(data, &meta) == nil {
+					agentType = meta.Agent
+				}
+			}
+		}
+	}
+	if hashIndex < 0 {
+		return treeHash, nil
+	}
+
+	tree, err := rw.repo.TreeObject(treeHash)
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to read rewritten tree: %w", err)
+	}
+	transcript, err := readTranscriptFromTree(tree, agentType)
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to read rewritten transcript: %w", err)
+	}
+	contentHash := fmt.Sprintf("sha256:%x", sha256.Sum256(transcript))
+	blobHash, err := CreateBlobFromContent(rw.repo, []byte(contentHash))
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	entries[hashIndex].Hash = blobHash
+	return rw.storeTree(entries)
+}
+
+func (rw *historyRewriter) storeTree(entries []object.TreeEntry) (plumbing.Hash, error) {
+	// Git requires tree entries in name order (directories sort as "name/")
+	sort.Slice(entries, func(i, j int) bool {
+		return treeSortName(entries[i]) < treeSortName(entries[j])
+	})
+	tree := &object.Tree{Entries: entries}
+	obj := rw.repo.Storer.NewEncodedObject()
+	if err := tree.Encode(obj); err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to encode tree: %w", err)
+	}
+	return rw.store(obj)
+}
+
+func treeSortName(entry object.TreeEntry) string {
+	if entry.Mode == filemode.Dir {
+		return entry.Name + "/"
+	}
+	return entry.Name
+}
+
+// store writes obj to the repository, or only computes its hash in a dry run.
+func (rw *historyRewriter) store(obj plumbing.EncodedObject) (plumbing.Hash, error) {
+	if rw.dryRun {
+		return obj.Hash(), nil
+	}
+	hash, err := rw.repo.Storer.SetEncodedObject(obj)
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to store object: %w", err)
+	}
+	return hash, nil
+}
+
+func (rw *historyRewriter) readBlob(hash plumbing.Hash) ([]byte, error) {
+	blob, err := rw.repo.BlobObject(hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read blob: %w", err)
+	}
+	reader, err := blob.Reader()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open blob: %w", err)
+	}
+	defer reader.Close()
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read blob: %w", err)
+	}
+	return data, nil
+}
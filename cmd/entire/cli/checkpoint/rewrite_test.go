@@ -0,0 +1,131 @@
+package checkpoint
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+func replaceTicket(_ string, content []byte) ([]byte, error) {
+	return bytes.ReplaceAll(content, []byte("TICKET-123"), []byte("REDACTED")), nil
+}
+
+func TestRewriteBranch(t *testing.T) {
+	t.Parallel()
+	repo, store, cpID := setupRepoForUpdate(t)
+
+	// A second checkpoint carries the value; the first one doesn't
+	otherID := id.MustCheckpointID("b1b2c3d4e5f6")
+	err := store.WriteCommitted(context.Background(), WriteCommittedOptions{
+		CheckpointID: otherID,
+		SessionID:    "session-002",
+		Strategy:     "manual-commit",
+		Transcript:   []byte(`{"type":"user","message":"close TICKET-123"}` + "\n"),
+		Prompts:      []string{"close TICKET-123"},
+		AuthorName:   "Test",
+		AuthorEmail:  "test@test.com",
+	})
+	if err != nil {
+		t.Fatalf("WriteCommitted() error = %v", err)
+	}
+
+	refName := plumbing.NewBranchReferenceName(paths.MetadataBranchName)
+	before, err := repo.Reference(refName, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Dry run reports the change without moving the branch
+	result, err := RewriteBranch(repo, paths.MetadataBranchName, replaceTicket, true)
+	if err != nil {
+		t.Fatalf("RewriteBranch(dry run) error = %v", err)
+	}
+	if !result.Changed() || result.Commits != 1 || result.Blobs != 2 {
+		t.Errorf("dry run result = %+v, want 1 commit and 2 blobs", result)
+	}
+	if ref, _ := repo.Reference(refName, true); ref.Hash() != before.Hash() {
+		t.Error("dry run moved the branch")
+	}
+
+	result, err = RewriteBranch(repo, paths.MetadataBranchName, replaceTicket, false)
+	if err != nil {
+		t.Fatalf("RewriteBranch() error = %v", err)
+	}
+	after, err := repo.Reference(refName, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after.Hash() != result.NewHash || after.Hash() == before.Hash() {
+		t.Fatalf("branch not moved to the rewritten commit")
+	}
+
+	content, err := store.ReadSessionContent(context.Background(), otherID, 0)
+	if err != nil {
+		t.Fatalf("ReadSessionContent() error = %v", err)
+	}
+	if strings.Contains(string(content.Transcript), "TICKET-123") || strings.Contains(content.Prompts, "TICKET-123") {
+		t.Errorf("value not rewritten: transcript %q, prompts %q", content.Transcript, content.Prompts)
+	}
+
+	// content_hash.txt matches the rewritten transcript
+	commit, err := repo.CommitObject(after.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	hashFile, err := tree.File(otherID.Path() + "/0/" + paths.ContentHashFileName)
+	if err != nil {
+		t.Fatalf("content hash missing: %v", err)
+	}
+	gotHash, _ := hashFile.Contents()
+	if want := fmt.Sprintf("sha256:%x", sha256.Sum256(content.Transcript)); gotHash != want {
+		t.Errorf("content hash = %s, want %s", gotHash, want)
+	}
+
+	// The unchanged parent commit keeps its hash and the author is preserved
+	oldCommit, err := repo.CommitObject(before.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commit.ParentHashes) != 1 || commit.ParentHashes[0] != oldCommit.ParentHashes[0] {
+		t.Errorf("parents = %v, want the original parent %v", commit.ParentHashes, oldCommit.ParentHashes)
+	}
+	if commit.Author.Name != oldCommit.Author.Name || !commit.Author.When.Equal(oldCommit.Author.When) {
+		t.Errorf("author changed: %v -> %v", oldCommit.Author, commit.Author)
+	}
+
+	// The first checkpoint is untouched
+	first, err := store.ReadSessionContent(context.Background(), cpID, 0)
+	if err != nil || first.Prompts != "initial prompt" {
+		t.Errorf("first checkpoint changed: %+v, %v", first, err)
+	}
+
+	// Rewriting again finds nothing
+	result, err = RewriteBranch(repo, paths.MetadataBranchName, replaceTicket, false)
+	if err != nil {
+		t.Fatalf("RewriteBranch() error = %v", err)
+	}
+	if result.Changed() {
+		t.Errorf("second rewrite changed the branch: %+v", result)
+	}
+}
+
+func TestRewriteBranch_MissingBranch(t *testing.T) {
+	t.Parallel()
+	repo, _, _ := setupRepoForUpdate(t)
+
+	if _, err := RewriteBranch(repo, "entire/does-not-exist", replaceTicket, true); err == nil {
+		t.Error("expected error for a missing branch")
+	}
+}
@@ -0,0 +1,295 @@
+package checkpoint
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/redact"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// DefaultSnapshotExclude are the globs of files that commonly hold secrets.
+var DefaultSnapshotExclude = []string{
+	".env",
+	".env.*",
+	"*.pem",
+	"*.key",
+	"*.p12",
+	"*.pfx",
+	"*.keystore",
+	"id_rsa",
+	"id_ecdsa",
+	"id_ed25519",
+	".netrc",
+	".pgpass",
+}
+
+// DefaultSnapshotInclude are the globs of files that match DefaultSnapshotExclude
+// but are conventionally committed templates without real values.
+var DefaultSnapshotInclude = []string{
+	".env.example",
+	".env.sample",
+	".env.template",
+}
+
+// SnapshotAction is how a working tree file is stored in a shadow branch snapshot.
+type SnapshotAction int
+
+const (
+	// SnapshotKeep stores the file verbatim.
+	SnapshotKeep SnapshotAction = iota
+
+	// SnapshotRedact stores the file with secrets redacted.
+	SnapshotRedact
+
+	// SnapshotSkip leaves the file out of the snapshot.
+	SnapshotSkip
+)
+
+// SnapshotPolicy decides which files shadow branch snapshots redact or skip.
+//
+// A file is a secret file if it matches an Exclude glob and no Include glob.
+// Gitignored secret files are skipped, so an agent-created .env never reaches a
+// shadow branch. Other secret files and other gitignored files are redacted.
+// Everything else is the user's code and is stored verbatim, so rewinding
+// restores it exactly.
+//
+// Globs use path.Match syntax. A glob without a slash matches the file name in
+// any directory; a glob with a slash matches the path from the repository root.
+type SnapshotPolicy struct {
+	Exclude []string
+	Include []string
+}
+
+// NewSnapshotPolicy returns the default policy extended with extra exclude and
+// include globs. Include globs take precedence, so they can also re-enable a
+// default exclusion.
+func NewSnapshotPolicy(exclude, include []string) (*SnapshotPolicy, error) {
+	for _, glob := range slices.Concat(exclude, include) {
+		if _, err := path.Match(glob, ""); err != nil {
+			return nil, fmt.Errorf("invalid snapshot glob %q: %w", glob, err)
+		}
+	}
+	return &SnapshotPolicy{
+		Exclude: slices.Concat(DefaultSnapshotExclude, exclude),
+		Include: slices.Concat(DefaultSnapshotInclude, include),
+	}, nil
+}
+
+// IsSecretFile reports whether the repo-relative path is a secret file.
+func (p *SnapshotPolicy) IsSecretFile(file string) bool {
+	return matchesAnyGlob(p.Exclude, file) && !matchesAnyGlob(p.Include, file)
+}
+
+// Action returns how to snapshot the file, given whether git ignores it.
+func (p *SnapshotPolicy) Action(file string, ignored bool) SnapshotAction {
+	secret := p.IsSecretFile(file)
+	switch {
+	case secret && ignored:
+		return SnapshotSkip
+	case secret || ignored:
+		return SnapshotRedact
+	default:
+		return SnapshotKeep
+	}
+}
+
+func matchesAnyGlob(globs []string, file string) bool {
+	name := path.Base(file)
+	for _, glob := range globs {
+		target := name
+		if strings.Contains(glob, "/") {
+			target = file
+		}
+		if ok, _ := path.Match(glob, target); ok { //nolint:errcheck // Globs are validated in NewSnapshotPolicy
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	snapshotPolicyMu     sync.Mutex
+	snapshotPolicy       *SnapshotPolicy
+	snapshotPolicyLoader func() *SnapshotPolicy
+)
+
+// SetSnapshotPolicyLoader registers the function that builds the policy used
+// for shadow branch snapshots. It is called once, on first use. A nil result
+// uses the default globs.
+func SetSnapshotPolicyLoader(load func() *SnapshotPolicy) {
+	snapshotPolicyMu.Lock()
+	defer snapshotPolicyMu.Unlock()
+	snapshotPolicyLoader = load
+	snapshotPolicy = nil
+}
+
+// DefaultSnapshotPolicy returns the policy used for shadow branch snapshots.
+func DefaultSnapshotPolicy() *SnapshotPolicy {
+	snapshotPolicyMu.Lock()
+	defer snapshotPolicyMu.Unlock()
+	if snapshotPolicy == nil {
+		if snapshotPolicyLoader != nil {
+			snapshotPolicy = snapshotPolicyLoader()
+		}
+		if snapshotPolicy == nil {
+			snapshotPolicy = &SnapshotPolicy{
+				Exclude: DefaultSnapshotExclude,
+				Include: DefaultSnapshotInclude,
+			}
+		}
+	}
+	return snapshotPolicy
+}
+
+// gitIgnoredFiles returns which of the repo-relative files git ignores.
+// Uses git CLI instead of go-git because go-git doesn't respect global gitignore.
+func gitIgnoredFiles(ctx context.Context, repoRoot string, files []string) (map[string]bool, error) {
+	ignored := make(map[string]bool)
+	if len(files) == 0 {
+		return ignored, nil
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "check-ignore", "-z", "--stdin")
+	cmd.Dir = repoRoot
+	cmd.Stdin = strings.NewReader(strings.Join(files, "\x00") + "\x00")
+	output, err := cmd.Output()
+	if err != nil {
+		// Exit status 1 means none of the files are ignored
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+			return nil, fmt.Errorf("failed to check ignored files: %w", err)
+		}
+	}
+
+	for _, file := range strings.Split(string(output), "\x00") {
+		if file != "" {
+			ignored[file] = true
+		}
+	}
+	return ignored, nil
+}
+
+// SnapshotRedactedFiles returns the files whose contents a snapshot tree holds
+// with secrets redacted. Rewinding to the snapshot restores those files with
+// placeholders instead of the real values.
+func SnapshotRedactedFiles(tree *object.Tree) ([]string, error) {
+	file, err := tree.File(paths.SnapshotRedactionsFile)
+	if err != nil {
+		return nil, nil //nolint:nilerr // A missing list means nothing was redacted
+	}
+	content, err := file.Contents()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot redactions: %w", err)
+	}
+	return parseSnapshotRedactions(content), nil
+}
+
+func parseSnapshotRedactions(content string) []string {
+	var files []string
+	for _, line := range strings.Split(content, "\n") {
+		if line != "" {
+			files = append(files, line)
+		}
+	}
+	return files
+}
+
+// updateSnapshotRedactions rewrites the list of redacted files in the tree
+// entries: written files are replaced by their new state and deleted files are
+// dropped. The list is removed when no redacted files remain.
+func (s *GitStore) updateSnapshotRedactions(entries map[string]object.TreeEntry, written, deleted []string, redacted map[string]bool) error {
+	listed := make(map[string]bool)
+	if entry, ok := entries[paths.SnapshotRedactionsFile]; ok {
+		blob, err := s.repo.BlobObject(entry.Hash)
+		if err != nil {
+			return fmt.Errorf("failed to read snapshot redactions: %w", err)
+		}
+		content, err := object.NewFile(entry.Name, entry.Mode, blob).Contents()
+		if err != nil {
+			return fmt.Errorf("failed to read snapshot redactions: %w", err)
+		}
+		for _, file := range parseSnapshotRedactions(content) {
+			listed[file] = true
+		}
+	}
+	for _, file := range slices.Concat(written, deleted) {
+		delete(listed, file)
+	}
+	for file := range redacted {
+		listed[file] = true
+	}
+
+	if len(listed) == 0 {
+		delete(entries, paths.SnapshotRedactionsFile)
+		return nil
+	}
+
+	files := make([]string, 0, len(listed))
+	for file := range listed {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	var buf bytes.Buffer
+	for _, file := range files {
+		buf.WriteString(file)
+		buf.WriteByte('\n')
+	}
+	blobHash, err := CreateBlobFromContent(s.repo, buf.Bytes())
+	if err != nil {
+		return err
+	}
+	entries[paths.SnapshotRedactionsFile] = object.TreeEntry{
+		Name: paths.SnapshotRedactionsFile,
+		Mode: filemode.Regular,
+		Hash: blobHash,
+	}
+	return nil
+}
+
+// createSnapshotBlob stores a working tree file for a snapshot, redacting its
+// contents when the action calls for it. Reports whether anything was redacted.
+func createSnapshotBlob(repo *git.Repository, absPath, file string, action SnapshotAction) (plumbing.Hash, filemode.FileMode, bool, error) {
+	if action != SnapshotRedact {
+		hash, mode, err := createBlobFromFile(repo, absPath)
+		return hash, mode, false, err
+	}
+
+	info, err := os.Stat(absPath)
+	if err != nil {
+		return plumbing.ZeroHash, 0, false, fmt.Errorf("failed to stat file: %w", err)
+	}
+	mode := filemode.Regular
+	if info.Mode()&0o111 != 0 {
+		mode = filemode.Executable
+	}
+
+	content, err := os.ReadFile(absPath) //nolint:gosec // absPath is a repo-relative path joined to the repo root
+	if err != nil {
+		return plumbing.ZeroHash, 0, false, fmt.Errorf("failed to read file: %w", err)
+	}
+	redacted, err := RedactFileContent(redact.Default(), file, content)
+	if err != nil {
+		return plumbing.ZeroHash, 0, false, err
+	}
+	hash, err := CreateBlobFromContent(repo, redacted)
+	if err != nil {
+		return plumbing.ZeroHash, 0, false, err
+	}
+	return hash, mode, !bytes.Equal(redacted, content), nil
+}
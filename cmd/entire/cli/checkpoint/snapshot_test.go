@@ -0,0 +1,145 @@
+package checkpoint
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestSnapshotPolicy_Action(t *testing.T) {
+	t.Parallel()
+
+	policy, err := NewSnapshotPolicy([]string{"config/secrets.yaml"}, []string{".env.test"})
+	if err != nil {
+		t.Fatalf("NewSnapshotPolicy() error = %v", err)
+	}
+
+	tests := []struct {
+		file    string
+		ignored bool
+		want    SnapshotAction
+	}{
+		{"main.go", false, SnapshotKeep},
+		{".env", true, SnapshotSkip},
+		{"services/api/.env.local", true, SnapshotSkip},
+		{"certs/server.pem", false, SnapshotRedact},
+		{".env.example", true, SnapshotRedact},
+		{".env.test", true, SnapshotRedact},
+		{"config/secrets.yaml", true, SnapshotSkip},
+		{"other/config/secrets.yaml", true, SnapshotRedact},
+		{"build/output.log", true, SnapshotRedact},
+	}
+	for _, tt := range tests {
+		if got := policy.Action(tt.file, tt.ignored); got != tt.want {
+			t.Errorf("Action(%q, ignored=%v) = %v, want %v", tt.file, tt.ignored, got, tt.want)
+		}
+	}
+}
+
+func TestNewSnapshotPolicy_InvalidGlob(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewSnapshotPolicy([]string{"[secrets"}, nil); err == nil {
+		t.Error("expected an error for a malformed glob")
+	}
+}
+
+func TestWriteTemporary_SnapshotPolicy(t *testing.T) {
+	tempDir := t.TempDir()
+	repo, err := git.PlainInit(tempDir, false)
+	if err != nil {
+		t.Fatalf("failed to init git repo: %v", err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("failed to get worktree: %v", err)
+	}
+	writeFile := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	writeFile(".gitignore", ".env\nlocal.cfg\n")
+	if _, err := worktree.Add(".gitignore"); err != nil {
+		t.Fatalf("failed to add .gitignore: %v", err)
+	}
+	initialCommit, err := worktree.Commit("Initial commit", &git.CommitOptions{
+		Author: &object.Signature{Name: "Test", Email: "test@test.com"},
+	})
+	if err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+	t.Chdir(tempDir)
+
+	const secret = "sk-proj-Xk9mQ2vR7nL4pW8sT1yB6cF3hJ5dG0aZ"
+	writeFile(".env", "API_KEY="+secret+"\n")
+	writeFile("local.cfg", "token = "+secret+"\n")
+	writeFile("main.go", "package main\n")
+
+	store := NewGitStore(repo)
+	write := func(files []string) *object.Tree {
+		t.Helper()
+		result, err := store.WriteTemporary(context.Background(), WriteTemporaryOptions{
+			SessionID:     "test-session",
+			BaseCommit:    initialCommit.String(),
+			NewFiles:      files,
+			CommitMessage: "Checkpoint",
+			AuthorName:    "Test",
+			AuthorEmail:   "test@test.com",
+		})
+		if err != nil {
+			t.Fatalf("WriteTemporary() error = %v", err)
+		}
+		commit, err := repo.CommitObject(result.CommitHash)
+		if err != nil {
+			t.Fatalf("failed to get commit: %v", err)
+		}
+		tree, err := commit.Tree()
+		if err != nil {
+			t.Fatalf("failed to get tree: %v", err)
+		}
+		return tree
+	}
+
+	tree := write([]string{".env", "local.cfg", "main.go"})
+
+	if _, err := tree.File(".env"); err == nil {
+		t.Error("gitignored .env should not be snapshotted")
+	}
+	cfg, err := tree.File("local.cfg")
+	if err != nil {
+		t.Fatalf("local.cfg missing from snapshot: %v", err)
+	}
+	if content, _ := cfg.Contents(); strings.Contains(content, secret) { //nolint:errcheck // Checked by the assertion
+		t.Errorf("local.cfg snapshot not redacted: %q", content)
+	}
+	mainFile, err := tree.File("main.go")
+	if err != nil {
+		t.Fatalf("main.go missing from snapshot: %v", err)
+	}
+	if content, _ := mainFile.Contents(); content != "package main\n" { //nolint:errcheck // Checked by the assertion
+		t.Errorf("main.go snapshot = %q, want it verbatim", content)
+	}
+	redacted, err := SnapshotRedactedFiles(tree)
+	if err != nil {
+		t.Fatalf("SnapshotRedactedFiles() error = %v", err)
+	}
+	if len(redacted) != 1 || redacted[0] != "local.cfg" {
+		t.Errorf("SnapshotRedactedFiles() = %v, want [local.cfg]", redacted)
+	}
+
+	// Once the secret is gone, the file no longer counts as redacted
+	writeFile("local.cfg", "token = none\n")
+	tree = write([]string{"local.cfg"})
+	if _, err := tree.File(paths.SnapshotRedactionsFile); err == nil {
+		t.Error("expected the redaction list to be removed")
+	}
+}
@@ -106,7 +106,7 @@ func (s *GitStore) WriteTemporary(ctx context.Context, opts WriteTemporaryOption
 	}
 
 	// Build tree with changes
-	treeHash, err := s.buildTreeWithChanges(baseTreeHash, allFiles, allDeletedFiles, opts.MetadataDir, opts.MetadataDirAbs)
+	treeHash, err := s.buildTreeWithChanges(ctx, baseTreeHash, allFiles, allDeletedFiles, opts.MetadataDir, opts.MetadataDirAbs)
 	if err != nil {
 		return WriteTemporaryResult{}, fmt.Errorf("failed to build tree: %w", err)
 	}
@@ -226,8 +226,6 @@ func (s *GitStore) ListTemporary(ctx context.Context) ([]TemporaryInfo, error) {
 // Task checkpoints include both code changes and task-specific metadata.
 // Returns the commit hash of the created checkpoint.
 func (s *GitStore) WriteTemporaryTask(ctx context.Context, opts WriteTemporaryTaskOptions) (plumbing.Hash, error) {
-	_ = ctx // Reserved for future use
-
 	// Validate base commit - required for shadow branch naming
 	if opts.BaseCommit == "" {
 		return plumbing.ZeroHash, errors.New("BaseCommit is required for task checkpoint")
@@ -259,7 +257,7 @@ func (s *GitStore) WriteTemporaryTask(ctx context.Context, opts WriteTemporaryTa
 	allFiles = append(allFiles, opts.NewFiles...)
 
 	// Build new tree with code changes (no metadata dir yet)
-	newTreeHash, err := s.buildTreeWithChanges(baseTreeHash, allFiles, opts.DeletedFiles, "", "")
+	newTreeHash, err := s.buildTreeWithChanges(ctx, baseTreeHash, allFiles, opts.DeletedFiles, "", "")
 	if err != nil {
 		return plumbing.ZeroHash, fmt.Errorf("failed to build tree: %w", err)
 	}
@@ -687,7 +685,9 @@ func (s *GitStore) getOrCreateShadowBranch(branchName string) (plumbing.Hash, pl
 // buildTreeWithChanges builds a git tree with the given changes.
 // metadataDir is the relative path for git tree entries, metadataDirAbs is the absolute path
 // for filesystem operations (needed when CLI is run from a subdirectory).
+// Modified files are snapshotted according to DefaultSnapshotPolicy.
 func (s *GitStore) buildTreeWithChanges(
+	ctx context.Context,
 	baseTreeHash plumbing.Hash,
 	modifiedFiles, deletedFiles []string,
 	metadataDir, metadataDirAbs string,
@@ -718,6 +718,15 @@ func (s *GitStore) buildTreeWithChanges(
 		delete(entries, file)
 	}
 
+	// Agents can write files the user has gitignored, such as .env, so check
+	// which modified files are ignored before snapshotting them
+	policy := DefaultSnapshotPolicy()
+	ignored, err := gitIgnoredFiles(ctx, repoRoot, modifiedFiles)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	redacted := make(map[string]bool)
+
 	// Add/update modified files
 	for _, file := range modifiedFiles {
 		// Resolve path relative to repo root for filesystem operations
@@ -727,11 +736,20 @@ func (s *GitStore) buildTreeWithChanges(
 			continue
 		}
 
-		blobHash, mode, err := createBlobFromFile(s.repo, absPath)
+		action := policy.Action(file, ignored[file])
+		if action == SnapshotSkip {
+			delete(entries, file)
+			continue
+		}
+
+		blobHash, mode, wasRedacted, err := createSnapshotBlob(s.repo, absPath, file, action)
 		if err != nil {
 			// Skip files that can't be staged (may have been deleted since detection)
 			continue
 		}
+		if wasRedacted {
+			redacted[file] = true
+		}
 
 		entries[file] = object.TreeEntry{
 			Name: file,
@@ -740,6 +758,10 @@ func (s *GitStore) buildTreeWithChanges(
 		}
 	}
 
+	if err := s.updateSnapshotRedactions(entries, modifiedFiles, deletedFiles, redacted); err != nil {
+		return plumbing.ZeroHash, err
+	}
+
 	// Add metadata directory files
 	if metadataDir != "" && metadataDirAbs != "" {
 		if err := addDirectoryToEntriesWithAbsPath(s.repo, metadataDirAbs, metadataDir, entries); err != nil {
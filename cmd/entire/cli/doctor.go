@@ -37,7 +37,10 @@ For each stuck session, you can choose to:
   - Skip: Leave the session as-is
 
 Use --force to condense all fixable sessions without prompting.  Sessions that can't
-be condensed will be discarded.`,
+be condensed will be discarded.
+
+To end stale ACTIVE sessions automatically, set strategy_options.idle_timeout
+(e.g. "2h") in .entire/settings.json.`,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			return runSessionsFix(cmd, forceFlag)
 		},
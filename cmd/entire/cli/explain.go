@@ -7,6 +7,7 @@ import (
 	"io"
 	"os"
 	"os/exec"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -17,7 +18,9 @@ import (
 	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
 	"github.com/entireio/cli/cmd/entire/cli/logging"
 	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/session"
 	"github.com/entireio/cli/cmd/entire/cli/strategy"
+	"github.com/entireio/cli/cmd/entire/cli/stringutil"
 	"github.com/entireio/cli/cmd/entire/cli/summarize"
 	"github.com/entireio/cli/cmd/entire/cli/trailers"
 	"github.com/entireio/cli/cmd/entire/cli/transcript"
@@ -465,7 +468,17 @@ func getAssociatedCommits(repo *git.Repository, checkpointID id.CheckpointID, se
 	}
 
 	commits := []associatedCommit{} // Initialize as empty slice, not nil (nil means "not searched")
-	targetID := checkpointID.String()
+
+	// Rewritten commits (amend, rebase, cherry-pick) may be linked without a trailer
+	linkedSHAs := make(map[string]bool)
+	if summary, readErr := checkpoint.NewGitStore(repo).ReadCommitted(context.Background(), checkpointID); readErr == nil && summary != nil {
+		for _, link := range summary.LinkedCommits {
+			linkedSHAs[link.SHA] = true
+		}
+	}
+	belongsToCheckpoint := func(c *object.Commit) bool {
+		return linkedSHAs[c.Hash.String()] || slices.Contains(trailers.ParseAllCheckpoints(c.Message), checkpointID)
+	}
 
 	collectCommit := func(c *object.Commit) {
 		fullSHA := c.Hash.String()
@@ -495,8 +508,7 @@ func getAssociatedCommits(repo *git.Repository, checkpointID id.CheckpointID, se
 		defer iter.Close()
 
 		err = iter.ForEach(func(c *object.Commit) error {
-			cpID, found := trailers.ParseCheckpoint(c.Message)
-			if found && cpID.String() == targetID {
+			if belongsToCheckpoint(c) {
 				collectCommit(c)
 			}
 			return nil
@@ -513,8 +525,7 @@ func getAssociatedCommits(repo *git.Repository, checkpointID id.CheckpointID, se
 				return errStopIteration
 			}
 
-			cpID, found := trailers.ParseCheckpoint(c.Message)
-			if found && cpID.String() == targetID {
+			if belongsToCheckpoint(c) {
 				collectCommit(c)
 			}
 			return nil
@@ -586,6 +597,12 @@ func formatCheckpointOutput(summary *checkpoint.CheckpointSummary, content *chec
 	// Note: CheckpointID is always exactly 12 characters, matching checkpointIDDisplayLength
 	fmt.Fprintf(&sb, "Checkpoint: %s\n", checkpointID)
 	fmt.Fprintf(&sb, "Session: %s\n", meta.SessionID)
+	if meta.Title != "" {
+		fmt.Fprintf(&sb, "Title: %s\n", meta.Title)
+	}
+	if len(meta.Tags) > 0 {
+		fmt.Fprintf(&sb, "Tags: %s\n", meta.FormatTags())
+	}
 	fmt.Fprintf(&sb, "Created: %s\n", meta.CreatedAt.Format("2006-01-02 15:04:05"))
 
 	// Author (only for committed checkpoints with known author)
@@ -639,6 +656,14 @@ func formatCheckpointOutput(summary *checkpoint.CheckpointSummary, content *chec
 		sb.WriteString("Outcome: (not generated)\n")
 	}
 
+	// Notes the user attached with `entire session note`
+	if len(meta.Notes) > 0 {
+		sb.WriteString("\nNotes:\n")
+		for _, note := range meta.Notes {
+			fmt.Fprintf(&sb, "  %s %s\n", note.CreatedAt.Local().Format("2006-01-02 15:04"), note.Text)
+		}
+	}
+
 	// Verbose: add learnings, friction, files, and scoped transcript
 	if verbose || full {
 		// AI Summary details (learnings, friction, open items)
@@ -657,6 +682,10 @@ func formatCheckpointOutput(summary *checkpoint.CheckpointSummary, content *chec
 		} else {
 			sb.WriteString("Files: (none)\n")
 		}
+
+		if meta.InitialAttribution != nil {
+			formatAttribution(&sb, meta.InitialAttribution)
+		}
 	}
 
 	// Transcript section: full shows entire session, verbose shows checkpoint scope
@@ -761,6 +790,150 @@ func formatSummaryDetails(sb *strings.Builder, summary *checkpoint.Summary) {
 			fmt.Fprintf(sb, "  - %s\n", item)
 		}
 	}
+
+	formatSummaryExtensions(sb, summary.Extensions)
+}
+
+// formatSummaryExtensions appends the fields added by a summary template,
+// sorted by name. Lists are shown as bullets; other values inline.
+func formatSummaryExtensions(sb *strings.Builder, extensions map[string]any) {
+	for _, ext := range summaryExtensions(extensions) {
+		if !ext.List {
+			fmt.Fprintf(sb, "\n%s: %s\n", ext.Label, ext.Values[0])
+			continue
+		}
+		fmt.Fprintf(sb, "\n%s:\n", ext.Label)
+		for _, item := range ext.Values {
+			fmt.Fprintf(sb, "  - %s\n", item)
+		}
+	}
+}
+
+// summaryExtension is a summary template field prepared for display.
+type summaryExtension struct {
+	Name   string
+	Label  string
+	List   bool
+	Values []string
+}
+
+// summaryExtensions returns the non-empty fields added by a summary template,
+// sorted by name. Non-list fields have a single value.
+func summaryExtensions(extensions map[string]any) []summaryExtension {
+	names := make([]string, 0, len(extensions))
+	for name := range extensions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var out []summaryExtension
+	for _, name := range names {
+		ext := summaryExtension{Name: name, Label: extensionLabel(name)}
+		switch value := extensions[name].(type) {
+		case []any:
+			ext.List = true
+			for _, item := range value {
+				ext.Values = append(ext.Values, fmt.Sprint(item))
+			}
+		case string:
+			if value != "" {
+				ext.Values = []string{value}
+			}
+		case nil:
+			// Unset
+		default:
+			ext.Values = []string{fmt.Sprint(value)}
+		}
+		if len(ext.Values) > 0 {
+			out = append(out, ext)
+		}
+	}
+	return out
+}
+
+// extensionLabel turns a snake_case field name into a title, e.g.
+// "risk_assessment" becomes "Risk Assessment".
+func extensionLabel(name string) string {
+	words := strings.Fields(strings.ReplaceAll(name, "_", " "))
+	for i, word := range words {
+		words[i] = stringutil.CapitalizeFirst(word)
+	}
+	return strings.Join(words, " ")
+}
+
+// formatAttribution appends the attribution totals and, when available, a per-file
+// table showing how much of each file was written by the agent versus by hand.
+func formatAttribution(sb *strings.Builder, attr *checkpoint.InitialAttribution) {
+	fmt.Fprintf(sb, "\nAttribution: %.1f%% agent (%d of %d lines)\n",
+		attr.AgentPercentage, attr.AgentLines, attr.TotalCommitted)
+	if len(attr.Files) > 0 {
+		formatFileAttribution(sb, attr.Files)
+	}
+	if len(attr.Prompts) > 0 {
+		formatPromptAttribution(sb, attr.Prompts)
+	}
+	if len(attr.Subagents) > 0 {
+		formatSubagentAttribution(sb, attr)
+	}
+}
+
+// formatSubagentAttribution writes the agent lines split between the main agent
+// and each subagent. The main agent gets whatever the subagents didn't write.
+func formatSubagentAttribution(sb *strings.Builder, attr *checkpoint.InitialAttribution) {
+	labels := make([]string, len(attr.Subagents))
+	width := len("main agent")
+	subagentLines := 0
+	for i, sa := range attr.Subagents {
+		label := sa.SubagentType
+		if label == "" {
+			label = "subagent"
+		}
+		if sa.AgentID != "" {
+			label += " (" + stringutil.TruncateRunes(sa.AgentID, 8, "") + ")"
+		}
+		labels[i] = label
+		width = max(width, len(label))
+		subagentLines += sa.AgentLines
+	}
+
+	sb.WriteString("\nBy agent:\n")
+	fmt.Fprintf(sb, "  %-*s  %6s  %5s\n", width, "Agent", "Lines", "Files")
+	fmt.Fprintf(sb, "  %-*s  %6d\n", width, "main agent", max(0, attr.AgentLines-subagentLines))
+	for i, sa := range attr.Subagents {
+		fmt.Fprintf(sb, "  %-*s  %6d  %5d\n", width, labels[i], sa.AgentLines, len(sa.Files))
+	}
+}
+
+// formatFileAttribution writes the per-file attribution table, sorted by path.
+func formatFileAttribution(sb *strings.Builder, files map[string]checkpoint.FileAttribution) {
+	paths := make([]string, 0, len(files))
+	width := len("File")
+	for path := range files {
+		paths = append(paths, path)
+		width = max(width, len(path))
+	}
+	sort.Strings(paths)
+
+	fmt.Fprintf(sb, "  %-*s  %6s  %6s  %8s  %7s\n", width, "File", "Agent", "Human", "Modified", "Removed")
+	for _, path := range paths {
+		f := files[path]
+		fmt.Fprintf(sb, "  %-*s  %6d  %6d  %8d  %7d\n", width, path, f.AgentLines, f.HumanAdded, f.HumanModified, f.HumanRemoved)
+	}
+}
+
+// formatPromptAttribution writes the per-prompt attribution table. Human columns
+// are the edits made after the agent responded, before the next prompt.
+func formatPromptAttribution(sb *strings.Builder, prompts []checkpoint.PromptAttribution) {
+	sb.WriteString("\nBy prompt (human edits made before the next prompt):\n")
+	fmt.Fprintf(sb, "  %3s  %6s  %6s  %7s  %s\n", "#", "Agent", "Human", "Removed", "Prompt")
+	for i, p := range prompts {
+		prompt := p.Prompt
+		if prompt == "" {
+			prompt = "(unknown)"
+		}
+		fmt.Fprintf(sb, "  %3d  %6d  %6d  %7d  %s\n", i+1, p.AgentLines, p.HumanAdded, p.HumanRemoved,
+			stringutil.TruncateRunes(prompt, 60, "..."))
+	}
 }
 
 // runExplainDefault shows all checkpoints on the current branch.
@@ -924,6 +1097,7 @@ func getBranchCheckpoints(repo *git.Repository, limit int) ([]strategy.RewindPoi
 			IsTaskCheckpoint: cpInfo.IsTask,
 			ToolUseID:        cpInfo.ToolUseID,
 			Agent:            cpInfo.Agent,
+			Annotations:      cpInfo.Annotations,
 		}
 		// Read session prompt from metadata branch (best-effort)
 		content, _ := store.ReadLatestSessionContent(context.Background(), cpID) //nolint:errcheck  // Best-effort
@@ -1078,6 +1252,12 @@ func convertTemporaryCheckpoint(repo *git.Repository, tc checkpoint.TemporaryChe
 		sessionPrompt = strategy.ReadSessionPromptFromTree(shadowTree, tc.MetadataDir)
 	}
 
+	// Annotations of an uncommitted session live in its state (best-effort)
+	var annotations session.Annotations
+	if state, err := strategy.LoadSessionState(tc.SessionID); err == nil && state != nil {
+		annotations = state.Annotations
+	}
+
 	return &strategy.RewindPoint{
 		ID:               tc.CommitHash.String(),
 		Message:          tc.Message,
@@ -1087,6 +1267,7 @@ func convertTemporaryCheckpoint(repo *git.Repository, tc checkpoint.TemporaryChe
 		ToolUseID:        tc.ToolUseID,
 		SessionID:        tc.SessionID,
 		SessionPrompt:    sessionPrompt,
+		Annotations:      annotations,
 		IsLogsOnly:       false, // Temporary checkpoints can be fully rewound
 	}
 }
@@ -1166,18 +1347,31 @@ func runExplainCommit(w io.Writer, commitRef string, noPager, verbose, full, sea
 		return fmt.Errorf("failed to get commit: %w", err)
 	}
 
-	// Extract Entire-Checkpoint trailer
-	checkpointID, hasCheckpoint := trailers.ParseCheckpoint(commit.Message)
-	if !hasCheckpoint {
+	// Extract Entire-Checkpoint trailers, following links for rewritten commits
+	checkpointIDs, err := strategy.ResolveCommitCheckpoints(context.Background(), repo, commit)
+	if err != nil {
+		return fmt.Errorf("failed to resolve checkpoint for commit: %w", err)
+	}
+	if len(checkpointIDs) == 0 {
 		fmt.Fprintln(w, "No associated Entire checkpoint")
 		fmt.Fprintf(w, "\nCommit %s does not have an Entire-Checkpoint trailer.\n", hash.String()[:7])
 		fmt.Fprintln(w, "This commit was not created during an Entire session, or the trailer was removed.")
 		return nil
 	}
 
+	// A squash commit can carry several checkpoints; explain the first and list the rest
+	if len(checkpointIDs) > 1 {
+		others := make([]string, 0, len(checkpointIDs)-1)
+		for _, cpID := range checkpointIDs[1:] {
+			others = append(others, cpID.String())
+		}
+		fmt.Fprintf(w, "Commit %s is linked to %d checkpoints; also see: %s\n\n",
+			hash.String()[:7], len(checkpointIDs), strings.Join(others, ", "))
+	}
+
 	// Delegate to checkpoint detail view
 	// Note: errW is only used for generate mode, but we pass w for safety
-	return runExplainCheckpoint(w, w, checkpointID.String(), noPager, verbose, full, false, false, false, searchAll)
+	return runExplainCheckpoint(w, w, checkpointIDs[0].String(), noPager, verbose, full, false, false, false, searchAll)
 }
 
 // formatSessionInfo formats session information for display.
@@ -1363,8 +1557,10 @@ func formatBranchCheckpoints(branchName string, points []strategy.RewindPoint, s
 type checkpointGroup struct {
 	checkpointID string
 	prompt       string
-	isTemporary  bool // true if any commit is not logs-only (can be rewound)
-	isTask       bool // true if this is a task checkpoint
+	title        string   // session title set with `entire session rename`
+	tags         []string // session tags set with `entire session tag`
+	isTemporary  bool     // true if any commit is not logs-only (can be rewound)
+	isTask       bool     // true if this is a task checkpoint
 	commits      []commitEntry
 }
 
@@ -1403,6 +1599,8 @@ func groupByCheckpointID(points []strategy.RewindPoint) []checkpointGroup {
 			group = &checkpointGroup{
 				checkpointID: cpID,
 				prompt:       point.SessionPrompt,
+				title:        point.Annotations.Title,
+				tags:         point.Annotations.Tags,
 				isTemporary:  !point.IsLogsOnly,
 				isTask:       point.IsTaskCheckpoint,
 			}
@@ -1433,6 +1631,10 @@ func groupByCheckpointID(points []strategy.RewindPoint) []checkpointGroup {
 		if group.prompt == "" && point.SessionPrompt != "" {
 			group.prompt = point.SessionPrompt
 		}
+		if group.title == "" && len(group.tags) == 0 {
+			group.title = point.Annotations.Title
+			group.tags = point.Annotations.Tags
+		}
 	}
 
 	// Sort commits within each group by date (most recent first)
@@ -1486,15 +1688,22 @@ func formatCheckpointGroup(sb *strings.Builder, group checkpointGroup) {
 		indicatorStr = " " + strings.Join(indicators, " ")
 	}
 
-	// Prompt (truncated)
+	// Title, or else prompt (truncated)
 	var promptStr string
-	if group.prompt == "" {
+	switch {
+	case group.title != "":
+		promptStr = strategy.TruncateDescription(group.title, maxPromptDisplayLength)
+	case group.prompt == "":
 		promptStr = "(no prompt)"
-	} else {
+	default:
 		// Quote actual prompts
 		promptStr = fmt.Sprintf("%q", strategy.TruncateDescription(group.prompt, maxPromptDisplayLength))
 	}
 
+	if len(group.tags) > 0 {
+		promptStr += " " + session.Annotations{Tags: group.tags}.FormatTags()
+	}
+
 	// Checkpoint header: [checkpoint_id] [indicators] prompt
 	fmt.Fprintf(sb, "[%s]%s %s\n", cpID, indicatorStr, promptStr)
 
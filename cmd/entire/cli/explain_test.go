@@ -13,6 +13,7 @@ import (
 	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
 	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
 	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/session"
 	"github.com/entireio/cli/cmd/entire/cli/strategy"
 	"github.com/entireio/cli/cmd/entire/cli/trailers"
 	"github.com/entireio/cli/cmd/entire/cli/transcript"
@@ -1015,6 +1016,142 @@ func TestFormatCheckpointOutput_Verbose_NoCommitMessage(t *testing.T) {
 	}
 }
 
+func TestFormatCheckpointOutput_Verbose_PerFileAttribution(t *testing.T) {
+	summary := &checkpoint.CheckpointSummary{
+		CheckpointID:     id.MustCheckpointID("abc123def456"),
+		CheckpointsCount: 1,
+		FilesTouched:     []string{"main.go"},
+	}
+	content := &checkpoint.SessionContent{
+		Metadata: checkpoint.CommittedMetadata{
+			CheckpointID:     "abc123def456",
+			SessionID:        "2026-01-21-test-session",
+			CreatedAt:        time.Date(2026, 1, 21, 10, 30, 0, 0, time.UTC),
+			FilesTouched:     []string{"main.go"},
+			CheckpointsCount: 1,
+			InitialAttribution: &checkpoint.InitialAttribution{
+				AgentLines:      8,
+				HumanAdded:      2,
+				TotalCommitted:  10,
+				AgentPercentage: 80,
+				Files: map[string]checkpoint.FileAttribution{
+					"main.go":      {AgentLines: 8},
+					"docs/HACK.md": {HumanAdded: 2, HumanModified: 1, HumanRemoved: 3},
+				},
+			},
+		},
+		Prompts: "Add a feature",
+	}
+
+	output := formatCheckpointOutput(summary, content, id.MustCheckpointID("abc123def456"), nil, checkpoint.Author{}, true, false)
+
+	for _, want := range []string{
+		"Attribution: 80.0% agent (8 of 10 lines)\n",
+		"  File           Agent   Human  Modified  Removed\n",
+		"  docs/HACK.md       0       2         1        3\n",
+		"  main.go            8       0         0        0\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("verbose output missing %q:\n%s", want, output)
+		}
+	}
+	if strings.Index(output, "docs/HACK.md") > strings.Index(output, "  main.go  ") {
+		t.Error("attribution rows should be sorted by path")
+	}
+
+	// Default output stays compact.
+	short := formatCheckpointOutput(summary, content, id.MustCheckpointID("abc123def456"), nil, checkpoint.Author{}, false, false)
+	if strings.Contains(short, "Attribution:") {
+		t.Error("default output should not include the attribution table")
+	}
+}
+
+func TestFormatCheckpointOutput_Verbose_PerPromptAttribution(t *testing.T) {
+	summary := &checkpoint.CheckpointSummary{
+		CheckpointID:     id.MustCheckpointID("abc123def456"),
+		CheckpointsCount: 2,
+		FilesTouched:     []string{"main.go"},
+	}
+	content := &checkpoint.SessionContent{
+		Metadata: checkpoint.CommittedMetadata{
+			CheckpointID:     "abc123def456",
+			SessionID:        "2026-01-21-test-session",
+			CreatedAt:        time.Date(2026, 1, 21, 10, 30, 0, 0, time.UTC),
+			FilesTouched:     []string{"main.go"},
+			CheckpointsCount: 2,
+			InitialAttribution: &checkpoint.InitialAttribution{
+				AgentLines:      12,
+				HumanAdded:      3,
+				TotalCommitted:  15,
+				AgentPercentage: 80,
+				Prompts: []checkpoint.PromptAttribution{
+					{Checkpoint: 1, Prompt: "Add a feature", AgentLines: 10, HumanAdded: 3, HumanRemoved: 4},
+					{Checkpoint: 2, AgentLines: 2},
+				},
+			},
+		},
+		Prompts: "Add a feature",
+	}
+
+	output := formatCheckpointOutput(summary, content, id.MustCheckpointID("abc123def456"), nil, checkpoint.Author{}, true, false)
+
+	for _, want := range []string{
+		"By prompt (human edits made before the next prompt):\n",
+		"    #   Agent   Human  Removed  Prompt\n",
+		"    1      10       3        4  Add a feature\n",
+		"    2       2       0        0  (unknown)\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("verbose output missing %q:\n%s", want, output)
+		}
+	}
+	// Checkpoints without per-file data still show the prompt table.
+	if strings.Contains(output, "  File  ") {
+		t.Error("file table should be omitted without per-file data")
+	}
+}
+
+func TestFormatCheckpointOutput_Verbose_SubagentAttribution(t *testing.T) {
+	summary := &checkpoint.CheckpointSummary{
+		CheckpointID:     id.MustCheckpointID("abc123def456"),
+		CheckpointsCount: 1,
+		FilesTouched:     []string{"main.go", "review.md"},
+	}
+	content := &checkpoint.SessionContent{
+		Metadata: checkpoint.CommittedMetadata{
+			CheckpointID:     "abc123def456",
+			SessionID:        "2026-01-21-test-session",
+			CreatedAt:        time.Date(2026, 1, 21, 10, 30, 0, 0, time.UTC),
+			FilesTouched:     []string{"main.go", "review.md"},
+			CheckpointsCount: 1,
+			InitialAttribution: &checkpoint.InitialAttribution{
+				AgentLines:      20,
+				TotalCommitted:  20,
+				AgentPercentage: 100,
+				Subagents: []checkpoint.SubagentAttribution{
+					{AgentID: "a1b2c3d4e5f6", SubagentType: "dev", AgentLines: 12, Files: []string{"main.go", "util.go"}},
+					{SubagentType: "reviewer", AgentLines: 3, Files: []string{"review.md"}},
+				},
+			},
+		},
+		Prompts: "Add a feature",
+	}
+
+	output := formatCheckpointOutput(summary, content, id.MustCheckpointID("abc123def456"), nil, checkpoint.Author{}, true, false)
+
+	for _, want := range []string{
+		"By agent:\n",
+		"  Agent            Lines  Files\n",
+		"  main agent           5\n",
+		"  dev (a1b2c3d4)      12      2\n",
+		"  reviewer             3      1\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("verbose output missing %q:\n%s", want, output)
+		}
+	}
+}
+
 func TestFormatCheckpointOutput_Full(t *testing.T) {
 	// Use proper transcript format that matches actual Claude transcripts
 	transcriptData := `{"type":"user","message":{"content":"Add a new feature"}}
@@ -1211,6 +1348,35 @@ func TestFormatSummaryDetails_EmptyCategories(t *testing.T) {
 	}
 }
 
+func TestFormatSummaryDetails_Extensions(t *testing.T) {
+	summary := &checkpoint.Summary{
+		Intent:  "Test intent",
+		Outcome: "Test outcome",
+		Extensions: map[string]any{
+			"risk_assessment": "Low: only touches the CLI",
+			"test_gaps":       []any{"No test for timeouts", "No test for retries"},
+			"skipped":         "",
+		},
+	}
+
+	var sb strings.Builder
+	formatSummaryDetails(&sb, summary)
+	output := sb.String()
+
+	if !strings.Contains(output, "Risk Assessment: Low: only touches the CLI") {
+		t.Errorf("should render string extension inline, got:\n%s", output)
+	}
+	if !strings.Contains(output, "Test Gaps:\n  - No test for timeouts\n  - No test for retries\n") {
+		t.Errorf("should render list extension as bullets, got:\n%s", output)
+	}
+	if strings.Contains(output, "Skipped") {
+		t.Errorf("empty extension should not be shown, got:\n%s", output)
+	}
+	if strings.Index(output, "Risk Assessment") > strings.Index(output, "Test Gaps") {
+		t.Errorf("extensions should be sorted by name, got:\n%s", output)
+	}
+}
+
 func TestFormatBranchCheckpoints_BasicOutput(t *testing.T) {
 	now := time.Now()
 	points := []strategy.RewindPoint{
@@ -1253,6 +1419,54 @@ func TestFormatBranchCheckpoints_BasicOutput(t *testing.T) {
 	}
 }
 
+func TestFormatBranchCheckpoints_SessionAnnotations(t *testing.T) {
+	points := []strategy.RewindPoint{
+		{
+			ID:            "abc123def456",
+			Message:       "Add feature X",
+			Date:          time.Now(),
+			CheckpointID:  "chk123456789",
+			SessionID:     "2026-01-22-session-1",
+			SessionPrompt: "Implement feature X",
+			Annotations:   session.Annotations{Title: "Feature X", Tags: []string{"spike"}},
+			IsLogsOnly:    true,
+		},
+	}
+
+	output := formatBranchCheckpoints("main", points, "")
+
+	if !strings.Contains(output, "[chk123456789] Feature X #spike") {
+		t.Errorf("expected the title and tags in the checkpoint header, got:\n%s", output)
+	}
+	if strings.Contains(output, "Implement feature X") {
+		t.Errorf("expected the title to replace the prompt, got:\n%s", output)
+	}
+}
+
+func TestFormatCheckpointOutput_SessionAnnotations(t *testing.T) {
+	content := &checkpoint.SessionContent{
+		Metadata: checkpoint.CommittedMetadata{
+			CheckpointID: "abc123def456",
+			SessionID:    "2026-01-21-test-session",
+			CreatedAt:    time.Date(2026, 1, 21, 10, 30, 0, 0, time.UTC),
+			Annotations: session.Annotations{
+				Title: "Login refactor",
+				Tags:  []string{"auth", "spike"},
+				Notes: []session.Note{{Text: "Keep the old endpoint", CreatedAt: time.Date(2026, 1, 21, 11, 0, 0, 0, time.UTC)}},
+			},
+		},
+		Prompts: "Refactor login",
+	}
+
+	output := formatCheckpointOutput(nil, content, id.MustCheckpointID("abc123def456"), nil, checkpoint.Author{}, false, false)
+
+	for _, want := range []string{"Title: Login refactor\n", "Tags: #auth #spike\n", "Notes:\n", "Keep the old endpoint"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, output)
+		}
+	}
+}
+
 func TestFormatBranchCheckpoints_GroupedByCheckpointID(t *testing.T) {
 	// Create checkpoints spanning multiple days
 	today := time.Date(2026, 1, 22, 10, 0, 0, 0, time.UTC)
@@ -2686,6 +2900,50 @@ func TestGetAssociatedCommits(t *testing.T) {
 	}
 }
 
+func TestGetAssociatedCommits_FollowsRewriteLinks(t *testing.T) {
+	repo, cpID := setupServeTestRepo(t)
+
+	// A rewritten commit without the trailer, linked after the fact.
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("failed to get worktree: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(wt.Filesystem.Root(), "hello.txt"), []byte("hello there\n"), 0o644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if _, err := wt.Add("hello.txt"); err != nil {
+		t.Fatalf("failed to add file: %v", err)
+	}
+	rewritten, err := wt.Commit("Reworded greeting", &git.CommitOptions{
+		Author: &object.Signature{Name: "Test", Email: "test@example.com", When: time.Now()},
+	})
+	if err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+	if _, err := checkpoint.NewGitStore(repo).LinkCommits(context.Background(), cpID, []checkpoint.LinkedCommit{{
+		SHA:    rewritten.String(),
+		Reason: checkpoint.LinkReasonRebase,
+	}}); err != nil {
+		t.Fatalf("LinkCommits() error = %v", err)
+	}
+
+	commits, err := getAssociatedCommits(repo, cpID, true)
+	if err != nil {
+		t.Fatalf("getAssociatedCommits() error = %v", err)
+	}
+	if len(commits) != 2 || commits[0].SHA != rewritten.String() {
+		t.Errorf("commits = %+v, want the linked commit and the original", commits)
+	}
+
+	var out bytes.Buffer
+	if err := runExplainCommit(&out, "HEAD", true, false, false, false); err != nil {
+		t.Fatalf("runExplainCommit() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "Checkpoint: "+cpID.String()) {
+		t.Errorf("explain of linked commit should show checkpoint %s:\n%s", cpID, out.String())
+	}
+}
+
 func TestGetAssociatedCommits_NoMatches(t *testing.T) {
 	tmpDir := t.TempDir()
 	t.Chdir(tmpDir)
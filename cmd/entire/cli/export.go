@@ -0,0 +1,588 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/summarize"
+	"github.com/entireio/cli/redact"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/spf13/cobra"
+)
+
+// Export formats supported by `entire export-transcript`.
+const (
+	exportFormatMarkdown = "md"
+	exportFormatHTML     = "html"
+	exportFormatJSON     = "json"
+)
+
+func newExportTranscriptCmd() *cobra.Command {
+	var formatFlag string
+	var outputFlag string
+	var fullFlag bool
+	var searchAllFlag bool
+
+	cmd := &cobra.Command{
+		Use:   "export-transcript <checkpoint>",
+		Short: "Export a checkpoint as a Markdown, HTML, or JSON document",
+		Long: `Export a committed checkpoint as a standalone document for design reviews,
+audits, tickets, and pull requests.
+
+The document contains the prompts, responses, and tool calls for each session
+in the checkpoint, the diff of each commit linked to the checkpoint, the AI
+summary, and line attribution. Secrets are redacted from all text.
+
+Output is deterministic: exporting the same checkpoint twice produces
+byte-identical documents.
+
+Formats:
+  md    Markdown (default)
+  html  Self-contained HTML page
+  json  Structured JSON`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if checkDisabledGuard(cmd.OutOrStdout()) {
+				return nil
+			}
+			if !slices.Contains([]string{exportFormatMarkdown, exportFormatHTML, exportFormatJSON}, formatFlag) {
+				return fmt.Errorf("invalid format %q: must be one of md, html, json", formatFlag)
+			}
+
+			return writeOutput(cmd.OutOrStdout(), outputFlag, func(w io.Writer) error {
+				return runExportTranscript(cmd.Context(), w, args[0], formatFlag, fullFlag, searchAllFlag)
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&formatFlag, "format", "f", exportFormatMarkdown, "Output format: md, html, or json")
+	cmd.Flags().StringVarP(&outputFlag, "output", "o", "", "Write to a file instead of stdout")
+	cmd.Flags().BoolVar(&fullFlag, "full", false, "Include the full session transcript, not just this checkpoint's portion")
+	cmd.Flags().BoolVar(&searchAllFlag, "search-all", false, "Search all commits for linked commits (no branch/depth limit, may be slow)")
+
+	return cmd
+}
+
+func runExportTranscript(ctx context.Context, w io.Writer, checkpointIDPrefix, format string, full, searchAll bool) error {
+	repo, err := openRepository()
+	if err != nil {
+		return fmt.Errorf("not a git repository: %w", err)
+	}
+
+	store := checkpoint.NewGitStore(repo)
+	checkpointID, err := resolveCommittedCheckpointPrefix(ctx, store, checkpointIDPrefix)
+	if err != nil {
+		return err
+	}
+
+	doc, err := buildTranscriptExport(ctx, repo, store, checkpointID, full, searchAll)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case exportFormatJSON:
+		return writeExportJSON(w, doc)
+	case exportFormatHTML:
+		if err := exportHTMLTemplate.Execute(w, doc); err != nil {
+			return fmt.Errorf("failed to render HTML: %w", err)
+		}
+		return nil
+	default:
+		if _, err := io.WriteString(w, formatExportMarkdown(doc)); err != nil {
+			return fmt.Errorf("failed to write markdown: %w", err)
+		}
+		return nil
+	}
+}
+
+// resolveCommittedCheckpointPrefix expands a checkpoint ID prefix to the single
+// committed checkpoint it identifies.
+func resolveCommittedCheckpointPrefix(ctx context.Context, store *checkpoint.GitStore, prefix string) (id.CheckpointID, error) {
+	if prefix == "" {
+		return id.EmptyCheckpointID, errors.New("checkpoint ID is required")
+	}
+
+	committed, err := store.ListCommitted(ctx)
+	if err != nil {
+		return id.EmptyCheckpointID, fmt.Errorf("failed to list checkpoints: %w", err)
+	}
+
+	var matches []id.CheckpointID
+	for _, info := range committed {
+		if strings.HasPrefix(info.CheckpointID.String(), prefix) {
+			matches = append(matches, info.CheckpointID)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return id.EmptyCheckpointID, fmt.Errorf("checkpoint not found: %s", prefix)
+	case 1:
+		return matches[0], nil
+	default:
+		examples := make([]string, 0, 5)
+		for i := 0; i < len(matches) && i < 5; i++ {
+			examples = append(examples, matches[i].String())
+		}
+		return id.EmptyCheckpointID, fmt.Errorf("ambiguous checkpoint prefix %q matches %d checkpoints: %s", prefix, len(matches), strings.Join(examples, ", "))
+	}
+}
+
+// transcriptExport is the document produced by `entire export-transcript`.
+// All free-form text has been passed through the redactor.
+type transcriptExport struct {
+	CheckpointID   string          `json:"checkpoint_id"`
+	Strategy       string          `json:"strategy,omitempty"`
+	Branch         string          `json:"branch,omitempty"`
+	Author         string          `json:"author,omitempty"`
+	FullTranscript bool            `json:"full_transcript"`
+	FilesTouched   []string        `json:"files_touched"`
+	Sessions       []exportSession `json:"sessions"`
+	Commits        []exportCommit  `json:"commits"`
+}
+
+// exportSession is one agent session within the exported checkpoint.
+type exportSession struct {
+	SessionID    string                         `json:"session_id"`
+	Agent        string                         `json:"agent,omitempty"`
+	CreatedAt    time.Time                      `json:"created_at"`
+	FilesTouched []string                       `json:"files_touched"`
+	Summary      *checkpoint.Summary            `json:"summary,omitempty"`
+	Attribution  *checkpoint.InitialAttribution `json:"attribution,omitempty"`
+	Transcript   []exportEntry                  `json:"transcript"`
+}
+
+// exportEntry is one prompt, response, or tool call.
+type exportEntry struct {
+	Type    summarize.EntryType `json:"type"`
+	Content string              `json:"content,omitempty"`
+	Tool    string              `json:"tool,omitempty"`
+	Detail  string              `json:"detail,omitempty"`
+}
+
+// exportCommit is a commit linked to the checkpoint and its diff.
+type exportCommit struct {
+	SHA     string    `json:"sha"`
+	Message string    `json:"message"`
+	Author  string    `json:"author"`
+	Date    time.Time `json:"date"`
+	Diff    string    `json:"diff"`
+}
+
+// buildTranscriptExport gathers and redacts everything recorded for a checkpoint.
+// Sessions appear in storage order and commits oldest first so output is stable.
+func buildTranscriptExport(ctx context.Context, repo *git.Repository, store *checkpoint.GitStore, checkpointID id.CheckpointID, full, searchAll bool) (*transcriptExport, error) {
+	summary, err := store.ReadCommitted(ctx, checkpointID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+	if summary == nil {
+		return nil, fmt.Errorf("checkpoint not found: %s", checkpointID)
+	}
+
+	doc := &transcriptExport{
+		CheckpointID:   checkpointID.String(),
+		Strategy:       summary.Strategy,
+		Branch:         summary.Branch,
+		FullTranscript: full,
+		FilesTouched:   nonNilStrings(summary.FilesTouched),
+		Sessions:       make([]exportSession, 0, len(summary.Sessions)),
+		Commits:        []exportCommit{},
+	}
+
+	//nolint:errcheck // Author is optional
+	if author, _ := store.GetCheckpointAuthor(ctx, checkpointID); author.Name != "" {
+		doc.Author = author.Name
+		if author.Email != "" {
+			doc.Author += " <" + author.Email + ">"
+		}
+	}
+
+	for i := range summary.Sessions {
+		content, readErr := store.ReadSessionContent(ctx, checkpointID, i)
+		if readErr != nil {
+			return nil, fmt.Errorf("failed to read session %d of checkpoint %s: %w", i, checkpointID, readErr)
+		}
+		doc.Sessions = append(doc.Sessions, newExportSession(content, full))
+	}
+
+	commits, err := getAssociatedCommits(repo, checkpointID, searchAll)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find commits for checkpoint: %w", err)
+	}
+	// getAssociatedCommits walks from HEAD backwards; present commits in the order they were made.
+	for i := len(commits) - 1; i >= 0; i-- {
+		c := commits[i]
+		patch, patchErr := commitPatch(repo, plumbing.NewHash(c.SHA))
+		if patchErr != nil {
+			patch = fmt.Sprintf("(failed to compute diff: %v)", patchErr)
+		}
+		doc.Commits = append(doc.Commits, exportCommit{
+			SHA:     c.SHA,
+			Message: redact.String(c.Message),
+			Author:  c.Author,
+			Date:    c.Date.UTC(),
+			Diff:    redact.String(patch),
+		})
+	}
+
+	return doc, nil
+}
+
+func newExportSession(content *checkpoint.SessionContent, full bool) exportSession {
+	meta := content.Metadata
+	session := exportSession{
+		SessionID:    meta.SessionID,
+		Agent:        string(meta.Agent),
+		CreatedAt:    meta.CreatedAt.UTC(),
+		FilesTouched: nonNilStrings(meta.FilesTouched),
+		Summary:      redactSummary(meta.Summary),
+		Attribution:  meta.InitialAttribution,
+		Transcript:   []exportEntry{},
+	}
+
+	transcript := content.Transcript
+	if !full {
+		transcript = scopeTranscriptForCheckpoint(transcript, meta.GetTranscriptStart(), meta.Agent)
+	}
+	if len(transcript) > 0 {
+		if entries, err := summarize.BuildCondensedTranscriptFromBytes(transcript, meta.Agent); err == nil {
+			for _, e := range entries {
+				session.Transcript = append(session.Transcript, exportEntry{
+					Type:    e.Type,
+					Content: redact.String(e.Content),
+					Tool:    e.ToolName,
+					Detail:  redact.String(e.ToolDetail),
+				})
+			}
+		}
+	}
+
+	// Older checkpoints may lack a parseable transcript; fall back to the stored prompts.
+	if len(session.Transcript) == 0 {
+		for _, prompt := range splitPrompts(content.Prompts) {
+			session.Transcript = append(session.Transcript, exportEntry{
+				Type:    summarize.EntryTypeUser,
+				Content: redact.String(prompt),
+			})
+		}
+	}
+
+	return session
+}
+
+// redactSummary returns a copy of summary with secrets removed from every field.
+func redactSummary(summary *checkpoint.Summary) *checkpoint.Summary {
+	if summary == nil {
+		return nil
+	}
+	redactAll := func(items []string) []string {
+		out := make([]string, len(items))
+		for i, item := range items {
+			out[i] = redact.String(item)
+		}
+		return out
+	}
+
+	redacted := &checkpoint.Summary{
+		Intent:    redact.String(summary.Intent),
+		Outcome:   redact.String(summary.Outcome),
+		Friction:  redactAll(summary.Friction),
+		OpenItems: redactAll(summary.OpenItems),
+		Learnings: checkpoint.LearningsSummary{
+			Repo:     redactAll(summary.Learnings.Repo),
+			Workflow: redactAll(summary.Learnings.Workflow),
+			Code:     make([]checkpoint.CodeLearning, len(summary.Learnings.Code)),
+		},
+	}
+	for i, l := range summary.Learnings.Code {
+		l.Finding = redact.String(l.Finding)
+		redacted.Learnings.Code[i] = l
+	}
+	if summary.Extensions != nil {
+		redacted.Extensions = make(map[string]any, len(summary.Extensions))
+		for name, value := range summary.Extensions {
+			redacted.Extensions[name] = redactExtensionValue(value)
+		}
+	}
+	return redacted
+}
+
+// redactExtensionValue redacts the strings in a summary extension value,
+// which template normalization leaves as a string or a list of strings.
+func redactExtensionValue(value any) any {
+	switch v := value.(type) {
+	case string:
+		return redact.String(v)
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = redactExtensionValue(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+func nonNilStrings(items []string) []string {
+	if items == nil {
+		return []string{}
+	}
+	return items
+}
+
+func writeExportJSON(w io.Writer, doc *transcriptExport) error {
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode export: %w", err)
+	}
+	data = append(data, '\n')
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	return nil
+}
+
+// formatExportMarkdown renders the export as a Markdown document.
+func formatExportMarkdown(doc *transcriptExport) string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "# Checkpoint %s\n\n", doc.CheckpointID)
+	if doc.Branch != "" {
+		fmt.Fprintf(&sb, "- **Branch:** %s\n", doc.Branch)
+	}
+	if doc.Strategy != "" {
+		fmt.Fprintf(&sb, "- **Strategy:** %s\n", doc.Strategy)
+	}
+	if doc.Author != "" {
+		fmt.Fprintf(&sb, "- **Author:** %s\n", doc.Author)
+	}
+	fmt.Fprintf(&sb, "- **Sessions:** %d\n", len(doc.Sessions))
+	if len(doc.FilesTouched) > 0 {
+		sb.WriteString("- **Files:**\n")
+		for _, f := range doc.FilesTouched {
+			fmt.Fprintf(&sb, "  - `%s`\n", f)
+		}
+	}
+
+	for _, s := range doc.Sessions {
+		fmt.Fprintf(&sb, "\n## Session %s\n\n", s.SessionID)
+		if s.Agent != "" {
+			fmt.Fprintf(&sb, "- **Agent:** %s\n", s.Agent)
+		}
+		if !s.CreatedAt.IsZero() {
+			fmt.Fprintf(&sb, "- **Created:** %s\n", s.CreatedAt.Format(time.RFC3339))
+		}
+
+		if s.Summary != nil {
+			sb.WriteString("\n### Summary\n\n")
+			fmt.Fprintf(&sb, "**Intent:** %s\n\n", s.Summary.Intent)
+			fmt.Fprintf(&sb, "**Outcome:** %s\n", s.Summary.Outcome)
+			writeMarkdownList(&sb, "Repository learnings", s.Summary.Learnings.Repo)
+			if len(s.Summary.Learnings.Code) > 0 {
+				sb.WriteString("\n**Code learnings:**\n\n")
+				for _, l := range s.Summary.Learnings.Code {
+					fmt.Fprintf(&sb, "- `%s`: %s\n", codeLearningLocation(l), l.Finding)
+				}
+			}
+			writeMarkdownList(&sb, "Workflow learnings", s.Summary.Learnings.Workflow)
+			writeMarkdownList(&sb, "Friction", s.Summary.Friction)
+			writeMarkdownList(&sb, "Open items", s.Summary.OpenItems)
+			for _, ext := range summaryExtensions(s.Summary.Extensions) {
+				writeMarkdownList(&sb, ext.Label, ext.Values)
+			}
+		}
+
+		if a := s.Attribution; a != nil {
+			sb.WriteString("\n### Attribution\n\n")
+			sb.WriteString("| Agent lines | Human added | Human modified | Human removed | Total committed | Agent % |\n")
+			sb.WriteString("| --- | --- | --- | --- | --- | --- |\n")
+			fmt.Fprintf(&sb, "| %d | %d | %d | %d | %d | %.1f%% |\n",
+				a.AgentLines, a.HumanAdded, a.HumanModified, a.HumanRemoved, a.TotalCommitted, a.AgentPercentage)
+		}
+
+		if doc.FullTranscript {
+			sb.WriteString("\n### Transcript (full session)\n\n")
+		} else {
+			sb.WriteString("\n### Transcript\n\n")
+		}
+		if len(s.Transcript) == 0 {
+			sb.WriteString("_No transcript recorded._\n")
+		}
+		for _, e := range s.Transcript {
+			switch e.Type {
+			case summarize.EntryTypeUser:
+				sb.WriteString("**User:**\n\n")
+				writeMarkdownQuote(&sb, e.Content)
+			case summarize.EntryTypeAssistant:
+				sb.WriteString("**Assistant:**\n\n")
+				sb.WriteString(strings.TrimSpace(e.Content))
+				sb.WriteString("\n\n")
+			case summarize.EntryTypeTool:
+				if e.Detail != "" {
+					fmt.Fprintf(&sb, "- Tool `%s`: %s\n\n", e.Tool, oneLine(e.Detail))
+				} else {
+					fmt.Fprintf(&sb, "- Tool `%s`\n\n", e.Tool)
+				}
+			}
+		}
+	}
+
+	sb.WriteString("\n## Commits\n\n")
+	if len(doc.Commits) == 0 {
+		sb.WriteString("_No linked commits found._\n")
+	}
+	for _, c := range doc.Commits {
+		fmt.Fprintf(&sb, "### %s %s\n\n", shortSHA(c.SHA), c.Message)
+		fmt.Fprintf(&sb, "%s, %s\n\n", c.Author, c.Date.Format(time.RFC3339))
+		fence := markdownFence(c.Diff)
+		fmt.Fprintf(&sb, "%sdiff\n%s\n%s\n\n", fence, strings.TrimSuffix(c.Diff, "\n"), fence)
+	}
+
+	return strings.TrimRight(sb.String(), "\n") + "\n"
+}
+
+func writeMarkdownList(sb *strings.Builder, title string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+	fmt.Fprintf(sb, "\n**%s:**\n\n", title)
+	for _, item := range items {
+		fmt.Fprintf(sb, "- %s\n", oneLine(item))
+	}
+}
+
+func writeMarkdownQuote(sb *strings.Builder, text string) {
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		if line == "" {
+			sb.WriteString(">\n")
+		} else {
+			sb.WriteString("> " + line + "\n")
+		}
+	}
+	sb.WriteString("\n")
+}
+
+// markdownFence returns a code fence longer than any backtick run in content,
+// so diffs containing ``` can't terminate the block early.
+func markdownFence(content string) string {
+	longest, run := 0, 0
+	for _, r := range content {
+		if r == '`' {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	return strings.Repeat("`", max(3, longest+1))
+}
+
+func codeLearningLocation(l checkpoint.CodeLearning) string {
+	switch {
+	case l.Line > 0 && l.EndLine > 0:
+		return fmt.Sprintf("%s:%d-%d", l.Path, l.Line, l.EndLine)
+	case l.Line > 0:
+		return fmt.Sprintf("%s:%d", l.Path, l.Line)
+	default:
+		return l.Path
+	}
+}
+
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+var exportHTMLTemplate = template.Must(template.New("export").Funcs(serveTemplateFuncs).Funcs(template.FuncMap{
+	"rfc3339":      func(t time.Time) string { return t.Format(time.RFC3339) },
+	"codeLocation": codeLearningLocation,
+	"shortSHA":     shortSHA,
+}).Parse(serveStyles + exportHTMLPage))
+
+const exportHTMLPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Checkpoint {{.CheckpointID}} · Entire</title>
+<style>
+{{template "styles"}}
+</style>
+</head>
+<body>
+<main>
+<h1>Checkpoint <code>{{.CheckpointID}}</code></h1>
+<dl>
+{{with .Branch}}<dt>Branch</dt><dd>{{.}}</dd>{{end}}
+{{with .Strategy}}<dt>Strategy</dt><dd>{{.}}</dd>{{end}}
+{{with .Author}}<dt>Author</dt><dd>{{.}}</dd>{{end}}
+<dt>Sessions</dt><dd>{{len .Sessions}}</dd>
+{{if .FilesTouched}}<dt>Files</dt><dd>{{range .FilesTouched}}<code>{{.}}</code><br>{{end}}</dd>{{end}}
+</dl>
+{{$full := .FullTranscript}}
+{{range .Sessions}}
+<h2>Session <code>{{.SessionID}}</code></h2>
+<dl>
+{{with .Agent}}<dt>Agent</dt><dd>{{.}}</dd>{{end}}
+{{if not .CreatedAt.IsZero}}<dt>Created</dt><dd>{{rfc3339 .CreatedAt}}</dd>{{end}}
+</dl>
+{{with .Summary}}
+<h3>Summary</h3>
+<dl>
+<dt>Intent</dt><dd>{{.Intent}}</dd>
+<dt>Outcome</dt><dd>{{.Outcome}}</dd>
+</dl>
+{{with .Learnings.Repo}}<h4>Repository learnings</h4><ul>{{range .}}<li>{{.}}</li>{{end}}</ul>{{end}}
+{{with .Learnings.Code}}<h4>Code learnings</h4><ul>{{range .}}<li><code>{{codeLocation .}}</code>: {{.Finding}}</li>{{end}}</ul>{{end}}
+{{with .Learnings.Workflow}}<h4>Workflow learnings</h4><ul>{{range .}}<li>{{.}}</li>{{end}}</ul>{{end}}
+{{with .Friction}}<h4>Friction</h4><ul>{{range .}}<li>{{.}}</li>{{end}}</ul>{{end}}
+{{with .OpenItems}}<h4>Open items</h4><ul>{{range .}}<li>{{.}}</li>{{end}}</ul>{{end}}
+{{range summaryExtensions .Extensions}}<h4>{{.Label}}</h4><ul>{{range .Values}}<li>{{.}}</li>{{end}}</ul>{{end}}
+{{end}}
+{{with .Attribution}}
+<h3>Attribution</h3>
+<table>
+<tr><th>Agent lines</th><th>Human added</th><th>Human modified</th><th>Human removed</th><th>Total committed</th><th>Agent %</th></tr>
+<tr><td>{{.AgentLines}}</td><td>{{.HumanAdded}}</td><td>{{.HumanModified}}</td><td>{{.HumanRemoved}}</td><td>{{.TotalCommitted}}</td><td>{{printf "%.1f" .AgentPercentage}}%</td></tr>
+</table>
+{{end}}
+<h3>Transcript{{if $full}} (full session){{end}}</h3>
+{{range .Transcript}}
+{{if eq .Type "tool"}}<details class="tool"><summary>{{.Tool}}{{with .Detail}}: {{truncate . 120}}{{end}}</summary><pre>{{.Detail}}</pre></details>
+{{else}}<div class="entry entry-{{.Type}}">{{.Content}}</div>
+{{end}}
+{{else}}
+<p class="muted">No transcript recorded.</p>
+{{end}}
+{{end}}
+<h2>Commits</h2>
+{{range .Commits}}
+<h3><code>{{shortSHA .SHA}}</code> {{.Message}}</h3>
+<p class="muted">{{.Author}}, {{rfc3339 .Date}}</p>
+<pre class="diff">{{range diffLines .Diff}}<span{{with diffClass .}} class="{{.}}"{{end}}>{{.}}</span>{{end}}</pre>
+{{else}}
+<p class="muted">No linked commits found.</p>
+{{end}}
+</main>
+</body>
+</html>
+`
@@ -0,0 +1,191 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/agent"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/summarize"
+)
+
+// exportTestSecret has high enough entropy to be caught by the redactor.
+const exportTestSecret = "sk-ant-REDACTED"
+
+func TestNewExportTranscriptCmd(t *testing.T) {
+	t.Parallel()
+
+	cmd := newExportTranscriptCmd()
+	if err := cmd.Args(cmd, []string{}); err == nil {
+		t.Error("expected a checkpoint argument to be required")
+	}
+	formatFlag := cmd.Flags().Lookup("format")
+	if formatFlag == nil || formatFlag.DefValue != exportFormatMarkdown {
+		t.Errorf("--format default should be %q", exportFormatMarkdown)
+	}
+}
+
+func TestExportTranscript_Markdown(t *testing.T) {
+	_, cpID := setupServeTestRepo(t)
+
+	var out bytes.Buffer
+	if err := runExportTranscript(context.Background(), &out, cpID.String()[:6], exportFormatMarkdown, false, false); err != nil {
+		t.Fatalf("runExportTranscript() error = %v", err)
+	}
+	md := out.String()
+
+	for _, want := range []string{
+		"# Checkpoint " + cpID.String(),
+		"## Session 2026-01-01-serve-session",
+		"**Intent:** Add a greeting file",
+		"- `hello.txt:1`: Greeting lives here",
+		"**Test Gaps:**\n\n- No test for the greeting",
+		"| 1 | 0 | 0 | 0 | 1 | 100.0% |",
+		"> Add a greeting file",
+		"I'll create hello.txt.",
+		"- Tool `Write`: hello.txt",
+		"Add greeting",
+		"+hello world",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+}
+
+func TestExportTranscript_Deterministic(t *testing.T) {
+	_, cpID := setupServeTestRepo(t)
+
+	for _, format := range []string{exportFormatMarkdown, exportFormatHTML, exportFormatJSON} {
+		var first, second bytes.Buffer
+		if err := runExportTranscript(context.Background(), &first, cpID.String(), format, false, false); err != nil {
+			t.Fatalf("%s export error = %v", format, err)
+		}
+		if err := runExportTranscript(context.Background(), &second, cpID.String(), format, false, false); err != nil {
+			t.Fatalf("%s export error = %v", format, err)
+		}
+		if first.String() != second.String() {
+			t.Errorf("%s export is not deterministic", format)
+		}
+	}
+}
+
+func TestExportTranscript_HTMLAndJSON(t *testing.T) {
+	_, cpID := setupServeTestRepo(t)
+
+	var html bytes.Buffer
+	if err := runExportTranscript(context.Background(), &html, cpID.String(), exportFormatHTML, false, false); err != nil {
+		t.Fatalf("html export error = %v", err)
+	}
+	for _, want := range []string{
+		"<!DOCTYPE html>",
+		"<dt>Intent</dt><dd>Add a greeting file</dd>",
+		"<h4>Test Gaps</h4><ul><li>No test for the greeting</li></ul>",
+		`<span class="diff-add">&#43;hello world</span>`,
+	} {
+		if !strings.Contains(html.String(), want) {
+			t.Errorf("html missing %q", want)
+		}
+	}
+	if strings.Contains(html.String(), `href="/`) {
+		t.Error("standalone html should not link back to the local server")
+	}
+
+	var out bytes.Buffer
+	if err := runExportTranscript(context.Background(), &out, cpID.String(), exportFormatJSON, false, false); err != nil {
+		t.Fatalf("json export error = %v", err)
+	}
+	var doc transcriptExport
+	if err := json.Unmarshal(out.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if doc.CheckpointID != cpID.String() || len(doc.Sessions) != 1 || len(doc.Commits) != 1 {
+		t.Fatalf("unexpected document: %+v", doc)
+	}
+	if got := doc.Sessions[0].Transcript; len(got) != 3 || got[2].Tool != "Write" {
+		t.Errorf("transcript = %+v, want user, assistant, Write tool", got)
+	}
+}
+
+func TestExportTranscript_NotFound(t *testing.T) {
+	setupServeTestRepo(t)
+
+	var out bytes.Buffer
+	err := runExportTranscript(context.Background(), &out, "ffffff", exportFormatMarkdown, false, false)
+	if err == nil || !strings.Contains(err.Error(), "checkpoint not found") {
+		t.Errorf("expected checkpoint not found error, got %v", err)
+	}
+}
+
+func TestNewExportSession_Redacts(t *testing.T) {
+	t.Parallel()
+
+	transcript := `{"type":"user","uuid":"u1","message":{"content":"use key ` + exportTestSecret + `"}}
+{"type":"assistant","uuid":"a1","message":{"content":[{"type":"text","text":"Stored ` + exportTestSecret + `"}]}}
+`
+	content := &checkpoint.SessionContent{
+		Metadata: checkpoint.CommittedMetadata{
+			SessionID: "s1",
+			Agent:     agent.AgentTypeClaudeCode,
+			Summary: &checkpoint.Summary{
+				Intent:    "Configure " + exportTestSecret,
+				Learnings: checkpoint.LearningsSummary{Code: []checkpoint.CodeLearning{{Path: "a.go", Finding: exportTestSecret}}},
+				Extensions: map[string]any{
+					"risk":      "Rotate " + exportTestSecret,
+					"test_gaps": []any{"No test for " + exportTestSecret},
+				},
+			},
+		},
+		Transcript: []byte(transcript),
+	}
+
+	session := newExportSession(content, false)
+	encoded, err := json.Marshal(session)
+	if err != nil {
+		t.Fatalf("failed to marshal session: %v", err)
+	}
+	if strings.Contains(string(encoded), exportTestSecret) {
+		t.Errorf("export leaked secret: %s", encoded)
+	}
+	if len(session.Transcript) != 2 || session.Transcript[0].Type != summarize.EntryTypeUser {
+		t.Errorf("transcript = %+v, want user and assistant entries", session.Transcript)
+	}
+	if gaps, ok := session.Summary.Extensions["test_gaps"].([]any); !ok || len(gaps) != 1 {
+		t.Errorf("extensions = %v, want test_gaps kept", session.Summary.Extensions)
+	}
+	if content.Metadata.Summary.Intent != "Configure "+exportTestSecret {
+		t.Error("redaction must not modify the stored summary")
+	}
+}
+
+func TestNewExportSession_FallsBackToPrompts(t *testing.T) {
+	t.Parallel()
+
+	content := &checkpoint.SessionContent{
+		Metadata: checkpoint.CommittedMetadata{SessionID: "s1"},
+		Prompts:  "first prompt\n\n---\n\nsecond prompt",
+	}
+	session := newExportSession(content, false)
+	if len(session.Transcript) != 2 || session.Transcript[1].Content != "second prompt" {
+		t.Errorf("transcript = %+v, want both stored prompts", session.Transcript)
+	}
+}
+
+func TestMarkdownFence(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"plain":             "```",
+		"has ``` inside":    "````",
+		"has ````` inside":  "``````",
+		"single ` backtick": "```",
+	}
+	for content, want := range tests {
+		if got := markdownFence(content); got != want {
+			t.Errorf("markdownFence(%q) = %q, want %q", content, got, want)
+		}
+	}
+}
@@ -0,0 +1,455 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/agent"
+	"github.com/entireio/cli/cmd/entire/cli/agent/claudecode"
+	"github.com/entireio/cli/cmd/entire/cli/agent/geminicli"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+	"github.com/entireio/cli/cmd/entire/cli/transcript"
+	"github.com/entireio/cli/redact"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// maxHandoffPrompts is the number of most recent prompts included in a handoff.
+	maxHandoffPrompts = 20
+	// maxHandoffPromptLength truncates long prompts, matching context.md.
+	maxHandoffPromptLength = 500
+	// maxHandoffFailedCommands is the number of most recent failed commands included.
+	maxHandoffFailedCommands = 10
+	// maxHandoffDiffBytes bounds the uncommitted diff embedded in the handoff.
+	maxHandoffDiffBytes = 50 * 1024
+)
+
+func newHandoffCmd() *cobra.Command {
+	var outputFlag string
+
+	cmd := &cobra.Command{
+		Use:   "handoff [session]",
+		Short: "Write a handoff document so the next session can pick up where this one left off",
+		Long: `Generate a handoff document for a session and install it where the agent
+loads project memory, so a fresh session starts with the previous one's context.
+
+The document includes what was attempted, the AI summaries' outcomes, open items
+and learnings, commands that failed during the session, the current state of the
+files the session touched, and their uncommitted changes. Secrets are redacted.
+
+The session defaults to the most recently active session in this worktree.
+A session ID prefix is accepted.
+
+Where the handoff is written:
+  Claude Code  .claude/entire-handoff.md, imported from CLAUDE.local.md
+  Gemini CLI   .gemini/entire-handoff.md, imported from GEMINI.md
+
+Use --output to write the document somewhere else instead (- for stdout).`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if checkDisabledGuard(cmd.OutOrStdout()) {
+				return nil
+			}
+			sessionArg := ""
+			if len(args) > 0 {
+				sessionArg = args[0]
+			}
+			return runHandoff(cmd.Context(), cmd.OutOrStdout(), sessionArg, outputFlag)
+		},
+	}
+
+	cmd.Flags().StringVarP(&outputFlag, "output", "o", "", "Write the handoff to this file (- for stdout) instead of the agent's memory")
+
+	return cmd
+}
+
+func runHandoff(ctx context.Context, w io.Writer, sessionArg, output string) error {
+	repo, err := openRepository()
+	if err != nil {
+		return fmt.Errorf("not a git repository: %w", err)
+	}
+	store := checkpoint.NewGitStore(repo)
+
+	sessionID, state, err := resolveSession(ctx, store, sessionArg)
+	if err != nil {
+		return err
+	}
+
+	data, err := buildHandoff(ctx, repo, store, sessionID, state)
+	if err != nil {
+		return err
+	}
+	content := []byte(redact.String(formatHandoffMarkdown(data)))
+
+	switch output {
+	case "-":
+		if _, err := w.Write(content); err != nil {
+			return fmt.Errorf("failed to write handoff: %w", err)
+		}
+		return nil
+	case "":
+		// Install into the agent's memory below.
+	default:
+		if err := os.WriteFile(output, content, 0o600); err != nil {
+			return fmt.Errorf("failed to write handoff: %w", err)
+		}
+		fmt.Fprintf(w, "✓ Handoff for session %s written to %s\n", sessionID, output)
+		return nil
+	}
+
+	ag, err := agent.GetByAgentType(data.Agent)
+	if err != nil {
+		ag = agent.Default()
+	}
+	contextAgent, ok := ag.(agent.ContextFileSupport)
+	if !ok {
+		return fmt.Errorf("%s does not load project memory files; use --output to write the handoff to a file", ag.Type())
+	}
+	path, importedFrom, err := contextAgent.WriteHandoffContext(content)
+	if err != nil {
+		return fmt.Errorf("failed to write handoff: %w", err)
+	}
+	fmt.Fprintf(w, "✓ Handoff for session %s written to %s (imported from %s)\n", sessionID, path, importedFrom)
+	fmt.Fprintf(w, "  The next %s session will load it automatically.\n", ag.Type())
+	return nil
+}
+
+// handoffData is everything known about a session at handoff time.
+type handoffData struct {
+	SessionID   string
+	Agent       agent.AgentType
+	StartedAt   time.Time
+	Branch      string
+	Checkpoints []string
+
+	Prompts        []string
+	OmittedPrompts int
+
+	Intents           []string
+	Outcomes          []string
+	OpenItems         []string
+	Friction          []string
+	RepoLearnings     []string
+	CodeLearnings     []checkpoint.CodeLearning
+	WorkflowLearnings []string
+
+	FailedCommands []agent.FailedCommand
+	Files          []handoffFile
+
+	Diff          string
+	DiffTruncated bool
+}
+
+// handoffFile is a file the session touched and its current working tree status.
+type handoffFile struct {
+	Path   string
+	Status string
+}
+
+func buildHandoff(ctx context.Context, repo *git.Repository, store *checkpoint.GitStore, sessionID string, state *strategy.SessionState) (*handoffData, error) {
+	data := &handoffData{
+		SessionID: sessionID,
+		Branch:    strategy.GetCurrentBranchName(repo),
+	}
+
+	type committedSession struct {
+		cpID    id.CheckpointID
+		content *checkpoint.SessionContent
+	}
+	var committed []committedSession
+	if err := forEachCommittedSession(ctx, store, func(cpID id.CheckpointID, content *checkpoint.SessionContent) error {
+		if content.Metadata.SessionID == sessionID {
+			committed = append(committed, committedSession{cpID: cpID, content: content})
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	// Scan order is most recent first; the handoff reads chronologically.
+	slices.Reverse(committed)
+
+	intents, outcomes, openItems, friction := newOrderedSet(), newOrderedSet(), newOrderedSet(), newOrderedSet()
+	repoLearnings, workflowLearnings := newOrderedSet(), newOrderedSet()
+	files := newOrderedSet()
+	var storedPrompts []string
+
+	for _, c := range committed {
+		meta := c.content.Metadata
+		data.Checkpoints = append(data.Checkpoints, c.cpID.String())
+		if data.Agent == "" {
+			data.Agent = meta.Agent
+		}
+		if data.StartedAt.IsZero() || (!meta.CreatedAt.IsZero() && meta.CreatedAt.Before(data.StartedAt)) {
+			data.StartedAt = meta.CreatedAt
+		}
+		files.add(meta.FilesTouched...)
+		storedPrompts = append(storedPrompts, splitPrompts(c.content.Prompts)...)
+		if s := meta.Summary; s != nil {
+			intents.add(s.Intent)
+			outcomes.add(s.Outcome)
+			openItems.add(s.OpenItems...)
+			friction.add(s.Friction...)
+			repoLearnings.add(s.Learnings.Repo...)
+			workflowLearnings.add(s.Learnings.Workflow...)
+			data.CodeLearnings = append(data.CodeLearnings, s.Learnings.Code...)
+		}
+	}
+
+	var transcriptBytes []byte
+	if state != nil {
+		if state.AgentType != "" {
+			data.Agent = state.AgentType
+		}
+		if !state.StartedAt.IsZero() {
+			data.StartedAt = state.StartedAt
+		}
+		files.add(state.FilesTouched...)
+		if state.TranscriptPath != "" {
+			//nolint:errcheck // The live transcript may have been cleaned up; committed data still applies
+			transcriptBytes, _ = os.ReadFile(state.TranscriptPath)
+		}
+	}
+	if len(transcriptBytes) == 0 && len(committed) > 0 {
+		latest := committed[len(committed)-1].cpID
+		if content, err := store.ReadSessionContentByID(ctx, latest, sessionID); err == nil {
+			transcriptBytes = content.Transcript
+		}
+	}
+
+	prompts := extractPromptsFromTranscript(transcriptBytes, data.Agent)
+	if len(prompts) == 0 {
+		prompts = storedPrompts
+	}
+	if len(prompts) > maxHandoffPrompts {
+		data.OmittedPrompts = len(prompts) - maxHandoffPrompts
+		prompts = prompts[len(prompts)-maxHandoffPrompts:]
+	}
+	data.Prompts = prompts
+
+	failed := extractFailedCommands(transcriptBytes, data.Agent)
+	if len(failed) > maxHandoffFailedCommands {
+		failed = failed[len(failed)-maxHandoffFailedCommands:]
+	}
+	data.FailedCommands = failed
+
+	data.Intents = intents.items
+	data.Outcomes = outcomes.items
+	data.OpenItems = openItems.items
+	data.Friction = friction.items
+	data.RepoLearnings = repoLearnings.items
+	data.WorkflowLearnings = workflowLearnings.items
+
+	touched := files.items
+	sort.Strings(touched)
+	if len(touched) > 0 {
+		data.Files = handoffFileStatuses(ctx, touched)
+		data.Diff, data.DiffTruncated = uncommittedDiff(ctx, touched)
+	}
+
+	return data, nil
+}
+
+// extractFailedCommands finds shell commands that failed in an agent transcript.
+func extractFailedCommands(transcriptBytes []byte, agentType agent.AgentType) []agent.FailedCommand {
+	if len(transcriptBytes) == 0 {
+		return nil
+	}
+	switch agentType {
+	case agent.AgentTypeGemini:
+		parsed, err := geminicli.ParseTranscript(transcriptBytes)
+		if err != nil {
+			return nil
+		}
+		return geminicli.ExtractFailedCommands(parsed)
+	default:
+		lines, err := transcript.ParseFromBytes(transcriptBytes)
+		if err != nil {
+			return nil
+		}
+		return claudecode.ExtractFailedCommands(lines)
+	}
+}
+
+// handoffFileStatuses reports the working tree status of each file.
+// Uses git CLI for the same reason as HasUncommittedChanges (global gitignore support).
+func handoffFileStatuses(ctx context.Context, files []string) []handoffFile {
+	statuses := make(map[string]string, len(files))
+
+	args := append([]string{"status", "--porcelain", "-z", "--"}, files...)
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = paths.RepoRootOr(".")
+	if out, err := cmd.Output(); err == nil {
+		entries := strings.Split(string(out), "\x00")
+		for i := 0; i < len(entries); i++ {
+			entry := entries[i]
+			if len(entry) < 4 {
+				continue
+			}
+			code, path := entry[:2], entry[3:]
+			statuses[path] = describePorcelainStatus(code)
+			if code[0] == 'R' || code[0] == 'C' {
+				i++ // Skip the rename source
+			}
+		}
+	}
+
+	root := paths.RepoRootOr(".")
+	result := make([]handoffFile, 0, len(files))
+	for _, f := range files {
+		status, ok := statuses[f]
+		if !ok {
+			if _, err := os.Stat(filepath.Join(root, f)); err != nil {
+				status = "deleted (committed)"
+			} else {
+				status = "unchanged since last commit"
+			}
+		}
+		result = append(result, handoffFile{Path: f, Status: status})
+	}
+	return result
+}
+
+func describePorcelainStatus(code string) string {
+	switch {
+	case code == "??":
+		return "untracked"
+	case strings.Contains(code, "D"):
+		return "deleted (uncommitted)"
+	case strings.Contains(code, "A"):
+		return "added (uncommitted)"
+	case strings.Contains(code, "R"):
+		return "renamed (uncommitted)"
+	case strings.Contains(code, "U"):
+		return "merge conflict"
+	default:
+		return "modified (uncommitted)"
+	}
+}
+
+// uncommittedDiff returns `git diff HEAD` for the given files, truncated to a bounded size.
+func uncommittedDiff(ctx context.Context, files []string) (string, bool) {
+	args := append([]string{"diff", "--no-color", "HEAD", "--"}, files...)
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = paths.RepoRootOr(".")
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	if err := cmd.Run(); err != nil {
+		return "", false // No HEAD yet, or git unavailable: omit the section
+	}
+	return truncateDiff(out.String(), maxHandoffDiffBytes)
+}
+
+// formatHandoffMarkdown renders the handoff document.
+func formatHandoffMarkdown(data *handoffData) string {
+	var sb strings.Builder
+
+	sb.WriteString("# Session handoff\n\n")
+	fmt.Fprintf(&sb, "Generated by `entire handoff` from session `%s`. It records where that session left off; "+
+		"verify details against the code before relying on them.\n\n", data.SessionID)
+
+	if data.Agent != "" {
+		fmt.Fprintf(&sb, "- **Agent:** %s\n", data.Agent)
+	}
+	if !data.StartedAt.IsZero() {
+		fmt.Fprintf(&sb, "- **Started:** %s\n", data.StartedAt.Format(time.RFC3339))
+	}
+	if data.Branch != "" {
+		fmt.Fprintf(&sb, "- **Branch:** %s\n", data.Branch)
+	}
+	if len(data.Checkpoints) > 0 {
+		fmt.Fprintf(&sb, "- **Checkpoints:** %s\n", strings.Join(data.Checkpoints, ", "))
+	}
+
+	sb.WriteString("\n## What was attempted\n\n")
+	for _, intent := range data.Intents {
+		fmt.Fprintf(&sb, "- %s\n", oneLine(intent))
+	}
+	if len(data.Intents) > 0 && len(data.Prompts) > 0 {
+		sb.WriteString("\n")
+	}
+	if len(data.Prompts) > 0 {
+		if data.OmittedPrompts > 0 {
+			fmt.Fprintf(&sb, "Prompts (last %d of %d):\n\n", len(data.Prompts), len(data.Prompts)+data.OmittedPrompts)
+		} else {
+			sb.WriteString("Prompts:\n\n")
+		}
+		for i, p := range data.Prompts {
+			fmt.Fprintf(&sb, "%d. %s\n", data.OmittedPrompts+i+1, strategy.TruncateDescription(oneLine(p), maxHandoffPromptLength))
+		}
+	}
+	if len(data.Intents) == 0 && len(data.Prompts) == 0 {
+		sb.WriteString("_No prompts recorded._\n")
+	}
+
+	writeMarkdownSection(&sb, "Outcomes so far", data.Outcomes)
+	writeMarkdownSection(&sb, "Open items", data.OpenItems)
+	writeMarkdownSection(&sb, "Friction", data.Friction)
+
+	if len(data.RepoLearnings) > 0 || len(data.CodeLearnings) > 0 || len(data.WorkflowLearnings) > 0 {
+		sb.WriteString("\n## Learnings\n\n")
+		for _, l := range data.RepoLearnings {
+			fmt.Fprintf(&sb, "- %s\n", oneLine(l))
+		}
+		for _, l := range data.CodeLearnings {
+			fmt.Fprintf(&sb, "- `%s`: %s\n", codeLearningLocation(l), oneLine(l.Finding))
+		}
+		for _, l := range data.WorkflowLearnings {
+			fmt.Fprintf(&sb, "- %s\n", oneLine(l))
+		}
+	}
+
+	if len(data.FailedCommands) > 0 {
+		sb.WriteString("\n## Failing commands\n\n")
+		for _, c := range data.FailedCommands {
+			fence := markdownFence(c.Command + c.Output)
+			fmt.Fprintf(&sb, "%s\n$ %s\n", fence, c.Command)
+			if c.Output != "" {
+				sb.WriteString(c.Output + "\n")
+			}
+			sb.WriteString(fence + "\n\n")
+		}
+	}
+
+	if len(data.Files) > 0 {
+		sb.WriteString("\n## Files touched\n\n")
+		sb.WriteString("| File | Status |\n| --- | --- |\n")
+		for _, f := range data.Files {
+			fmt.Fprintf(&sb, "| `%s` | %s |\n", f.Path, f.Status)
+		}
+	}
+
+	if data.Diff != "" {
+		sb.WriteString("\n## Uncommitted changes\n\n")
+		fence := markdownFence(data.Diff)
+		fmt.Fprintf(&sb, "%sdiff\n%s\n%s\n", fence, strings.TrimSuffix(data.Diff, "\n"), fence)
+		if data.DiffTruncated {
+			sb.WriteString("\n_Diff truncated; run `git diff HEAD` for the rest._\n")
+		}
+	}
+
+	return strings.TrimRight(sb.String(), "\n") + "\n"
+}
+
+func writeMarkdownSection(sb *strings.Builder, title string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+	fmt.Fprintf(sb, "\n## %s\n\n", title)
+	for _, item := range items {
+		fmt.Fprintf(sb, "- %s\n", oneLine(item))
+	}
+}
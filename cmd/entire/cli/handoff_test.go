@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/agent"
+	"github.com/entireio/cli/cmd/entire/cli/agent/claudecode"
+)
+
+func TestRunHandoff_Stdout(t *testing.T) {
+	setupServeTestRepo(t)
+
+	if err := os.WriteFile("hello.txt", []byte("hello world\nand more\n"), 0o644); err != nil {
+		t.Fatalf("failed to modify file: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := runHandoff(context.Background(), &out, "2026-01-01-serve", "-"); err != nil {
+		t.Fatalf("runHandoff() error = %v", err)
+	}
+	doc := out.String()
+
+	for _, want := range []string{
+		"# Session handoff",
+		"session `2026-01-01-serve-session`",
+		"- **Checkpoints:** a1b2c3d4e5f6",
+		"## What was attempted\n\n- Add a greeting file",
+		"1. Add a greeting file",
+		"## Outcomes so far\n\n- Created hello.txt",
+		"- `hello.txt:1`: Greeting lives here",
+		"| `hello.txt` | modified (uncommitted) |",
+		"+and more",
+	} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("handoff missing %q:\n%s", want, doc)
+		}
+	}
+}
+
+func TestRunHandoff_InstallsIntoAgentMemory(t *testing.T) {
+	setupServeTestRepo(t)
+
+	var out bytes.Buffer
+	if err := runHandoff(context.Background(), &out, "", ""); err != nil {
+		t.Fatalf("runHandoff() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "imported from "+claudecode.ClaudeLocalMemoryFileName) {
+		t.Errorf("unexpected output: %q", out.String())
+	}
+
+	content, err := os.ReadFile(filepath.Join(".claude", agent.HandoffFileName))
+	if err != nil {
+		t.Fatalf("handoff not written: %v", err)
+	}
+	if !strings.Contains(string(content), "| `hello.txt` | unchanged since last commit |") {
+		t.Errorf("handoff missing clean file status:\n%s", content)
+	}
+	if strings.Contains(string(content), "## Uncommitted changes") {
+		t.Errorf("clean tree should have no uncommitted changes section:\n%s", content)
+	}
+}
+
+func TestRunHandoff_UnknownSession(t *testing.T) {
+	setupServeTestRepo(t)
+
+	err := runHandoff(context.Background(), &bytes.Buffer{}, "nope", "-")
+	if err == nil || !strings.Contains(err.Error(), "session not found") {
+		t.Errorf("expected session not found, got %v", err)
+	}
+}
+
+func TestFormatHandoffMarkdown_FailedCommands(t *testing.T) {
+	t.Parallel()
+
+	doc := formatHandoffMarkdown(&handoffData{
+		SessionID:      "s1",
+		FailedCommands: []agent.FailedCommand{{Command: "go test ./...", Output: "FAIL ```"}},
+	})
+	if !strings.Contains(doc, "## Failing commands\n\n````\n$ go test ./...\nFAIL ```\n````") {
+		t.Errorf("failing commands not fenced safely:\n%s", doc)
+	}
+	if !strings.Contains(doc, "_No prompts recorded._") {
+		t.Errorf("expected empty prompts note:\n%s", doc)
+	}
+}
+
+func TestDescribePorcelainStatus(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"??": "untracked",
+		" M": "modified (uncommitted)",
+		"M ": "modified (uncommitted)",
+		"A ": "added (uncommitted)",
+		" D": "deleted (uncommitted)",
+		"R ": "renamed (uncommitted)",
+		"UU": "merge conflict",
+	}
+	for code, want := range tests {
+		if got := describePorcelainStatus(code); got != want {
+			t.Errorf("describePorcelainStatus(%q) = %q, want %q", code, got, want)
+		}
+	}
+}
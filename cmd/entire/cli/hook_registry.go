@@ -228,6 +228,7 @@ func newAgentHooksCmd(agentName agent.AgentName, handler agent.HookHandler) *cob
 		Hidden: true,
 		PersistentPreRunE: func(_ *cobra.Command, _ []string) error {
 			agentHookLogCleanup = initHookLogging()
+			expireIdleSessions(nil, time.Now())
 			return nil
 		},
 		PersistentPostRunE: func(_ *cobra.Command, _ []string) error {
@@ -293,6 +293,17 @@ func handleSessionStartCommon() error {
 	return nil
 }
 
+// isRecordingPaused reports whether the session was paused with
+// "entire session pause". Hooks keep tracking a paused session's phase but
+// don't create checkpoints for it until it is resumed.
+func isRecordingPaused(sessionID string) bool {
+	state, err := strategy.LoadSessionState(sessionID)
+	if err != nil || state == nil {
+		return false
+	}
+	return state.Phase.IsPaused()
+}
+
 // hookResponse represents a JSON response.
 // Used to control whether Agent continues processing the prompt.
 type hookResponse struct {
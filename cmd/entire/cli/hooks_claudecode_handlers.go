@@ -135,6 +135,15 @@ func commitWithMetadata() error { //nolint:maintidx // already present in codeba
 		return NewSilentError(strategy.ErrEmptyRepository)
 	}
 
+	if isRecordingPaused(sessionID) {
+		fmt.Fprintln(os.Stderr, "Entire: recording is paused for this session, skipping checkpoint")
+		transitionSessionTurnEnd(sessionID)
+		if err := CleanupPrePromptState(sessionID); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to cleanup pre-prompt state: %v\n", err)
+		}
+		return nil
+	}
+
 	// Create session metadata folder using the entire session ID (preserves original date on resume)
 	// Use AbsPath to ensure we create at repo root, not relative to cwd
 	sessionDir := paths.SessionMetadataDirFromSessionID(sessionID)
@@ -437,6 +446,10 @@ func handleClaudeCodePostTodo() error {
 		return nil
 	}
 
+	if isRecordingPaused(input.SessionID) {
+		return nil
+	}
+
 	// Detect file changes since last checkpoint
 	changes, err := DetectFileChanges(nil)
 	if err != nil {
@@ -650,6 +663,12 @@ func handleClaudeCodePostTask() error {
 		return nil
 	}
 
+	if isRecordingPaused(input.SessionID) {
+		fmt.Fprintf(os.Stderr, "[entire] Recording is paused for this session, skipping task checkpoint\n")
+		_ = CleanupPreTaskState(input.ToolUseID) //nolint:errcheck // best-effort cleanup
+		return nil
+	}
+
 	// Find checkpoint UUID from main transcript (best-effort, ignore errors)
 	mainLines, _, _ := transcript.ParseFromFileAtLine(input.TranscriptPath, 0) //nolint:errcheck // best-effort extraction
 	checkpointUUID, _ := FindCheckpointUUID(mainLines, input.ToolUseID)
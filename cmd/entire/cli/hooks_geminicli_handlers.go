@@ -495,6 +495,15 @@ func handleGeminiAfterAgent() error {
 		return NewSilentError(strategy.ErrEmptyRepository)
 	}
 
+	if isRecordingPaused(sessionID) {
+		fmt.Fprintln(os.Stderr, "Entire: recording is paused for this session, skipping checkpoint")
+		transitionSessionTurnEnd(sessionID)
+		if cleanupErr := CleanupPrePromptState(sessionID); cleanupErr != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to cleanup pre-prompt state: %v\n", cleanupErr)
+		}
+		return nil
+	}
+
 	// Create session context and commit
 	ctx := &geminiSessionContext{
 		sessionID:      sessionID,
@@ -97,6 +97,7 @@ func newHooksGitCmd() *cobra.Command {
 	cmd.AddCommand(newHooksGitPrepareCommitMsgCmd())
 	cmd.AddCommand(newHooksGitCommitMsgCmd())
 	cmd.AddCommand(newHooksGitPostCommitCmd())
+	cmd.AddCommand(newHooksGitPostRewriteCmd())
 	cmd.AddCommand(newHooksGitPrePushCmd())
 
 	return cmd
@@ -168,6 +169,26 @@ func newHooksGitPostCommitCmd() *cobra.Command {
 	}
 }
 
+func newHooksGitPostRewriteCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "post-rewrite <amend|rebase>",
+		Short: "Handle post-rewrite git hook",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			rewriteType := args[0]
+
+			g := newGitHookContext("post-rewrite")
+			g.logInvoked(slog.String("type", rewriteType))
+
+			// Linking rewritten commits to checkpoints doesn't depend on the strategy
+			hookErr := strategy.HandlePostRewrite(rewriteType, cmd.InOrStdin())
+			g.logCompleted(hookErr, slog.String("type", rewriteType))
+
+			return nil
+		},
+	}
+}
+
 func newHooksGitPrePushCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "pre-push <remote>",
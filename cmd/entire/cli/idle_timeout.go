@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/logging"
+	"github.com/entireio/cli/cmd/entire/cli/session"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+)
+
+// expireIdleSessions ends ACTIVE sessions that have had no activity for longer
+// than strategy_options.idle_timeout, condensing any checkpoints they recorded.
+// A session whose agent crashed mid-turn never gets a TurnEnd or SessionStop,
+// so without this it would stay ACTIVE until "entire doctor" is run.
+//
+// It is called opportunistically by agent hooks and user-facing commands, so
+// it is best-effort: failures are logged, never returned. Git hooks don't call
+// it, so a commit still links to the sessions that were active when it started.
+// Reports of ended sessions are written to w (may be nil).
+func expireIdleSessions(w io.Writer, now time.Time) {
+	logCtx := logging.WithComponent(context.Background(), "idle-timeout")
+
+	s, err := LoadEntireSettings()
+	if err != nil || !s.Enabled {
+		return
+	}
+	timeout, err := s.GetIdleTimeout()
+	if err != nil {
+		logging.Warn(logCtx, "ignoring idle timeout", slog.String("error", err.Error()))
+		return
+	}
+	if timeout <= 0 {
+		return
+	}
+
+	states, err := strategy.ListSessionStates()
+	if err != nil {
+		return // Not in a repository, or nothing tracked yet
+	}
+
+	for _, state := range states {
+		if !state.Phase.IsActive() {
+			continue
+		}
+		idle := now.Sub(lastSessionActivity(state))
+		if idle <= timeout {
+			continue
+		}
+		if err := expireSession(state, now); err != nil {
+			logging.Warn(logCtx, "failed to expire idle session",
+				slog.String("session_id", state.SessionID),
+				slog.String("error", err.Error()),
+			)
+			continue
+		}
+		logging.Info(logCtx, "expired idle session",
+			slog.String("session_id", state.SessionID),
+			slog.Duration("idle", idle),
+		)
+		if w != nil {
+			fmt.Fprintf(w, "[entire] Ended session %s after %s without activity\n", state.SessionID, idle.Truncate(time.Minute))
+		}
+	}
+}
+
+// lastSessionActivity returns the latest sign of life of a session: its last
+// recorded interaction, or a later write to its transcript. Agents write the
+// transcript throughout a turn, so long-running turns are not mistaken for
+// crashed ones.
+func lastSessionActivity(state *strategy.SessionState) time.Time {
+	last := state.StartedAt
+	if state.LastInteractionTime != nil && state.LastInteractionTime.After(last) {
+		last = *state.LastInteractionTime
+	}
+	if state.TranscriptPath != "" {
+		if info, err := os.Stat(state.TranscriptPath); err == nil && info.ModTime().After(last) {
+			last = info.ModTime()
+		}
+	}
+	return last
+}
+
+// expireSession fires EventTimeout for a session and dispatches the resulting
+// condensation through the strategy, like "entire doctor" does. The session is
+// marked ended once it has been condensed, since condensing leaves it idle.
+func expireSession(state *strategy.SessionState, now time.Time) error {
+	actions := strategy.TransitionAndLog(state, session.EventTimeout, session.TransitionContext{
+		HasFilesTouched: len(state.FilesTouched) > 0,
+	})
+	phase := state.Phase
+
+	for _, action := range actions {
+		if action != session.ActionCondense || state.StepCount == 0 {
+			continue
+		}
+		condenser, ok := GetStrategy().(strategy.SessionCondenser)
+		if !ok {
+			continue // Strategies without shadow branches have nothing to condense
+		}
+		if err := condenser.CondenseSessionByID(state.SessionID); err != nil {
+			return fmt.Errorf("failed to condense session: %w", err)
+		}
+
+		// Pick up the condensed state; it's gone if there was nothing to condense
+		condensed, err := strategy.LoadSessionState(state.SessionID)
+		if err != nil {
+			return fmt.Errorf("failed to load condensed session state: %w", err)
+		}
+		if condensed == nil {
+			return nil
+		}
+		state = condensed
+	}
+
+	state.Phase = phase
+	if phase == session.PhaseEnded {
+		state.EndedAt = &now
+	}
+	if err := strategy.SaveSessionState(state); err != nil {
+		return fmt.Errorf("failed to save session state: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/session"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+)
+
+func saveIdleTestSessions(t *testing.T, now time.Time) {
+	t.Helper()
+
+	threeHoursAgo := now.Add(-3 * time.Hour)
+	transcriptPath := filepath.Join(t.TempDir(), "transcript.jsonl")
+	if err := os.WriteFile(transcriptPath, []byte("{}\n"), 0o600); err != nil {
+		t.Fatalf("failed to write transcript: %v", err)
+	}
+
+	for _, state := range []*strategy.SessionState{
+		// Crashed mid-turn, nothing checkpointed
+		{SessionID: "crashed", Phase: session.PhaseActive, StartedAt: threeHoursAgo, LastInteractionTime: &threeHoursAgo},
+		// Crashed mid-turn with checkpoints but no shadow branch left to condense
+		{SessionID: "crashed-with-checkpoints", Phase: session.PhaseActive, StartedAt: threeHoursAgo, LastInteractionTime: &threeHoursAgo, StepCount: 2},
+		// A long turn that is still writing its transcript
+		{SessionID: "long-turn", Phase: session.PhaseActive, StartedAt: threeHoursAgo, LastInteractionTime: &threeHoursAgo, TranscriptPath: transcriptPath},
+		// Waiting for the user
+		{SessionID: "waiting", Phase: session.PhaseIdle, StartedAt: threeHoursAgo, LastInteractionTime: &threeHoursAgo},
+	} {
+		if err := strategy.SaveSessionState(state); err != nil {
+			t.Fatalf("SaveSessionState() error = %v", err)
+		}
+	}
+}
+
+func loadIdleTestSession(t *testing.T, sessionID string) *strategy.SessionState {
+	t.Helper()
+	state, err := strategy.LoadSessionState(sessionID)
+	if err != nil {
+		t.Fatalf("LoadSessionState(%s) error = %v", sessionID, err)
+	}
+	return state
+}
+
+func TestExpireIdleSessions(t *testing.T) {
+	setupTestRepo(t)
+	writeSettings(t, `{"strategy": "manual-commit", "enabled": true, "strategy_options": {"idle_timeout": "1h"}}`)
+
+	now := time.Now()
+	saveIdleTestSessions(t, now)
+
+	var out bytes.Buffer
+	expireIdleSessions(&out, now)
+
+	crashed := loadIdleTestSession(t, "crashed")
+	if crashed == nil || crashed.Phase != session.PhaseEnded || crashed.EndedAt == nil {
+		t.Errorf("crashed session = %+v, want ended with EndedAt", crashed)
+	}
+	if !strings.Contains(out.String(), "[entire] Ended session crashed after 3h0m0s without activity") {
+		t.Errorf("unexpected output: %s", out.String())
+	}
+
+	// Condensing a session whose shadow branch is gone only clears its state
+	if state := loadIdleTestSession(t, "crashed-with-checkpoints"); state != nil {
+		t.Errorf("crashed session with checkpoints = %+v, want it condensed and cleared", state)
+	}
+
+	if state := loadIdleTestSession(t, "long-turn"); state == nil || state.Phase != session.PhaseActive {
+		t.Errorf("session with a fresh transcript = %+v, want it still active", state)
+	}
+	if state := loadIdleTestSession(t, "waiting"); state == nil || state.Phase != session.PhaseIdle {
+		t.Errorf("idle session = %+v, want it untouched", state)
+	}
+}
+
+func TestExpireIdleSessions_DisabledByDefault(t *testing.T) {
+	setupTestRepo(t)
+	writeSettings(t, `{"strategy": "manual-commit", "enabled": true}`)
+
+	now := time.Now()
+	saveIdleTestSessions(t, now)
+
+	var out bytes.Buffer
+	expireIdleSessions(&out, now)
+
+	if state := loadIdleTestSession(t, "crashed"); state == nil || state.Phase != session.PhaseActive {
+		t.Errorf("crashed session = %+v, want it still active without a timeout", state)
+	}
+	if out.Len() != 0 {
+		t.Errorf("unexpected output: %s", out.String())
+	}
+}
+
+func TestExpireIdleSessions_CondensesThenEnds(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+	setupResumeTestRepo(t, tmpDir, false)
+	writeSettings(t, `{"strategy": "manual-commit", "enabled": true, "strategy_options": {"idle_timeout": "1h"}}`)
+
+	// Record a checkpoint so the session has a shadow branch to condense
+	sessionID := "crashed-mid-turn"
+	metadataDir := filepath.Join(paths.EntireMetadataDir, sessionID)
+	metadataDirAbs := filepath.Join(tmpDir, metadataDir)
+	if err := os.MkdirAll(metadataDirAbs, 0o755); err != nil {
+		t.Fatalf("failed to create metadata dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(metadataDirAbs, paths.TranscriptFileName), []byte(`{"type":"human","message":{"content":"hi"}}`+"\n"), 0o644); err != nil {
+		t.Fatalf("failed to write transcript: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "test.txt"), []byte("agent content"), 0o644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+	if err := strategy.NewManualCommitStrategy().SaveChanges(strategy.SaveContext{
+		SessionID:      sessionID,
+		ModifiedFiles:  []string{"test.txt"},
+		MetadataDir:    metadataDir,
+		MetadataDirAbs: metadataDirAbs,
+		CommitMessage:  "Checkpoint 1",
+		AuthorName:     "Test",
+		AuthorEmail:    "test@test.com",
+	}); err != nil {
+		t.Fatalf("SaveChanges() error = %v", err)
+	}
+
+	now := time.Now()
+	threeHoursAgo := now.Add(-3 * time.Hour)
+	state := loadIdleTestSession(t, sessionID)
+	state.Phase = session.PhaseActive
+	state.StartedAt = threeHoursAgo
+	state.LastInteractionTime = &threeHoursAgo
+	if err := strategy.SaveSessionState(state); err != nil {
+		t.Fatalf("SaveSessionState() error = %v", err)
+	}
+
+	expireIdleSessions(nil, now)
+
+	state = loadIdleTestSession(t, sessionID)
+	if state == nil || state.Phase != session.PhaseEnded || state.EndedAt == nil {
+		t.Fatalf("session = %+v, want ended with EndedAt", state)
+	}
+	if state.StepCount != 0 || state.LastCheckpointID.IsEmpty() {
+		t.Errorf("StepCount = %d, LastCheckpointID = %q; want the session condensed", state.StepCount, state.LastCheckpointID)
+	}
+}
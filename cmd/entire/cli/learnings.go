@@ -0,0 +1,634 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/agent/geminicli"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/jsonutil"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+	"github.com/entireio/cli/cmd/entire/cli/stringutil"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/spf13/cobra"
+)
+
+const (
+	learningsFormatText = "text"
+	learningsFormatJSON = "json"
+
+	// learningsBlockStart and learningsBlockEnd delimit the section that
+	// `entire learnings --propose --write` manages in an agent's memory file.
+	learningsBlockStart = "<!-- entire:learnings:start -->"
+	learningsBlockEnd   = "<!-- entire:learnings:end -->"
+
+	// claudeMemoryFileName is the shared, committed Claude Code memory file.
+	claudeMemoryFileName = "CLAUDE.md"
+)
+
+// learningsMemoryFiles maps --propose targets to the memory file they update.
+var learningsMemoryFiles = map[string]string{
+	"claude": claudeMemoryFileName,
+	"gemini": geminicli.GeminiMemoryFileName,
+}
+
+func newLearningsCmd() *cobra.Command {
+	var formatFlag string
+	var staleFlag bool
+	var proposeFlag string
+	var writeFlag bool
+
+	cmd := &cobra.Command{
+		Use:   "learnings",
+		Short: "Collect learnings from all checkpoint summaries into a knowledge base",
+		Long: `Collect the learnings recorded in AI summaries across all checkpoints, so
+agents stop rediscovering the same gotchas.
+
+Learnings are deduplicated and sorted by how many checkpoints reported them.
+Code learnings are grouped by file. A code learning is marked stale when the
+lines it refers to have changed since the checkpoint's commit, or the file was
+deleted.
+
+--propose prints a section for an agent's memory file (claude: CLAUDE.md,
+gemini: GEMINI.md) with the learnings that aren't stale and aren't already in
+the file. Add --write to update the file; the section is kept between
+` + learningsBlockStart + ` markers and replaced on later runs, or removed once
+none of its learnings are left.`,
+		Example: `  entire learnings
+  entire learnings --stale
+  entire learnings --propose claude --write`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if checkDisabledGuard(cmd.OutOrStdout()) {
+				return nil
+			}
+			if formatFlag != learningsFormatText && formatFlag != learningsFormatJSON {
+				return fmt.Errorf("invalid format %q: must be one of text, json", formatFlag)
+			}
+			if proposeFlag != "" {
+				if _, ok := learningsMemoryFiles[proposeFlag]; !ok {
+					return fmt.Errorf("invalid --propose target %q: must be one of claude, gemini", proposeFlag)
+				}
+			}
+			if writeFlag && proposeFlag == "" {
+				return errors.New("--write requires --propose")
+			}
+			return runLearnings(cmd.Context(), cmd.OutOrStdout(), formatFlag, staleFlag, proposeFlag, writeFlag)
+		},
+	}
+
+	cmd.Flags().StringVar(&formatFlag, "format", learningsFormatText, "Output format: text or json")
+	cmd.Flags().BoolVar(&staleFlag, "stale", false, "Only show code learnings whose lines changed since")
+	cmd.Flags().StringVar(&proposeFlag, "propose", "", "Propose a memory file section for an agent: claude or gemini")
+	cmd.Flags().BoolVar(&writeFlag, "write", false, "With --propose, write the section into the memory file")
+
+	return cmd
+}
+
+// learningsReport is the knowledge base collected from checkpoint summaries.
+type learningsReport struct {
+	Checkpoints int                 `json:"checkpoints"`
+	Repo        []learningEntry     `json:"repo"`
+	Code        []codeLearningGroup `json:"code"`
+	Workflow    []learningEntry     `json:"workflow"`
+}
+
+// learningEntry is a deduplicated learning and where it was reported.
+type learningEntry struct {
+	Text        string    `json:"text"`
+	Count       int       `json:"count"`
+	Checkpoints []string  `json:"checkpoints"`
+	LastSeen    time.Time `json:"last_seen"`
+}
+
+// codeLearningGroup holds the code learnings about one file.
+type codeLearningGroup struct {
+	Path      string              `json:"path"`
+	Learnings []codeLearningEntry `json:"learnings"`
+}
+
+// codeLearningEntry is a deduplicated code learning. Line numbers come from the
+// most recent checkpoint that reported it.
+type codeLearningEntry struct {
+	learningEntry
+
+	Line        int    `json:"line,omitempty"`
+	EndLine     int    `json:"end_line,omitempty"`
+	Stale       bool   `json:"stale,omitempty"`
+	StaleReason string `json:"stale_reason,omitempty"`
+
+	path         string
+	checkpointID id.CheckpointID
+}
+
+func runLearnings(ctx context.Context, w io.Writer, format string, staleOnly bool, propose string, write bool) error {
+	repo, err := openRepository()
+	if err != nil {
+		return fmt.Errorf("not a git repository: %w", err)
+	}
+	store := checkpoint.NewGitStore(repo)
+
+	report, err := collectLearnings(ctx, store)
+	if err != nil {
+		return err
+	}
+	// Rebased or amended commits may be linked to their checkpoints without a trailer
+	links, err := strategy.LoadCommitLinkIndex(ctx, store)
+	if err != nil {
+		return err //nolint:wrapcheck // Already wrapped by the strategy package
+	}
+	markStaleLearnings(repo, links, report)
+
+	if propose != "" {
+		return proposeLearnings(w, report, learningsMemoryFiles[propose], write)
+	}
+
+	if staleOnly {
+		report.Repo, report.Workflow = nil, nil
+		report.Code = filterStaleCodeLearnings(report.Code)
+	}
+
+	if format == learningsFormatJSON {
+		data, err := jsonutil.MarshalIndentWithNewline(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal learnings: %w", err)
+		}
+		if _, err := w.Write(data); err != nil {
+			return fmt.Errorf("failed to write learnings: %w", err)
+		}
+		return nil
+	}
+
+	fmt.Fprint(w, formatLearningsText(report, staleOnly))
+	return nil
+}
+
+// collectLearnings gathers and deduplicates the learnings of every committed session.
+// Sessions are scanned most recent first, so the latest wording of a learning is kept.
+func collectLearnings(ctx context.Context, store *checkpoint.GitStore) (*learningsReport, error) {
+	repoSet, workflowSet := newLearningSet(), newLearningSet()
+	codeEntries := make(map[string]*codeLearningEntry)
+	checkpoints := make(map[id.CheckpointID]bool)
+
+	if err := forEachCommittedSession(ctx, store, func(cpID id.CheckpointID, content *checkpoint.SessionContent) error {
+		summary := content.Metadata.Summary
+		if summary == nil {
+			return nil
+		}
+		checkpoints[cpID] = true
+		seen := content.Metadata.CreatedAt
+
+		repoSet.add(cpID, seen, summary.Learnings.Repo...)
+		workflowSet.add(cpID, seen, summary.Learnings.Workflow...)
+		for _, learning := range summary.Learnings.Code {
+			finding := strings.TrimSpace(learning.Finding)
+			if learning.Path == "" || finding == "" {
+				continue
+			}
+			key := learning.Path + "\x00" + normalizeLearning(finding)
+			entry, ok := codeEntries[key]
+			if !ok {
+				entry = &codeLearningEntry{path: learning.Path, learningEntry: learningEntry{Text: finding}}
+				codeEntries[key] = entry
+			}
+			entry.record(cpID, seen)
+			if seen.Equal(entry.LastSeen) {
+				entry.Line, entry.EndLine, entry.checkpointID = learning.Line, learning.EndLine, cpID
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	report := &learningsReport{
+		Checkpoints: len(checkpoints),
+		Repo:        repoSet.sorted(),
+		Workflow:    workflowSet.sorted(),
+	}
+
+	byPath := make(map[string][]codeLearningEntry)
+	for _, entry := range codeEntries {
+		byPath[entry.path] = append(byPath[entry.path], *entry)
+	}
+	for path, entries := range byPath {
+		sort.SliceStable(entries, func(i, j int) bool {
+			if entries[i].Line != entries[j].Line {
+				return entries[i].Line < entries[j].Line
+			}
+			return entries[i].Text < entries[j].Text
+		})
+		report.Code = append(report.Code, codeLearningGroup{Path: path, Learnings: entries})
+	}
+	sort.Slice(report.Code, func(i, j int) bool { return report.Code[i].Path < report.Code[j].Path })
+
+	return report, nil
+}
+
+// learningSet deduplicates learnings that differ only in case, spacing, or a trailing period.
+type learningSet struct {
+	entries map[string]*learningEntry
+}
+
+func newLearningSet() *learningSet {
+	return &learningSet{entries: make(map[string]*learningEntry)}
+}
+
+func (s *learningSet) add(cpID id.CheckpointID, seen time.Time, texts ...string) {
+	for _, text := range texts {
+		text = strings.TrimSpace(text)
+		if text == "" {
+			continue
+		}
+		key := normalizeLearning(text)
+		entry, ok := s.entries[key]
+		if !ok {
+			entry = &learningEntry{Text: text}
+			s.entries[key] = entry
+		}
+		entry.record(cpID, seen)
+	}
+}
+
+// sorted returns the learnings reported most often first, then most recent first.
+func (s *learningSet) sorted() []learningEntry {
+	result := make([]learningEntry, 0, len(s.entries))
+	for _, entry := range s.entries {
+		result = append(result, *entry)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		if !result[i].LastSeen.Equal(result[j].LastSeen) {
+			return result[i].LastSeen.After(result[j].LastSeen)
+		}
+		return result[i].Text < result[j].Text
+	})
+	return result
+}
+
+// record counts one more checkpoint reporting the learning.
+func (e *learningEntry) record(cpID id.CheckpointID, seen time.Time) {
+	if e.Count > 0 && e.Checkpoints[len(e.Checkpoints)-1] == cpID.String() {
+		return // Several sessions of one checkpoint count once
+	}
+	e.Count++
+	e.Checkpoints = append(e.Checkpoints, cpID.String())
+	if seen.After(e.LastSeen) || e.LastSeen.IsZero() {
+		e.LastSeen = seen
+	}
+}
+
+// normalizeLearning returns the key used to spot duplicate learnings.
+func normalizeLearning(text string) string {
+	return strings.TrimRight(strings.ToLower(stringutil.CollapseWhitespace(text)), ". ")
+}
+
+// markStaleLearnings flags code learnings whose file is gone at HEAD, or whose
+// lines at the checkpoint's commit no longer appear in the file at HEAD.
+func markStaleLearnings(repo *git.Repository, links strategy.CommitLinkIndex, report *learningsReport) {
+	headTree, commits := learningsHeadState(repo, links)
+	if headTree == nil {
+		return
+	}
+
+	for gi := range report.Code {
+		group := &report.Code[gi]
+		current, exists := readTreeFile(headTree, group.Path)
+		for li := range group.Learnings {
+			entry := &group.Learnings[li]
+			if !exists {
+				entry.Stale, entry.StaleReason = true, "file no longer exists"
+				continue
+			}
+			commit, ok := commits[entry.checkpointID]
+			if !ok || entry.Line <= 0 {
+				continue
+			}
+			tree, err := commit.Tree()
+			if err != nil {
+				continue
+			}
+			original, ok := readTreeFile(tree, group.Path)
+			if !ok {
+				continue
+			}
+			if !containsLines(current, lineRange(original, entry.Line, entry.EndLine)) {
+				entry.Stale, entry.StaleReason = true, "lines changed since the checkpoint"
+			}
+		}
+	}
+}
+
+// learningsHeadState returns the HEAD tree and, for each checkpoint in HEAD's
+// history, the commit linked to it by a trailer or the commit link index.
+func learningsHeadState(repo *git.Repository, links strategy.CommitLinkIndex) (*object.Tree, map[id.CheckpointID]*object.Commit) {
+	head, err := repo.Head()
+	if err != nil {
+		return nil, nil
+	}
+	headCommit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return nil, nil
+	}
+	headTree, err := headCommit.Tree()
+	if err != nil {
+		return nil, nil
+	}
+
+	commits := make(map[id.CheckpointID]*object.Commit)
+	iter, err := repo.Log(&git.LogOptions{From: head.Hash()})
+	if err != nil {
+		return headTree, commits
+	}
+	//nolint:errcheck // Best-effort: checkpoints without a commit just aren't checked for staleness
+	_ = iter.ForEach(func(c *object.Commit) error {
+		for _, cpID := range links.CheckpointsForCommit(c) {
+			if _, ok := commits[cpID]; !ok {
+				commits[cpID] = c
+			}
+		}
+		return nil
+	})
+	return headTree, commits
+}
+
+// readTreeFile returns the lines of a file in tree.
+func readTreeFile(tree *object.Tree, path string) ([]string, bool) {
+	file, err := tree.File(path)
+	if err != nil {
+		return nil, false
+	}
+	content, err := file.Contents()
+	if err != nil {
+		return nil, false
+	}
+	return strings.Split(content, "\n"), true
+}
+
+// lineRange returns lines start..end (1-based, inclusive). A missing or
+// invalid end selects the start line only.
+func lineRange(lines []string, start, end int) []string {
+	if end < start {
+		end = start
+	}
+	if start < 1 || start > len(lines) {
+		return nil
+	}
+	return lines[start-1 : min(end, len(lines))]
+}
+
+// containsLines reports whether block appears as consecutive lines in lines.
+// Lines that moved are still found; only edits or deletions make it fail.
+func containsLines(lines, block []string) bool {
+	if len(block) == 0 {
+		return false
+	}
+	for i := 0; i+len(block) <= len(lines); i++ {
+		match := true
+		for j := range block {
+			if lines[i+j] != block[j] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+// filterStaleCodeLearnings keeps only stale code learnings.
+func filterStaleCodeLearnings(groups []codeLearningGroup) []codeLearningGroup {
+	var result []codeLearningGroup
+	for _, group := range groups {
+		var stale []codeLearningEntry
+		for _, entry := range group.Learnings {
+			if entry.Stale {
+				stale = append(stale, entry)
+			}
+		}
+		if len(stale) > 0 {
+			result = append(result, codeLearningGroup{Path: group.Path, Learnings: stale})
+		}
+	}
+	return result
+}
+
+// formatCodeLearningLocation formats a code learning's path and line range.
+func formatCodeLearningLocation(path string, entry codeLearningEntry) string {
+	switch {
+	case entry.Line > 0 && entry.EndLine > entry.Line:
+		return fmt.Sprintf("%s:%d-%d", path, entry.Line, entry.EndLine)
+	case entry.Line > 0:
+		return fmt.Sprintf("%s:%d", path, entry.Line)
+	default:
+		return path
+	}
+}
+
+func formatLearningsText(report *learningsReport, staleOnly bool) string {
+	var sb strings.Builder
+
+	if staleOnly {
+		if len(report.Code) == 0 {
+			sb.WriteString("No stale code learnings.\n")
+			return sb.String()
+		}
+	} else if len(report.Repo) == 0 && len(report.Code) == 0 && len(report.Workflow) == 0 {
+		sb.WriteString("No learnings found. Learnings come from checkpoint summaries; enable auto-summarize or run 'entire explain --generate'.\n")
+		return sb.String()
+	}
+
+	fmt.Fprintf(&sb, "Learnings from %d checkpoint(s)\n", report.Checkpoints)
+
+	writeEntries := func(title string, entries []learningEntry) {
+		if len(entries) == 0 {
+			return
+		}
+		fmt.Fprintf(&sb, "\n%s:\n", title)
+		for _, entry := range entries {
+			fmt.Fprintf(&sb, "  - %s%s\n", entry.Text, formatLearningCount(entry.Count))
+		}
+	}
+	writeEntries("Repository", report.Repo)
+
+	if len(report.Code) > 0 {
+		sb.WriteString("\nCode:\n")
+		for _, group := range report.Code {
+			fmt.Fprintf(&sb, "  %s\n", group.Path)
+			for _, entry := range group.Learnings {
+				location := formatCodeLearningLocation(group.Path, entry)
+				fmt.Fprintf(&sb, "    - %s: %s%s", location, entry.Text, formatLearningCount(entry.Count))
+				if entry.Stale {
+					fmt.Fprintf(&sb, " [stale: %s]", entry.StaleReason)
+				}
+				sb.WriteString("\n")
+			}
+		}
+	}
+
+	writeEntries("Workflow", report.Workflow)
+	return sb.String()
+}
+
+// formatLearningCount notes how many checkpoints reported a learning, if more than one.
+func formatLearningCount(count int) string {
+	if count <= 1 {
+		return ""
+	}
+	return fmt.Sprintf(" (%d checkpoints)", count)
+}
+
+// proposeLearnings prints, and with write applies, the learnings section for
+// an agent memory file.
+func proposeLearnings(w io.Writer, report *learningsReport, memoryFile string, write bool) error {
+	path := filepath.Join(paths.RepoRootOr("."), memoryFile)
+	existing, err := os.ReadFile(path) //nolint:gosec // Path is the repo's agent memory file
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read %s: %w", memoryFile, err)
+	}
+
+	section := formatLearningsSection(report, stripLearningsBlock(string(existing)))
+	if section == "" {
+		if !strings.Contains(string(existing), learningsBlockStart) {
+			fmt.Fprintf(w, "No new learnings to add to %s.\n", memoryFile)
+			return nil
+		}
+		// Every learning in the existing section went stale; drop the section
+		// rather than leave outdated guidance in place.
+		if !write {
+			fmt.Fprintf(w, "No learnings left for %s; run with --write to remove its learnings section.\n", memoryFile)
+			return nil
+		}
+		if err := os.WriteFile(path, []byte(removeLearningsBlock(string(existing))), 0o644); err != nil { //nolint:gosec // Memory files are committed and shared like other repo files
+			return fmt.Errorf("failed to write %s: %w", memoryFile, err)
+		}
+		fmt.Fprintf(w, "✓ Removed learnings section from %s\n", memoryFile)
+		return nil
+	}
+
+	if !write {
+		fmt.Fprintf(w, "Proposed section for %s (run with --write to apply):\n\n%s", memoryFile, section)
+		return nil
+	}
+
+	if err := os.WriteFile(path, []byte(replaceLearningsBlock(string(existing), section)), 0o644); err != nil { //nolint:gosec // Memory files are committed and shared like other repo files
+		return fmt.Errorf("failed to write %s: %w", memoryFile, err)
+	}
+	fmt.Fprintf(w, "✓ Updated learnings in %s\n", memoryFile)
+	return nil
+}
+
+// formatLearningsSection renders the managed memory file section. Learnings that
+// are stale or already mentioned in the rest of the file are left out. Returns
+// "" if nothing is left.
+func formatLearningsSection(report *learningsReport, documented string) string {
+	documented = normalizeLearning(documented)
+	isNew := func(text string) bool {
+		return !strings.Contains(documented, normalizeLearning(text))
+	}
+
+	var repoItems, codeItems, workflowItems []string
+	for _, entry := range report.Repo {
+		if isNew(entry.Text) {
+			repoItems = append(repoItems, entry.Text)
+		}
+	}
+	for _, group := range report.Code {
+		for _, entry := range group.Learnings {
+			if !entry.Stale && isNew(entry.Text) {
+				codeItems = append(codeItems, fmt.Sprintf("`%s`: %s", formatCodeLearningLocation(group.Path, entry), entry.Text))
+			}
+		}
+	}
+	for _, entry := range report.Workflow {
+		if isNew(entry.Text) {
+			workflowItems = append(workflowItems, entry.Text)
+		}
+	}
+	if len(repoItems) == 0 && len(codeItems) == 0 && len(workflowItems) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString(learningsBlockStart + "\n")
+	sb.WriteString("## Learnings from past sessions\n\n")
+	sb.WriteString("_Collected by `entire learnings` from checkpoint summaries. Regenerate instead of editing by hand._\n")
+	writeItems := func(title string, items []string) {
+		if len(items) == 0 {
+			return
+		}
+		fmt.Fprintf(&sb, "\n### %s\n\n", title)
+		for _, item := range items {
+			fmt.Fprintf(&sb, "- %s\n", item)
+		}
+	}
+	writeItems("Repository", repoItems)
+	writeItems("Code", codeItems)
+	writeItems("Workflow", workflowItems)
+	sb.WriteString(learningsBlockEnd + "\n")
+	return sb.String()
+}
+
+// stripLearningsBlock removes the managed learnings section from content.
+func stripLearningsBlock(content string) string {
+	start := strings.Index(content, learningsBlockStart)
+	end := strings.Index(content, learningsBlockEnd)
+	if start < 0 || end < start {
+		return content
+	}
+	return content[:start] + content[end+len(learningsBlockEnd):]
+}
+
+// removeLearningsBlock removes the managed learnings section from content,
+// along with the blank lines that separated it from the rest of the file.
+func removeLearningsBlock(content string) string {
+	start := strings.Index(content, learningsBlockStart)
+	end := strings.Index(content, learningsBlockEnd)
+	if start < 0 || end < start {
+		return content
+	}
+	before := strings.TrimRight(content[:start], "\n")
+	after := strings.TrimLeft(content[end+len(learningsBlockEnd):], "\n")
+	switch {
+	case before == "":
+		return after
+	case after == "":
+		return before + "\n"
+	default:
+		return before + "\n\n" + after
+	}
+}
+
+// replaceLearningsBlock replaces the managed learnings section in content, or
+// appends it if the file doesn't have one yet.
+func replaceLearningsBlock(content, section string) string {
+	start := strings.Index(content, learningsBlockStart)
+	end := strings.Index(content, learningsBlockEnd)
+	if start >= 0 && end > start {
+		rest := strings.TrimPrefix(content[end+len(learningsBlockEnd):], "\n")
+		return content[:start] + section + rest
+	}
+
+	if content == "" {
+		return section
+	}
+	if !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	return content + "\n" + section
+}
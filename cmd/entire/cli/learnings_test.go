@@ -0,0 +1,348 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+	"github.com/entireio/cli/cmd/entire/cli/trailers"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// addLearningsCheckpoint commits a file (with a checkpoint trailer when cpID is
+// set) and writes a committed checkpoint carrying summary.
+func addLearningsCheckpoint(t *testing.T, repo *git.Repository, cpID id.CheckpointID, sessionID, file, content string, summary *checkpoint.Summary) {
+	t.Helper()
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("failed to get worktree: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(wt.Filesystem.Root(), file), []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write %s: %v", file, err)
+	}
+	if _, err := wt.Add(file); err != nil {
+		t.Fatalf("failed to add %s: %v", file, err)
+	}
+	message := "Update " + file
+	if !cpID.IsEmpty() {
+		message += "\n\n" + trailers.CheckpointTrailerKey + ": " + cpID.String() + "\n"
+	}
+	sig := &object.Signature{Name: "Test", Email: "test@example.com", When: time.Now()}
+	if _, err := wt.Commit(message, &git.CommitOptions{Author: sig}); err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+	if cpID.IsEmpty() {
+		return
+	}
+
+	store := checkpoint.NewGitStore(repo)
+	if err := store.WriteCommitted(context.Background(), checkpoint.WriteCommittedOptions{
+		CheckpointID: cpID,
+		SessionID:    sessionID,
+		Strategy:     "manual-commit",
+		Transcript:   []byte(serveTestTranscript),
+		FilesTouched: []string{file},
+		AuthorName:   "Test",
+		AuthorEmail:  "test@example.com",
+		Summary:      summary,
+	}); err != nil {
+		t.Fatalf("failed to write committed checkpoint: %v", err)
+	}
+}
+
+// setupLearningsTestRepo creates checkpoints with overlapping learnings, then
+// edits a file so that one code learning goes stale.
+func setupLearningsTestRepo(t *testing.T) *git.Repository {
+	t.Helper()
+
+	repo, _ := setupServeTestRepo(t)
+	addLearningsCheckpoint(t, repo, id.MustCheckpointID("b1b2b3b4b5b6"), "2026-01-02-session", "config.go",
+		"package config\n\nconst Timeout = 30\n\nfunc Load() {}\n",
+		&checkpoint.Summary{
+			Intent: "Tune config",
+			Learnings: checkpoint.LearningsSummary{
+				Repo: []string{"Run make test before committing", "Config lives in config.go"},
+				Code: []checkpoint.CodeLearning{
+					{Path: "config.go", Line: 3, Finding: "Timeout is in seconds"},
+					{Path: "config.go", Line: 5, Finding: "Load reads env vars first"},
+				},
+				Workflow: []string{"Use the e2e suite for hook changes"},
+			},
+		})
+	addLearningsCheckpoint(t, repo, id.MustCheckpointID("c1c2c3c4c5c6"), "2026-01-03-session", "notes.txt",
+		"notes\n",
+		&checkpoint.Summary{
+			Intent: "Write notes",
+			Learnings: checkpoint.LearningsSummary{
+				Repo: []string{"run make test before committing."},
+			},
+		})
+	// A later edit without a checkpoint changes the Timeout line
+	addLearningsCheckpoint(t, repo, id.EmptyCheckpointID, "", "config.go",
+		"package config\n\nconst Timeout = 60 * time.Second\n\nfunc Load() {}\n", nil)
+
+	return repo
+}
+
+func TestCollectLearnings_DeduplicatesAndGroups(t *testing.T) {
+	repo := setupLearningsTestRepo(t)
+
+	report, err := collectLearnings(context.Background(), checkpoint.NewGitStore(repo))
+	if err != nil {
+		t.Fatalf("collectLearnings() error = %v", err)
+	}
+
+	if report.Checkpoints != 3 {
+		t.Errorf("Checkpoints = %d, want 3", report.Checkpoints)
+	}
+	if len(report.Repo) != 2 {
+		t.Fatalf("Repo = %+v, want 2 deduplicated learnings", report.Repo)
+	}
+	if report.Repo[0].Count != 2 || !strings.EqualFold(strings.TrimSuffix(report.Repo[0].Text, "."), "Run make test before committing") {
+		t.Errorf("Repo[0] = %+v, want the learning reported twice first", report.Repo[0])
+	}
+	if len(report.Workflow) != 1 {
+		t.Errorf("Workflow = %+v, want 1 learning", report.Workflow)
+	}
+
+	if len(report.Code) != 2 || report.Code[0].Path != "config.go" || report.Code[1].Path != "hello.txt" {
+		t.Fatalf("Code groups = %+v, want config.go and hello.txt", report.Code)
+	}
+	if len(report.Code[0].Learnings) != 2 || report.Code[0].Learnings[0].Line != 3 {
+		t.Errorf("config.go learnings = %+v, want two ordered by line", report.Code[0].Learnings)
+	}
+}
+
+func TestMarkStaleLearnings(t *testing.T) {
+	repo := setupLearningsTestRepo(t)
+
+	report, err := collectLearnings(context.Background(), checkpoint.NewGitStore(repo))
+	if err != nil {
+		t.Fatalf("collectLearnings() error = %v", err)
+	}
+	links, err := strategy.LoadCommitLinkIndex(context.Background(), checkpoint.NewGitStore(repo))
+	if err != nil {
+		t.Fatalf("LoadCommitLinkIndex() error = %v", err)
+	}
+	markStaleLearnings(repo, links, report)
+
+	stale := make(map[string]bool)
+	for _, group := range report.Code {
+		for _, entry := range group.Learnings {
+			stale[entry.Text] = entry.Stale
+		}
+	}
+	if !stale["Timeout is in seconds"] {
+		t.Error("learning about the edited Timeout line should be stale")
+	}
+	if stale["Load reads env vars first"] {
+		t.Error("learning about the unchanged Load line should not be stale")
+	}
+	if stale["Greeting lives here"] {
+		t.Error("learning about the unchanged greeting should not be stale")
+	}
+}
+
+func TestMarkStaleLearnings_LinkedCommit(t *testing.T) {
+	repo, _ := setupServeTestRepo(t)
+
+	// A rebase dropped the trailer; the checkpoint is only linked to the commit.
+	cpID := id.MustCheckpointID("d1d2d3d4d5d6")
+	addLearningsCheckpoint(t, repo, id.EmptyCheckpointID, "", "config.go", "package config\n\nconst Timeout = 30\n", nil)
+	head, err := repo.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := checkpoint.NewGitStore(repo).WriteCommitted(context.Background(), checkpoint.WriteCommittedOptions{
+		CheckpointID: cpID,
+		SessionID:    "2026-01-04-session",
+		Strategy:     "manual-commit",
+		Transcript:   []byte(serveTestTranscript),
+		Summary: &checkpoint.Summary{Learnings: checkpoint.LearningsSummary{
+			Code: []checkpoint.CodeLearning{{Path: "config.go", Line: 3, Finding: "Timeout is in seconds"}},
+		}},
+	}); err != nil {
+		t.Fatalf("failed to write committed checkpoint: %v", err)
+	}
+	addLearningsCheckpoint(t, repo, id.EmptyCheckpointID, "", "config.go", "package config\n\nconst Timeout = 60 * time.Second\n", nil)
+
+	report, err := collectLearnings(context.Background(), checkpoint.NewGitStore(repo))
+	if err != nil {
+		t.Fatalf("collectLearnings() error = %v", err)
+	}
+	markStaleLearnings(repo, strategy.CommitLinkIndex{head.Hash().String(): {cpID}}, report)
+
+	for _, group := range report.Code {
+		for _, entry := range group.Learnings {
+			if entry.Text == "Timeout is in seconds" && !entry.Stale {
+				t.Error("learning from a linked commit should be checked for staleness")
+			}
+		}
+	}
+}
+
+func TestContainsLines(t *testing.T) {
+	t.Parallel()
+
+	lines := []string{"a", "b", "c", "d"}
+	if !containsLines(lines, []string{"b", "c"}) {
+		t.Error("should find a consecutive block")
+	}
+	if containsLines(lines, []string{"b", "d"}) {
+		t.Error("should not match non-consecutive lines")
+	}
+	if containsLines(lines, nil) {
+		t.Error("an empty block can't be verified")
+	}
+	if got := lineRange(lines, 2, 0); len(got) != 1 || got[0] != "b" {
+		t.Errorf("lineRange(2, 0) = %v, want [b]", got)
+	}
+	if got := lineRange(lines, 3, 10); len(got) != 2 {
+		t.Errorf("lineRange(3, 10) = %v, want clamped to the end", got)
+	}
+}
+
+func TestRunLearnings_Text(t *testing.T) {
+	setupLearningsTestRepo(t)
+
+	var buf bytes.Buffer
+	if err := runLearnings(context.Background(), &buf, learningsFormatText, false, "", false); err != nil {
+		t.Fatalf("runLearnings() error = %v", err)
+	}
+	output := buf.String()
+	for _, want := range []string{
+		"Learnings from 3 checkpoint(s)",
+		"Repository:",
+		"(2 checkpoints)",
+		"config.go:3: Timeout is in seconds",
+		"[stale: lines changed since the checkpoint]",
+		"hello.txt:1: Greeting lives here",
+		"Workflow:",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+}
+
+func TestRunLearnings_StaleJSON(t *testing.T) {
+	setupLearningsTestRepo(t)
+
+	var buf bytes.Buffer
+	if err := runLearnings(context.Background(), &buf, learningsFormatJSON, true, "", false); err != nil {
+		t.Fatalf("runLearnings() error = %v", err)
+	}
+	var report learningsReport
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if len(report.Repo) != 0 || len(report.Code) != 1 || len(report.Code[0].Learnings) != 1 {
+		t.Fatalf("report = %+v, want only the stale code learning", report)
+	}
+	if got := report.Code[0].Learnings[0]; got.Text != "Timeout is in seconds" || !got.Stale {
+		t.Errorf("stale learning = %+v", got)
+	}
+}
+
+func TestRunLearnings_ProposeWrite(t *testing.T) {
+	setupLearningsTestRepo(t)
+
+	root, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	memoryPath := filepath.Join(root, "CLAUDE.md")
+	if err := os.WriteFile(memoryPath, []byte("# Project\n\nConfig lives in config.go\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := runLearnings(context.Background(), &buf, learningsFormatText, false, "claude", false); err != nil {
+		t.Fatalf("runLearnings(propose) error = %v", err)
+	}
+	proposal := buf.String()
+	if !strings.Contains(proposal, "--write") || !strings.Contains(proposal, "make test before committing") {
+		t.Errorf("proposal = %q", proposal)
+	}
+	if strings.Contains(proposal, "- Config lives in config.go") {
+		t.Error("proposal should skip learnings already in the memory file")
+	}
+	if strings.Contains(proposal, "Timeout is in seconds") {
+		t.Error("proposal should skip stale learnings")
+	}
+
+	for range 2 {
+		buf.Reset()
+		if err := runLearnings(context.Background(), &buf, learningsFormatText, false, "claude", true); err != nil {
+			t.Fatalf("runLearnings(write) error = %v", err)
+		}
+	}
+	data, err := os.ReadFile(memoryPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(data)
+	if !strings.HasPrefix(content, "# Project\n\nConfig lives in config.go\n\n"+learningsBlockStart) {
+		t.Errorf("memory file should keep its content and append the section:\n%s", content)
+	}
+	if strings.Count(content, learningsBlockStart) != 1 {
+		t.Errorf("rewriting should replace the section, not add another:\n%s", content)
+	}
+	if !strings.Contains(content, "- `hello.txt:1`: Greeting lives here") {
+		t.Errorf("section missing code learning:\n%s", content)
+	}
+}
+
+func TestRunLearnings_ProposeRemovesStaleSection(t *testing.T) {
+	repo, _ := setupServeTestRepo(t)
+
+	root, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	memoryPath := filepath.Join(root, "CLAUDE.md")
+	var buf bytes.Buffer
+	if err := runLearnings(context.Background(), &buf, learningsFormatText, false, "claude", true); err != nil {
+		t.Fatalf("runLearnings(write) error = %v", err)
+	}
+
+	// Deleting the file makes its only learning stale.
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wt.Remove("hello.txt"); err != nil {
+		t.Fatal(err)
+	}
+	addLearningsCheckpoint(t, repo, id.EmptyCheckpointID, "", "other.txt", "other\n", nil)
+	data, err := os.ReadFile(memoryPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(memoryPath, []byte("# Project\n\n"+string(data)+"\n## Notes\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	buf.Reset()
+	if err := runLearnings(context.Background(), &buf, learningsFormatText, false, "claude", true); err != nil {
+		t.Fatalf("runLearnings(write) error = %v", err)
+	}
+	data, err = os.ReadFile(memoryPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "# Project\n\n## Notes\n" {
+		t.Errorf("memory file = %q, want the stale section removed", data)
+	}
+}
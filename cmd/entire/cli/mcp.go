@@ -0,0 +1,482 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/agent"
+	"github.com/entireio/cli/cmd/entire/cli/buildinfo"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/mcp"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/session"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+	"github.com/entireio/cli/cmd/entire/cli/trailers"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/spf13/cobra"
+)
+
+const (
+	mcpDefaultSearchLimit = 20
+	mcpMaxSearchLimit     = 100
+)
+
+func newMCPCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "mcp",
+		Short: "Run an MCP server exposing checkpoint history to coding agents",
+		Long: `Run a Model Context Protocol (MCP) server over stdio.
+
+Agents connected to this server can look up why code was written using the
+checkpoints recorded on the entire/checkpoints/v1 branch. Available tools:
+
+  search_sessions            Search checkpoints by prompt, summary, file or session
+  get_checkpoint_for_commit  Get the checkpoint linked to a commit via its trailer
+  get_session_summary        Get summaries and prompts for every checkpoint of a session
+  get_learnings_for_path     Get learnings recorded for a file or directory
+
+This command is normally started by the agent, not run by hand. Register it
+with 'entire enable --mcp'.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runMCP(cmd.Context(), cmd.InOrStdin(), cmd.OutOrStdout(), cmd.ErrOrStderr())
+		},
+	}
+}
+
+func runMCP(ctx context.Context, r io.Reader, w, errW io.Writer) error {
+	repo, err := openRepository()
+	if err != nil {
+		fmt.Fprintln(errW, "Not a git repository. Run 'entire mcp' from within a git repository.")
+		return NewSilentError(err)
+	}
+
+	if err := newEntireMCPServer(repo).Serve(ctx, r, w); err != nil {
+		return fmt.Errorf("mcp server failed: %w", err)
+	}
+	return nil
+}
+
+// mcpTools implements the Entire MCP tools on top of the checkpoint store.
+type mcpTools struct {
+	repo  *git.Repository
+	store *checkpoint.GitStore
+}
+
+// newEntireMCPServer creates an MCP server with all Entire tools registered.
+func newEntireMCPServer(repo *git.Repository) *mcp.Server {
+	t := &mcpTools{repo: repo, store: checkpoint.NewGitStore(repo)}
+	s := mcp.NewServer(agent.MCPServerName, buildinfo.Version)
+
+	s.AddTool(mcp.Tool{
+		Name: "search_sessions",
+		Description: "Search recorded agent sessions. Matches every query term (case-insensitive) against " +
+			"prompts, AI summaries, files touched, branch, session ID, checkpoint ID, and the session's " +
+			"title, tags and notes. Returns the most recent matching checkpoints first. " +
+			"An empty query lists recent checkpoints.",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"query": map[string]any{"type": "string", "description": "Search terms"},
+				"tag":   map[string]any{"type": "string", "description": "Only return sessions with this tag"},
+				"limit": map[string]any{"type": "integer", "description": "Maximum results (default 20, max 100)"},
+			},
+		},
+	}, t.searchSessions)
+
+	s.AddTool(mcp.Tool{
+		Name: "get_checkpoint_for_commit",
+		Description: "Get the checkpoint linked to a git commit through its Entire-Checkpoint trailer: " +
+			"the prompts, AI summary, files touched and line attribution for each session that produced it.",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"commit": map[string]any{"type": "string", "description": "Commit SHA or revision (e.g. HEAD~2)"},
+			},
+			"required": []string{"commit"},
+		},
+	}, t.getCheckpointForCommit)
+
+	s.AddTool(mcp.Tool{
+		Name:        "get_session_summary",
+		Description: "Get the prompts and AI summaries for every checkpoint recorded by a session, oldest first.",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"session_id": map[string]any{"type": "string", "description": "Session ID (as returned by search_sessions)"},
+			},
+			"required": []string{"session_id"},
+		},
+	}, t.getSessionSummary)
+
+	s.AddTool(mcp.Tool{
+		Name: "get_learnings_for_path",
+		Description: "Get learnings recorded in checkpoint summaries for a file or directory. Returns code " +
+			"learnings about the path, plus repository and workflow learnings from sessions that touched it.",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"path": map[string]any{"type": "string", "description": "Repository-relative file or directory path"},
+			},
+			"required": []string{"path"},
+		},
+	}, t.getLearningsForPath)
+
+	return s
+}
+
+// mcpSession is the per-session view of a checkpoint returned by the tools.
+type mcpSession struct {
+	CheckpointID string                         `json:"checkpoint_id"`
+	SessionID    string                         `json:"session_id"`
+	CreatedAt    time.Time                      `json:"created_at"`
+	Agent        agent.AgentType                `json:"agent,omitempty"`
+	Branch       string                         `json:"branch,omitempty"`
+	FilesTouched []string                       `json:"files_touched,omitempty"`
+	Prompts      []string                       `json:"prompts,omitempty"`
+	Summary      *checkpoint.Summary            `json:"summary,omitempty"`
+	Attribution  *checkpoint.InitialAttribution `json:"attribution,omitempty"`
+	Title        string                         `json:"title,omitempty"`
+	Tags         []string                       `json:"tags,omitempty"`
+	Notes        []session.Note                 `json:"notes,omitempty"`
+}
+
+func newMCPSession(cpID id.CheckpointID, content *checkpoint.SessionContent) mcpSession {
+	meta := content.Metadata
+	return mcpSession{
+		CheckpointID: cpID.String(),
+		SessionID:    meta.SessionID,
+		CreatedAt:    meta.CreatedAt,
+		Agent:        meta.Agent,
+		Branch:       meta.Branch,
+		FilesTouched: meta.FilesTouched,
+		Prompts:      splitPrompts(content.Prompts),
+		Summary:      meta.Summary,
+		Attribution:  meta.InitialAttribution,
+		Title:        meta.Title,
+		Tags:         meta.Tags,
+		Notes:        meta.Notes,
+	}
+}
+
+// splitPrompts splits prompt.txt content into individual prompts.
+func splitPrompts(content string) []string {
+	var prompts []string
+	for _, p := range strings.Split(content, "\n\n---\n\n") {
+		if p = strings.TrimSpace(p); p != "" {
+			prompts = append(prompts, p)
+		}
+	}
+	return prompts
+}
+
+// forEachCommittedSession calls fn for every session of every committed checkpoint,
+// most recent checkpoint first. Transcripts are not loaded. Returning
+// errStopIteration from fn ends the scan early without error.
+func forEachCommittedSession(ctx context.Context, store *checkpoint.GitStore, fn func(cpID id.CheckpointID, content *checkpoint.SessionContent) error) error {
+	infos, err := store.ListCommitted(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list checkpoints: %w", err)
+	}
+
+	for _, info := range infos {
+		sessionCount := info.SessionCount
+		if sessionCount == 0 {
+			sessionCount = 1
+		}
+		for i := range sessionCount {
+			content, readErr := store.ReadSessionMetadataAndPrompts(ctx, info.CheckpointID, i)
+			if readErr != nil {
+				continue
+			}
+			if err := fn(info.CheckpointID, content); err != nil {
+				if errors.Is(err, errStopIteration) {
+					return nil
+				}
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (t *mcpTools) searchSessions(ctx context.Context, args json.RawMessage) (string, error) {
+	in := struct {
+		Query string `json:"query"`
+		Tag   string `json:"tag"`
+		Limit int    `json:"limit"`
+	}{Limit: mcpDefaultSearchLimit}
+	if err := mcp.DecodeArgs(args, &in); err != nil {
+		return "", err //nolint:wrapcheck // Already descriptive, reported to the agent as-is
+	}
+	if in.Limit <= 0 {
+		in.Limit = mcpDefaultSearchLimit
+	}
+	in.Limit = min(in.Limit, mcpMaxSearchLimit)
+
+	terms := strings.Fields(strings.ToLower(in.Query))
+	results := []mcpSession{}
+	err := forEachCommittedSession(ctx, t.store, func(cpID id.CheckpointID, content *checkpoint.SessionContent) error {
+		if in.Tag != "" && !content.Metadata.HasTag(in.Tag) {
+			return nil
+		}
+		if !sessionMatchesTerms(cpID, content, terms) {
+			return nil
+		}
+		results = append(results, newMCPSession(cpID, content))
+		if len(results) >= in.Limit {
+			return errStopIteration
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return marshalMCPResult(results)
+}
+
+// sessionMatchesTerms reports whether every term appears in the session's searchable text.
+func sessionMatchesTerms(cpID id.CheckpointID, content *checkpoint.SessionContent, terms []string) bool {
+	if len(terms) == 0 {
+		return true
+	}
+
+	meta := content.Metadata
+	parts := []string{cpID.String(), meta.SessionID, meta.Branch, content.Prompts}
+	parts = append(parts, meta.FilesTouched...)
+	parts = append(parts, meta.Title, meta.FormatTags())
+	for _, note := range meta.Notes {
+		parts = append(parts, note.Text)
+	}
+	if s := meta.Summary; s != nil {
+		parts = append(parts, s.Intent, s.Outcome)
+		parts = append(parts, s.Learnings.Repo...)
+		parts = append(parts, s.Learnings.Workflow...)
+		for _, l := range s.Learnings.Code {
+			parts = append(parts, l.Path, l.Finding)
+		}
+		parts = append(parts, s.Friction...)
+		parts = append(parts, s.OpenItems...)
+	}
+	haystack := strings.ToLower(strings.Join(parts, "\n"))
+
+	for _, term := range terms {
+		if !strings.Contains(haystack, term) {
+			return false
+		}
+	}
+	return true
+}
+
+func (t *mcpTools) getCheckpointForCommit(ctx context.Context, args json.RawMessage) (string, error) {
+	var in struct {
+		Commit string `json:"commit"`
+	}
+	if err := mcp.DecodeArgs(args, &in); err != nil {
+		return "", err //nolint:wrapcheck // Already descriptive, reported to the agent as-is
+	}
+	if in.Commit == "" {
+		return "", errors.New("commit is required")
+	}
+
+	hash, err := t.repo.ResolveRevision(plumbing.Revision(in.Commit))
+	if err != nil {
+		return "", fmt.Errorf("commit not found: %s", in.Commit)
+	}
+	commit, err := t.repo.CommitObject(*hash)
+	if err != nil {
+		return "", fmt.Errorf("failed to get commit: %w", err)
+	}
+
+	cpIDs, err := strategy.ResolveCommitCheckpoints(ctx, t.repo, commit)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve checkpoint for commit: %w", err)
+	}
+	if len(cpIDs) == 0 {
+		return fmt.Sprintf("Commit %s has no %s trailer; it was not made during an Entire session.",
+			hash.String()[:7], trailers.CheckpointTrailerKey), nil
+	}
+	cpID := cpIDs[0]
+
+	summary, err := t.store.ReadCommitted(ctx, cpID)
+	if err != nil {
+		return "", fmt.Errorf("failed to read checkpoint %s: %w", cpID, err)
+	}
+	if summary == nil {
+		return "", fmt.Errorf("checkpoint %s referenced by commit %s not found (try 'git fetch origin %s')",
+			cpID, hash.String()[:7], paths.MetadataBranchName)
+	}
+
+	sessions := make([]mcpSession, 0, len(summary.Sessions))
+	for i := range summary.Sessions {
+		content, readErr := t.store.ReadSessionMetadataAndPrompts(ctx, cpID, i)
+		if readErr != nil {
+			continue
+		}
+		sessions = append(sessions, newMCPSession(cpID, content))
+	}
+
+	return marshalMCPResult(struct {
+		Commit        string       `json:"commit"`
+		CommitMessage string       `json:"commit_message"`
+		CheckpointID  string       `json:"checkpoint_id"`
+		Sessions      []mcpSession `json:"sessions"`
+	}{
+		Commit:        hash.String(),
+		CommitMessage: strings.TrimSpace(commit.Message),
+		CheckpointID:  cpID.String(),
+		Sessions:      sessions,
+	})
+}
+
+func (t *mcpTools) getSessionSummary(ctx context.Context, args json.RawMessage) (string, error) {
+	var in struct {
+		SessionID string `json:"session_id"`
+	}
+	if err := mcp.DecodeArgs(args, &in); err != nil {
+		return "", err //nolint:wrapcheck // Already descriptive, reported to the agent as-is
+	}
+	if in.SessionID == "" {
+		return "", errors.New("session_id is required")
+	}
+
+	var checkpoints []mcpSession
+	err := forEachCommittedSession(ctx, t.store, func(cpID id.CheckpointID, content *checkpoint.SessionContent) error {
+		if content.Metadata.SessionID == in.SessionID {
+			checkpoints = append(checkpoints, newMCPSession(cpID, content))
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(checkpoints) == 0 {
+		return "", fmt.Errorf("no checkpoints found for session %s", in.SessionID)
+	}
+
+	// Scan order is most recent first; present the session chronologically.
+	for i, j := 0, len(checkpoints)-1; i < j; i, j = i+1, j-1 {
+		checkpoints[i], checkpoints[j] = checkpoints[j], checkpoints[i]
+	}
+
+	return marshalMCPResult(struct {
+		SessionID   string       `json:"session_id"`
+		Checkpoints []mcpSession `json:"checkpoints"`
+	}{
+		SessionID:   in.SessionID,
+		Checkpoints: checkpoints,
+	})
+}
+
+// mcpCodeLearning is a code learning tagged with the checkpoint it came from.
+type mcpCodeLearning struct {
+	checkpoint.CodeLearning
+
+	CheckpointID string    `json:"checkpoint_id"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// mcpLearning is a repository or workflow learning tagged with its checkpoint.
+type mcpLearning struct {
+	Learning     string    `json:"learning"`
+	CheckpointID string    `json:"checkpoint_id"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func (t *mcpTools) getLearningsForPath(ctx context.Context, args json.RawMessage) (string, error) {
+	var in struct {
+		Path string `json:"path"`
+	}
+	if err := mcp.DecodeArgs(args, &in); err != nil {
+		return "", err //nolint:wrapcheck // Already descriptive, reported to the agent as-is
+	}
+	target := normalizeLearningPath(in.Path)
+	if target == "" {
+		return "", errors.New("path is required")
+	}
+
+	result := struct {
+		Path     string            `json:"path"`
+		Code     []mcpCodeLearning `json:"code"`
+		Repo     []mcpLearning     `json:"repo"`
+		Workflow []mcpLearning     `json:"workflow"`
+	}{
+		Path:     target,
+		Code:     []mcpCodeLearning{},
+		Repo:     []mcpLearning{},
+		Workflow: []mcpLearning{},
+	}
+
+	err := forEachCommittedSession(ctx, t.store, func(cpID id.CheckpointID, content *checkpoint.SessionContent) error {
+		meta := content.Metadata
+		if meta.Summary == nil {
+			return nil
+		}
+		for _, l := range meta.Summary.Learnings.Code {
+			if pathMatches(normalizeLearningPath(l.Path), target) {
+				result.Code = append(result.Code, mcpCodeLearning{CodeLearning: l, CheckpointID: cpID.String(), CreatedAt: meta.CreatedAt})
+			}
+		}
+
+		touched := false
+		for _, f := range meta.FilesTouched {
+			if pathMatches(normalizeLearningPath(f), target) {
+				touched = true
+				break
+			}
+		}
+		if !touched {
+			return nil
+		}
+		for _, l := range meta.Summary.Learnings.Repo {
+			result.Repo = append(result.Repo, mcpLearning{Learning: l, CheckpointID: cpID.String(), CreatedAt: meta.CreatedAt})
+		}
+		for _, l := range meta.Summary.Learnings.Workflow {
+			result.Workflow = append(result.Workflow, mcpLearning{Learning: l, CheckpointID: cpID.String(), CreatedAt: meta.CreatedAt})
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return marshalMCPResult(result)
+}
+
+// normalizeLearningPath cleans a repo-relative path for comparison.
+func normalizeLearningPath(p string) string {
+	p = strings.TrimSpace(p)
+	if p == "" {
+		return ""
+	}
+	p = path.Clean(strings.ReplaceAll(p, "\\", "/"))
+	p = strings.TrimPrefix(p, "./")
+	if p == "." {
+		return ""
+	}
+	return p
+}
+
+// pathMatches reports whether candidate is target itself or lies beneath it.
+func pathMatches(candidate, target string) bool {
+	if candidate == "" {
+		return false
+	}
+	return candidate == target || strings.HasPrefix(candidate, target+"/")
+}
+
+func marshalMCPResult(v any) (string, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode result: %w", err)
+	}
+	return string(data), nil
+}
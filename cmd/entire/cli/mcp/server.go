@@ -0,0 +1,242 @@
+// Package mcp implements a minimal Model Context Protocol server over stdio.
+//
+// Only the subset of the protocol needed to expose tools is supported:
+// initialize, ping, tools/list and tools/call. Messages are newline-delimited
+// JSON-RPC 2.0, as specified by the MCP stdio transport.
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+// ProtocolVersion is the MCP protocol revision this server implements.
+// Clients requesting a different revision are answered with this one,
+// and may disconnect if they don't support it.
+const ProtocolVersion = "2025-06-18"
+
+// maxMessageSize bounds a single JSON-RPC message read from the client.
+const maxMessageSize = 10 * 1024 * 1024
+
+// JSON-RPC 2.0 error codes.
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+// ToolHandler executes a tool call. args holds the raw "arguments" object
+// from the request (may be nil). The returned text is sent back as a single
+// text content block. Returning an error reports a tool-level failure to the
+// agent (isError: true) rather than a protocol error.
+type ToolHandler func(ctx context.Context, args json.RawMessage) (string, error)
+
+// Tool describes a tool exposed by the server.
+type Tool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	InputSchema map[string]any `json:"inputSchema"`
+
+	handler ToolHandler
+}
+
+// Server is an MCP server exposing a fixed set of tools.
+type Server struct {
+	name    string
+	version string
+
+	mu    sync.RWMutex
+	tools map[string]Tool
+}
+
+// NewServer creates a server that identifies itself with the given name and version.
+func NewServer(name, version string) *Server {
+	return &Server{
+		name:    name,
+		version: version,
+		tools:   make(map[string]Tool),
+	}
+}
+
+// AddTool registers a tool. Registering a name twice replaces the earlier tool.
+func (s *Server) AddTool(tool Tool, handler ToolHandler) {
+	tool.handler = handler
+	if tool.InputSchema == nil {
+		tool.InputSchema = map[string]any{"type": "object"}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tools[tool.Name] = tool
+}
+
+// request is an incoming JSON-RPC message. ID is absent for notifications.
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// textContent is an MCP text content block.
+type textContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type callToolResult struct {
+	Content []textContent `json:"content"`
+	IsError bool          `json:"isError,omitempty"`
+}
+
+// Serve reads requests from r and writes responses to w until r is exhausted
+// or ctx is cancelled. Requests are handled sequentially.
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxMessageSize)
+	encoder := json.NewEncoder(w)
+
+	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return nil //nolint:nilerr // Cancellation is a normal shutdown
+		}
+
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		resp := s.handleMessage(ctx, line)
+		if resp == nil {
+			continue // Notification: no response
+		}
+		if err := encoder.Encode(resp); err != nil {
+			return fmt.Errorf("failed to write response: %w", err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read request: %w", err)
+	}
+	return nil
+}
+
+// handleMessage processes a single raw message. Returns nil for notifications.
+func (s *Server) handleMessage(ctx context.Context, raw []byte) *response {
+	var req request
+	if err := json.Unmarshal(raw, &req); err != nil {
+		return errorResponse(json.RawMessage("null"), codeParseError, "parse error: "+err.Error())
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		if len(req.ID) == 0 {
+			return nil
+		}
+		return errorResponse(req.ID, codeInvalidRequest, "invalid request")
+	}
+
+	result, rpcErr := s.dispatch(ctx, req)
+
+	// Notifications never get a response, even on error.
+	if len(req.ID) == 0 {
+		return nil
+	}
+	if rpcErr != nil {
+		return &response{JSONRPC: "2.0", ID: req.ID, Error: rpcErr}
+	}
+	return &response{JSONRPC: "2.0", ID: req.ID, Result: result}
+}
+
+func (s *Server) dispatch(ctx context.Context, req request) (any, *rpcError) {
+	switch req.Method {
+	case "initialize":
+		return map[string]any{
+			"protocolVersion": ProtocolVersion,
+			"capabilities": map[string]any{
+				"tools": map[string]any{},
+			},
+			"serverInfo": map[string]any{
+				"name":    s.name,
+				"version": s.version,
+			},
+		}, nil
+	case "notifications/initialized", "notifications/cancelled":
+		return nil, nil
+	case "ping":
+		return map[string]any{}, nil
+	case "tools/list":
+		return map[string]any{"tools": s.listTools()}, nil
+	case "tools/call":
+		return s.callTool(ctx, req.Params)
+	default:
+		return nil, &rpcError{Code: codeMethodNotFound, Message: "method not found: " + req.Method}
+	}
+}
+
+func (s *Server) listTools() []Tool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tools := make([]Tool, 0, len(s.tools))
+	for _, t := range s.tools {
+		tools = append(tools, t)
+	}
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+	return tools
+}
+
+func (s *Server) callTool(ctx context.Context, params json.RawMessage) (any, *rpcError) {
+	var call struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments,omitempty"`
+	}
+	if err := json.Unmarshal(params, &call); err != nil || call.Name == "" {
+		return nil, &rpcError{Code: codeInvalidParams, Message: "tools/call requires a tool name"}
+	}
+
+	s.mu.RLock()
+	tool, ok := s.tools[call.Name]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, &rpcError{Code: codeInvalidParams, Message: "unknown tool: " + call.Name}
+	}
+
+	text, err := tool.handler(ctx, call.Arguments)
+	if err != nil {
+		return callToolResult{
+			Content: []textContent{{Type: "text", Text: err.Error()}},
+			IsError: true,
+		}, nil
+	}
+	return callToolResult{Content: []textContent{{Type: "text", Text: text}}}, nil
+}
+
+func errorResponse(id json.RawMessage, code int, message string) *response {
+	return &response{JSONRPC: "2.0", ID: id, Error: &rpcError{Code: code, Message: message}}
+}
+
+// DecodeArgs unmarshals tool arguments into v. Empty arguments leave v untouched.
+func DecodeArgs(args json.RawMessage, v any) error {
+	if len(args) == 0 || string(args) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(args, v); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	return nil
+}
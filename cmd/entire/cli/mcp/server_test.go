@@ -0,0 +1,185 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func newTestServer() *Server {
+	s := NewServer("entire", "test")
+	s.AddTool(Tool{
+		Name:        "echo",
+		Description: "Echo the message argument",
+		InputSchema: map[string]any{
+			"type":       "object",
+			"properties": map[string]any{"message": map[string]any{"type": "string"}},
+		},
+	}, func(_ context.Context, args json.RawMessage) (string, error) {
+		var in struct {
+			Message string `json:"message"`
+		}
+		if err := DecodeArgs(args, &in); err != nil {
+			return "", err
+		}
+		if in.Message == "" {
+			return "", errors.New("message is required")
+		}
+		return in.Message, nil
+	})
+	return s
+}
+
+// serveLines runs the server over the given request lines and returns the decoded responses.
+func serveLines(t *testing.T, s *Server, lines ...string) []map[string]any {
+	t.Helper()
+
+	var out bytes.Buffer
+	if err := s.Serve(context.Background(), strings.NewReader(strings.Join(lines, "\n")+"\n"), &out); err != nil {
+		t.Fatalf("Serve() error = %v", err)
+	}
+
+	var responses []map[string]any
+	dec := json.NewDecoder(&out)
+	for dec.More() {
+		var resp map[string]any
+		if err := dec.Decode(&resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		responses = append(responses, resp)
+	}
+	return responses
+}
+
+func TestServe_Initialize(t *testing.T) {
+	t.Parallel()
+
+	responses := serveLines(t, newTestServer(),
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{},"clientInfo":{"name":"test","version":"1"}}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+	)
+
+	if len(responses) != 1 {
+		t.Fatalf("got %d responses, want 1 (notifications get no response)", len(responses))
+	}
+	result, ok := responses[0]["result"].(map[string]any)
+	if !ok {
+		t.Fatalf("initialize result missing: %v", responses[0])
+	}
+	if result["protocolVersion"] != ProtocolVersion {
+		t.Errorf("protocolVersion = %v, want %s", result["protocolVersion"], ProtocolVersion)
+	}
+	serverInfo, ok := result["serverInfo"].(map[string]any)
+	if !ok || serverInfo["name"] != "entire" {
+		t.Errorf("serverInfo = %v, want name entire", result["serverInfo"])
+	}
+	if _, ok := result["capabilities"].(map[string]any)["tools"]; !ok {
+		t.Error("capabilities should advertise tools")
+	}
+}
+
+func TestServe_ToolsList(t *testing.T) {
+	t.Parallel()
+
+	responses := serveLines(t, newTestServer(), `{"jsonrpc":"2.0","id":"a","method":"tools/list"}`)
+
+	if len(responses) != 1 {
+		t.Fatalf("got %d responses, want 1", len(responses))
+	}
+	if responses[0]["id"] != "a" {
+		t.Errorf("id = %v, want a (string ids must round-trip)", responses[0]["id"])
+	}
+	tools := responses[0]["result"].(map[string]any)["tools"].([]any)
+	if len(tools) != 1 {
+		t.Fatalf("got %d tools, want 1", len(tools))
+	}
+	tool := tools[0].(map[string]any)
+	if tool["name"] != "echo" {
+		t.Errorf("tool name = %v, want echo", tool["name"])
+	}
+	if _, ok := tool["inputSchema"]; !ok {
+		t.Error("tool should include inputSchema")
+	}
+}
+
+func TestServe_ToolsCall(t *testing.T) {
+	t.Parallel()
+
+	responses := serveLines(t, newTestServer(),
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"echo","arguments":{"message":"hi"}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"echo","arguments":{}}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"nope"}}`,
+	)
+	if len(responses) != 3 {
+		t.Fatalf("got %d responses, want 3", len(responses))
+	}
+
+	ok := responses[0]["result"].(map[string]any)
+	if text := ok["content"].([]any)[0].(map[string]any)["text"]; text != "hi" {
+		t.Errorf("echo text = %v, want hi", text)
+	}
+	if ok["isError"] == true {
+		t.Error("successful call should not set isError")
+	}
+
+	failed := responses[1]["result"].(map[string]any)
+	if failed["isError"] != true {
+		t.Errorf("handler error should set isError, got %v", failed)
+	}
+
+	if responses[2]["error"] == nil {
+		t.Errorf("unknown tool should produce a JSON-RPC error, got %v", responses[2])
+	}
+}
+
+func TestServe_Errors(t *testing.T) {
+	t.Parallel()
+
+	responses := serveLines(t, newTestServer(),
+		`not json`,
+		`{"jsonrpc":"2.0","id":7,"method":"resources/list"}`,
+		`{"jsonrpc":"2.0","id":8,"method":"ping"}`,
+	)
+	if len(responses) != 3 {
+		t.Fatalf("got %d responses, want 3", len(responses))
+	}
+
+	codeOf := func(resp map[string]any) float64 {
+		errObj, ok := resp["error"].(map[string]any)
+		if !ok {
+			return 0
+		}
+		code, _ := errObj["code"].(float64) //nolint:errcheck // zero value signals absence
+		return code
+	}
+	if got := codeOf(responses[0]); got != codeParseError {
+		t.Errorf("parse error code = %v, want %d", got, codeParseError)
+	}
+	if got := codeOf(responses[1]); got != codeMethodNotFound {
+		t.Errorf("unknown method code = %v, want %d", got, codeMethodNotFound)
+	}
+	if _, ok := responses[2]["result"]; !ok {
+		t.Errorf("ping should return an empty result, got %v", responses[2])
+	}
+}
+
+func TestDecodeArgs(t *testing.T) {
+	t.Parallel()
+
+	var in struct {
+		Limit int `json:"limit"`
+	}
+	in.Limit = 5
+	if err := DecodeArgs(nil, &in); err != nil || in.Limit != 5 {
+		t.Errorf("nil args should leave defaults: limit=%d err=%v", in.Limit, err)
+	}
+	if err := DecodeArgs(json.RawMessage(`{"limit":2}`), &in); err != nil || in.Limit != 2 {
+		t.Errorf("DecodeArgs limit=%d err=%v, want 2", in.Limit, err)
+	}
+	if err := DecodeArgs(json.RawMessage(`{"limit":"x"}`), &in); err == nil {
+		t.Error("expected error for wrong argument type")
+	}
+}
@@ -0,0 +1,215 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/agent"
+	"github.com/entireio/cli/cmd/entire/cli/agent/claudecode"
+	"github.com/entireio/cli/cmd/entire/cli/mcp"
+)
+
+// callMCPTool runs a single tools/call through the Entire MCP server and
+// returns the text content and whether the call reported an error.
+func callMCPTool(t *testing.T, s *mcp.Server, name string, args map[string]any) (string, bool) {
+	t.Helper()
+
+	params, err := json.Marshal(map[string]any{"name": name, "arguments": args})
+	if err != nil {
+		t.Fatalf("failed to marshal params: %v", err)
+	}
+	req := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":` + string(params) + "}\n"
+
+	var out bytes.Buffer
+	if err := s.Serve(context.Background(), strings.NewReader(req), &out); err != nil {
+		t.Fatalf("Serve() error = %v", err)
+	}
+
+	var resp struct {
+		Result struct {
+			Content []struct {
+				Text string `json:"text"`
+			} `json:"content"`
+			IsError bool `json:"isError"`
+		} `json:"result"`
+		Error *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response %q: %v", out.String(), err)
+	}
+	if resp.Error != nil {
+		t.Fatalf("%s returned protocol error: %s", name, resp.Error.Message)
+	}
+	if len(resp.Result.Content) != 1 {
+		t.Fatalf("%s returned %d content blocks, want 1", name, len(resp.Result.Content))
+	}
+	return resp.Result.Content[0].Text, resp.Result.IsError
+}
+
+func TestNewMCPCmd(t *testing.T) {
+	t.Parallel()
+
+	cmd := newMCPCmd()
+	if cmd.Use != "mcp" {
+		t.Errorf("Use = %q, want mcp", cmd.Use)
+	}
+	if err := cmd.Args(cmd, []string{"extra"}); err == nil {
+		t.Error("expected positional args to be rejected")
+	}
+}
+
+func TestMCP_SearchSessions(t *testing.T) {
+	repo, cpID := setupServeTestRepo(t)
+	s := newEntireMCPServer(repo)
+
+	text, isErr := callMCPTool(t, s, "search_sessions", map[string]any{"query": "GREETING hello.txt"})
+	if isErr {
+		t.Fatalf("search_sessions error: %s", text)
+	}
+	var results []mcpSession
+	if err := json.Unmarshal([]byte(text), &results); err != nil {
+		t.Fatalf("failed to parse results: %v\n%s", err, text)
+	}
+	if len(results) != 1 || results[0].CheckpointID != cpID.String() {
+		t.Fatalf("results = %+v, want checkpoint %s", results, cpID)
+	}
+	if results[0].Summary == nil || results[0].Summary.Intent != "Add a greeting file" {
+		t.Errorf("summary missing from result: %+v", results[0])
+	}
+	if len(results[0].Prompts) != 1 {
+		t.Errorf("prompts = %v, want 1 prompt", results[0].Prompts)
+	}
+
+	text, _ = callMCPTool(t, s, "search_sessions", map[string]any{"query": "greeting nonexistent"})
+	if err := json.Unmarshal([]byte(text), &results); err != nil {
+		t.Fatalf("failed to parse results: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("all terms must match, got %d results", len(results))
+	}
+}
+
+func TestMCP_GetCheckpointForCommit(t *testing.T) {
+	repo, cpID := setupServeTestRepo(t)
+	s := newEntireMCPServer(repo)
+
+	text, isErr := callMCPTool(t, s, "get_checkpoint_for_commit", map[string]any{"commit": "HEAD"})
+	if isErr {
+		t.Fatalf("get_checkpoint_for_commit error: %s", text)
+	}
+	if !strings.Contains(text, `"checkpoint_id": "`+cpID.String()+`"`) {
+		t.Errorf("result missing checkpoint id:\n%s", text)
+	}
+	if !strings.Contains(text, "2026-01-01-serve-session") {
+		t.Errorf("result missing session:\n%s", text)
+	}
+
+	text, _ = callMCPTool(t, s, "get_checkpoint_for_commit", map[string]any{"commit": "HEAD~1"})
+	if !strings.Contains(text, "no Entire-Checkpoint trailer") {
+		t.Errorf("commit without trailer should say so, got:\n%s", text)
+	}
+
+	if _, isErr := callMCPTool(t, s, "get_checkpoint_for_commit", map[string]any{"commit": "nosuchref"}); !isErr {
+		t.Error("unresolvable commit should be a tool error")
+	}
+}
+
+func TestMCP_GetSessionSummary(t *testing.T) {
+	repo, cpID := setupServeTestRepo(t)
+	s := newEntireMCPServer(repo)
+
+	text, isErr := callMCPTool(t, s, "get_session_summary", map[string]any{"session_id": "2026-01-01-serve-session"})
+	if isErr {
+		t.Fatalf("get_session_summary error: %s", text)
+	}
+	if !strings.Contains(text, cpID.String()) || !strings.Contains(text, "Created hello.txt") {
+		t.Errorf("session summary missing checkpoint or outcome:\n%s", text)
+	}
+	if !strings.Contains(text, "No test for the greeting") {
+		t.Errorf("session summary missing template extension:\n%s", text)
+	}
+
+	if text, isErr := callMCPTool(t, s, "get_session_summary", map[string]any{"session_id": "unknown"}); !isErr {
+		t.Errorf("unknown session should be a tool error, got:\n%s", text)
+	}
+}
+
+func TestMCP_GetLearningsForPath(t *testing.T) {
+	repo, _ := setupServeTestRepo(t)
+	s := newEntireMCPServer(repo)
+
+	text, isErr := callMCPTool(t, s, "get_learnings_for_path", map[string]any{"path": "./hello.txt"})
+	if isErr {
+		t.Fatalf("get_learnings_for_path error: %s", text)
+	}
+	if !strings.Contains(text, "Greeting lives here") {
+		t.Errorf("learnings missing code learning:\n%s", text)
+	}
+
+	text, _ = callMCPTool(t, s, "get_learnings_for_path", map[string]any{"path": "other.go"})
+	if strings.Contains(text, "Greeting lives here") {
+		t.Errorf("unrelated path should not return learnings:\n%s", text)
+	}
+
+	if _, isErr := callMCPTool(t, s, "get_learnings_for_path", map[string]any{}); !isErr {
+		t.Error("missing path should be a tool error")
+	}
+}
+
+func TestPathMatches(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		candidate, target string
+		want              bool
+	}{
+		{"cmd/cli/root.go", "cmd/cli/root.go", true},
+		{"cmd/cli/root.go", "cmd/cli", true},
+		{"cmd/clix/root.go", "cmd/cli", false},
+		{"", "cmd", false},
+	}
+	for _, tt := range tests {
+		if got := pathMatches(tt.candidate, tt.target); got != tt.want {
+			t.Errorf("pathMatches(%q, %q) = %v, want %v", tt.candidate, tt.target, got, tt.want)
+		}
+	}
+	if got := normalizeLearningPath(`./a\b/../c.go`); got != "a/c.go" {
+		t.Errorf("normalizeLearningPath = %q, want a/c.go", got)
+	}
+}
+
+func TestSetupMCPServer(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+
+	ag, err := agent.Get(agent.AgentNameClaudeCode)
+	if err != nil {
+		t.Fatalf("agent.Get() error = %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := setupMCPServer(&out, ag, false); err != nil {
+		t.Fatalf("setupMCPServer() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "MCP server registered") {
+		t.Errorf("unexpected output: %q", out.String())
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, claudecode.ClaudeMCPConfigFileName)); err != nil {
+		t.Errorf(".mcp.json not written: %v", err)
+	}
+
+	out.Reset()
+	if err := setupMCPServer(&out, ag, false); err != nil {
+		t.Fatalf("second setupMCPServer() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "already registered") {
+		t.Errorf("second run should be idempotent, got %q", out.String())
+	}
+}
@@ -18,6 +18,10 @@ const (
 	EntireDir         = ".entire"
 	EntireTmpDir      = ".entire/tmp"
 	EntireMetadataDir = ".entire/metadata"
+
+	// SnapshotRedactionsFile lists, in shadow branch trees, the snapshotted
+	// files whose contents were redacted.
+	SnapshotRedactionsFile = ".entire/snapshot-redactions.txt"
 )
 
 // Metadata file names
@@ -30,6 +34,7 @@ const (
 	MetadataFileName         = "metadata.json"
 	CheckpointFileName       = "checkpoint.json"
 	ContentHashFileName      = "content_hash.txt"
+	RedactionsFileName       = "redactions.json"
 	SettingsFileName         = "settings.json"
 )
 
@@ -0,0 +1,548 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/entireio/cli/cmd/entire/cli/agent"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/logging"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+	"github.com/entireio/cli/cmd/entire/cli/summarize"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/spf13/cobra"
+)
+
+// prDescriptionTemplateFile is the repo-relative path of an optional custom
+// template for `entire pr-description`. It is a Go text/template rendered with
+// a prDescription value.
+const prDescriptionTemplateFile = paths.EntireDir + "/pr-description.md.tmpl"
+
+func newPRDescriptionCmd() *cobra.Command {
+	var generateFlag bool
+	var templateFlag string
+
+	cmd := &cobra.Command{
+		Use:   "pr-description [base..head]",
+		Short: "Generate a pull request description from branch checkpoints",
+		Long: `Generate a Markdown pull request body from the checkpoints linked to the
+commits in a range.
+
+The range defaults to <default branch>..HEAD. A single revision is treated as
+<revision>..HEAD.
+
+The description merges the intent, outcome, open items, and friction from each
+checkpoint's AI summary, and reports aggregate line attribution and token usage.
+
+Teams can shape the output with a Go text/template at
+.entire/pr-description.md.tmpl (or pass --template). Templates can use
+.Intents, .Outcomes, .OpenItems, .Friction, .Extensions, .Attribution,
+.TokenUsage, .TotalTokens, .Commits, .Checkpoints, and .MissingSummaries.
+.Extensions holds the fields added by a summary template, each with a .Label
+and merged .Values.
+
+Use --generate to create AI summaries for checkpoints that don't have one yet.
+Generated summaries are saved to the checkpoint, as with 'entire explain --generate'.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if checkDisabledGuard(cmd.OutOrStdout()) {
+				return nil
+			}
+			rangeArg := ""
+			if len(args) > 0 {
+				rangeArg = args[0]
+			}
+			return runPRDescription(cmd.Context(), cmd.OutOrStdout(), cmd.ErrOrStderr(), rangeArg, templateFlag, generateFlag)
+		},
+	}
+
+	cmd.Flags().BoolVar(&generateFlag, "generate", false, "Generate AI summaries for checkpoints that are missing one")
+	cmd.Flags().StringVar(&templateFlag, "template", "", "Path to a custom template (default: "+prDescriptionTemplateFile+" if present)")
+
+	return cmd
+}
+
+func runPRDescription(ctx context.Context, w, errW io.Writer, rangeArg, templatePath string, generate bool) error {
+	repo, err := openRepository()
+	if err != nil {
+		return fmt.Errorf("not a git repository: %w", err)
+	}
+
+	base, head, err := parseRevisionRange(rangeArg)
+	if err != nil {
+		return err
+	}
+	if base == "" {
+		base = defaultBranchRevision(repo)
+		if base == "" {
+			return errors.New("could not determine the default branch; pass a range such as main..HEAD")
+		}
+	}
+
+	tmpl, err := loadPRDescriptionTemplate(templatePath)
+	if err != nil {
+		return err
+	}
+
+	var generator summarize.Generator
+	if generate {
+		generator, err = summarize.ConfiguredGenerator()
+		if err != nil {
+			return fmt.Errorf("failed to configure summary generator: %w", err)
+		}
+	}
+
+	desc, err := buildPRDescription(ctx, repo, base, head, generator, errW)
+	if err != nil {
+		return err
+	}
+
+	if err := tmpl.Execute(w, desc); err != nil {
+		return fmt.Errorf("failed to render PR description: %w", err)
+	}
+	return nil
+}
+
+// parseRevisionRange splits "base..head" into its parts. A bare revision is
+// treated as base with HEAD as head; an empty argument leaves base empty so
+// the caller can pick a default.
+func parseRevisionRange(arg string) (string, string, error) {
+	if strings.Contains(arg, "...") {
+		return "", "", fmt.Errorf("symmetric difference ranges are not supported: %s (use base..head)", arg)
+	}
+	base, head, found := strings.Cut(arg, "..")
+	if !found {
+		return arg, "HEAD", nil
+	}
+	if head == "" {
+		head = "HEAD"
+	}
+	if base == "" {
+		return "", "", fmt.Errorf("range %q is missing a base revision", arg)
+	}
+	return base, head, nil
+}
+
+// defaultBranchRevision returns a revision for the repository's default branch,
+// preferring the local branch over origin's. Returns "" if none can be found.
+func defaultBranchRevision(repo *git.Repository) string {
+	name := strategy.GetDefaultBranchName(repo)
+	if name == "" {
+		return ""
+	}
+	if _, err := repo.Reference(plumbing.NewBranchReferenceName(name), true); err == nil {
+		return name
+	}
+	if _, err := repo.Reference(plumbing.NewRemoteReferenceName("origin", name), true); err == nil {
+		return "origin/" + name
+	}
+	return ""
+}
+
+// prDescription is the data passed to the PR description template.
+type prDescription struct {
+	Base string
+	Head string
+
+	// Commits are the commits in the range that carry a checkpoint trailer, oldest first.
+	Commits []prCommit
+	// Checkpoints are the distinct checkpoints linked from Commits, oldest first.
+	Checkpoints []prCheckpoint
+
+	// Merged summary fields, de-duplicated in checkpoint order.
+	Intents   []string
+	Outcomes  []string
+	OpenItems []string
+	Friction  []string
+	// Extensions are the summary template's fields, sorted by name, with
+	// values merged the same way.
+	Extensions []summaryExtension
+
+	// Attribution sums each checkpoint's line attribution. Nil if no checkpoint has any.
+	Attribution *checkpoint.InitialAttribution
+	// TokenUsage sums token usage across checkpoints. Nil if none was recorded.
+	TokenUsage  *agent.TokenUsage
+	TotalTokens int
+
+	// MissingSummaries counts checkpoints without an AI summary.
+	MissingSummaries int
+}
+
+// prCommit is a commit in the range linked to a checkpoint.
+type prCommit struct {
+	SHA          string
+	ShortSHA     string
+	Subject      string
+	CheckpointID string
+}
+
+// prCheckpoint summarizes one checkpoint in the range.
+type prCheckpoint struct {
+	ID       string
+	Agents   []string
+	Sessions int
+	Summary  *checkpoint.Summary
+}
+
+// buildPRDescription collects and merges the checkpoints linked to commits in base..head.
+// When generator is non-nil, checkpoints missing a summary get one generated and saved.
+func buildPRDescription(ctx context.Context, repo *git.Repository, base, head string, generator summarize.Generator, errW io.Writer) (*prDescription, error) {
+	commits, err := commitsInRange(repo, base, head)
+	if err != nil {
+		return nil, err
+	}
+
+	desc := &prDescription{Base: base, Head: head}
+	store := checkpoint.NewGitStore(repo)
+	seen := make(map[id.CheckpointID]bool)
+	var order []id.CheckpointID
+
+	// Rebased or amended commits may be linked to their checkpoints without a trailer
+	links, err := strategy.LoadCommitLinkIndex(ctx, store)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // Already wrapped by the strategy package
+	}
+
+	for _, c := range commits {
+		cpIDs := links.CheckpointsForCommit(c)
+		if len(cpIDs) == 0 {
+			continue
+		}
+		desc.Commits = append(desc.Commits, prCommit{
+			SHA:          c.Hash.String(),
+			ShortSHA:     c.Hash.String()[:7],
+			Subject:      strings.Split(c.Message, "\n")[0],
+			CheckpointID: cpIDs[0].String(),
+		})
+		for _, cpID := range cpIDs {
+			if !seen[cpID] {
+				seen[cpID] = true
+				order = append(order, cpID)
+			}
+		}
+	}
+
+	intents := newOrderedSet()
+	outcomes := newOrderedSet()
+	openItems := newOrderedSet()
+	friction := newOrderedSet()
+	extensions := make(map[string]*orderedSet)
+	addSummary := func(s *checkpoint.Summary) {
+		intents.add(s.Intent)
+		outcomes.add(s.Outcome)
+		openItems.add(s.OpenItems...)
+		friction.add(s.Friction...)
+		for _, ext := range summaryExtensions(s.Extensions) {
+			if extensions[ext.Name] == nil {
+				extensions[ext.Name] = newOrderedSet()
+			}
+			extensions[ext.Name].add(ext.Values...)
+		}
+	}
+
+	for _, cpID := range order {
+		summary, readErr := store.ReadCommitted(ctx, cpID)
+		if readErr != nil {
+			return nil, fmt.Errorf("failed to read checkpoint %s: %w", cpID, readErr)
+		}
+		if summary == nil {
+			// The metadata branch may not have been fetched; skip rather than fail.
+			fmt.Fprintf(errW, "Warning: checkpoint %s not found on %s\n", cpID, paths.MetadataBranchName)
+			continue
+		}
+
+		cp := prCheckpoint{ID: cpID.String(), Sessions: len(summary.Sessions)}
+		var latest *checkpoint.SessionContent
+		for i := range summary.Sessions {
+			content, contentErr := store.ReadSessionMetadataAndPrompts(ctx, cpID, i)
+			if contentErr != nil {
+				return nil, fmt.Errorf("failed to read session %d of checkpoint %s: %w", i, cpID, contentErr)
+			}
+			latest = content
+			if content.Metadata.Agent != "" && !slices.Contains(cp.Agents, string(content.Metadata.Agent)) {
+				cp.Agents = append(cp.Agents, string(content.Metadata.Agent))
+			}
+			if s := content.Metadata.Summary; s != nil {
+				addSummary(s)
+			}
+		}
+		if latest == nil {
+			continue
+		}
+
+		cp.Summary = latest.Metadata.Summary
+		if cp.Summary == nil && generator != nil {
+			generated, genErr := generatePRCheckpointSummary(ctx, store, cpID, summary, generator)
+			if genErr != nil {
+				fmt.Fprintf(errW, "Warning: failed to generate summary for checkpoint %s: %v\n", cpID, genErr)
+			} else {
+				cp.Summary = generated
+				addSummary(generated)
+			}
+		}
+		if cp.Summary == nil {
+			desc.MissingSummaries++
+		}
+
+		// Every session in a checkpoint is attributed against the same commit, so
+		// summing sessions would double-count; use the latest, as explain does.
+		desc.Attribution = addAttribution(desc.Attribution, latest.Metadata.InitialAttribution)
+		desc.TokenUsage = addTokenUsage(desc.TokenUsage, summary.TokenUsage)
+
+		desc.Checkpoints = append(desc.Checkpoints, cp)
+	}
+
+	desc.Intents = intents.items
+	desc.Outcomes = outcomes.items
+	desc.OpenItems = openItems.items
+	desc.Friction = friction.items
+	names := make([]string, 0, len(extensions))
+	for name := range extensions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if values := extensions[name].items; len(values) > 0 {
+			desc.Extensions = append(desc.Extensions, summaryExtension{Name: name, Label: extensionLabel(name), List: true, Values: values})
+		}
+	}
+	if u := desc.TokenUsage; u != nil {
+		desc.TotalTokens = u.InputTokens + u.CacheCreationTokens + u.CacheReadTokens + u.OutputTokens
+	}
+
+	return desc, nil
+}
+
+// generatePRCheckpointSummary generates and saves a summary for the latest
+// session of a checkpoint, the same way `entire explain --generate` does.
+func generatePRCheckpointSummary(ctx context.Context, store *checkpoint.GitStore, cpID id.CheckpointID, cpSummary *checkpoint.CheckpointSummary, generator summarize.Generator) (*checkpoint.Summary, error) {
+	content, err := store.ReadLatestSessionContent(ctx, cpID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint content: %w", err)
+	}
+	scoped := scopeTranscriptForCheckpoint(content.Transcript, content.Metadata.GetTranscriptStart(), content.Metadata.Agent)
+	if len(scoped) == 0 {
+		return nil, errors.New("checkpoint has no transcript to summarize")
+	}
+
+	logging.Info(logging.WithComponent(ctx, "pr-description"), "generating checkpoint summary",
+		slog.String("checkpoint_id", cpID.String()))
+
+	summary, err := summarize.GenerateFromTranscript(ctx, scoped, cpSummary.FilesTouched, content.Metadata.Agent, generator)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // Already wrapped by summarize
+	}
+	if err := store.UpdateSummary(ctx, cpID, summary); err != nil {
+		return nil, fmt.Errorf("failed to save summary: %w", err)
+	}
+	return summary, nil
+}
+
+// commitsInRange returns the commits reachable from head but not from base,
+// oldest first, approximating `git rev-list base..head`.
+func commitsInRange(repo *git.Repository, base, head string) ([]*object.Commit, error) {
+	baseCommit, err := resolveCommit(repo, base)
+	if err != nil {
+		return nil, err
+	}
+	headCommit, err := resolveCommit(repo, head)
+	if err != nil {
+		return nil, err
+	}
+
+	mergeBases, err := headCommit.MergeBase(baseCommit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find merge base of %s and %s: %w", base, head, err)
+	}
+	stop := make([]plumbing.Hash, 0, len(mergeBases))
+	for _, mb := range mergeBases {
+		stop = append(stop, mb.Hash)
+	}
+
+	// Commits on base since the fork point. Excluding them handles feature
+	// branches that merged the base branch back in.
+	onBase := make(map[plumbing.Hash]bool)
+	baseIter := object.NewCommitPreorderIter(baseCommit, nil, stop)
+	if err := baseIter.ForEach(func(c *object.Commit) error {
+		onBase[c.Hash] = true
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to walk %s: %w", base, err)
+	}
+
+	var commits []*object.Commit
+	headIter := object.NewCommitPreorderIter(headCommit, nil, stop)
+	if err := headIter.ForEach(func(c *object.Commit) error {
+		if !onBase[c.Hash] {
+			commits = append(commits, c)
+		}
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to walk %s: %w", head, err)
+	}
+
+	// Preorder walks newest first; reverse so the description reads chronologically.
+	for i, j := 0, len(commits)-1; i < j; i, j = i+1, j-1 {
+		commits[i], commits[j] = commits[j], commits[i]
+	}
+	return commits, nil
+}
+
+func resolveCommit(repo *git.Repository, rev string) (*object.Commit, error) {
+	hash, err := repo.ResolveRevision(plumbing.Revision(rev))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", rev, err)
+	}
+	commit, err := repo.CommitObject(*hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit %s: %w", rev, err)
+	}
+	return commit, nil
+}
+
+func addAttribution(total, a *checkpoint.InitialAttribution) *checkpoint.InitialAttribution {
+	if a == nil {
+		return total
+	}
+	if total == nil {
+		total = &checkpoint.InitialAttribution{}
+	}
+	total.AgentLines += a.AgentLines
+	total.HumanAdded += a.HumanAdded
+	total.HumanModified += a.HumanModified
+	total.HumanRemoved += a.HumanRemoved
+	total.TotalCommitted += a.TotalCommitted
+	if total.TotalCommitted > 0 {
+		total.AgentPercentage = float64(total.AgentLines) / float64(total.TotalCommitted) * 100
+	}
+	return total
+}
+
+func addTokenUsage(total, u *agent.TokenUsage) *agent.TokenUsage {
+	if u == nil {
+		return total
+	}
+	if total == nil {
+		total = &agent.TokenUsage{}
+	}
+	total.InputTokens += u.InputTokens
+	total.CacheCreationTokens += u.CacheCreationTokens
+	total.CacheReadTokens += u.CacheReadTokens
+	total.OutputTokens += u.OutputTokens
+	total.APICallCount += u.APICallCount
+	return total
+}
+
+// orderedSet collects non-empty strings, dropping duplicates but keeping first-seen order.
+type orderedSet struct {
+	items []string
+	seen  map[string]bool
+}
+
+func newOrderedSet() *orderedSet {
+	return &orderedSet{seen: make(map[string]bool)}
+}
+
+func (s *orderedSet) add(values ...string) {
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" || s.seen[v] {
+			continue
+		}
+		s.seen[v] = true
+		s.items = append(s.items, v)
+	}
+}
+
+// loadPRDescriptionTemplate loads the template at path, or the repo's
+// .entire/pr-description.md.tmpl, falling back to the built-in template.
+func loadPRDescriptionTemplate(path string) (*template.Template, error) {
+	explicit := path != ""
+	if !explicit {
+		abs, err := paths.AbsPath(prDescriptionTemplateFile)
+		if err != nil {
+			abs = prDescriptionTemplateFile
+		}
+		path = abs
+	}
+
+	data, err := os.ReadFile(path) //nolint:gosec // Template path comes from the repo or the user
+	if err != nil {
+		if !explicit && errors.Is(err, os.ErrNotExist) {
+			return defaultPRDescriptionTemplate, nil
+		}
+		return nil, fmt.Errorf("failed to read template: %w", err)
+	}
+
+	tmpl, err := template.New(filepath.Base(path)).Funcs(prDescriptionFuncs).Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %w", path, err)
+	}
+	return tmpl, nil
+}
+
+var prDescriptionFuncs = template.FuncMap{
+	"join":    strings.Join,
+	"percent": func(f float64) string { return fmt.Sprintf("%.1f%%", f) },
+}
+
+var defaultPRDescriptionTemplate = template.Must(template.New("pr-description").Funcs(prDescriptionFuncs).Parse(defaultPRDescriptionText))
+
+const defaultPRDescriptionText = `## Summary
+{{if .Intents}}
+{{range .Intents}}- {{.}}
+{{end}}{{else}}
+_No AI summaries recorded for this range._
+{{end}}
+{{- with .Outcomes}}
+## What changed
+
+{{range .}}- {{.}}
+{{end}}{{end}}
+{{- with .OpenItems}}
+## Open items
+
+{{range .}}- [ ] {{.}}
+{{end}}{{end}}
+{{- with .Friction}}
+## Notes for reviewers
+
+{{range .}}- {{.}}
+{{end}}{{end}}
+{{- range .Extensions}}
+## {{.Label}}
+
+{{range .Values}}- {{.}}
+{{end}}{{end}}
+{{- if or .Attribution .TokenUsage}}
+## AI assistance
+{{with .Attribution}}
+- Agent-written lines: {{.AgentLines}} of {{.TotalCommitted}} ({{percent .AgentPercentage}})
+- Human lines: {{.HumanAdded}} added, {{.HumanModified}} modified, {{.HumanRemoved}} removed
+{{- end}}
+{{- if .TokenUsage}}
+- Tokens: {{.TotalTokens}} across {{.TokenUsage.APICallCount}} API calls
+{{- end}}
+{{end}}
+{{- with .Commits}}
+## Checkpoints
+
+{{range .}}- ` + "`{{.ShortSHA}}`" + ` {{.Subject}} (checkpoint ` + "`{{.CheckpointID}}`" + `)
+{{end}}{{end}}
+{{- if .MissingSummaries}}
+_{{.MissingSummaries}} checkpoint(s) have no AI summary. Run ` + "`entire pr-description --generate`" + ` to create them._
+{{end}}`
@@ -0,0 +1,219 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/agent"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/summarize"
+	"github.com/entireio/cli/cmd/entire/cli/trailers"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// fakeSummaryGenerator returns a fixed summary and records how often it was called.
+type fakeSummaryGenerator struct {
+	calls int
+}
+
+func (g *fakeSummaryGenerator) Generate(_ context.Context, _ summarize.Input) (*checkpoint.Summary, error) {
+	g.calls++
+	return &checkpoint.Summary{
+		Intent:    "Generated intent",
+		Outcome:   "Generated outcome",
+		OpenItems: []string{"Generated follow-up"},
+	}, nil
+}
+
+// addUnsummarizedCheckpoint commits a file with a checkpoint trailer and writes
+// a matching checkpoint that has no AI summary.
+func addUnsummarizedCheckpoint(t *testing.T, repo *git.Repository, cpID id.CheckpointID) {
+	t.Helper()
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("failed to get worktree: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(wt.Filesystem.Root(), "bye.txt"), []byte("bye\n"), 0o644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if _, err := wt.Add("bye.txt"); err != nil {
+		t.Fatalf("failed to add file: %v", err)
+	}
+	sig := &object.Signature{Name: "Test", Email: "test@example.com", When: time.Now()}
+	if _, err := wt.Commit("Add farewell\n\n"+trailers.CheckpointTrailerKey+": "+cpID.String()+"\n", &git.CommitOptions{Author: sig}); err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+
+	store := checkpoint.NewGitStore(repo)
+	if err := store.WriteCommitted(context.Background(), checkpoint.WriteCommittedOptions{
+		CheckpointID: cpID,
+		SessionID:    "2026-01-02-second-session",
+		Strategy:     "manual-commit",
+		Agent:        agent.AgentTypeClaudeCode,
+		Transcript:   []byte(serveTestTranscript),
+		Prompts:      []string{"Add a farewell file"},
+		FilesTouched: []string{"bye.txt"},
+		AuthorName:   "Test",
+		AuthorEmail:  "test@example.com",
+		TokenUsage:   &agent.TokenUsage{InputTokens: 100, OutputTokens: 50, APICallCount: 2},
+		InitialAttribution: &checkpoint.InitialAttribution{
+			AgentLines:     1,
+			HumanAdded:     1,
+			TotalCommitted: 2,
+		},
+	}); err != nil {
+		t.Fatalf("failed to write committed checkpoint: %v", err)
+	}
+}
+
+func TestParseRevisionRange(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		arg, base, head string
+		wantErr         bool
+	}{
+		{"", "", "HEAD", false},
+		{"main", "main", "HEAD", false},
+		{"main..feature", "main", "feature", false},
+		{"main..", "main", "HEAD", false},
+		{"..feature", "", "", true},
+		{"main...feature", "", "", true},
+	}
+	for _, tt := range tests {
+		base, head, err := parseRevisionRange(tt.arg)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseRevisionRange(%q) error = %v, wantErr %v", tt.arg, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && (base != tt.base || head != tt.head) {
+			t.Errorf("parseRevisionRange(%q) = %q, %q; want %q, %q", tt.arg, base, head, tt.base, tt.head)
+		}
+	}
+}
+
+func TestBuildPRDescription(t *testing.T) {
+	repo, cpID := setupServeTestRepo(t)
+	secondID := id.MustCheckpointID("b1b2c3d4e5f6")
+	addUnsummarizedCheckpoint(t, repo, secondID)
+
+	desc, err := buildPRDescription(context.Background(), repo, "HEAD~2", "HEAD", nil, io.Discard)
+	if err != nil {
+		t.Fatalf("buildPRDescription() error = %v", err)
+	}
+
+	if len(desc.Checkpoints) != 2 || desc.Checkpoints[0].ID != cpID.String() || desc.Checkpoints[1].ID != secondID.String() {
+		t.Fatalf("checkpoints = %+v, want %s then %s", desc.Checkpoints, cpID, secondID)
+	}
+	if len(desc.Intents) != 1 || desc.Intents[0] != "Add a greeting file" {
+		t.Errorf("intents = %v", desc.Intents)
+	}
+	if desc.MissingSummaries != 1 {
+		t.Errorf("MissingSummaries = %d, want 1", desc.MissingSummaries)
+	}
+	if a := desc.Attribution; a == nil || a.AgentLines != 2 || a.TotalCommitted != 3 {
+		t.Errorf("attribution = %+v, want 2 agent lines of 3", a)
+	} else if a.AgentPercentage < 66 || a.AgentPercentage > 67 {
+		t.Errorf("AgentPercentage = %v, want ~66.7", a.AgentPercentage)
+	}
+	if desc.TotalTokens != 150 {
+		t.Errorf("TotalTokens = %d, want 150", desc.TotalTokens)
+	}
+
+	// Only commits in the range contribute.
+	desc, err = buildPRDescription(context.Background(), repo, "HEAD~1", "HEAD", nil, io.Discard)
+	if err != nil {
+		t.Fatalf("buildPRDescription() error = %v", err)
+	}
+	if len(desc.Checkpoints) != 1 || desc.Checkpoints[0].ID != secondID.String() {
+		t.Errorf("checkpoints = %+v, want only %s", desc.Checkpoints, secondID)
+	}
+}
+
+func TestBuildPRDescription_GeneratesMissingSummaries(t *testing.T) {
+	repo, _ := setupServeTestRepo(t)
+	secondID := id.MustCheckpointID("b1b2c3d4e5f6")
+	addUnsummarizedCheckpoint(t, repo, secondID)
+
+	gen := &fakeSummaryGenerator{}
+	desc, err := buildPRDescription(context.Background(), repo, "HEAD~2", "HEAD", gen, io.Discard)
+	if err != nil {
+		t.Fatalf("buildPRDescription() error = %v", err)
+	}
+	if gen.calls != 1 {
+		t.Errorf("generator called %d times, want 1 (only for the missing summary)", gen.calls)
+	}
+	if desc.MissingSummaries != 0 {
+		t.Errorf("MissingSummaries = %d, want 0", desc.MissingSummaries)
+	}
+	if len(desc.OpenItems) != 1 || desc.OpenItems[0] != "Generated follow-up" {
+		t.Errorf("open items = %v", desc.OpenItems)
+	}
+
+	// The generated summary is persisted.
+	content, err := checkpoint.NewGitStore(repo).ReadLatestSessionContent(context.Background(), secondID)
+	if err != nil {
+		t.Fatalf("failed to read checkpoint: %v", err)
+	}
+	if content.Metadata.Summary == nil || content.Metadata.Summary.Intent != "Generated intent" {
+		t.Errorf("generated summary not saved: %+v", content.Metadata.Summary)
+	}
+}
+
+func TestRunPRDescription_DefaultTemplate(t *testing.T) {
+	setupServeTestRepo(t)
+
+	var out bytes.Buffer
+	if err := runPRDescription(context.Background(), &out, io.Discard, "HEAD~1..HEAD", "", false); err != nil {
+		t.Fatalf("runPRDescription() error = %v", err)
+	}
+	body := out.String()
+	for _, want := range []string{
+		"## Summary\n\n- Add a greeting file\n",
+		"## What changed\n\n- Created hello.txt\n",
+		"## Test Gaps\n\n- No test for the greeting\n",
+		"- Agent-written lines: 1 of 1 (100.0%)",
+		"(checkpoint `a1b2c3d4e5f6`)",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("description missing %q:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "no AI summary") {
+		t.Errorf("description should not report missing summaries:\n%s", body)
+	}
+}
+
+func TestRunPRDescription_RepoTemplate(t *testing.T) {
+	setupServeTestRepo(t)
+
+	if err := os.MkdirAll(".entire", 0o755); err != nil {
+		t.Fatalf("failed to create .entire: %v", err)
+	}
+	custom := "Checkpoints: {{len .Checkpoints}}\n{{range .Intents}}* {{.}}\n{{end}}"
+	if err := os.WriteFile(prDescriptionTemplateFile, []byte(custom), 0o644); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := runPRDescription(context.Background(), &out, io.Discard, "HEAD~1", "", false); err != nil {
+		t.Fatalf("runPRDescription() error = %v", err)
+	}
+	if got, want := out.String(), "Checkpoints: 1\n* Add a greeting file\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+
+	if err := runPRDescription(context.Background(), io.Discard, io.Discard, "HEAD~1", "missing.tmpl", false); err == nil {
+		t.Error("expected error for an explicit template that doesn't exist")
+	}
+}
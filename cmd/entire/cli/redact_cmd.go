@@ -0,0 +1,474 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+	"github.com/entireio/cli/redact"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/spf13/cobra"
+)
+
+// Output formats supported by `entire redact scan`.
+const (
+	redactFormatText = "text"
+	redactFormatJSON = "json"
+)
+
+func newRedactCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "redact",
+		Short: "Audit and re-apply redaction of stored checkpoints",
+		Long: `Transcripts, prompts, and context are redacted before they are written to
+the entire/checkpoints/v1 branch. Each session records how many values each
+rule redacted in redactions.json.
+
+These commands check stored checkpoints against the current redaction rules,
+for example after adding rules or enabling PII detection in settings, and
+rewrite the history of checkpoint branches to remove what they find.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(newRedactScanCmd())
+	cmd.AddCommand(newRedactRewriteCmd())
+
+	return cmd
+}
+
+func newRedactScanCmd() *cobra.Command {
+	var allFlag bool
+	var formatFlag string
+
+	cmd := &cobra.Command{
+		Use:   "scan [checkpoint]",
+		Short: "Report what the current redaction rules find in stored checkpoints",
+		Long: `Run the current redaction rules over the transcripts, prompts, and context
+stored on the entire/checkpoints/v1 branch without changing them.
+
+Findings are reported by rule, checkpoint, file, and line. The matched values
+are never printed. A finding means the value was stored before the rule
+existed, or slipped past the rules in effect when it was written.
+
+Pass a checkpoint ID (or prefix) to scan one checkpoint, or --all to scan
+every committed checkpoint.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if checkDisabledGuard(cmd.OutOrStdout()) {
+				return nil
+			}
+			if formatFlag != redactFormatText && formatFlag != redactFormatJSON {
+				return fmt.Errorf("invalid format %q: must be text or json", formatFlag)
+			}
+			if allFlag == (len(args) == 1) {
+				return errors.New("specify a checkpoint ID or --all")
+			}
+
+			store, err := openCheckpointStore()
+			if err != nil {
+				return err
+			}
+
+			var checkpointID id.CheckpointID
+			if len(args) == 1 {
+				checkpointID, err = resolveCommittedCheckpointPrefix(cmd.Context(), store, args[0])
+				if err != nil {
+					return err
+				}
+			}
+
+			report, err := scanCheckpoints(cmd.Context(), store, redact.Default(), checkpointID)
+			if err != nil {
+				return err
+			}
+			if formatFlag == redactFormatJSON {
+				return writeRedactScanJSON(cmd.OutOrStdout(), report)
+			}
+			writeRedactScanText(cmd.OutOrStdout(), report)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&allFlag, "all", false, "Scan every committed checkpoint")
+	cmd.Flags().StringVar(&formatFlag, "format", redactFormatText, "Output format: text or json")
+
+	return cmd
+}
+
+// redactScanReport is the result of `entire redact scan`.
+type redactScanReport struct {
+	SessionsScanned int                 `json:"sessions_scanned"`
+	Findings        int                 `json:"findings"`
+	ByRule          map[string]int      `json:"by_rule"`
+	Sessions        []redactScanSession `json:"sessions"`
+}
+
+// redactScanSession lists the findings in one checkpoint session.
+type redactScanSession struct {
+	CheckpointID string `json:"checkpoint_id"`
+	SessionIndex int    `json:"session_index"`
+	SessionID    string `json:"session_id,omitempty"`
+
+	// RedactedAtWrite is the total from the session's redactions.json.
+	RedactedAtWrite int `json:"redacted_at_write"`
+
+	Findings []redactScanFinding `json:"findings"`
+}
+
+// redactScanFinding locates a value the current rules would redact.
+type redactScanFinding struct {
+	Rule string `json:"rule"`
+	File string `json:"file"`
+	Line int    `json:"line"`
+}
+
+// scanCheckpoints scans one checkpoint, or every committed checkpoint when
+// checkpointID is empty. Only sessions with findings are listed in the report.
+func scanCheckpoints(ctx context.Context, store *checkpoint.GitStore, redactor *redact.Redactor, checkpointID id.CheckpointID) (*redactScanReport, error) {
+	infos, err := store.ListCommitted(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list checkpoints: %w", err)
+	}
+
+	report := &redactScanReport{ByRule: make(map[string]int), Sessions: []redactScanSession{}}
+	for _, info := range infos {
+		if !checkpointID.IsEmpty() && info.CheckpointID != checkpointID {
+			continue
+		}
+		for i := range max(info.SessionCount, 1) {
+			content, err := store.ReadSessionContent(ctx, info.CheckpointID, i)
+			if err != nil {
+				continue
+			}
+			report.SessionsScanned++
+
+			session := scanSessionContent(redactor, content)
+			if len(session.Findings) == 0 {
+				continue
+			}
+			session.CheckpointID = info.CheckpointID.String()
+			session.SessionIndex = i
+			if written, err := store.ReadRedactionReport(ctx, info.CheckpointID, i); err == nil && written != nil {
+				session.RedactedAtWrite = written.Total
+			}
+			for _, f := range session.Findings {
+				report.ByRule[f.Rule]++
+			}
+			report.Findings += len(session.Findings)
+			report.Sessions = append(report.Sessions, session)
+		}
+	}
+	return report, nil
+}
+
+// scanSessionContent runs the redactor over a session's stored files.
+func scanSessionContent(redactor *redact.Redactor, content *checkpoint.SessionContent) redactScanSession {
+	session := redactScanSession{SessionID: content.Metadata.SessionID, Findings: []redactScanFinding{}}
+	add := func(file string, findings []redact.Finding) {
+		for _, f := range findings {
+			session.Findings = append(session.Findings, redactScanFinding{Rule: f.RuleID, File: file, Line: f.Line})
+		}
+	}
+	add(paths.TranscriptFileName, redactor.ScanJSONL(string(content.Transcript)))
+	add(paths.PromptFileName, redactor.ScanText(content.Prompts))
+	add(paths.ContextFileName, redactor.ScanText(content.Context))
+	return session
+}
+
+func writeRedactScanJSON(w io.Writer, report *redactScanReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode scan report: %w", err)
+	}
+	data = append(data, '\n')
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("failed to write scan report: %w", err)
+	}
+	return nil
+}
+
+func writeRedactScanText(w io.Writer, report *redactScanReport) {
+	if report.Findings == 0 {
+		fmt.Fprintf(w, "Scanned %d session(s): no findings.\n", report.SessionsScanned)
+		return
+	}
+
+	fmt.Fprintf(w, "Scanned %d session(s): %d finding(s) in %d session(s).\n\n", report.SessionsScanned, report.Findings, len(report.Sessions))
+
+	rules := make([]string, 0, len(report.ByRule))
+	for rule := range report.ByRule {
+		rules = append(rules, rule)
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		if report.ByRule[rules[i]] != report.ByRule[rules[j]] {
+			return report.ByRule[rules[i]] > report.ByRule[rules[j]]
+		}
+		return rules[i] < rules[j]
+	})
+
+	width := 0
+	for _, rule := range rules {
+		width = max(width, len(rule))
+	}
+	fmt.Fprintln(w, "By rule:")
+	for _, rule := range rules {
+		fmt.Fprintf(w, "  %-*s  %d\n", width, rule, report.ByRule[rule])
+	}
+
+	for _, session := range report.Sessions {
+		fmt.Fprintf(w, "\n%s session %d", session.CheckpointID, session.SessionIndex)
+		if session.SessionID != "" {
+			fmt.Fprintf(w, " (%s)", session.SessionID)
+		}
+		fmt.Fprintf(w, ", %d redacted when written\n", session.RedactedAtWrite)
+		locations := make([]string, len(session.Findings))
+		width := 0
+		for i, f := range session.Findings {
+			locations[i] = fmt.Sprintf("%s:%d", f.File, f.Line)
+			width = max(width, len(locations[i]))
+		}
+		for i, f := range session.Findings {
+			fmt.Fprintf(w, "  %-*s  %s\n", width, locations[i], f.Rule)
+		}
+	}
+}
+
+func newRedactRewriteCmd() *cobra.Command {
+	var literalFlags []string
+	var forceFlag bool
+
+	cmd := &cobra.Command{
+		Use:   "rewrite",
+		Short: "Rewrite checkpoint branch history to redact stored secrets",
+		Long: `Re-apply redaction to every commit on the entire/checkpoints/v1 branch and
+the shadow branches (entire/<commit-hash>), rebuilding their trees and
+commits. Use this when a secret is found in a checkpoint that was already
+written or pushed.
+
+By default the current redaction rules are applied to the transcripts,
+prompts, and context on entire/checkpoints/v1, and to the same files in the
+session metadata (.entire/metadata/) on shadow branches. Structured metadata
+(.json files such as metadata.json, and content_hash.txt) and commit messages
+are left alone, so IDs and paths aren't mistaken for secrets. Code snapshots
+on shadow branches are left alone so rewind keeps working.
+
+With --literal, only the given values are replaced with REDACTED, in every
+file and commit message on all of those branches. Repeat the flag for
+several values.
+
+content_hash.txt is recomputed for every rewritten transcript. Commit
+authors and dates are kept; the commit hashes change.
+
+Default: shows what would be rewritten.
+With --force, rewrites the branches locally and prints the force-push needed
+to update the remote. Everyone who fetched the old history still has a copy.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if checkDisabledGuard(cmd.OutOrStdout()) {
+				return nil
+			}
+			for _, literal := range literalFlags {
+				if literal == "" {
+					return errors.New("--literal value cannot be empty")
+				}
+			}
+
+			repo, err := openRepository()
+			if err != nil {
+				return fmt.Errorf("not a git repository: %w", err)
+			}
+			return runRedactRewrite(cmd.OutOrStdout(), repo, redact.Default(), literalFlags, forceFlag)
+		},
+	}
+
+	cmd.Flags().StringArrayVar(&literalFlags, "literal", nil, "Replace this exact value instead of applying the redaction rules (repeatable)")
+	cmd.Flags().BoolVarP(&forceFlag, "force", "f", false, "Actually rewrite the branches (default: dry run)")
+
+	return cmd
+}
+
+// runRedactRewrite rewrites the checkpoint branches, or previews the rewrite
+// without force.
+func runRedactRewrite(w io.Writer, repo *git.Repository, redactor *redact.Redactor, literals []string, force bool) error {
+	branches, err := checkpointBranches(repo)
+	if err != nil {
+		return err
+	}
+	if len(branches) == 0 {
+		fmt.Fprintln(w, "No checkpoint branches to rewrite.")
+		return nil
+	}
+
+	var results []*checkpoint.RewriteResult
+	for _, branch := range branches {
+		rewrite := redactRewriteFunc(redactor, literals, branch != paths.MetadataBranchName)
+		result, err := checkpoint.RewriteBranch(repo, branch, rewrite, !force)
+		if err != nil {
+			return fmt.Errorf("failed to rewrite %s: %w", branch, err)
+		}
+		if result.Changed() {
+			results = append(results, result)
+		}
+	}
+
+	if len(results) == 0 {
+		fmt.Fprintf(w, "Checked %d branch(es): nothing to redact.\n", len(branches))
+		return nil
+	}
+
+	verb := "Would rewrite"
+	if force {
+		verb = "Rewrote"
+	}
+	fmt.Fprintf(w, "%s %d of %d branch(es):\n", verb, len(results), len(branches))
+	for _, result := range results {
+		fmt.Fprintf(w, "  %s: %d commit(s), %d file version(s) redacted\n", result.Branch, result.Commits, result.Blobs)
+	}
+
+	if !force {
+		fmt.Fprintln(w, "\nRun with --force to rewrite these branches.")
+		return nil
+	}
+
+	var pushes []string
+	for _, result := range results {
+		pushes = append(pushes, forcePushCommands(repo, result.Branch)...)
+	}
+	if len(pushes) > 0 {
+		fmt.Fprintln(w, "\nThe old history is still on the remote. To replace it, run:")
+		for _, push := range pushes {
+			fmt.Fprintf(w, "  %s\n", push)
+		}
+	}
+	fmt.Fprintln(w, "\nThe old objects stay in this clone until they are pruned:")
+	fmt.Fprintln(w, "  git reflog expire --expire=now --all && git gc --prune=now")
+	return nil
+}
+
+// checkpointBranches lists the local checkpoint branches: entire/checkpoints/v1
+// first, then the shadow branches.
+func checkpointBranches(repo *git.Repository) ([]string, error) {
+	refs, err := repo.References()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get references: %w", err)
+	}
+
+	var metadata bool
+	var shadows []string
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		if !ref.Name().IsBranch() {
+			return nil
+		}
+		switch name := ref.Name().Short(); {
+		case name == paths.MetadataBranchName:
+			metadata = true
+		case strategy.IsShadowBranch(name):
+			shadows = append(shadows, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate references: %w", err)
+	}
+
+	sort.Strings(shadows)
+	if metadata {
+		return append([]string{paths.MetadataBranchName}, shadows...), nil
+	}
+	return shadows, nil
+}
+
+// redactRewriteFunc returns the blob rewrite for a branch. Literals replace the
+// given values everywhere, including commit messages. Otherwise the redaction
+// rules apply to the free-text files (transcripts, prompts, context), and on
+// shadow branches only to those in the session metadata, whose other files are
+// code snapshots. Commit messages and structured metadata are kept, since
+// entropy and PII rules would corrupt the IDs and paths in them.
+func redactRewriteFunc(redactor *redact.Redactor, literals []string, shadow bool) checkpoint.RewriteFunc {
+	if len(literals) > 0 {
+		var pairs []string
+		for _, literal := range literals {
+			for _, form := range literalForms(literal) {
+				pairs = append(pairs, form, redact.DefaultReplacement)
+			}
+		}
+		replacer := strings.NewReplacer(pairs...)
+		return func(_ string, content []byte) ([]byte, error) {
+			return []byte(replacer.Replace(string(content))), nil
+		}
+	}
+
+	metadataPrefix := paths.EntireMetadataDir + "/"
+	return func(treePath string, content []byte) ([]byte, error) {
+		if treePath == "" || isStructuredMetadata(treePath) {
+			return content, nil // Commit message or structured metadata
+		}
+		if shadow && !strings.HasPrefix(treePath, metadataPrefix) {
+			return content, nil
+		}
+		return checkpoint.RedactFileContent(redactor, treePath, content) //nolint:wrapcheck // Already descriptive
+	}
+}
+
+// isStructuredMetadata reports whether a checkpoint file holds structured
+// metadata rather than free text: JSON documents and content_hash.txt.
+func isStructuredMetadata(treePath string) bool {
+	name := path.Base(treePath)
+	return strings.HasSuffix(name, ".json") || name == paths.ContentHashFileName
+}
+
+// literalForms returns a literal as written and as escaped inside JSON strings,
+// so it's also found in transcripts.
+func literalForms(literal string) []string {
+	forms := []string{literal}
+	for _, escapeHTML := range []bool{false, true} {
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(escapeHTML)
+		if err := enc.Encode(literal); err != nil {
+			continue
+		}
+		escaped := strings.TrimSuffix(buf.String(), "\n")
+		escaped = escaped[1 : len(escaped)-1]
+		if !slices.Contains(forms, escaped) {
+			forms = append(forms, escaped)
+		}
+	}
+	return forms
+}
+
+// forcePushCommands returns the push needed for each remote that has a copy of
+// branch. --force-with-lease refuses to overwrite checkpoints pushed since the
+// last fetch.
+func forcePushCommands(repo *git.Repository, branch string) []string {
+	remotes, err := repo.Remotes()
+	if err != nil {
+		return nil
+	}
+	var commands []string
+	for _, remote := range remotes {
+		name := remote.Config().Name
+		ref, err := repo.Reference(plumbing.NewRemoteReferenceName(name, branch), true)
+		if err != nil {
+			continue
+		}
+		commands = append(commands, fmt.Sprintf("git push --force-with-lease=%s:%s %s %s", branch, ref.Hash(), name, branch))
+	}
+	sort.Strings(commands)
+	return commands
+}
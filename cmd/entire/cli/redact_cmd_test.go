@@ -0,0 +1,214 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/redact"
+
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+func TestScanCheckpoints(t *testing.T) {
+	repo, cpID := setupServeTestRepo(t)
+	store := checkpoint.NewGitStore(repo)
+
+	r, err := redact.New(redact.Config{Rules: []redact.Rule{{ID: "greeting-file", Pattern: `hello\.txt`}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := scanCheckpoints(context.Background(), store, r, cpID)
+	if err != nil {
+		t.Fatalf("scanCheckpoints() error = %v", err)
+	}
+	if report.SessionsScanned != 1 || report.Findings != 2 || report.ByRule["greeting-file"] != 2 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	session := report.Sessions[0]
+	if session.CheckpointID != cpID.String() || session.SessionIndex != 0 {
+		t.Errorf("unexpected session: %+v", session)
+	}
+	for _, f := range session.Findings {
+		if f.File != "full.jsonl" || f.Line != 2 {
+			t.Errorf("unexpected finding location: %+v", f)
+		}
+	}
+
+	var buf bytes.Buffer
+	writeRedactScanText(&buf, report)
+	out := buf.String()
+	for _, want := range []string{"2 finding(s) in 1 session(s)", "greeting-file  2", cpID.String() + " session 0", "full.jsonl:2"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("text output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "hello.txt") {
+		t.Errorf("text output must not contain matched values:\n%s", out)
+	}
+
+	buf.Reset()
+	if err := writeRedactScanJSON(&buf, report); err != nil {
+		t.Fatal(err)
+	}
+	var decoded redactScanReport
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid JSON output: %v", err)
+	}
+	if decoded.Findings != 2 {
+		t.Errorf("decoded findings = %d, want 2", decoded.Findings)
+	}
+}
+
+func TestScanCheckpoints_NoFindings(t *testing.T) {
+	repo, _ := setupServeTestRepo(t)
+	store := checkpoint.NewGitStore(repo)
+
+	r, err := redact.New(redact.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := scanCheckpoints(context.Background(), store, r, "")
+	if err != nil {
+		t.Fatalf("scanCheckpoints() error = %v", err)
+	}
+	if report.Findings != 0 || len(report.Sessions) != 0 {
+		t.Errorf("expected no findings, got %+v", report)
+	}
+
+	var buf bytes.Buffer
+	writeRedactScanText(&buf, report)
+	if !strings.Contains(buf.String(), "no findings") {
+		t.Errorf("unexpected output: %s", buf.String())
+	}
+}
+
+func TestRedactScanCmd_RequiresTarget(t *testing.T) {
+	setupServeTestRepo(t)
+
+	for _, args := range [][]string{{}, {"a1b2c3", "--all"}} {
+		cmd := newRedactScanCmd()
+		cmd.SetArgs(args)
+		cmd.SetOut(&bytes.Buffer{})
+		if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "--all") {
+			t.Errorf("args %v: expected target error, got %v", args, err)
+		}
+	}
+}
+
+func TestRunRedactRewrite_Literal(t *testing.T) {
+	repo, cpID := setupServeTestRepo(t)
+	store := checkpoint.NewGitStore(repo)
+
+	// Pretend the branch was pushed so the force-push command is shown
+	metaRef, err := repo.Reference(plumbing.NewBranchReferenceName(paths.MetadataBranchName), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{"https://example.com/repo.git"}}); err != nil {
+		t.Fatal(err)
+	}
+	remoteRef := plumbing.NewHashReference(plumbing.NewRemoteReferenceName("origin", paths.MetadataBranchName), metaRef.Hash())
+	if err := repo.Storer.SetReference(remoteRef); err != nil {
+		t.Fatal(err)
+	}
+
+	var preview bytes.Buffer
+	if err := runRedactRewrite(&preview, repo, redact.Default(), []string{"hello.txt"}, false); err != nil {
+		t.Fatalf("runRedactRewrite(preview) error = %v", err)
+	}
+	if !strings.Contains(preview.String(), "Would rewrite 1 of 1 branch(es)") || !strings.Contains(preview.String(), "--force") {
+		t.Errorf("unexpected preview:\n%s", preview.String())
+	}
+
+	var out bytes.Buffer
+	if err := runRedactRewrite(&out, repo, redact.Default(), []string{"hello.txt"}, true); err != nil {
+		t.Fatalf("runRedactRewrite() error = %v", err)
+	}
+	wantPush := "git push --force-with-lease=" + paths.MetadataBranchName + ":" + metaRef.Hash().String() + " origin " + paths.MetadataBranchName
+	if !strings.Contains(out.String(), wantPush) {
+		t.Errorf("output missing %q:\n%s", wantPush, out.String())
+	}
+
+	content, err := store.ReadSessionContent(context.Background(), cpID, 0)
+	if err != nil {
+		t.Fatalf("ReadSessionContent() error = %v", err)
+	}
+	if strings.Contains(string(content.Transcript), "hello.txt") {
+		t.Errorf("literal still in transcript: %s", content.Transcript)
+	}
+	if !strings.Contains(string(content.Transcript), "I'll create REDACTED.") {
+		t.Errorf("literal not replaced in transcript: %s", content.Transcript)
+	}
+}
+
+func TestRedactRewriteFunc_ShadowBranchKeepsCode(t *testing.T) {
+	t.Parallel()
+
+	r, err := redact.New(redact.Config{Rules: []redact.Rule{{ID: "ticket", Pattern: `TICKET-\d+`}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rewrite := redactRewriteFunc(r, nil, true)
+
+	code := []byte("// fixes TICKET-1\n")
+	if got, _ := rewrite("main.go", code); string(got) != string(code) {
+		t.Errorf("code snapshot rewritten: %q", got)
+	}
+	got, err := rewrite(paths.EntireMetadataDir+"/session/full.jsonl", []byte(`{"message":"TICKET-1"}`))
+	if err != nil || string(got) != `{"message":"REDACTED"}` {
+		t.Errorf("metadata not redacted: %q, %v", got, err)
+	}
+	if got, _ := redactRewriteFunc(r, nil, false)("main.go", code); string(got) == string(code) {
+		t.Error("files on the metadata branch should be redacted")
+	}
+}
+
+func TestRedactRewriteFunc_KeepsMessagesAndStructuredMetadata(t *testing.T) {
+	t.Parallel()
+
+	r, err := redact.New(redact.Config{Rules: []redact.Rule{{ID: "ticket", Pattern: `TICKET-\d+`}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rewrite := redactRewriteFunc(r, nil, false)
+
+	for _, treePath := range []string{
+		"",                                 // Commit message
+		"a1/b2c3d4e5f6/metadata.json",      // Checkpoint summary
+		"a1/b2c3d4e5f6/0/metadata.json",    // Session metadata
+		"a1/b2c3d4e5f6/0/content_hash.txt", // Transcript hash
+	} {
+		content := []byte(`{"session_id": "TICKET-1", "files_touched": ["TICKET-2.go"]}`)
+		if got, err := rewrite(treePath, content); err != nil || string(got) != string(content) {
+			t.Errorf("rewrite(%q) = %q, %v; want it unchanged", treePath, got, err)
+		}
+	}
+	if got, _ := rewrite("a1/b2c3d4e5f6/0/prompt.txt", []byte("fix TICKET-1")); string(got) != "fix REDACTED" {
+		t.Errorf("prompt not redacted: %q", got)
+	}
+
+	// Literals are explicit, so they're replaced in messages and metadata too
+	literal := redactRewriteFunc(r, []string{"TICKET-1"}, false)
+	if got, _ := literal("", []byte("Checkpoint for TICKET-1")); string(got) != "Checkpoint for REDACTED" {
+		t.Errorf("literal not replaced in commit message: %q", got)
+	}
+}
+
+func TestLiteralForms(t *testing.T) {
+	t.Parallel()
+
+	forms := literalForms(`a"b<c`)
+	for _, want := range []string{`a"b<c`, `a\"b<c`, `a\"b\u003cc`} {
+		if !slices.Contains(forms, want) {
+			t.Errorf("literalForms() = %q, missing %q", forms, want)
+		}
+	}
+}
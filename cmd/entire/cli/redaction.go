@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/settings"
+	"github.com/entireio/cli/redact"
+)
+
+// loadConfiguredRedactor builds the redactor from the "redaction" settings.
+// It is registered with redact.SetDefaultLoader so every redact.* call honors
+// the repository's rules. Invalid settings print a warning and fall back to the
+// built-in rules rather than failing the hook or command that is redacting.
+func loadConfiguredRedactor() *redact.Redactor {
+	s, err := LoadEntireSettings()
+	if err != nil || s.Redaction == nil {
+		return nil
+	}
+	r, err := newRedactorFromSettings(s.Redaction)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[entire] Warning: %v; using default redaction rules\n", err)
+		return nil
+	}
+	return r
+}
+
+// newRedactorFromSettings converts the redaction settings into a redactor.
+// A relative gitleaks config path is resolved against the repository root.
+func newRedactorFromSettings(rs *settings.RedactionSettings) (*redact.Redactor, error) {
+	cfg := redact.Config{
+		EntropyThreshold: rs.EntropyThreshold,
+		AllowPatterns:    rs.Allowlist.Patterns,
+		AllowValues:      rs.Allowlist.Values,
+		Labels:           rs.Labels,
+	}
+	if rs.GitleaksConfig != "" {
+		path, err := paths.AbsPath(rs.GitleaksConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve gitleaks config path: %w", err)
+		}
+		cfg.GitleaksConfig = path
+	}
+	for _, name := range rs.PII {
+		c, err := redact.ParsePIICategory(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction settings: %w", err)
+		}
+		cfg.PII = append(cfg.PII, c)
+	}
+	for _, rule := range rs.Rules {
+		cfg.Rules = append(cfg.Rules, redact.Rule{ID: rule.ID, Pattern: rule.Regex, Replacement: rule.Replacement})
+	}
+
+	r, err := redact.New(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redaction settings: %w", err)
+	}
+	return r, nil
+}
+
+// loadSnapshotPolicy builds the shadow branch snapshot policy from the
+// "redaction.snapshots" settings. It is registered with
+// checkpoint.SetSnapshotPolicyLoader. Invalid globs print a warning and fall
+// back to the built-in globs.
+func loadSnapshotPolicy() *checkpoint.SnapshotPolicy {
+	s, err := LoadEntireSettings()
+	if err != nil || s.Redaction == nil {
+		return nil
+	}
+	policy, err := checkpoint.NewSnapshotPolicy(s.Redaction.Snapshots.Exclude, s.Redaction.Snapshots.Include)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[entire] Warning: invalid redaction settings: %v; using default snapshot globs\n", err)
+		return nil
+	}
+	return policy
+}
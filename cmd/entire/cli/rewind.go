@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"os/exec"
@@ -118,12 +119,7 @@ func runRewindInteractive() error { //nolint:maintidx // already present in code
 		var label string
 		timestamp := p.Date.Format("2006-01-02 15:04")
 
-		// Build session identifier for display when multiple sessions exist
-		sessionLabel := ""
-		if hasMultipleSessions && p.SessionPrompt != "" {
-			// Show truncated prompt to identify the session
-			sessionLabel = fmt.Sprintf(" [%s]", sanitizeForTerminal(p.SessionPrompt))
-		}
+		sessionLabel := rewindSessionLabel(p, hasMultipleSessions)
 
 		switch {
 		case p.IsLogsOnly:
@@ -201,14 +197,10 @@ func runRewindInteractive() error { //nolint:maintidx // already present in code
 		return handleLogsOnlyRewindInteractive(start, *selectedPoint, shortID)
 	}
 
-	// Preview rewind to show warnings about files that will be deleted
+	// Preview rewind to show warnings about files that will be deleted or redacted
 	preview, previewErr := start.PreviewRewind(*selectedPoint)
-	if previewErr == nil && preview != nil && len(preview.FilesToDelete) > 0 {
-		fmt.Fprintf(os.Stderr, "\nWarning: The following untracked files will be DELETED:\n")
-		for _, f := range preview.FilesToDelete {
-			fmt.Fprintf(os.Stderr, "  - %s\n", f)
-		}
-		fmt.Fprintf(os.Stderr, "\n")
+	if previewErr == nil && preview != nil {
+		printRewindPreviewWarnings(os.Stderr, preview)
 	}
 
 	// Confirm rewind
@@ -339,16 +331,18 @@ func runRewindList() error {
 
 	// Output as JSON for programmatic use
 	type jsonPoint struct {
-		ID               string `json:"id"`
-		Message          string `json:"message"`
-		MetadataDir      string `json:"metadata_dir"`
-		Date             string `json:"date"`
-		IsTaskCheckpoint bool   `json:"is_task_checkpoint"`
-		ToolUseID        string `json:"tool_use_id,omitempty"`
-		IsLogsOnly       bool   `json:"is_logs_only"`
-		CondensationID   string `json:"condensation_id,omitempty"`
-		SessionID        string `json:"session_id,omitempty"`
-		SessionPrompt    string `json:"session_prompt,omitempty"`
+		ID               string   `json:"id"`
+		Message          string   `json:"message"`
+		MetadataDir      string   `json:"metadata_dir"`
+		Date             string   `json:"date"`
+		IsTaskCheckpoint bool     `json:"is_task_checkpoint"`
+		ToolUseID        string   `json:"tool_use_id,omitempty"`
+		IsLogsOnly       bool     `json:"is_logs_only"`
+		CondensationID   string   `json:"condensation_id,omitempty"`
+		SessionID        string   `json:"session_id,omitempty"`
+		SessionPrompt    string   `json:"session_prompt,omitempty"`
+		SessionTitle     string   `json:"session_title,omitempty"`
+		SessionTags      []string `json:"session_tags,omitempty"`
 	}
 
 	output := make([]jsonPoint, len(points))
@@ -364,6 +358,8 @@ func runRewindList() error {
 			CondensationID:   p.CheckpointID.String(),
 			SessionID:        p.SessionID,
 			SessionPrompt:    p.SessionPrompt,
+			SessionTitle:     p.Annotations.Title,
+			SessionTags:      p.Annotations.Tags,
 		}
 	}
 
@@ -426,14 +422,10 @@ func runRewindToInternal(commitID string, logsOnly bool, reset bool) error {
 		return handleLogsOnlyRewindNonInteractive(start, *selectedPoint)
 	}
 
-	// Preview rewind to show warnings about files that will be deleted
+	// Preview rewind to show warnings about files that will be deleted or redacted
 	preview, previewErr := start.PreviewRewind(*selectedPoint)
-	if previewErr == nil && preview != nil && len(preview.FilesToDelete) > 0 {
-		fmt.Fprintf(os.Stderr, "\nWarning: The following untracked files will be DELETED:\n")
-		for _, f := range preview.FilesToDelete {
-			fmt.Fprintf(os.Stderr, "  - %s\n", f)
-		}
-		fmt.Fprintf(os.Stderr, "\n")
+	if previewErr == nil && preview != nil {
+		printRewindPreviewWarnings(os.Stderr, preview)
 	}
 
 	// Resolve agent once for use throughout
@@ -813,6 +805,25 @@ func createContextFileMinimal(contextFile, commitMessage, sessionID, promptFile,
 	return nil
 }
 
+// printRewindPreviewWarnings warns about untracked files the rewind will delete
+// and files it will restore with secrets redacted.
+func printRewindPreviewWarnings(w io.Writer, preview *strategy.RewindPreview) {
+	if len(preview.FilesToDelete) > 0 {
+		fmt.Fprintf(w, "\nWarning: The following untracked files will be DELETED:\n")
+		for _, f := range preview.FilesToDelete {
+			fmt.Fprintf(w, "  - %s\n", f)
+		}
+		fmt.Fprintf(w, "\n")
+	}
+	if len(preview.RedactedFiles) > 0 {
+		fmt.Fprintf(w, "\nWarning: The following files will be restored with secrets REDACTED:\n")
+		for _, f := range preview.RedactedFiles {
+			fmt.Fprintf(w, "  - %s\n", f)
+		}
+		fmt.Fprintf(w, "Restore the real values before using them.\n\n")
+	}
+}
+
 // handleLogsOnlyRewindInteractive handles rewind for logs-only points with a sub-choice menu.
 func handleLogsOnlyRewindInteractive(start strategy.Strategy, point strategy.RewindPoint, shortID string) error {
 	var action string
@@ -1190,6 +1201,27 @@ func performGitResetHard(commitHash string) error {
 	return nil
 }
 
+// rewindSessionLabel identifies the session of a rewind point in the picker.
+// A session title and tags are always shown; otherwise the session's first
+// prompt is shown only when the points span several sessions.
+func rewindSessionLabel(p strategy.RewindPoint, hasMultipleSessions bool) string {
+	var parts []string
+	switch {
+	case p.Annotations.Title != "":
+		parts = append(parts, p.Annotations.Title)
+	case hasMultipleSessions && p.SessionPrompt != "":
+		// Show truncated prompt to identify the session
+		parts = append(parts, p.SessionPrompt)
+	}
+	if len(p.Annotations.Tags) > 0 {
+		parts = append(parts, p.Annotations.FormatTags())
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" [%s]", sanitizeForTerminal(strings.Join(parts, " ")))
+}
+
 // sanitizeForTerminal removes or replaces characters that cause rendering issues
 // in terminal UI components. This includes emojis with skin-tone modifiers and
 // other multi-codepoint characters that confuse width calculations.
@@ -3,10 +3,13 @@ package cli
 import (
 	"fmt"
 	"runtime"
+	"time"
 
 	"github.com/entireio/cli/cmd/entire/cli/buildinfo"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
 	"github.com/entireio/cli/cmd/entire/cli/telemetry"
 	"github.com/entireio/cli/cmd/entire/cli/versioncheck"
+	"github.com/entireio/cli/redact"
 	"github.com/spf13/cobra"
 )
 
@@ -27,6 +30,10 @@ Environment Variables:
 `
 
 func NewRootCmd() *cobra.Command {
+	// Redaction and snapshot settings are read on first use, so commands that never redact don't load them
+	redact.SetDefaultLoader(loadConfiguredRedactor)
+	checkpoint.SetSnapshotPolicyLoader(loadSnapshotPolicy)
+
 	cmd := &cobra.Command{
 		Use:   "entire",
 		Short: "Entire CLI",
@@ -38,6 +45,15 @@ func NewRootCmd() *cobra.Command {
 		CompletionOptions: cobra.CompletionOptions{
 			HiddenDefaultCmd: true,
 		},
+		PersistentPreRun: func(cmd *cobra.Command, _ []string) {
+			// Hidden commands (hooks, workers) either run their own pre-run or must stay fast
+			for c := cmd; c != nil; c = c.Parent() {
+				if c.Hidden {
+					return
+				}
+			}
+			expireIdleSessions(cmd.ErrOrStderr(), time.Now())
+		},
 		PersistentPostRun: func(cmd *cobra.Command, _ []string) {
 			// Skip for hidden commands (walk parent chain — Cobra doesn't propagate Hidden)
 			for c := cmd; c != nil; c = c.Parent() {
@@ -83,6 +99,17 @@ func NewRootCmd() *cobra.Command {
 	cmd.AddCommand(newExplainCmd())
 	cmd.AddCommand(newDebugCmd())
 	cmd.AddCommand(newDoctorCmd())
+	cmd.AddCommand(newServeCmd())
+	cmd.AddCommand(newMCPCmd())
+	cmd.AddCommand(newExportTranscriptCmd())
+	cmd.AddCommand(newPRDescriptionCmd())
+	cmd.AddCommand(newHandoffCmd())
+	cmd.AddCommand(newAttributionCmd())
+	cmd.AddCommand(newLearningsCmd())
+	cmd.AddCommand(newRedactCmd())
+	cmd.AddCommand(newSessionCmd())
+	cmd.AddCommand(newSummarizeCmd())
+	cmd.AddCommand(newSummarizeWorkerCmd())
 	cmd.AddCommand(newSendAnalyticsCmd())
 	cmd.AddCommand(newCurlBashPostInstallCmd())
 
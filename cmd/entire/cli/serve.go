@@ -0,0 +1,450 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/agent"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/logging"
+	"github.com/entireio/cli/cmd/entire/cli/summarize"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// defaultServePort is the port `entire serve` listens on when --port is not given.
+	defaultServePort = 7327
+
+	// serveHost is the only interface the UI binds to. Transcripts can contain
+	// sensitive data, so the server is never exposed beyond the local machine.
+	serveHost = "127.0.0.1"
+
+	serveReadHeaderTimeout = 10 * time.Second
+	serveShutdownTimeout   = 5 * time.Second
+
+	// maxServeDiffBytes caps the size of a single rendered commit diff.
+	maxServeDiffBytes = 512 * 1024
+)
+
+func newServeCmd() *cobra.Command {
+	var port int
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Browse sessions and checkpoints in a local web UI",
+		Long: `Start a local web server for browsing Entire sessions.
+
+The UI lists sessions recorded on the entire/checkpoints/v1 branch and shows
+each checkpoint's transcript (with collapsible tool calls), AI summary,
+attribution, and the diffs of the commits it is linked to.
+
+The server only binds to 127.0.0.1 and reads local git data; nothing is sent
+to an external service. Press Ctrl+C to stop it.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runServe(cmd.Context(), cmd.OutOrStdout(), cmd.ErrOrStderr(), port)
+		},
+	}
+
+	cmd.Flags().IntVarP(&port, "port", "p", defaultServePort, "Port to listen on (0 picks a free port)")
+
+	return cmd
+}
+
+func runServe(ctx context.Context, w, errW io.Writer, port int) error {
+	repo, err := openRepository()
+	if err != nil {
+		fmt.Fprintln(errW, "Not a git repository. Run 'entire serve' from within a git repository.")
+		return NewSilentError(err)
+	}
+
+	listenConfig := &net.ListenConfig{}
+	listener, err := listenConfig.Listen(ctx, "tcp", net.JoinHostPort(serveHost, strconv.Itoa(port)))
+	if err != nil {
+		fmt.Fprintf(errW, "Failed to listen on port %d: %v\n", port, err)
+		fmt.Fprintln(errW, "Use --port to choose a different port.")
+		return NewSilentError(err)
+	}
+
+	server := &http.Server{
+		Handler:           newServeHandler(repo),
+		ReadHeaderTimeout: serveReadHeaderTimeout,
+	}
+
+	fmt.Fprintf(w, "Serving Entire sessions at http://%s\n", listener.Addr())
+	fmt.Fprintln(w, "Press Ctrl+C to stop.")
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.Serve(listener)
+	}()
+
+	select {
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), serveShutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			return fmt.Errorf("failed to shut down server: %w", err)
+		}
+		return nil
+	case err := <-serveErr:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return fmt.Errorf("server failed: %w", err)
+	}
+}
+
+// serveHandler renders the web UI from the checkpoint store.
+type serveHandler struct {
+	repo  *git.Repository
+	store *checkpoint.GitStore
+}
+
+// newServeHandler builds the HTTP handler for the web UI.
+func newServeHandler(repo *git.Repository) http.Handler {
+	h := &serveHandler{
+		repo:  repo,
+		store: checkpoint.NewGitStore(repo),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", h.handleSessions)
+	mux.HandleFunc("GET /sessions/{id}", h.handleSession)
+	mux.HandleFunc("GET /checkpoints/{id}", h.handleCheckpoint)
+
+	return requireLoopbackHost(mux)
+}
+
+// requireLoopbackHost rejects requests whose Host header isn't a loopback name.
+// Binding to 127.0.0.1 keeps other machines out, but a malicious web page could
+// still reach the server through DNS rebinding; checking Host closes that gap.
+func requireLoopbackHost(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if host != "localhost" {
+			if ip := net.ParseIP(host); ip == nil || !ip.IsLoopback() {
+				http.Error(w, "forbidden", http.StatusForbidden)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// serveSession groups the committed checkpoints that belong to one session.
+type serveSession struct {
+	ID          string
+	Agent       agent.AgentType
+	FirstSeen   time.Time
+	LastSeen    time.Time
+	Checkpoints []checkpoint.CommittedInfo
+}
+
+// groupCheckpointsBySession groups committed checkpoints by session ID.
+// A checkpoint shared by several sessions appears under each of them.
+// Sessions are sorted most recent first; checkpoints within a session oldest first.
+func groupCheckpointsBySession(infos []checkpoint.CommittedInfo) []serveSession {
+	byID := make(map[string]*serveSession)
+	var order []string
+
+	for _, info := range infos {
+		sessionIDs := info.SessionIDs
+		if len(sessionIDs) == 0 && info.SessionID != "" {
+			sessionIDs = []string{info.SessionID}
+		}
+		for _, sessionID := range sessionIDs {
+			s, ok := byID[sessionID]
+			if !ok {
+				s = &serveSession{ID: sessionID, FirstSeen: info.CreatedAt, LastSeen: info.CreatedAt}
+				byID[sessionID] = s
+				order = append(order, sessionID)
+			}
+			if info.CreatedAt.Before(s.FirstSeen) {
+				s.FirstSeen = info.CreatedAt
+			}
+			if !info.CreatedAt.Before(s.LastSeen) {
+				s.LastSeen = info.CreatedAt
+				if info.Agent != "" {
+					s.Agent = info.Agent
+				}
+			}
+			if s.Agent == "" {
+				s.Agent = info.Agent
+			}
+			s.Checkpoints = append(s.Checkpoints, info)
+		}
+	}
+
+	sessions := make([]serveSession, 0, len(order))
+	for _, sessionID := range order {
+		s := byID[sessionID]
+		sort.SliceStable(s.Checkpoints, func(i, j int) bool {
+			return s.Checkpoints[i].CreatedAt.Before(s.Checkpoints[j].CreatedAt)
+		})
+		sessions = append(sessions, *s)
+	}
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return sessions[i].LastSeen.After(sessions[j].LastSeen)
+	})
+	return sessions
+}
+
+func (h *serveHandler) listSessions(ctx context.Context) ([]serveSession, error) {
+	infos, err := h.store.ListCommitted(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list checkpoints: %w", err)
+	}
+	return groupCheckpointsBySession(infos), nil
+}
+
+func (h *serveHandler) handleSessions(w http.ResponseWriter, r *http.Request) {
+	sessions, err := h.listSessions(r.Context())
+	if err != nil {
+		h.serverError(w, r, err)
+		return
+	}
+	h.render(w, r, serveSessionsTemplate, map[string]any{
+		"Title":    "Sessions",
+		"Sessions": sessions,
+	})
+}
+
+func (h *serveHandler) handleSession(w http.ResponseWriter, r *http.Request) {
+	sessionID := r.PathValue("id")
+	sessions, err := h.listSessions(r.Context())
+	if err != nil {
+		h.serverError(w, r, err)
+		return
+	}
+	for _, s := range sessions {
+		if s.ID == sessionID {
+			h.render(w, r, serveSessionTemplate, map[string]any{
+				"Title":   "Session " + s.ID,
+				"Session": s,
+			})
+			return
+		}
+	}
+	http.NotFound(w, r)
+}
+
+// serveCheckpointView is the data rendered on a checkpoint page.
+type serveCheckpointView struct {
+	Title          string
+	CheckpointID   id.CheckpointID
+	Summary        *checkpoint.CheckpointSummary
+	Metadata       checkpoint.CommittedMetadata
+	Author         checkpoint.Author
+	TotalTokens    int
+	FullTranscript bool
+	Transcript     []summarize.Entry
+	Prompts        string
+	Commits        []serveCommitDiff
+}
+
+// serveCommitDiff is a commit linked to a checkpoint, with its rendered patch.
+type serveCommitDiff struct {
+	associatedCommit
+
+	Patch     string
+	Truncated bool
+}
+
+func (h *serveHandler) handleCheckpoint(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	checkpointID, err := id.NewCheckpointID(r.PathValue("id"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	summary, err := h.store.ReadCommitted(ctx, checkpointID)
+	if err != nil {
+		h.serverError(w, r, err)
+		return
+	}
+	if summary == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	var content *checkpoint.SessionContent
+	if sessionID := r.URL.Query().Get("session"); sessionID != "" {
+		content, err = h.store.ReadSessionContentByID(ctx, checkpointID, sessionID)
+	} else {
+		content, err = h.store.ReadLatestSessionContent(ctx, checkpointID)
+	}
+	if err != nil {
+		if errors.Is(err, checkpoint.ErrCheckpointNotFound) || errors.Is(err, checkpoint.ErrSessionNotFound) {
+			http.NotFound(w, r)
+			return
+		}
+		h.serverError(w, r, err)
+		return
+	}
+
+	view := serveCheckpointView{
+		Title:          "Checkpoint " + checkpointID.String(),
+		CheckpointID:   checkpointID,
+		Summary:        summary,
+		Metadata:       content.Metadata,
+		FullTranscript: r.URL.Query().Get("full") == "1",
+		Prompts:        content.Prompts,
+	}
+
+	//nolint:errcheck // Author is optional display information
+	view.Author, _ = h.store.GetCheckpointAuthor(ctx, checkpointID)
+
+	tokenUsage := content.Metadata.TokenUsage
+	if tokenUsage == nil {
+		tokenUsage = summary.TokenUsage
+	}
+	if tokenUsage != nil {
+		view.TotalTokens = tokenUsage.InputTokens + tokenUsage.CacheCreationTokens +
+			tokenUsage.CacheReadTokens + tokenUsage.OutputTokens
+	}
+
+	transcriptBytes := content.Transcript
+	if !view.FullTranscript {
+		transcriptBytes = scopeTranscriptForCheckpoint(content.Transcript, content.Metadata.GetTranscriptStart(), content.Metadata.Agent)
+	}
+	if len(transcriptBytes) > 0 {
+		if entries, parseErr := summarize.BuildCondensedTranscriptFromBytes(transcriptBytes, content.Metadata.Agent); parseErr == nil {
+			view.Transcript = entries
+		}
+	}
+
+	view.Commits = h.checkpointCommits(ctx, checkpointID)
+
+	h.render(w, r, serveCheckpointTemplate, view)
+}
+
+// checkpointCommits returns the commits linked to a checkpoint along with their diffs.
+// Lookup failures are logged and produce an empty list; diffs are best-effort.
+func (h *serveHandler) checkpointCommits(ctx context.Context, checkpointID id.CheckpointID) []serveCommitDiff {
+	commits, err := getAssociatedCommits(h.repo, checkpointID, false)
+	if err != nil {
+		logging.Debug(logging.WithComponent(ctx, "serve"), "failed to find commits for checkpoint",
+			slog.String("checkpoint_id", checkpointID.String()),
+			slog.String("error", err.Error()),
+		)
+		return nil
+	}
+
+	diffs := make([]serveCommitDiff, 0, len(commits))
+	for _, c := range commits {
+		diff := serveCommitDiff{associatedCommit: c}
+		patch, patchErr := commitPatch(h.repo, plumbing.NewHash(c.SHA))
+		if patchErr != nil {
+			diff.Patch = fmt.Sprintf("(failed to compute diff: %v)", patchErr)
+		} else {
+			diff.Patch, diff.Truncated = truncateDiff(patch, maxServeDiffBytes)
+		}
+		diffs = append(diffs, diff)
+	}
+	return diffs
+}
+
+// commitPatch returns the unified diff between a commit and its first parent.
+// Root commits are diffed against the empty tree.
+func commitPatch(repo *git.Repository, hash plumbing.Hash) (string, error) {
+	commit, err := repo.CommitObject(hash)
+	if err != nil {
+		return "", fmt.Errorf("failed to get commit %s: %w", hash, err)
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return "", fmt.Errorf("failed to get tree for %s: %w", hash, err)
+	}
+
+	var parentTree *object.Tree
+	if commit.NumParents() > 0 {
+		parent, parentErr := commit.Parent(0)
+		if parentErr != nil {
+			return "", fmt.Errorf("failed to get parent of %s: %w", hash, parentErr)
+		}
+		parentTree, err = parent.Tree()
+		if err != nil {
+			return "", fmt.Errorf("failed to get parent tree for %s: %w", hash, err)
+		}
+	}
+
+	changes, err := object.DiffTree(parentTree, tree)
+	if err != nil {
+		return "", fmt.Errorf("failed to diff %s: %w", hash, err)
+	}
+	patch, err := changes.Patch()
+	if err != nil {
+		return "", fmt.Errorf("failed to build patch for %s: %w", hash, err)
+	}
+	return patch.String(), nil
+}
+
+// truncateDiff cuts a patch at the last line boundary before limit bytes.
+func truncateDiff(patch string, limit int) (string, bool) {
+	if len(patch) <= limit {
+		return patch, false
+	}
+	cut := patch[:limit]
+	if idx := strings.LastIndexByte(cut, '\n'); idx > 0 {
+		cut = cut[:idx+1]
+	}
+	return cut, true
+}
+
+// diffLineClass returns the CSS class used to colour a line of a unified diff.
+func diffLineClass(line string) string {
+	switch {
+	case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"), strings.HasPrefix(line, "diff "):
+		return "diff-file"
+	case strings.HasPrefix(line, "@@"):
+		return "diff-hunk"
+	case strings.HasPrefix(line, "+"):
+		return "diff-add"
+	case strings.HasPrefix(line, "-"):
+		return "diff-del"
+	default:
+		return ""
+	}
+}
+
+// render executes a page template into a buffer so template errors
+// produce a clean 500 instead of a half-written page.
+func (h *serveHandler) render(w http.ResponseWriter, r *http.Request, tmpl *template.Template, data any) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		h.serverError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write(buf.Bytes()) //nolint:errcheck // Client disconnects are not actionable
+}
+
+func (h *serveHandler) serverError(w http.ResponseWriter, r *http.Request, err error) {
+	logging.Warn(logging.WithComponent(r.Context(), "serve"), "request failed",
+		slog.String("path", r.URL.Path),
+		slog.String("error", err.Error()),
+	)
+	http.Error(w, "internal error: "+err.Error(), http.StatusInternalServerError)
+}
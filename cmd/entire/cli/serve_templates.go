@@ -0,0 +1,207 @@
+package cli
+
+import (
+	"html/template"
+	"strings"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+)
+
+// serveTemplateFuncs are the helpers available to the `entire serve` templates.
+var serveTemplateFuncs = template.FuncMap{
+	"formatTime": func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.Local().Format("2006-01-02 15:04:05")
+	},
+	"truncate":          strategy.TruncateDescription,
+	"diffLines":         func(patch string) []string { return strings.Split(strings.TrimSuffix(patch, "\n"), "\n") },
+	"diffClass":         diffLineClass,
+	"inc":               func(i int) int { return i + 1 },
+	"summaryExtensions": summaryExtensions,
+}
+
+// serveStyles is the stylesheet shared by the `entire serve` pages and
+// standalone HTML exports.
+const serveStyles = `{{define "styles"}}
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", sans-serif; margin: 0; color: #1f2328; background: #f6f8fa; }
+header { background: #24292f; color: #fff; padding: 12px 24px; }
+header a { color: #fff; text-decoration: none; font-weight: 600; }
+main { max-width: 1100px; margin: 0 auto; padding: 24px; }
+h1 { font-size: 1.4em; word-break: break-all; }
+h2 { font-size: 1.1em; margin-top: 2em; border-bottom: 1px solid #d0d7de; padding-bottom: 4px; }
+table { border-collapse: collapse; width: 100%; background: #fff; }
+th, td { text-align: left; padding: 6px 10px; border-bottom: 1px solid #d0d7de; vertical-align: top; }
+code, pre { font-family: ui-monospace, SFMono-Regular, Menlo, monospace; font-size: 0.9em; }
+pre { background: #fff; border: 1px solid #d0d7de; padding: 10px; overflow-x: auto; white-space: pre-wrap; }
+dl { display: grid; grid-template-columns: max-content auto; gap: 4px 16px; }
+dt { font-weight: 600; }
+dd { margin: 0; }
+.muted { color: #656d76; }
+.entry { background: #fff; border: 1px solid #d0d7de; border-radius: 6px; padding: 8px 12px; margin: 8px 0; white-space: pre-wrap; }
+.entry-user { border-left: 4px solid #0969da; }
+.entry-assistant { border-left: 4px solid #8250df; }
+details.tool { margin: 4px 0 4px 16px; }
+details.tool summary { cursor: pointer; color: #656d76; }
+.diff { padding: 0; }
+.diff span { display: block; padding: 0 10px; white-space: pre; }
+.diff-add { background: #dafbe1; }
+.diff-del { background: #ffebe9; }
+.diff-hunk { color: #0969da; background: #ddf4ff; }
+.diff-file { font-weight: 600; }
+{{end}}`
+
+// serveLayout is the page chrome shared by every page. Each page template
+// defines a "content" block that is rendered inside it.
+const serveLayout = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}} · Entire</title>
+<style>
+{{template "styles"}}
+</style>
+</head>
+<body>
+<header><a href="/">Entire</a></header>
+<main>
+{{template "content" .}}
+</main>
+</body>
+</html>
+`
+
+const serveSessionsPage = `{{define "content"}}
+<h1>Sessions</h1>
+{{if .Sessions}}
+<table>
+<tr><th>Session</th><th>Agent</th><th>Checkpoints</th><th>Last activity</th></tr>
+{{range .Sessions}}
+<tr>
+<td><a href="/sessions/{{.ID}}"><code>{{.ID}}</code></a></td>
+<td>{{.Agent}}</td>
+<td>{{len .Checkpoints}}</td>
+<td>{{formatTime .LastSeen}}</td>
+</tr>
+{{end}}
+</table>
+{{else}}
+<p class="muted">No committed checkpoints yet. Checkpoints appear here once you commit work made with an agent.</p>
+{{end}}
+{{end}}`
+
+const serveSessionPage = `{{define "content"}}
+{{$session := .Session}}
+<h1>Session <code>{{$session.ID}}</code></h1>
+<dl>
+<dt>Agent</dt><dd>{{$session.Agent}}</dd>
+<dt>First checkpoint</dt><dd>{{formatTime $session.FirstSeen}}</dd>
+<dt>Last checkpoint</dt><dd>{{formatTime $session.LastSeen}}</dd>
+</dl>
+<h2>Checkpoints ({{len $session.Checkpoints}})</h2>
+<table>
+<tr><th>Checkpoint</th><th>Created</th><th>Files</th></tr>
+{{range $session.Checkpoints}}
+<tr>
+<td><a href="/checkpoints/{{.CheckpointID}}?session={{$session.ID}}"><code>{{.CheckpointID}}</code></a>{{if .IsTask}} <span class="muted">[Task]</span>{{end}}</td>
+<td>{{formatTime .CreatedAt}}</td>
+<td>{{len .FilesTouched}}</td>
+</tr>
+{{end}}
+</table>
+{{end}}`
+
+const serveCheckpointPage = `{{define "content"}}
+{{$meta := .Metadata}}
+<h1>Checkpoint <code>{{.CheckpointID}}</code></h1>
+<dl>
+<dt>Session</dt><dd><a href="/sessions/{{$meta.SessionID}}"><code>{{$meta.SessionID}}</code></a></dd>
+{{if gt (len .Summary.Sessions) 1}}<dt>Sessions</dt><dd>{{len .Summary.Sessions}} sessions contributed to this checkpoint</dd>{{end}}
+{{if $meta.Agent}}<dt>Agent</dt><dd>{{$meta.Agent}}</dd>{{end}}
+<dt>Created</dt><dd>{{formatTime $meta.CreatedAt}}</dd>
+{{if .Author.Name}}<dt>Author</dt><dd>{{.Author.Name}} &lt;{{.Author.Email}}&gt;</dd>{{end}}
+{{if $meta.Branch}}<dt>Branch</dt><dd><code>{{$meta.Branch}}</code></dd>{{end}}
+{{if .TotalTokens}}<dt>Tokens</dt><dd>{{.TotalTokens}}</dd>{{end}}
+</dl>
+
+<h2>Summary</h2>
+{{with $meta.Summary}}
+<dl>
+<dt>Intent</dt><dd>{{.Intent}}</dd>
+<dt>Outcome</dt><dd>{{.Outcome}}</dd>
+</dl>
+{{if or .Learnings.Repo .Learnings.Code .Learnings.Workflow}}
+<h3>Learnings</h3>
+<ul>
+{{range .Learnings.Repo}}<li><strong>Repository:</strong> {{.}}</li>{{end}}
+{{range .Learnings.Code}}<li><strong>Code:</strong> <code>{{.Path}}{{if gt .Line 0}}:{{.Line}}{{if gt .EndLine 0}}-{{.EndLine}}{{end}}{{end}}</code> {{.Finding}}</li>{{end}}
+{{range .Learnings.Workflow}}<li><strong>Workflow:</strong> {{.}}</li>{{end}}
+</ul>
+{{end}}
+{{if .Friction}}<h3>Friction</h3><ul>{{range .Friction}}<li>{{.}}</li>{{end}}</ul>{{end}}
+{{if .OpenItems}}<h3>Open Items</h3><ul>{{range .OpenItems}}<li>{{.}}</li>{{end}}</ul>{{end}}
+{{range summaryExtensions .Extensions}}<h3>{{.Label}}</h3><ul>{{range .Values}}<li>{{.}}</li>{{end}}</ul>{{end}}
+{{else}}
+<p class="muted">No summary generated. Run <code>entire explain -c {{.CheckpointID}} --generate</code> to create one.</p>
+{{end}}
+
+<h2>Attribution</h2>
+{{with $meta.InitialAttribution}}
+<table>
+<tr><th>Agent lines</th><th>Human added</th><th>Human modified</th><th>Human removed</th><th>Total committed</th><th>Agent %</th></tr>
+<tr><td>{{.AgentLines}}</td><td>{{.HumanAdded}}</td><td>{{.HumanModified}}</td><td>{{.HumanRemoved}}</td><td>{{.TotalCommitted}}</td><td>{{printf "%.1f" .AgentPercentage}}%</td></tr>
+</table>
+{{if .Prompts}}
+<h3>By prompt</h3>
+<p class="muted">Human columns are edits made after the agent responded, before the next prompt.</p>
+<table>
+<tr><th>#</th><th>Prompt</th><th>Agent lines</th><th>Human added</th><th>Human removed</th></tr>
+{{range $i, $p := .Prompts}}
+<tr><td>{{inc $i}}</td><td>{{if $p.Prompt}}{{truncate $p.Prompt 120}}{{else}}<span class="muted">(unknown)</span>{{end}}</td><td>{{$p.AgentLines}}</td><td>{{$p.HumanAdded}}</td><td>{{$p.HumanRemoved}}</td></tr>
+{{end}}
+</table>
+{{end}}
+{{else}}
+<p class="muted">No attribution recorded for this checkpoint.</p>
+{{end}}
+
+<h2>Files ({{len $meta.FilesTouched}})</h2>
+{{if $meta.FilesTouched}}<ul>{{range $meta.FilesTouched}}<li><code>{{.}}</code></li>{{end}}</ul>{{else}}<p class="muted">(none)</p>{{end}}
+
+<h2>Commits ({{len .Commits}})</h2>
+{{range .Commits}}
+<details>
+<summary><code>{{.ShortSHA}}</code> {{formatTime .Date}} {{.Message}} <span class="muted">({{.Author}})</span></summary>
+<pre class="diff">{{range diffLines .Patch}}<span{{with diffClass .}} class="{{.}}"{{end}}>{{.}}</span>{{end}}</pre>
+{{if .Truncated}}<p class="muted">Diff truncated.</p>{{end}}
+</details>
+{{else}}
+<p class="muted">No commits on this branch reference this checkpoint.</p>
+{{end}}
+
+<h2>Transcript ({{if .FullTranscript}}full session{{else}}checkpoint scope{{end}})</h2>
+<p>{{if .FullTranscript}}<a href="?session={{$meta.SessionID}}">Show checkpoint scope</a>{{else}}<a href="?session={{$meta.SessionID}}&amp;full=1">Show full session</a>{{end}}</p>
+{{range .Transcript}}
+{{if eq .Type "tool"}}<details class="tool"><summary>{{.ToolName}}{{if .ToolDetail}}: {{truncate .ToolDetail 80}}{{end}}</summary><pre>{{.ToolDetail}}</pre></details>
+{{else}}<div class="entry entry-{{.Type}}"><strong>{{if eq .Type "user"}}User{{else}}Assistant{{end}}</strong>
+{{.Content}}</div>
+{{end}}
+{{else}}
+{{if .Prompts}}<pre>{{.Prompts}}</pre>{{else}}<p class="muted">(no transcript)</p>{{end}}
+{{end}}
+{{end}}`
+
+var (
+	serveSessionsTemplate   = mustServePage(serveSessionsPage)
+	serveSessionTemplate    = mustServePage(serveSessionPage)
+	serveCheckpointTemplate = mustServePage(serveCheckpointPage)
+)
+
+// mustServePage parses a page template together with the shared layout.
+func mustServePage(page string) *template.Template {
+	return template.Must(template.Must(
+		template.New("layout").Funcs(serveTemplateFuncs).Parse(serveStyles + serveLayout),
+	).Parse(page))
+}
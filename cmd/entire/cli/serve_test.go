@@ -0,0 +1,307 @@
+package cli
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/agent"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/trailers"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+const serveTestTranscript = `{"type":"user","uuid":"u1","message":{"content":"Add a greeting file"}}
+{"type":"assistant","uuid":"a1","message":{"content":[{"type":"text","text":"I'll create hello.txt."},{"type":"tool_use","name":"Write","input":{"file_path":"hello.txt"}}]}}
+`
+
+// setupServeTestRepo creates a repo with an initial commit, a second commit
+// carrying a checkpoint trailer, and the matching committed checkpoint.
+func setupServeTestRepo(t *testing.T) (*git.Repository, id.CheckpointID) {
+	t.Helper()
+
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+
+	repo, err := git.PlainInit(tmpDir, false)
+	if err != nil {
+		t.Fatalf("failed to init git repo: %v", err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("failed to get worktree: %v", err)
+	}
+
+	sig := &object.Signature{Name: "Test", Email: "test@example.com", When: time.Now()}
+	commitFile := func(name, content, message string) plumbing.Hash {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+		if _, err := wt.Add(name); err != nil {
+			t.Fatalf("failed to add %s: %v", name, err)
+		}
+		hash, err := wt.Commit(message, &git.CommitOptions{Author: sig})
+		if err != nil {
+			t.Fatalf("failed to commit: %v", err)
+		}
+		return hash
+	}
+
+	commitFile("README.md", "# test\n", "initial commit")
+
+	cpID := id.MustCheckpointID("a1b2c3d4e5f6")
+	commitFile("hello.txt", "hello world\n", "Add greeting\n\n"+trailers.CheckpointTrailerKey+": "+cpID.String()+"\n")
+
+	store := checkpoint.NewGitStore(repo)
+	if err := store.WriteCommitted(context.Background(), checkpoint.WriteCommittedOptions{
+		CheckpointID: cpID,
+		SessionID:    "2026-01-01-serve-session",
+		Strategy:     "manual-commit",
+		Agent:        agent.AgentTypeClaudeCode,
+		Transcript:   []byte(serveTestTranscript),
+		Prompts:      []string{"Add a greeting file"},
+		FilesTouched: []string{"hello.txt"},
+		AuthorName:   "Test",
+		AuthorEmail:  "test@example.com",
+		Summary: &checkpoint.Summary{
+			Intent:  "Add a greeting file",
+			Outcome: "Created hello.txt",
+			Learnings: checkpoint.LearningsSummary{
+				Code: []checkpoint.CodeLearning{{Path: "hello.txt", Line: 1, Finding: "Greeting lives here"}},
+			},
+			Extensions: map[string]any{"test_gaps": []any{"No test for the greeting"}},
+		},
+		InitialAttribution: &checkpoint.InitialAttribution{
+			AgentLines:      1,
+			TotalCommitted:  1,
+			AgentPercentage: 100,
+			Prompts: []checkpoint.PromptAttribution{
+				{Checkpoint: 1, Prompt: "Add a greeting file", AgentLines: 1},
+			},
+		},
+	}); err != nil {
+		t.Fatalf("failed to write committed checkpoint: %v", err)
+	}
+
+	return repo, cpID
+}
+
+func serveGet(t *testing.T, handler http.Handler, target string) (int, string) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	req.Host = "127.0.0.1:7327"
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	body, err := io.ReadAll(rec.Result().Body)
+	if err != nil {
+		t.Fatalf("failed to read body: %v", err)
+	}
+	return rec.Code, string(body)
+}
+
+func TestNewServeCmd(t *testing.T) {
+	t.Parallel()
+
+	cmd := newServeCmd()
+	if cmd.Use != "serve" {
+		t.Errorf("Use = %q, want serve", cmd.Use)
+	}
+	portFlag := cmd.Flags().Lookup("port")
+	if portFlag == nil {
+		t.Fatal("expected --port flag")
+	}
+	if portFlag.DefValue != "7327" {
+		t.Errorf("--port default = %q, want 7327", portFlag.DefValue)
+	}
+	if err := cmd.Args(cmd, []string{"extra"}); err == nil {
+		t.Error("expected positional args to be rejected")
+	}
+}
+
+func TestServe_SessionList(t *testing.T) {
+	repo, _ := setupServeTestRepo(t)
+	handler := newServeHandler(repo)
+
+	code, body := serveGet(t, handler, "/")
+	if code != http.StatusOK {
+		t.Fatalf("GET / = %d, want 200", code)
+	}
+	if !strings.Contains(body, `href="/sessions/2026-01-01-serve-session"`) {
+		t.Errorf("session list missing session link:\n%s", body)
+	}
+	if !strings.Contains(body, "Claude Code") {
+		t.Errorf("session list missing agent name:\n%s", body)
+	}
+}
+
+func TestServe_SessionPage(t *testing.T) {
+	repo, cpID := setupServeTestRepo(t)
+	handler := newServeHandler(repo)
+
+	code, body := serveGet(t, handler, "/sessions/2026-01-01-serve-session")
+	if code != http.StatusOK {
+		t.Fatalf("GET session = %d, want 200", code)
+	}
+	if !strings.Contains(body, "/checkpoints/"+cpID.String()) {
+		t.Errorf("session page missing checkpoint link:\n%s", body)
+	}
+
+	code, _ = serveGet(t, handler, "/sessions/unknown-session")
+	if code != http.StatusNotFound {
+		t.Errorf("GET unknown session = %d, want 404", code)
+	}
+}
+
+func TestServe_CheckpointPage(t *testing.T) {
+	repo, cpID := setupServeTestRepo(t)
+	handler := newServeHandler(repo)
+
+	code, body := serveGet(t, handler, "/checkpoints/"+cpID.String())
+	if code != http.StatusOK {
+		t.Fatalf("GET checkpoint = %d, want 200:\n%s", code, body)
+	}
+
+	for _, want := range []string{
+		"Intent</dt><dd>Add a greeting file",               // summary
+		"Greeting lives here",                              // learnings
+		"<h3>Test Gaps</h3>",                               // template extension
+		"<td>100.0%</td>",                                  // attribution
+		"<h3>By prompt</h3>",                               // per-prompt attribution
+		"<td>1</td><td>Add a greeting file</td><td>1</td>", // first prompt row
+		`<details class="tool"><summary>Write: hello.txt`,  // collapsible tool call
+		"I&#39;ll create hello.txt.",                       // assistant text (escaped)
+		`<span class="diff-add">&#43;hello world</span>`,   // commit diff (html/template escapes +)
+		"Add greeting",                                     // commit subject
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("checkpoint page missing %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestServe_CheckpointNotFound(t *testing.T) {
+	repo, _ := setupServeTestRepo(t)
+	handler := newServeHandler(repo)
+
+	if code, _ := serveGet(t, handler, "/checkpoints/ffffffffffff"); code != http.StatusNotFound {
+		t.Errorf("GET missing checkpoint = %d, want 404", code)
+	}
+	if code, _ := serveGet(t, handler, "/checkpoints/not-an-id"); code != http.StatusNotFound {
+		t.Errorf("GET invalid checkpoint = %d, want 404", code)
+	}
+}
+
+func TestServe_CheckpointUnknownSession(t *testing.T) {
+	repo, cpID := setupServeTestRepo(t)
+	handler := newServeHandler(repo)
+
+	if code, _ := serveGet(t, handler, "/checkpoints/"+cpID.String()+"?session=no-such-session"); code != http.StatusNotFound {
+		t.Errorf("GET checkpoint with unknown session = %d, want 404", code)
+	}
+}
+
+func TestServe_RejectsNonLoopbackHost(t *testing.T) {
+	t.Parallel()
+
+	handler := requireLoopbackHost(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		host string
+		want int
+	}{
+		{"127.0.0.1:7327", http.StatusOK},
+		{"localhost:7327", http.StatusOK},
+		{"[::1]:7327", http.StatusOK},
+		{"evil.example.com", http.StatusForbidden},
+		{"192.168.1.10:7327", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Host = tt.host
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("host %q: got %d, want %d", tt.host, rec.Code, tt.want)
+		}
+	}
+}
+
+func TestGroupCheckpointsBySession(t *testing.T) {
+	t.Parallel()
+
+	t1 := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	t2 := t1.Add(time.Hour)
+	t3 := t2.Add(time.Hour)
+
+	infos := []checkpoint.CommittedInfo{
+		{CheckpointID: id.MustCheckpointID("aaaaaaaaaaaa"), SessionID: "s1", SessionIDs: []string{"s1"}, CreatedAt: t2},
+		{CheckpointID: id.MustCheckpointID("bbbbbbbbbbbb"), SessionID: "s1", SessionIDs: []string{"s1"}, CreatedAt: t1},
+		{CheckpointID: id.MustCheckpointID("cccccccccccc"), SessionID: "s2", SessionIDs: []string{"s1", "s2"}, CreatedAt: t3},
+	}
+
+	sessions := groupCheckpointsBySession(infos)
+	if len(sessions) != 2 {
+		t.Fatalf("got %d sessions, want 2", len(sessions))
+	}
+	// Both sessions were last active at t3; stable sort keeps first-seen order.
+	if sessions[0].ID != "s1" || len(sessions[0].Checkpoints) != 3 {
+		t.Errorf("session[0] = %s with %d checkpoints, want s1 with 3", sessions[0].ID, len(sessions[0].Checkpoints))
+	}
+	if !sessions[0].FirstSeen.Equal(t1) || !sessions[0].LastSeen.Equal(t3) {
+		t.Errorf("session[0] span = %v..%v, want %v..%v", sessions[0].FirstSeen, sessions[0].LastSeen, t1, t3)
+	}
+	if sessions[0].Checkpoints[0].CheckpointID.String() != "bbbbbbbbbbbb" {
+		t.Errorf("checkpoints not sorted oldest first: %v", sessions[0].Checkpoints)
+	}
+	if sessions[1].ID != "s2" || len(sessions[1].Checkpoints) != 1 {
+		t.Errorf("session[1] = %s with %d checkpoints, want s2 with 1", sessions[1].ID, len(sessions[1].Checkpoints))
+	}
+}
+
+func TestTruncateDiff(t *testing.T) {
+	t.Parallel()
+
+	patch := "line one\nline two\nline three\n"
+	if got, truncated := truncateDiff(patch, 100); truncated || got != patch {
+		t.Errorf("short patch should be unchanged, got %q (truncated=%v)", got, truncated)
+	}
+	got, truncated := truncateDiff(patch, 12)
+	if !truncated {
+		t.Error("expected truncation")
+	}
+	if got != "line one\n" {
+		t.Errorf("truncateDiff = %q, want cut at line boundary", got)
+	}
+}
+
+func TestDiffLineClass(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"diff --git a/x b/x": "diff-file",
+		"--- a/x":            "diff-file",
+		"+++ b/x":            "diff-file",
+		"@@ -1 +1 @@":        "diff-hunk",
+		"+added":             "diff-add",
+		"-removed":           "diff-del",
+		" context":           "",
+	}
+	for line, want := range tests {
+		if got := diffLineClass(line); got != want {
+			t.Errorf("diffLineClass(%q) = %q, want %q", line, got, want)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Annotations are the title, tags, and notes a user attached to a session with
+// "entire session rename", "tag", and "note". They are kept in the session state
+// while the session is active and copied into the checkpoint metadata when the
+// session is condensed.
+type Annotations struct {
+	// Title is a human-readable name shown instead of the first prompt
+	Title string `json:"title,omitempty"`
+
+	// Tags are normalized labels (see NormalizeTag), in the order they were added
+	Tags []string `json:"tags,omitempty"`
+
+	// Notes are free-form notes, oldest first
+	Notes []Note `json:"notes,omitempty"`
+}
+
+// Note is a free-form note attached to a session.
+type Note struct {
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NormalizeTag lowercases and trims a tag. Tags can't contain whitespace or
+// commas, so they can be listed and matched unambiguously.
+func NormalizeTag(tag string) (string, error) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	tag = strings.TrimPrefix(tag, "#")
+	if tag == "" {
+		return "", errors.New("tag must not be empty")
+	}
+	if strings.ContainsFunc(tag, func(r rune) bool { return unicode.IsSpace(r) || r == ',' }) {
+		return "", fmt.Errorf("tag %q must not contain spaces or commas", tag)
+	}
+	return tag, nil
+}
+
+// HasTag reports whether the annotations include the tag.
+// The tag is normalized before comparing.
+func (a Annotations) HasTag(tag string) bool {
+	normalized, err := NormalizeTag(tag)
+	if err != nil {
+		return false
+	}
+	return slices.Contains(a.Tags, normalized)
+}
+
+// AddTag adds an already-normalized tag. Reports false if it was present.
+func (a *Annotations) AddTag(tag string) bool {
+	if slices.Contains(a.Tags, tag) {
+		return false
+	}
+	a.Tags = append(a.Tags, tag)
+	return true
+}
+
+// RemoveTag removes an already-normalized tag. Reports false if it was absent.
+func (a *Annotations) RemoveTag(tag string) bool {
+	i := slices.Index(a.Tags, tag)
+	if i < 0 {
+		return false
+	}
+	a.Tags = slices.Delete(a.Tags, i, i+1)
+	return true
+}
+
+// AddNote appends a note.
+func (a *Annotations) AddNote(text string, now time.Time) {
+	a.Notes = append(a.Notes, Note{Text: text, CreatedAt: now.UTC()})
+}
+
+// FormatTags renders tags for display, e.g. "#auth #spike".
+func (a Annotations) FormatTags() string {
+	if len(a.Tags) == 0 {
+		return ""
+	}
+	return "#" + strings.Join(a.Tags, " #")
+}
@@ -0,0 +1,85 @@
+package session
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeTag(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "auth", want: "auth"},
+		{in: "  Auth ", want: "auth"},
+		{in: "#Spike", want: "spike"},
+		{in: "needs-review", want: "needs-review"},
+		{in: "", wantErr: true},
+		{in: "#", wantErr: true},
+		{in: "two words", wantErr: true},
+		{in: "a,b", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := NormalizeTag(tt.in)
+		if tt.wantErr {
+			assert.Error(t, err, "NormalizeTag(%q)", tt.in)
+			continue
+		}
+		require.NoError(t, err, "NormalizeTag(%q)", tt.in)
+		assert.Equal(t, tt.want, got, "NormalizeTag(%q)", tt.in)
+	}
+}
+
+func TestAnnotations_Tags(t *testing.T) {
+	t.Parallel()
+
+	var a Annotations
+	assert.True(t, a.AddTag("auth"))
+	assert.True(t, a.AddTag("spike"))
+	assert.False(t, a.AddTag("auth"), "duplicate tag should not be added")
+	assert.Equal(t, []string{"auth", "spike"}, a.Tags)
+	assert.True(t, a.HasTag("#Auth"))
+	assert.Equal(t, "#auth #spike", a.FormatTags())
+
+	assert.True(t, a.RemoveTag("auth"))
+	assert.False(t, a.RemoveTag("auth"))
+	assert.False(t, a.HasTag("auth"))
+	assert.Equal(t, []string{"spike"}, a.Tags)
+}
+
+func TestState_AnnotationsJSON(t *testing.T) {
+	t.Parallel()
+
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	state := State{SessionID: "s1"}
+	state.Title = "Login refactor"
+	state.AddTag("auth")
+	state.AddNote("keep the old endpoint", created)
+
+	data, err := json.Marshal(state)
+	require.NoError(t, err)
+
+	// Annotations are stored as top-level fields of the state file
+	var raw map[string]any
+	require.NoError(t, json.Unmarshal(data, &raw))
+	assert.Equal(t, "Login refactor", raw["title"])
+	assert.Equal(t, []any{"auth"}, raw["tags"])
+
+	var loaded State
+	require.NoError(t, json.Unmarshal(data, &loaded))
+	assert.Equal(t, state.Annotations, loaded.Annotations)
+
+	// State files without annotations omit the fields
+	data, err = json.Marshal(State{SessionID: "s2"})
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), `"title"`)
+	assert.NotContains(t, string(data), `"tags"`)
+	assert.NotContains(t, string(data), `"notes"`)
+}
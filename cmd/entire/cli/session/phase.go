@@ -15,10 +15,14 @@ const (
 	PhaseActive Phase = "active"
 	PhaseIdle   Phase = "idle"
 	PhaseEnded  Phase = "ended"
+
+	// PhasePaused means the user paused recording with "entire session pause".
+	// Agent turns create no checkpoints until the session is resumed.
+	PhasePaused Phase = "paused"
 )
 
 // allPhases is the canonical list of phases for enumeration (e.g., diagram generation).
-var allPhases = []Phase{PhaseIdle, PhaseActive, PhaseEnded}
+var allPhases = []Phase{PhaseIdle, PhaseActive, PhaseEnded, PhasePaused}
 
 // PhaseFromString normalizes a phase string, treating empty or unknown values
 // as PhaseIdle for backward compatibility with pre-state-machine session files.
@@ -33,6 +37,8 @@ func PhaseFromString(s string) Phase {
 		return PhaseIdle
 	case PhaseEnded:
 		return PhaseEnded
+	case PhasePaused:
+		return PhasePaused
 	default:
 		// Backward compat: truly unknown phases normalize to idle.
 		return PhaseIdle
@@ -44,6 +50,11 @@ func (p Phase) IsActive() bool {
 	return p == PhaseActive
 }
 
+// IsPaused reports whether the user paused recording for the session.
+func (p Phase) IsPaused() bool {
+	return p == PhasePaused
+}
+
 // Event represents something that happened to a session.
 type Event int
 
@@ -53,10 +64,13 @@ const (
 	EventGitCommit                 // A git commit was made (PostCommit hook)
 	EventSessionStart              // Session process started (SessionStart hook)
 	EventSessionStop               // Session process ended (SessionStop hook)
+	EventPause                     // User paused recording ("entire session pause")
+	EventResume                    // User resumed recording ("entire session start")
+	EventTimeout                   // No activity for longer than the configured idle timeout
 )
 
 // allEvents is the canonical list of events for enumeration.
-var allEvents = []Event{EventTurnStart, EventTurnEnd, EventGitCommit, EventSessionStart, EventSessionStop}
+var allEvents = []Event{EventTurnStart, EventTurnEnd, EventGitCommit, EventSessionStart, EventSessionStop, EventPause, EventResume, EventTimeout}
 
 // String returns a human-readable name for the event.
 func (e Event) String() string {
@@ -71,6 +85,12 @@ func (e Event) String() string {
 		return "SessionStart"
 	case EventSessionStop:
 		return "SessionStop"
+	case EventPause:
+		return "Pause"
+	case EventResume:
+		return "Resume"
+	case EventTimeout:
+		return "Timeout"
 	default:
 		return fmt.Sprintf("Event(%d)", int(e))
 	}
@@ -138,6 +158,8 @@ func Transition(current Phase, event Event, ctx TransitionContext) TransitionRes
 		return transitionFromActive(event, ctx)
 	case PhaseEnded:
 		return transitionFromEnded(event, ctx)
+	case PhasePaused:
+		return transitionFromPaused(event, ctx)
 	default:
 		// PhaseFromString guarantees we only get known phases, but the
 		// exhaustive linter requires handling the default case.
@@ -171,6 +193,17 @@ func transitionFromIdle(event Event, ctx TransitionContext) TransitionResult {
 			NewPhase: PhaseEnded,
 			Actions:  []Action{ActionUpdateLastInteraction},
 		}
+	case EventPause:
+		return TransitionResult{
+			NewPhase: PhasePaused,
+			Actions:  []Action{ActionUpdateLastInteraction},
+		}
+	case EventResume:
+		// Already recording, no-op.
+		return TransitionResult{NewPhase: PhaseIdle}
+	case EventTimeout:
+		// Waiting for the user is not a stuck session, no-op.
+		return TransitionResult{NewPhase: PhaseIdle}
 	default:
 		return TransitionResult{NewPhase: PhaseIdle}
 	}
@@ -207,6 +240,22 @@ func transitionFromActive(event Event, ctx TransitionContext) TransitionResult {
 			NewPhase: PhaseEnded,
 			Actions:  []Action{ActionUpdateLastInteraction},
 		}
+	case EventPause:
+		// Pausing mid-turn: the rest of the turn is not recorded.
+		return TransitionResult{
+			NewPhase: PhasePaused,
+			Actions:  []Action{ActionUpdateLastInteraction},
+		}
+	case EventResume:
+		// Already recording, no-op.
+		return TransitionResult{NewPhase: PhaseActive}
+	case EventTimeout:
+		// The turn never finished: the agent most likely crashed or was
+		// killed. End the session and condense what it recorded.
+		return TransitionResult{
+			NewPhase: PhaseEnded,
+			Actions:  []Action{ActionCondense},
+		}
 	default:
 		return TransitionResult{NewPhase: PhaseActive}
 	}
@@ -244,11 +293,68 @@ func transitionFromEnded(event Event, ctx TransitionContext) TransitionResult {
 	case EventSessionStop:
 		// Already ended, no-op.
 		return TransitionResult{NewPhase: PhaseEnded}
+	case EventPause:
+		// Nothing to pause, no-op.
+		return TransitionResult{NewPhase: PhaseEnded}
+	case EventResume:
+		// Re-opening an ended session, like a SessionStart.
+		return TransitionResult{
+			NewPhase: PhaseIdle,
+			Actions:  []Action{ActionClearEndedAt, ActionUpdateLastInteraction},
+		}
+	case EventTimeout:
+		// Already ended, no-op.
+		return TransitionResult{NewPhase: PhaseEnded}
 	default:
 		return TransitionResult{NewPhase: PhaseEnded}
 	}
 }
 
+// transitionFromPaused handles a session whose recording the user paused.
+// It behaves like IDLE for commits, so work recorded before the pause is still
+// condensed, but turns keep it paused until the user resumes.
+func transitionFromPaused(event Event, ctx TransitionContext) TransitionResult {
+	switch event {
+	case EventTurnStart:
+		return TransitionResult{
+			NewPhase: PhasePaused,
+			Actions:  []Action{ActionUpdateLastInteraction},
+		}
+	case EventTurnEnd:
+		// Turn end while paused is a no-op (the turn was not recorded).
+		return TransitionResult{NewPhase: PhasePaused}
+	case EventGitCommit:
+		if ctx.IsRebaseInProgress {
+			return TransitionResult{NewPhase: PhasePaused}
+		}
+		return TransitionResult{
+			NewPhase: PhasePaused,
+			Actions:  []Action{ActionCondense, ActionUpdateLastInteraction},
+		}
+	case EventSessionStart:
+		// Restarting the agent doesn't resume recording; only the user does.
+		return TransitionResult{NewPhase: PhasePaused}
+	case EventSessionStop:
+		return TransitionResult{
+			NewPhase: PhaseEnded,
+			Actions:  []Action{ActionUpdateLastInteraction},
+		}
+	case EventPause:
+		// Already paused, no-op.
+		return TransitionResult{NewPhase: PhasePaused}
+	case EventResume:
+		return TransitionResult{
+			NewPhase: PhaseIdle,
+			Actions:  []Action{ActionUpdateLastInteraction},
+		}
+	case EventTimeout:
+		// The user paused the session on purpose, no-op.
+		return TransitionResult{NewPhase: PhasePaused}
+	default:
+		return TransitionResult{NewPhase: PhasePaused}
+	}
+}
+
 // ApplyCommonActions applies the common (non-strategy-specific) actions from a
 // TransitionResult to the given State. It updates Phase, LastInteractionTime,
 // and EndedAt as indicated by the transition.
@@ -287,6 +393,7 @@ func MermaidDiagram() string {
 	b.WriteString("    state \"IDLE\" as idle\n")
 	b.WriteString("    state \"ACTIVE\" as active\n")
 	b.WriteString("    state \"ENDED\" as ended\n")
+	b.WriteString("    state \"PAUSED\" as paused\n")
 	b.WriteString("\n")
 
 	// Context variants for GitCommit: rebase, files/no-files.
@@ -20,6 +20,7 @@ func TestPhaseFromString(t *testing.T) {
 		{name: "active_committed", input: "active_committed", want: PhaseActive},
 		{name: "idle", input: "idle", want: PhaseIdle},
 		{name: "ended", input: "ended", want: PhaseEnded},
+		{name: "paused", input: "paused", want: PhasePaused},
 		{name: "empty_string_defaults_to_idle", input: "", want: PhaseIdle},
 		{name: "unknown_string_defaults_to_idle", input: "bogus", want: PhaseIdle},
 		{name: "uppercase_treated_as_unknown", input: "ACTIVE", want: PhaseIdle},
@@ -45,6 +46,7 @@ func TestPhase_IsActive(t *testing.T) {
 		{name: "active_is_active", phase: PhaseActive, want: true},
 		{name: "idle_is_not_active", phase: PhaseIdle, want: false},
 		{name: "ended_is_not_active", phase: PhaseEnded, want: false},
+		{name: "paused_is_not_active", phase: PhasePaused, want: false},
 	}
 
 	for _, tt := range tests {
@@ -67,6 +69,9 @@ func TestEvent_String(t *testing.T) {
 		{EventGitCommit, "GitCommit"},
 		{EventSessionStart, "SessionStart"},
 		{EventSessionStop, "SessionStop"},
+		{EventPause, "Pause"},
+		{EventResume, "Resume"},
+		{EventTimeout, "Timeout"},
 	}
 
 	for _, tt := range tests {
@@ -169,6 +174,27 @@ func TestTransitionFromIdle(t *testing.T) {
 			wantPhase:   PhaseIdle,
 			wantActions: nil,
 		},
+		{
+			name:        "Pause_transitions_to_PAUSED",
+			current:     PhaseIdle,
+			event:       EventPause,
+			wantPhase:   PhasePaused,
+			wantActions: []Action{ActionUpdateLastInteraction},
+		},
+		{
+			name:        "Resume_is_noop",
+			current:     PhaseIdle,
+			event:       EventResume,
+			wantPhase:   PhaseIdle,
+			wantActions: nil,
+		},
+		{
+			name:        "Timeout_is_noop",
+			current:     PhaseIdle,
+			event:       EventTimeout,
+			wantPhase:   PhaseIdle,
+			wantActions: nil,
+		},
 	})
 }
 
@@ -218,6 +244,27 @@ func TestTransitionFromActive(t *testing.T) {
 			wantPhase:   PhaseActive,
 			wantActions: []Action{ActionWarnStaleSession},
 		},
+		{
+			name:        "Pause_transitions_to_PAUSED",
+			current:     PhaseActive,
+			event:       EventPause,
+			wantPhase:   PhasePaused,
+			wantActions: []Action{ActionUpdateLastInteraction},
+		},
+		{
+			name:        "Resume_is_noop",
+			current:     PhaseActive,
+			event:       EventResume,
+			wantPhase:   PhaseActive,
+			wantActions: nil,
+		},
+		{
+			name:        "Timeout_ends_and_condenses",
+			current:     PhaseActive,
+			event:       EventTimeout,
+			wantPhase:   PhaseEnded,
+			wantActions: []Action{ActionCondense},
+		},
 	})
 }
 
@@ -275,6 +322,97 @@ func TestTransitionFromEnded(t *testing.T) {
 			wantPhase:   PhaseEnded,
 			wantActions: nil,
 		},
+		{
+			name:        "Pause_is_noop",
+			current:     PhaseEnded,
+			event:       EventPause,
+			wantPhase:   PhaseEnded,
+			wantActions: nil,
+		},
+		{
+			name:        "Resume_transitions_to_IDLE",
+			current:     PhaseEnded,
+			event:       EventResume,
+			wantPhase:   PhaseIdle,
+			wantActions: []Action{ActionClearEndedAt, ActionUpdateLastInteraction},
+		},
+		{
+			name:        "Timeout_is_noop",
+			current:     PhaseEnded,
+			event:       EventTimeout,
+			wantPhase:   PhaseEnded,
+			wantActions: nil,
+		},
+	})
+}
+
+func TestTransitionFromPaused(t *testing.T) {
+	t.Parallel()
+	runTransitionTests(t, []transitionCase{
+		{
+			name:        "TurnStart_stays_PAUSED",
+			current:     PhasePaused,
+			event:       EventTurnStart,
+			wantPhase:   PhasePaused,
+			wantActions: []Action{ActionUpdateLastInteraction},
+		},
+		{
+			name:        "TurnEnd_is_noop",
+			current:     PhasePaused,
+			event:       EventTurnEnd,
+			wantPhase:   PhasePaused,
+			wantActions: nil,
+		},
+		{
+			name:        "GitCommit_condenses_recorded_work",
+			current:     PhasePaused,
+			event:       EventGitCommit,
+			wantPhase:   PhasePaused,
+			wantActions: []Action{ActionCondense, ActionUpdateLastInteraction},
+		},
+		{
+			name:        "GitCommit_rebase_skips_everything",
+			current:     PhasePaused,
+			event:       EventGitCommit,
+			ctx:         TransitionContext{IsRebaseInProgress: true},
+			wantPhase:   PhasePaused,
+			wantActions: nil,
+		},
+		{
+			name:        "SessionStart_stays_PAUSED",
+			current:     PhasePaused,
+			event:       EventSessionStart,
+			wantPhase:   PhasePaused,
+			wantActions: nil,
+		},
+		{
+			name:        "SessionStop_transitions_to_ENDED",
+			current:     PhasePaused,
+			event:       EventSessionStop,
+			wantPhase:   PhaseEnded,
+			wantActions: []Action{ActionUpdateLastInteraction},
+		},
+		{
+			name:        "Pause_is_noop",
+			current:     PhasePaused,
+			event:       EventPause,
+			wantPhase:   PhasePaused,
+			wantActions: nil,
+		},
+		{
+			name:        "Resume_transitions_to_IDLE",
+			current:     PhasePaused,
+			event:       EventResume,
+			wantPhase:   PhaseIdle,
+			wantActions: []Action{ActionUpdateLastInteraction},
+		},
+		{
+			name:        "Timeout_is_noop",
+			current:     PhasePaused,
+			event:       EventTimeout,
+			wantPhase:   PhasePaused,
+			wantActions: nil,
+		},
 	})
 }
 
@@ -529,6 +667,9 @@ func TestMermaidDiagram(t *testing.T) {
 	assert.Contains(t, diagram, "active --> active") // ACTIVE+GitCommit stays ACTIVE
 	assert.Contains(t, diagram, "ended --> idle")
 	assert.Contains(t, diagram, "ended --> active")
+	assert.Contains(t, diagram, "idle --> paused")
+	assert.Contains(t, diagram, "paused --> idle")
+	assert.Contains(t, diagram, "active --> ended : Timeout / Condense")
 
 	// Verify actions appear in labels.
 	assert.Contains(t, diagram, "Condense")
@@ -92,6 +92,15 @@ type State struct {
 	// for checkpoint condensation: "everything since last checkpoint".
 	CheckpointTranscriptStart int `json:"checkpoint_transcript_start,omitempty"`
 
+	// PausedTranscriptStart is the transcript offset at which the user paused
+	// recording. While the session is paused, condensation stops here.
+	PausedTranscriptStart int `json:"paused_transcript_start,omitempty"`
+
+	// PausedTranscriptRanges are the parts of the transcript written while
+	// recording was paused. Every checkpoint stores the full transcript, so they
+	// are kept for the life of the session and left out of each condensation.
+	PausedTranscriptRanges []TranscriptRange `json:"paused_transcript_ranges,omitempty"`
+
 	// Deprecated: CondensedTranscriptLines is replaced by CheckpointTranscriptStart.
 	// Kept for backward compatibility with existing state files.
 	// Use NormalizeAfterLoad() to migrate.
@@ -103,6 +112,12 @@ type State struct {
 	// FilesTouched tracks files modified/created/deleted during this session
 	FilesTouched []string `json:"files_touched,omitempty"`
 
+	// FileHashes maps each file in FilesTouched to the git blob hash of the
+	// content the session last left it in ("" if the session deleted it).
+	// Updated at every checkpoint, including subagent checkpoints, so PostCommit
+	// can attribute a commit only to the sessions whose edits it contains.
+	FileHashes map[string]string `json:"file_hashes,omitempty"`
+
 	// LastCheckpointID is the checkpoint ID from the most recent condensation.
 	// Used to restore the Entire-Checkpoint trailer on amend and to identify
 	// sessions that have been condensed at least once. Cleared on new prompt.
@@ -128,6 +143,10 @@ type State struct {
 	// FirstPrompt is the first user prompt that started this session (truncated for display)
 	FirstPrompt string `json:"first_prompt,omitempty"`
 
+	// Annotations are the user's title, tags, and notes for this session.
+	// Carried into the checkpoint metadata at condensation.
+	Annotations
+
 	// PromptAttributions tracks user and agent line changes at each prompt start.
 	// This enables accurate attribution by capturing user edits between checkpoints.
 	PromptAttributions []PromptAttribution `json:"prompt_attributions,omitempty"`
@@ -135,6 +154,32 @@ type State struct {
 	// PendingPromptAttribution holds attribution calculated at prompt start (before agent runs).
 	// This is moved to PromptAttributions when SaveChanges is called.
 	PendingPromptAttribution *PromptAttribution `json:"pending_prompt_attribution,omitempty"`
+
+	// Subagents records the files each subagent modified, captured when its task
+	// checkpoint is saved. Used to split agent lines by subagent at condensation.
+	Subagents []SubagentActivity `json:"subagents,omitempty"`
+}
+
+// SubagentActivity captures the files a single subagent (Task tool invocation) modified.
+type SubagentActivity struct {
+	// ToolUseID identifies the Task tool invocation that spawned the subagent
+	ToolUseID string `json:"tool_use_id"`
+
+	// AgentID is the subagent's ID (used to locate its transcript)
+	AgentID string `json:"agent_id,omitempty"`
+
+	// SubagentType is the type of subagent (e.g., "dev", "reviewer")
+	SubagentType string `json:"subagent_type,omitempty"`
+
+	// Files are the repo-relative paths the subagent modified or created
+	Files []string `json:"files"`
+}
+
+// TranscriptRange is a half-open range [Start, End) of transcript offsets:
+// lines for JSONL transcripts, message indexes for Gemini.
+type TranscriptRange struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
 }
 
 // PromptAttribution captures line-level attribution data at the start of each prompt.
@@ -144,6 +189,9 @@ type PromptAttribution struct {
 	// CheckpointNumber is which checkpoint this was recorded before (1-indexed)
 	CheckpointNumber int `json:"checkpoint_number"`
 
+	// Prompt is the user prompt that started this turn (truncated for display)
+	Prompt string `json:"prompt,omitempty"`
+
 	// UserLinesAdded is lines added by user since the last checkpoint
 	UserLinesAdded int `json:"user_lines_added"`
 
@@ -0,0 +1,463 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/session"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+
+	"github.com/spf13/cobra"
+)
+
+func newSessionCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "session",
+		Short: "Manage sessions: pause, resume, end, name, tag, and annotate",
+		Long: `Control how a session is recorded, and attach a title, tags, and notes to it
+so it is easier to find later.
+
+"entire session pause" stops creating checkpoints for a session until
+"entire session start" resumes it, for example while you explore an idea you
+don't want recorded. "entire session end" ends a session without closing the
+agent.
+
+Annotations are kept with the session while it is active and copied into its
+checkpoints on the entire/checkpoints/v1 branch when they are committed.
+Changing a session that already has checkpoints updates them too.
+
+Sessions can be given by full ID or by a unique prefix. Titles and tags are
+shown by "entire status", "entire explain", and "entire rewind", and can be
+searched through the MCP server's search_sessions tool.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(newSessionStartCmd())
+	cmd.AddCommand(newSessionPauseCmd())
+	cmd.AddCommand(newSessionEndCmd())
+	cmd.AddCommand(newSessionRenameCmd())
+	cmd.AddCommand(newSessionTagCmd())
+	cmd.AddCommand(newSessionNoteCmd())
+
+	return cmd
+}
+
+func newSessionStartCmd() *cobra.Command {
+	var nameFlag string
+
+	cmd := &cobra.Command{
+		Use:   "start [session]",
+		Short: "Resume recording a paused or ended session",
+		Long: `Resume recording a session that was paused with "entire session pause" or
+ended with "entire session end". Without an argument, the most recently active
+session is used.
+
+Sessions are started automatically when the agent starts; use --name to give
+the session a title at the same time (see "entire session rename").
+
+When nothing was checkpointed yet, the conversation that happened while the
+session was paused is left out of its next checkpoint.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if checkDisabledGuard(cmd.OutOrStdout()) {
+				return nil
+			}
+			return runSessionStart(cmd.Context(), cmd.OutOrStdout(), sessionArg(args), strings.TrimSpace(nameFlag))
+		},
+	}
+
+	cmd.Flags().StringVar(&nameFlag, "name", "", "Give the session a title")
+
+	return cmd
+}
+
+func newSessionPauseCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "pause [session]",
+		Short: "Stop creating checkpoints for a session",
+		Long: `Stop creating checkpoints for a session until it is resumed with
+"entire session start". Without an argument, the most recently active session
+is used.
+
+Work that was checkpointed before the pause is still condensed when you
+commit. Turns made while paused are left out of checkpoint transcripts, even
+though the agent's own transcript file keeps the whole conversation.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if checkDisabledGuard(cmd.OutOrStdout()) {
+				return nil
+			}
+			return runSessionPause(cmd.Context(), cmd.OutOrStdout(), sessionArg(args))
+		},
+	}
+}
+
+func newSessionEndCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "end [session]",
+		Short: "End a session",
+		Long: `End a session, as if its agent had been closed. Without an argument, the
+most recently active session is used.
+
+Checkpoints that haven't been committed yet are condensed with your next
+commit. Sending the agent another prompt, or "entire session start", resumes
+the session.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if checkDisabledGuard(cmd.OutOrStdout()) {
+				return nil
+			}
+			return runSessionEnd(cmd.Context(), cmd.OutOrStdout(), sessionArg(args))
+		},
+	}
+}
+
+func sessionArg(args []string) string {
+	if len(args) == 0 {
+		return ""
+	}
+	return args[0]
+}
+
+// resolveTrackedSession resolves a session like resolveSession, but requires
+// it to still have live state; lifecycle commands can't act on sessions that
+// only exist in committed checkpoints.
+func resolveTrackedSession(ctx context.Context, arg string) (*checkpoint.GitStore, *strategy.SessionState, error) {
+	store, err := openCheckpointStore()
+	if err != nil {
+		return nil, nil, err
+	}
+	sessionID, state, err := resolveSession(ctx, store, arg)
+	if err != nil {
+		return nil, nil, err
+	}
+	if state == nil {
+		return nil, nil, fmt.Errorf("session %s is no longer tracked; it has been fully committed", sessionID)
+	}
+	return store, state, nil
+}
+
+func runSessionStart(ctx context.Context, w io.Writer, arg, name string) error {
+	store, state, err := resolveTrackedSession(ctx, arg)
+	if err != nil {
+		return err
+	}
+
+	wasPaused := state.Phase.IsPaused()
+	resumed := wasPaused || state.Phase == session.PhaseEnded
+	if resumed {
+		strategy.TransitionAndLog(state, session.EventResume, session.TransitionContext{})
+	}
+	if wasPaused {
+		// Remember the turns made while paused so condensation leaves them out
+		resumeOffset := currentTranscriptOffset(state)
+		if resumeOffset > state.PausedTranscriptStart {
+			state.PausedTranscriptRanges = append(state.PausedTranscriptRanges, session.TranscriptRange{
+				Start: state.PausedTranscriptStart,
+				End:   resumeOffset,
+			})
+		}
+		state.PausedTranscriptStart = 0
+		if state.StepCount == 0 {
+			// Nothing was checkpointed yet, so start the next checkpoint's
+			// transcript after the turns made while paused.
+			state.CheckpointTranscriptStart = max(state.CheckpointTranscriptStart, resumeOffset)
+		}
+	}
+	if name != "" {
+		state.Title = name
+	}
+	if err := strategy.SaveSessionState(state); err != nil {
+		return fmt.Errorf("failed to save session state: %w", err)
+	}
+
+	if name != "" {
+		if _, err := store.UpdateSessionAnnotations(ctx, state.SessionID, func(a *session.Annotations) {
+			a.Title = name
+		}); err != nil {
+			return fmt.Errorf("failed to update checkpoints: %w", err)
+		}
+	}
+
+	switch {
+	case resumed && name != "":
+		fmt.Fprintf(w, "✓ Resumed session %s as %q\n", state.SessionID, name)
+	case resumed:
+		fmt.Fprintf(w, "✓ Resumed session %s\n", state.SessionID)
+	case name != "":
+		fmt.Fprintf(w, "✓ Renamed session %s to %q\n", state.SessionID, name)
+	default:
+		fmt.Fprintf(w, "Session %s is already recording\n", state.SessionID)
+	}
+	return nil
+}
+
+func runSessionPause(ctx context.Context, w io.Writer, arg string) error {
+	_, state, err := resolveTrackedSession(ctx, arg)
+	if err != nil {
+		return err
+	}
+
+	switch state.Phase {
+	case session.PhasePaused:
+		fmt.Fprintf(w, "Session %s is already paused\n", state.SessionID)
+		return nil
+	case session.PhaseEnded:
+		return fmt.Errorf("session %s has ended; resume it with \"entire session start\" first", state.SessionID)
+	case session.PhaseIdle, session.PhaseActive:
+	}
+
+	strategy.TransitionAndLog(state, session.EventPause, session.TransitionContext{})
+	state.PausedTranscriptStart = currentTranscriptOffset(state)
+	if err := strategy.SaveSessionState(state); err != nil {
+		return fmt.Errorf("failed to save session state: %w", err)
+	}
+	fmt.Fprintf(w, "✓ Paused session %s\n", state.SessionID)
+	fmt.Fprintln(w, "  No checkpoints will be created until you run \"entire session start\".")
+	return nil
+}
+
+// currentTranscriptOffset returns the length of the session's live transcript,
+// in lines (or messages for Gemini). Returns 0 if it can't be read.
+func currentTranscriptOffset(state *strategy.SessionState) int {
+	if state.TranscriptPath == "" {
+		return 0
+	}
+	content, err := os.ReadFile(state.TranscriptPath)
+	if err != nil {
+		return 0
+	}
+	return transcriptOffset(content, state.AgentType)
+}
+
+func runSessionEnd(ctx context.Context, w io.Writer, arg string) error {
+	_, state, err := resolveTrackedSession(ctx, arg)
+	if err != nil {
+		return err
+	}
+	if state.Phase == session.PhaseEnded {
+		fmt.Fprintf(w, "Session %s has already ended\n", state.SessionID)
+		return nil
+	}
+
+	if err := markSessionEnded(state.SessionID); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "✓ Ended session %s\n", state.SessionID)
+	if state.StepCount > 0 {
+		fmt.Fprintf(w, "  Its %d uncommitted checkpoint(s) will be condensed with your next commit.\n", state.StepCount)
+	}
+	return nil
+}
+
+func newSessionRenameCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rename <session> <title>",
+		Short: "Give a session a title",
+		Long: `Give a session a human-readable title, shown instead of its first prompt.
+Pass an empty title to remove it.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if checkDisabledGuard(cmd.OutOrStdout()) {
+				return nil
+			}
+			title := strings.TrimSpace(args[1])
+			sessionID, updated, err := annotateSession(cmd.Context(), args[0], func(a *session.Annotations) {
+				a.Title = title
+			})
+			if err != nil {
+				return err
+			}
+			if title == "" {
+				fmt.Fprintf(cmd.OutOrStdout(), "✓ Removed the title of session %s%s\n", sessionID, formatCheckpointsUpdated(updated))
+			} else {
+				fmt.Fprintf(cmd.OutOrStdout(), "✓ Renamed session %s to %q%s\n", sessionID, title, formatCheckpointsUpdated(updated))
+			}
+			return nil
+		},
+	}
+}
+
+func newSessionTagCmd() *cobra.Command {
+	var removeFlag bool
+
+	cmd := &cobra.Command{
+		Use:   "tag <session> <tag>...",
+		Short: "Add or remove session tags",
+		Long: `Add tags to a session, or remove them with --remove.
+
+Tags are case-insensitive and can't contain spaces or commas. A leading # is
+ignored, so "#auth" and "auth" are the same tag. Filter active sessions by tag
+with "entire status --tag <tag>".`,
+		Args: cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if checkDisabledGuard(cmd.OutOrStdout()) {
+				return nil
+			}
+			tags := make([]string, 0, len(args)-1)
+			for _, arg := range args[1:] {
+				tag, err := session.NormalizeTag(arg)
+				if err != nil {
+					return fmt.Errorf("invalid tag: %w", err)
+				}
+				tags = append(tags, tag)
+			}
+			sessionID, updated, err := annotateSession(cmd.Context(), args[0], func(a *session.Annotations) {
+				for _, tag := range tags {
+					if removeFlag {
+						a.RemoveTag(tag)
+					} else {
+						a.AddTag(tag)
+					}
+				}
+			})
+			if err != nil {
+				return err
+			}
+			formatted := session.Annotations{Tags: tags}.FormatTags()
+			if removeFlag {
+				fmt.Fprintf(cmd.OutOrStdout(), "✓ Removed %s from session %s%s\n", formatted, sessionID, formatCheckpointsUpdated(updated))
+			} else {
+				fmt.Fprintf(cmd.OutOrStdout(), "✓ Tagged session %s with %s%s\n", sessionID, formatted, formatCheckpointsUpdated(updated))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&removeFlag, "remove", false, "Remove the tags instead of adding them")
+
+	return cmd
+}
+
+func newSessionNoteCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "note <session> <text>",
+		Short: "Add a note to a session",
+		Long:  `Add a free-form note to a session, for example what was decided or what is left to do.`,
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if checkDisabledGuard(cmd.OutOrStdout()) {
+				return nil
+			}
+			text := strings.TrimSpace(args[1])
+			if text == "" {
+				return errors.New("note must not be empty")
+			}
+			now := time.Now()
+			sessionID, updated, err := annotateSession(cmd.Context(), args[0], func(a *session.Annotations) {
+				a.AddNote(text, now)
+			})
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "✓ Added a note to session %s%s\n", sessionID, formatCheckpointsUpdated(updated))
+			return nil
+		},
+	}
+}
+
+// annotateSession applies update to the session's live state, if it is still
+// tracked, and to the metadata of its committed checkpoints. Returns the full
+// session ID and the number of checkpoints updated.
+func annotateSession(ctx context.Context, arg string, update func(*session.Annotations)) (string, int, error) {
+	store, err := openCheckpointStore()
+	if err != nil {
+		return "", 0, err
+	}
+	sessionID, state, err := resolveSession(ctx, store, arg)
+	if err != nil {
+		return "", 0, err
+	}
+
+	if state != nil {
+		update(&state.Annotations)
+		if err := strategy.SaveSessionState(state); err != nil {
+			return "", 0, fmt.Errorf("failed to save session state: %w", err)
+		}
+	}
+
+	updated, err := store.UpdateSessionAnnotations(ctx, sessionID, update)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to update checkpoints: %w", err)
+	}
+	return sessionID, updated, nil
+}
+
+func formatCheckpointsUpdated(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (%d checkpoint(s) updated)", n)
+}
+
+// resolveSession resolves a session ID or unique prefix against the tracked
+// sessions and the sessions of committed checkpoints. An empty arg selects the
+// most recently active session, falling back to the latest committed one.
+// Returns the session's live state when it is still tracked (may be nil).
+func resolveSession(ctx context.Context, store *checkpoint.GitStore, arg string) (string, *strategy.SessionState, error) {
+	states, err := strategy.ListSessionStates()
+	if err != nil {
+		states = nil // Live state is optional; committed history is enough
+	}
+	findState := func(sessionID string) *strategy.SessionState {
+		for _, s := range states {
+			if s.SessionID == sessionID {
+				return s
+			}
+		}
+		return nil
+	}
+
+	infos, err := store.ListCommitted(ctx)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to list checkpoints: %w", err)
+	}
+
+	if arg == "" {
+		if sessionID := strategy.FindMostRecentSession(); sessionID != "" {
+			return sessionID, findState(sessionID), nil
+		}
+		if len(infos) > 0 && infos[0].SessionID != "" {
+			return infos[0].SessionID, findState(infos[0].SessionID), nil
+		}
+		return "", nil, errors.New("no sessions found; start a session with an agent first")
+	}
+
+	candidates := make(map[string]bool)
+	for _, s := range states {
+		candidates[s.SessionID] = true
+	}
+	for _, info := range infos {
+		if info.SessionID != "" {
+			candidates[info.SessionID] = true
+		}
+	}
+	if candidates[arg] {
+		return arg, findState(arg), nil
+	}
+
+	var matches []string
+	for sessionID := range candidates {
+		if strings.HasPrefix(sessionID, arg) {
+			matches = append(matches, sessionID)
+		}
+	}
+	sort.Strings(matches)
+	switch len(matches) {
+	case 0:
+		return "", nil, fmt.Errorf("session not found: %s", arg)
+	case 1:
+		return matches[0], findState(matches[0]), nil
+	default:
+		return "", nil, fmt.Errorf("ambiguous session prefix %q matches %d sessions: %s", arg, len(matches), strings.Join(matches[:min(len(matches), 5)], ", "))
+	}
+}
@@ -0,0 +1,219 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/session"
+	"github.com/entireio/cli/cmd/entire/cli/strategy"
+)
+
+func runSessionCmd(t *testing.T, args ...string) string {
+	t.Helper()
+
+	cmd := newSessionCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs(args)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("session %v error = %v\n%s", args, err, out.String())
+	}
+	return out.String()
+}
+
+func TestSessionCmd_AnnotatesCommittedSession(t *testing.T) {
+	repo, cpID := setupServeTestRepo(t)
+
+	out := runSessionCmd(t, "rename", "2026-01-01-serve", "Greeting work")
+	if !strings.Contains(out, `Renamed session 2026-01-01-serve-session to "Greeting work" (1 checkpoint(s) updated)`) {
+		t.Errorf("unexpected rename output: %s", out)
+	}
+	runSessionCmd(t, "tag", "2026-01-01-serve", "#Docs", "spike")
+	runSessionCmd(t, "tag", "--remove", "2026-01-01-serve", "spike")
+	runSessionCmd(t, "note", "2026-01-01-serve", "Keep the greeting short")
+
+	store := checkpoint.NewGitStore(repo)
+	content, err := store.ReadSessionContentByID(context.Background(), cpID, "2026-01-01-serve-session")
+	if err != nil {
+		t.Fatalf("ReadSessionContentByID() error = %v", err)
+	}
+	got := content.Metadata.Annotations
+	if got.Title != "Greeting work" {
+		t.Errorf("Title = %q, want Greeting work", got.Title)
+	}
+	if !slices.Equal(got.Tags, []string{"docs"}) {
+		t.Errorf("Tags = %v, want [docs]", got.Tags)
+	}
+	if len(got.Notes) != 1 || got.Notes[0].Text != "Keep the greeting short" {
+		t.Errorf("Notes = %+v, want the added note", got.Notes)
+	}
+
+	// The annotations are searchable through the MCP server
+	text, isErr := callMCPTool(t, newEntireMCPServer(repo), "search_sessions", map[string]any{"query": "greeting work", "tag": "docs"})
+	if isErr {
+		t.Fatalf("search_sessions error: %s", text)
+	}
+	var results []mcpSession
+	if err := json.Unmarshal([]byte(text), &results); err != nil {
+		t.Fatalf("failed to parse results: %v\n%s", err, text)
+	}
+	if len(results) != 1 || results[0].Title != "Greeting work" {
+		t.Errorf("results = %+v, want the renamed session", results)
+	}
+
+	text, _ = callMCPTool(t, newEntireMCPServer(repo), "search_sessions", map[string]any{"tag": "spike"})
+	if err := json.Unmarshal([]byte(text), &results); err != nil {
+		t.Fatalf("failed to parse results: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("removed tag still matches %d results", len(results))
+	}
+}
+
+func TestSessionCmd_AnnotatesLiveSession(t *testing.T) {
+	setupServeTestRepo(t)
+
+	state := &strategy.SessionState{
+		SessionID: "2026-02-02-live-session",
+		StartedAt: time.Now(),
+	}
+	if err := strategy.SaveSessionState(state); err != nil {
+		t.Fatalf("SaveSessionState() error = %v", err)
+	}
+
+	out := runSessionCmd(t, "tag", "2026-02-02", "auth")
+	if !strings.Contains(out, "Tagged session 2026-02-02-live-session with #auth\n") {
+		t.Errorf("unexpected tag output: %s", out)
+	}
+
+	loaded, err := strategy.LoadSessionState("2026-02-02-live-session")
+	if err != nil || loaded == nil {
+		t.Fatalf("LoadSessionState() = %v, %v", loaded, err)
+	}
+	if !loaded.HasTag("auth") {
+		t.Errorf("Tags = %v, want auth", loaded.Tags)
+	}
+}
+
+func TestSessionCmd_Lifecycle(t *testing.T) {
+	setupServeTestRepo(t)
+
+	transcriptPath := filepath.Join(t.TempDir(), "transcript.jsonl")
+	if err := os.WriteFile(transcriptPath, []byte("{}\n{}\n{}\n"), 0o600); err != nil {
+		t.Fatalf("failed to write transcript: %v", err)
+	}
+	if err := strategy.SaveSessionState(&strategy.SessionState{
+		SessionID:      "2026-03-03-lifecycle-session",
+		StartedAt:      time.Now(),
+		Phase:          session.PhaseIdle,
+		TranscriptPath: transcriptPath,
+	}); err != nil {
+		t.Fatalf("SaveSessionState() error = %v", err)
+	}
+	load := func() *strategy.SessionState {
+		t.Helper()
+		state, err := strategy.LoadSessionState("2026-03-03-lifecycle-session")
+		if err != nil || state == nil {
+			t.Fatalf("LoadSessionState() = %v, %v", state, err)
+		}
+		return state
+	}
+
+	out := runSessionCmd(t, "pause", "2026-03-03")
+	if !strings.Contains(out, "✓ Paused session 2026-03-03-lifecycle-session") {
+		t.Errorf("unexpected pause output: %s", out)
+	}
+	if !isRecordingPaused("2026-03-03-lifecycle-session") {
+		t.Error("expected recording to be paused")
+	}
+	if got := load().PausedTranscriptStart; got != 3 {
+		t.Errorf("PausedTranscriptStart = %d, want 3", got)
+	}
+	if out := runSessionCmd(t, "pause", "2026-03-03"); !strings.Contains(out, "already paused") {
+		t.Errorf("unexpected second pause output: %s", out)
+	}
+
+	// The agent keeps talking while paused
+	if err := os.WriteFile(transcriptPath, []byte("{}\n{}\n{}\n{}\n{}\n"), 0o600); err != nil {
+		t.Fatalf("failed to write transcript: %v", err)
+	}
+
+	out = runSessionCmd(t, "start", "--name", "Spike", "2026-03-03")
+	if !strings.Contains(out, `✓ Resumed session 2026-03-03-lifecycle-session as "Spike"`) {
+		t.Errorf("unexpected start output: %s", out)
+	}
+	state := load()
+	if state.Phase != session.PhaseIdle || state.Title != "Spike" {
+		t.Errorf("after start: Phase = %s, Title = %q; want idle, Spike", state.Phase, state.Title)
+	}
+	if state.CheckpointTranscriptStart != 5 {
+		t.Errorf("CheckpointTranscriptStart = %d, want 5 (after the paused turns)", state.CheckpointTranscriptStart)
+	}
+	if want := []session.TranscriptRange{{Start: 3, End: 5}}; !slices.Equal(state.PausedTranscriptRanges, want) {
+		t.Errorf("PausedTranscriptRanges = %v, want %v", state.PausedTranscriptRanges, want)
+	}
+
+	runSessionCmd(t, "end", "2026-03-03")
+	state = load()
+	if state.Phase != session.PhaseEnded || state.EndedAt == nil {
+		t.Errorf("after end: Phase = %s, EndedAt = %v; want ended with a timestamp", state.Phase, state.EndedAt)
+	}
+
+	cmd := newSessionCmd()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"pause", "2026-03-03"})
+	if err := cmd.Execute(); err == nil {
+		t.Error("expected pausing an ended session to fail")
+	}
+
+	runSessionCmd(t, "start", "2026-03-03")
+	state = load()
+	if state.Phase != session.PhaseIdle || state.EndedAt != nil {
+		t.Errorf("after restart: Phase = %s, EndedAt = %v; want idle without EndedAt", state.Phase, state.EndedAt)
+	}
+}
+
+func TestSessionCmd_RejectsInvalidInput(t *testing.T) {
+	setupServeTestRepo(t)
+
+	for _, args := range [][]string{
+		{"tag", "2026-01-01-serve", "two words"},
+		{"note", "2026-01-01-serve", "   "},
+		{"rename", "no-such-session", "Title"},
+	} {
+		cmd := newSessionCmd()
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs(args)
+		if err := cmd.Execute(); err == nil {
+			t.Errorf("session %v: expected an error", args)
+		}
+	}
+}
+
+func TestRewindSessionLabel(t *testing.T) {
+	t.Parallel()
+
+	point := strategy.RewindPoint{SessionPrompt: "fix the login flow"}
+	if got := rewindSessionLabel(point, false); got != "" {
+		t.Errorf("single session without annotations = %q, want empty", got)
+	}
+	if got := rewindSessionLabel(point, true); got != " [fix the login flow]" {
+		t.Errorf("multiple sessions = %q, want the prompt", got)
+	}
+
+	point.Annotations = session.Annotations{Title: "Login refactor", Tags: []string{"auth"}}
+	if got := rewindSessionLabel(point, false); got != " [Login refactor #auth]" {
+		t.Errorf("annotated session = %q, want title and tags", got)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/entireio/cli/cmd/entire/cli/jsonutil"
 	"github.com/entireio/cli/cmd/entire/cli/paths"
@@ -49,6 +50,68 @@ type EntireSettings struct {
 	// Telemetry controls anonymous usage analytics.
 	// nil = not asked yet (show prompt), true = opted in, false = opted out
 	Telemetry *bool `json:"telemetry,omitempty"`
+
+	// Redaction customizes how secrets are redacted from transcripts and
+	// metadata before they are written to checkpoints.
+	Redaction *RedactionSettings `json:"redaction,omitempty"`
+}
+
+// RedactionSettings is the "redaction" section of the settings.
+type RedactionSettings struct {
+	// EntropyThreshold overrides the minimum Shannon entropy (default 4.5) for a
+	// string to be redacted as a likely secret.
+	EntropyThreshold float64 `json:"entropy_threshold,omitempty"`
+
+	// GitleaksConfig is the path, relative to the repository root, of a gitleaks
+	// TOML config used instead of the built-in gitleaks rules.
+	GitleaksConfig string `json:"gitleaks_config,omitempty"`
+
+	// Rules are additional regex rules for organization-specific secrets.
+	Rules []RedactionRule `json:"rules,omitempty"`
+
+	// Allowlist lists values that are never redacted.
+	Allowlist RedactionAllowlist `json:"allowlist,omitzero"`
+
+	// PII lists the personal data categories to redact: "email", "phone",
+	// "ip_address", "credit_card", and "national_id". None are redacted by default.
+	PII []string `json:"pii,omitempty"`
+
+	// Labels maps rule IDs (e.g. "aws-access-token", a custom rule ID, a PII
+	// category, or "entropy") to the replacement text, such as "REDACTED:aws-key".
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Snapshots controls which working tree files shadow branch snapshots
+	// skip or redact.
+	Snapshots RedactionSnapshots `json:"snapshots,omitzero"`
+}
+
+// RedactionSnapshots adds to the built-in globs of secret files, such as .env
+// and *.pem. Gitignored secret files are left out of shadow branch snapshots;
+// other secret files and gitignored files are snapshotted with secrets redacted.
+type RedactionSnapshots struct {
+	// Exclude are extra globs of secret files.
+	Exclude []string `json:"exclude,omitempty"`
+
+	// Include are globs of files that are never treated as secret files, even
+	// when they match an exclude glob (e.g. ".env.test").
+	Include []string `json:"include,omitempty"`
+}
+
+// RedactionRule is a custom redaction regex. If the regex has a capture group,
+// only the first group is replaced.
+type RedactionRule struct {
+	ID          string `json:"id"`
+	Regex       string `json:"regex"`
+	Replacement string `json:"replacement,omitempty"`
+}
+
+// RedactionAllowlist lists detected values that should be kept.
+type RedactionAllowlist struct {
+	// Patterns are regexes matched against each detected value.
+	Patterns []string `json:"patterns,omitempty"`
+
+	// Values are literal values, such as well-known test keys.
+	Values []string `json:"values,omitempty"`
 }
 
 // Load loads the Entire settings from .entire/settings.json,
@@ -204,6 +267,15 @@ func mergeJSON(settings *EntireSettings, data []byte) error {
 		settings.Telemetry = &t
 	}
 
+	// Override redaction if present
+	if redactionRaw, ok := raw["redaction"]; ok {
+		var r RedactionSettings
+		if err := json.Unmarshal(redactionRaw, &r); err != nil {
+			return fmt.Errorf("parsing redaction field: %w", err)
+		}
+		settings.Redaction = &r
+	}
+
 	return nil
 }
 
@@ -239,6 +311,131 @@ func (s *EntireSettings) IsSummarizeEnabled() bool {
 	return enabled
 }
 
+// Summary generator providers for strategy_options.summarize.provider.
+const (
+	SummarizeProviderClaude = "claude"
+	SummarizeProviderGemini = "gemini"
+	SummarizeProviderOpenAI = "openai"
+)
+
+// SummarizeOptions is the typed form of strategy_options.summarize.
+type SummarizeOptions struct {
+	// Enabled turns on auto-summarize at commit time.
+	Enabled bool
+
+	// Provider selects the summary generator: "claude" (default), "gemini",
+	// or "openai" for any OpenAI-compatible endpoint (e.g. Ollama, llama.cpp).
+	Provider string
+
+	// Model overrides the provider's default model.
+	Model string
+
+	// Endpoint is the base URL of an OpenAI-compatible API (e.g. http://localhost:11434/v1).
+	Endpoint string
+
+	// APIKeyEnv names the environment variable holding the API key for the
+	// OpenAI-compatible provider. The key itself is never stored in settings.
+	APIKeyEnv string
+
+	// Timeout bounds a single summary generation. Zero means no timeout.
+	Timeout time.Duration
+
+	// ChunkTokens is the estimated transcript size summarized per call. Longer
+	// transcripts are summarized in windows and merged. Zero means the default.
+	ChunkTokens int
+}
+
+// GetSummarizeOptions loads the summarize options from settings.
+// Returns defaults (the Claude provider) if settings cannot be loaded.
+func GetSummarizeOptions() (SummarizeOptions, error) {
+	settings, err := Load()
+	if err != nil {
+		return SummarizeOptions{Provider: SummarizeProviderClaude}, err
+	}
+	return settings.GetSummarizeOptions()
+}
+
+// GetSummarizeOptions returns the summarize options from this settings instance.
+// The provider defaults to "claude". Returns an error for an unknown provider or
+// an unparseable timeout (e.g. "90s" and "2m" are valid).
+func (s *EntireSettings) GetSummarizeOptions() (SummarizeOptions, error) {
+	opts := SummarizeOptions{Provider: SummarizeProviderClaude}
+	summarizeOpts, ok := s.StrategyOptions["summarize"].(map[string]any)
+	if !ok {
+		return opts, nil
+	}
+
+	opts.Enabled, _ = summarizeOpts["enabled"].(bool)
+	opts.Model, _ = summarizeOpts["model"].(string)
+	opts.Endpoint, _ = summarizeOpts["endpoint"].(string)
+	opts.APIKeyEnv, _ = summarizeOpts["api_key_env"].(string)
+
+	if provider, ok := summarizeOpts["provider"].(string); ok && provider != "" {
+		switch provider {
+		case SummarizeProviderClaude, SummarizeProviderGemini, SummarizeProviderOpenAI:
+			opts.Provider = provider
+		default:
+			return opts, fmt.Errorf("unknown summarize provider %q (expected %s, %s, or %s)",
+				provider, SummarizeProviderClaude, SummarizeProviderGemini, SummarizeProviderOpenAI)
+		}
+	}
+
+	if chunkTokens, ok := summarizeOpts["chunk_tokens"].(float64); ok {
+		if chunkTokens < 0 {
+			return opts, fmt.Errorf("invalid summarize chunk_tokens: %v", chunkTokens)
+		}
+		opts.ChunkTokens = int(chunkTokens)
+	}
+
+	switch timeout := summarizeOpts["timeout"].(type) {
+	case nil:
+	case string:
+		d, err := time.ParseDuration(timeout)
+		if err != nil {
+			return opts, fmt.Errorf("invalid summarize timeout %q: %w", timeout, err)
+		}
+		opts.Timeout = d
+	case float64:
+		// Bare numbers are seconds.
+		opts.Timeout = time.Duration(timeout * float64(time.Second))
+	default:
+		return opts, fmt.Errorf("invalid summarize timeout: %v", timeout)
+	}
+
+	return opts, nil
+}
+
+// GetIdleTimeout returns strategy_options.idle_timeout: how long an ACTIVE
+// session can go without activity before Entire ends it. Zero (the default)
+// disables the timeout. Accepts a duration string like "2h" or a number of
+// seconds.
+func (s *EntireSettings) GetIdleTimeout() (time.Duration, error) {
+	switch timeout := s.StrategyOptions["idle_timeout"].(type) {
+	case nil:
+		return 0, nil
+	case string:
+		if timeout == "" {
+			return 0, nil
+		}
+		d, err := time.ParseDuration(timeout)
+		if err != nil {
+			return 0, fmt.Errorf("invalid idle_timeout %q: %w", timeout, err)
+		}
+		if d < 0 {
+			return 0, fmt.Errorf("invalid idle_timeout %q: must not be negative", timeout)
+		}
+		return d, nil
+	case float64:
+		// Bare numbers are seconds.
+		if timeout < 0 {
+			return 0, fmt.Errorf("invalid idle_timeout %v: must not be negative", timeout)
+		}
+		return time.Duration(timeout * float64(time.Second)), nil
+	default:
+		return 0, fmt.Errorf("invalid idle_timeout: %v", timeout)
+	}
+}
+
 // IsPushSessionsDisabled checks if push_sessions is disabled in settings.
 // Returns true if push_sessions is explicitly set to false.
 func (s *EntireSettings) IsPushSessionsDisabled() bool {
@@ -53,6 +53,7 @@ func newEnableCmd() *cobra.Command {
 	var forceHooks bool
 	var skipPushSessions bool
 	var telemetry bool
+	var registerMCP bool
 
 	cmd := &cobra.Command{
 		Use:   "enable",
@@ -96,13 +97,13 @@ Strategies: manual-commit (default), auto-commit`,
 					printWrongAgentError(cmd.ErrOrStderr(), agentName)
 					return NewSilentError(errors.New("wrong agent name"))
 				}
-				return setupAgentHooksNonInteractive(cmd.OutOrStdout(), ag, strategyFlag, localDev, forceHooks, skipPushSessions, telemetry)
+				return setupAgentHooksNonInteractive(cmd.OutOrStdout(), ag, strategyFlag, localDev, forceHooks, skipPushSessions, telemetry, registerMCP)
 			}
 			// If strategy is specified via flag, skip interactive selection
 			if strategyFlag != "" {
-				return runEnableWithStrategy(cmd.OutOrStdout(), strategyFlag, localDev, ignoreUntracked, useLocalSettings, useProjectSettings, forceHooks, skipPushSessions, telemetry)
+				return runEnableWithStrategy(cmd.OutOrStdout(), strategyFlag, localDev, ignoreUntracked, useLocalSettings, useProjectSettings, forceHooks, skipPushSessions, telemetry, registerMCP)
 			}
-			return runEnableInteractive(cmd.OutOrStdout(), localDev, ignoreUntracked, useLocalSettings, useProjectSettings, forceHooks, skipPushSessions, telemetry)
+			return runEnableInteractive(cmd.OutOrStdout(), localDev, ignoreUntracked, useLocalSettings, useProjectSettings, forceHooks, skipPushSessions, telemetry, registerMCP)
 		},
 	}
 
@@ -117,6 +118,7 @@ Strategies: manual-commit (default), auto-commit`,
 	cmd.Flags().BoolVarP(&forceHooks, "force", "f", false, "Force reinstall hooks (removes existing Entire hooks first)")
 	cmd.Flags().BoolVar(&skipPushSessions, "skip-push-sessions", false, "Disable automatic pushing of session logs on git push")
 	cmd.Flags().BoolVar(&telemetry, "telemetry", true, "Enable anonymous usage analytics")
+	cmd.Flags().BoolVar(&registerMCP, "mcp", false, "Register the Entire MCP server (entire mcp) in the agent's MCP config")
 	//nolint:errcheck,gosec // completion is optional, flag is defined above
 	cmd.RegisterFlagCompletionFunc("strategy", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
 		return []string{strategyDisplayManualCommit, strategyDisplayAutoCommit}, cobra.ShellCompDirectiveNoFileComp
@@ -154,10 +156,11 @@ show a disabled message.
 
 To completely remove Entire integrations from this repository, use --uninstall:
   - .entire/ directory (settings, logs, metadata)
-  - Git hooks (prepare-commit-msg, commit-msg, post-commit, pre-push)
+  - Git hooks (prepare-commit-msg, commit-msg, post-commit, post-rewrite, pre-push)
   - Session state files (.git/entire-sessions/)
   - Shadow branches (entire/<hash>)
-  - Agent hooks (Claude Code, Gemini CLI)`,
+  - Agent hooks (Claude Code, Gemini CLI)
+  - MCP server registrations (.mcp.json, .gemini/settings.json)`,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			if uninstall {
 				return runUninstall(cmd.OutOrStdout(), cmd.ErrOrStderr(), force)
@@ -221,7 +224,7 @@ func isFullyEnabled() (enabled bool, agentDesc string, configPath string) {
 // runEnableWithStrategy enables Entire with a specified strategy (non-interactive).
 // The selectedStrategy can be either a display name (manual-commit, auto-commit)
 // or an internal name (manual-commit, auto-commit).
-func runEnableWithStrategy(w io.Writer, selectedStrategy string, localDev, _, useLocalSettings, useProjectSettings, forceHooks, skipPushSessions, telemetry bool) error {
+func runEnableWithStrategy(w io.Writer, selectedStrategy string, localDev, _, useLocalSettings, useProjectSettings, forceHooks, skipPushSessions, telemetry, registerMCP bool) error {
 	// Map the strategy to internal name if it's a display name
 	internalStrategy := selectedStrategy
 	if mapped, ok := strategyDisplayToInternal[selectedStrategy]; ok {
@@ -314,16 +317,22 @@ func runEnableWithStrategy(w io.Writer, selectedStrategy string, localDev, _, us
 		return fmt.Errorf("failed to setup strategy: %w", err)
 	}
 
+	if registerMCP {
+		if err := setupClaudeCodeMCPServer(w, localDev); err != nil {
+			return err
+		}
+	}
+
 	fmt.Fprintln(w, "\nReady.")
 
 	return nil
 }
 
 // runEnableInteractive runs the interactive enable flow.
-func runEnableInteractive(w io.Writer, localDev, _, useLocalSettings, useProjectSettings, forceHooks, skipPushSessions, telemetry bool) error {
+func runEnableInteractive(w io.Writer, localDev, _, useLocalSettings, useProjectSettings, forceHooks, skipPushSessions, telemetry, registerMCP bool) error {
 	// Check if already fully enabled — show summary and return early.
 	// Skip early return if any configuration flags are set (user wants to reconfigure).
-	hasConfigFlags := forceHooks || skipPushSessions || !telemetry || useLocalSettings || useProjectSettings || localDev
+	hasConfigFlags := forceHooks || skipPushSessions || !telemetry || useLocalSettings || useProjectSettings || localDev || registerMCP
 	if !hasConfigFlags {
 		if fullyEnabled, agentDesc, configPath := isFullyEnabled(); fullyEnabled {
 			fmt.Fprintln(w, "Already enabled. Everything looks good.")
@@ -431,6 +440,12 @@ func runEnableInteractive(w io.Writer, localDev, _, useLocalSettings, useProject
 		return fmt.Errorf("failed to setup strategy: %w", err)
 	}
 
+	if registerMCP {
+		if err := setupClaudeCodeMCPServer(w, localDev); err != nil {
+			return err
+		}
+	}
+
 	fmt.Fprintln(w, "\nReady.")
 
 	return nil
@@ -517,6 +532,36 @@ func setupClaudeCodeHook(localDev, forceHooks bool) (int, error) { //nolint:unpa
 	return count, nil
 }
 
+// setupClaudeCodeMCPServer registers the Entire MCP server for Claude Code.
+func setupClaudeCodeMCPServer(w io.Writer, localDev bool) error {
+	ag, err := agent.Get(agent.AgentNameClaudeCode)
+	if err != nil {
+		return fmt.Errorf("failed to get claude-code agent: %w", err)
+	}
+	return setupMCPServer(w, ag, localDev)
+}
+
+// setupMCPServer registers the Entire MCP server in the agent's MCP config.
+// Agents without MCP support are skipped with a note rather than failing enable.
+func setupMCPServer(w io.Writer, ag agent.Agent, localDev bool) error {
+	mcpAgent, ok := ag.(agent.MCPServerSupport)
+	if !ok {
+		fmt.Fprintf(w, "Note: %s does not support MCP servers; skipping MCP registration\n", ag.Type())
+		return nil
+	}
+
+	changed, err := mcpAgent.InstallMCPServer(localDev)
+	if err != nil {
+		return fmt.Errorf("failed to register MCP server for %s: %w", ag.Type(), err)
+	}
+	if changed {
+		fmt.Fprintf(w, "✓ MCP server registered for %s\n", ag.Type())
+	} else {
+		fmt.Fprintf(w, "✓ MCP server already registered for %s\n", ag.Type())
+	}
+	return nil
+}
+
 // printAgentError writes an error message followed by available agents and usage.
 func printAgentError(w io.Writer, message string) {
 	agents := agent.List()
@@ -545,7 +590,7 @@ func printWrongAgentError(w io.Writer, name string) {
 
 // setupAgentHooksNonInteractive sets up hooks for a specific agent non-interactively.
 // If strategyName is provided, it sets the strategy; otherwise uses default.
-func setupAgentHooksNonInteractive(w io.Writer, ag agent.Agent, strategyName string, localDev, forceHooks, skipPushSessions, telemetry bool) error {
+func setupAgentHooksNonInteractive(w io.Writer, ag agent.Agent, strategyName string, localDev, forceHooks, skipPushSessions, telemetry, registerMCP bool) error {
 	agentName := ag.Name()
 	// Check if agent supports hooks
 	hookAgent, ok := ag.(agent.HookSupport)
@@ -640,6 +685,12 @@ func setupAgentHooksNonInteractive(w io.Writer, ag agent.Agent, strategyName str
 		return fmt.Errorf("failed to setup strategy: %w", err)
 	}
 
+	if registerMCP {
+		if err := setupMCPServer(w, ag, localDev); err != nil {
+			return err
+		}
+	}
+
 	fmt.Fprintln(w, "\nReady.")
 
 	return nil
@@ -939,7 +990,7 @@ func runUninstall(w, errW io.Writer, force bool) error {
 			fmt.Fprintln(w, "  - .entire/ directory")
 		}
 		if gitHooksInstalled {
-			fmt.Fprintln(w, "  - Git hooks (prepare-commit-msg, commit-msg, post-commit, pre-push)")
+			fmt.Fprintln(w, "  - Git hooks (prepare-commit-msg, commit-msg, post-commit, post-rewrite, pre-push)")
 		}
 		if sessionStateCount > 0 {
 			fmt.Fprintf(w, "  - Session state files (%d)\n", sessionStateCount)
@@ -1108,6 +1159,23 @@ func removeAgentHooks(w io.Writer) error {
 		}
 	}
 
+	// Remove MCP server registrations
+	for _, name := range []agent.AgentName{agent.AgentNameClaudeCode, agent.AgentNameGemini} {
+		ag, err := agent.Get(name)
+		if err != nil {
+			continue
+		}
+		mcpAgent, ok := ag.(agent.MCPServerSupport)
+		if !ok || !mcpAgent.IsMCPServerInstalled() {
+			continue
+		}
+		if err := mcpAgent.UninstallMCPServer(); err != nil {
+			errs = append(errs, err)
+		} else {
+			fmt.Fprintf(w, "  Removed %s MCP server\n", ag.Type())
+		}
+	}
+
 	return errors.Join(errs...)
 }
 
@@ -22,22 +22,24 @@ import (
 
 func newStatusCmd() *cobra.Command {
 	var detailed bool
+	var tag string
 
 	cmd := &cobra.Command{
 		Use:   "status",
 		Short: "Show Entire status",
 		Long:  "Show whether Entire is currently enabled or disabled",
 		RunE: func(cmd *cobra.Command, _ []string) error {
-			return runStatus(cmd.OutOrStdout(), detailed)
+			return runStatus(cmd.OutOrStdout(), detailed, tag)
 		},
 	}
 
 	cmd.Flags().BoolVar(&detailed, "detailed", false, "Show detailed status for each settings file")
+	cmd.Flags().StringVar(&tag, "tag", "", "Only show active sessions with this tag")
 
 	return cmd
 }
 
-func runStatus(w io.Writer, detailed bool) error {
+func runStatus(w io.Writer, detailed bool, tag string) error {
 	// Check if we're in a git repository
 	if _, repoErr := paths.RepoRoot(); repoErr != nil {
 		fmt.Fprintln(w, "✕ not a git repository")
@@ -72,7 +74,7 @@ func runStatus(w io.Writer, detailed bool) error {
 	}
 
 	if detailed {
-		return runStatusDetailed(w, settingsPath, localSettingsPath, projectExists, localExists)
+		return runStatusDetailed(w, settingsPath, localSettingsPath, projectExists, localExists, tag)
 	}
 
 	// Short output: just show the effective/merged state
@@ -84,14 +86,14 @@ func runStatus(w io.Writer, detailed bool) error {
 	fmt.Fprintln(w, formatSettingsStatusShort(settings))
 
 	if settings.Enabled {
-		writeActiveSessions(w)
+		writeActiveSessions(w, tag)
 	}
 
 	return nil
 }
 
 // runStatusDetailed shows the effective status plus detailed status for each settings file.
-func runStatusDetailed(w io.Writer, settingsPath, localSettingsPath string, projectExists, localExists bool) error {
+func runStatusDetailed(w io.Writer, settingsPath, localSettingsPath string, projectExists, localExists bool, tag string) error {
 	// First show the effective/merged status
 	effectiveSettings, err := LoadEntireSettings()
 	if err != nil {
@@ -119,7 +121,7 @@ func runStatusDetailed(w io.Writer, settingsPath, localSettingsPath string, proj
 	}
 
 	if effectiveSettings.Enabled {
-		writeActiveSessions(w)
+		writeActiveSessions(w, tag)
 	}
 
 	return nil
@@ -181,7 +183,8 @@ type worktreeGroup struct {
 const unknownPlaceholder = "(unknown)"
 
 // writeActiveSessions writes active session information grouped by worktree.
-func writeActiveSessions(w io.Writer) {
+// A non-empty tag limits the output to sessions with that tag.
+func writeActiveSessions(w io.Writer, tag string) {
 	store, err := session.NewStateStore()
 	if err != nil {
 		return
@@ -195,7 +198,7 @@ func writeActiveSessions(w io.Writer) {
 	// Filter to active sessions only
 	var active []*session.State
 	for _, s := range states {
-		if s.EndedAt == nil {
+		if s.EndedAt == nil && (tag == "" || s.HasTag(tag)) {
 			active = append(active, s)
 		}
 	}
@@ -269,11 +272,22 @@ func writeActiveSessions(w io.Writer) {
 				activeStr = ", active " + timeAgo(*st.LastInteractionTime)
 			}
 
-			fmt.Fprintf(w, "    [%s] %-9s %s%s\n",
-				agentLabel, shortID, age, activeStr)
+			if st.Phase.IsPaused() {
+				activeStr += ", paused"
+			}
+
+			tagsStr := ""
+			if len(st.Tags) > 0 {
+				tagsStr = "  " + st.FormatTags()
+			}
+
+			fmt.Fprintf(w, "    [%s] %-9s %s%s%s\n",
+				agentLabel, shortID, age, activeStr, tagsStr)
 
-			// Show first prompt on indented second line
-			if st.FirstPrompt != "" {
+			// Show the title, or else the first prompt, on indented second line
+			if st.Title != "" {
+				fmt.Fprintf(w, "      %s\n", stringutil.TruncateRunes(st.Title, 60, "..."))
+			} else if st.FirstPrompt != "" {
 				prompt := stringutil.TruncateRunes(st.FirstPrompt, 60, "...")
 				fmt.Fprintf(w, "      \"%s\"\n", prompt)
 			}
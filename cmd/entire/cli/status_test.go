@@ -16,7 +16,7 @@ func TestRunStatus_Enabled(t *testing.T) {
 	writeSettings(t, testSettingsEnabled)
 
 	var stdout bytes.Buffer
-	if err := runStatus(&stdout, false); err != nil {
+	if err := runStatus(&stdout, false, ""); err != nil {
 		t.Fatalf("runStatus() error = %v", err)
 	}
 
@@ -30,7 +30,7 @@ func TestRunStatus_Disabled(t *testing.T) {
 	writeSettings(t, testSettingsDisabled)
 
 	var stdout bytes.Buffer
-	if err := runStatus(&stdout, false); err != nil {
+	if err := runStatus(&stdout, false, ""); err != nil {
 		t.Fatalf("runStatus() error = %v", err)
 	}
 
@@ -43,7 +43,7 @@ func TestRunStatus_NotSetUp(t *testing.T) {
 	setupTestRepo(t)
 
 	var stdout bytes.Buffer
-	if err := runStatus(&stdout, false); err != nil {
+	if err := runStatus(&stdout, false, ""); err != nil {
 		t.Fatalf("runStatus() error = %v", err)
 	}
 
@@ -60,7 +60,7 @@ func TestRunStatus_NotGitRepository(t *testing.T) {
 	setupTestDir(t) // No git init
 
 	var stdout bytes.Buffer
-	if err := runStatus(&stdout, false); err != nil {
+	if err := runStatus(&stdout, false, ""); err != nil {
 		t.Fatalf("runStatus() error = %v", err)
 	}
 
@@ -74,7 +74,7 @@ func TestRunStatus_LocalSettingsOnly(t *testing.T) {
 	writeLocalSettings(t, `{"strategy": "auto-commit", "enabled": true}`)
 
 	var stdout bytes.Buffer
-	if err := runStatus(&stdout, true); err != nil {
+	if err := runStatus(&stdout, true, ""); err != nil {
 		t.Fatalf("runStatus() error = %v", err)
 	}
 
@@ -101,7 +101,7 @@ func TestRunStatus_BothProjectAndLocal(t *testing.T) {
 	writeLocalSettings(t, `{"strategy": "auto-commit", "enabled": false}`)
 
 	var stdout bytes.Buffer
-	if err := runStatus(&stdout, true); err != nil {
+	if err := runStatus(&stdout, true, ""); err != nil {
 		t.Fatalf("runStatus() error = %v", err)
 	}
 
@@ -128,7 +128,7 @@ func TestRunStatus_BothProjectAndLocal_Short(t *testing.T) {
 	writeLocalSettings(t, `{"strategy": "auto-commit", "enabled": false}`)
 
 	var stdout bytes.Buffer
-	if err := runStatus(&stdout, false); err != nil {
+	if err := runStatus(&stdout, false, ""); err != nil {
 		t.Fatalf("runStatus() error = %v", err)
 	}
 
@@ -144,7 +144,7 @@ func TestRunStatus_ShowsStrategy(t *testing.T) {
 	writeSettings(t, `{"strategy": "auto-commit", "enabled": true}`)
 
 	var stdout bytes.Buffer
-	if err := runStatus(&stdout, false); err != nil {
+	if err := runStatus(&stdout, false, ""); err != nil {
 		t.Fatalf("runStatus() error = %v", err)
 	}
 
@@ -159,7 +159,7 @@ func TestRunStatus_ShowsManualCommitStrategy(t *testing.T) {
 	writeSettings(t, `{"strategy": "manual-commit", "enabled": false}`)
 
 	var stdout bytes.Buffer
-	if err := runStatus(&stdout, true); err != nil {
+	if err := runStatus(&stdout, true, ""); err != nil {
 		t.Fatalf("runStatus() error = %v", err)
 	}
 
@@ -245,7 +245,7 @@ func TestWriteActiveSessions(t *testing.T) {
 	}
 
 	var buf bytes.Buffer
-	writeActiveSessions(&buf)
+	writeActiveSessions(&buf, "")
 
 	output := buf.String()
 
@@ -339,7 +339,7 @@ func TestWriteActiveSessions_ActiveTimeOmittedWhenClose(t *testing.T) {
 	}
 
 	var buf bytes.Buffer
-	writeActiveSessions(&buf)
+	writeActiveSessions(&buf, "")
 
 	output := buf.String()
 	if strings.Contains(output, "active") {
@@ -351,7 +351,7 @@ func TestWriteActiveSessions_NoSessions(t *testing.T) {
 	setupTestRepo(t)
 
 	var buf bytes.Buffer
-	writeActiveSessions(&buf)
+	writeActiveSessions(&buf, "")
 
 	// Should produce no output when there are no sessions
 	if buf.Len() != 0 {
@@ -380,10 +380,60 @@ func TestWriteActiveSessions_EndedSessionsExcluded(t *testing.T) {
 	}
 
 	var buf bytes.Buffer
-	writeActiveSessions(&buf)
+	writeActiveSessions(&buf, "")
 
 	// Should produce no output when all sessions are ended
 	if buf.Len() != 0 {
 		t.Errorf("Expected empty output with only ended sessions, got: %s", buf.String())
 	}
 }
+
+func TestWriteActiveSessions_TitleAndTagFilter(t *testing.T) {
+	setupTestRepo(t)
+
+	store, err := session.NewStateStore()
+	if err != nil {
+		t.Fatalf("NewStateStore() error = %v", err)
+	}
+
+	for _, state := range []*session.State{
+		{
+			SessionID:    "tagged-session",
+			WorktreePath: "/Users/test/repo",
+			StartedAt:    time.Now().Add(-5 * time.Minute),
+			FirstPrompt:  "fix the login flow",
+			Annotations:  session.Annotations{Title: "Login refactor", Tags: []string{"auth", "spike"}},
+		},
+		{
+			SessionID:    "plain-session",
+			WorktreePath: "/Users/test/repo",
+			StartedAt:    time.Now().Add(-10 * time.Minute),
+			FirstPrompt:  "update docs",
+			Phase:        session.PhasePaused,
+		},
+	} {
+		if err := store.Save(context.Background(), state); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	var buf bytes.Buffer
+	writeActiveSessions(&buf, "")
+	output := buf.String()
+	if !strings.Contains(output, "#auth #spike") {
+		t.Errorf("Expected tags in output, got: %s", output)
+	}
+	if !strings.Contains(output, "Login refactor") || strings.Contains(output, "fix the login flow") {
+		t.Errorf("Expected the title to replace the first prompt, got: %s", output)
+	}
+	if !strings.Contains(output, "started 10m ago, paused") {
+		t.Errorf("Expected the paused session to be marked, got: %s", output)
+	}
+
+	buf.Reset()
+	writeActiveSessions(&buf, "Auth")
+	output = buf.String()
+	if !strings.Contains(output, "tagged-") || strings.Contains(output, "plain-s") {
+		t.Errorf("Expected only the tagged session, got: %s", output)
+	}
+}
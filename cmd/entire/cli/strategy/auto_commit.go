@@ -18,6 +18,7 @@ import (
 	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
 	"github.com/entireio/cli/cmd/entire/cli/logging"
 	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/session"
 	"github.com/entireio/cli/cmd/entire/cli/trailers"
 
 	"github.com/go-git/go-git/v5"
@@ -249,10 +250,13 @@ func (s *AutoCommitStrategy) commitMetadataToMetadataBranch(repo *git.Repository
 	// Combine all file changes into FilesTouched (same as manual-commit)
 	filesTouched := mergeFilesTouched(nil, ctx.ModifiedFiles, ctx.NewFiles, ctx.DeletedFiles)
 
-	// Load TurnID from session state (correlates checkpoints from the same turn)
+	// Load TurnID (correlates checkpoints from the same turn) and the user's
+	// annotations from session state
 	var turnID string
+	var annotations session.Annotations
 	if state, loadErr := LoadSessionState(sessionID); loadErr == nil && state != nil {
 		turnID = state.TurnID
+		annotations = state.Annotations
 	}
 
 	// Write committed checkpoint using the checkpoint store
@@ -274,6 +278,7 @@ func (s *AutoCommitStrategy) commitMetadataToMetadataBranch(repo *git.Repository
 		TokenUsage:                  ctx.TokenUsage,
 		CheckpointsCount:            1,            // Each auto-commit checkpoint = 1
 		FilesTouched:                filesTouched, // Track modified files (same as manual-commit)
+		Annotations:                 annotations,
 	})
 	if err != nil {
 		return plumbing.ZeroHash, fmt.Errorf("failed to write committed checkpoint: %w", err)
@@ -371,6 +376,7 @@ func (s *AutoCommitStrategy) GetRewindPoints(limit int) ([]RewindPoint, error) {
 			Agent:            metadata.Agent,
 			SessionID:        metadata.SessionID,
 			SessionPrompt:    sessionPrompt,
+			Annotations:      metadata.Annotations,
 		})
 
 		return nil
@@ -322,9 +322,10 @@ func DeleteOrphanedCheckpoints(checkpointIDs []string) (deleted []string, failed
 		return nil, nil, fmt.Errorf("failed to store commit: %w", err)
 	}
 
-	// Update branch reference
+	// Update branch reference, failing rather than dropping checkpoints
+	// written since the branch was read
 	newRef := plumbing.NewHashReference(refName, commitHash)
-	if err := repo.Storer.SetReference(newRef); err != nil {
+	if err := repo.Storer.CheckAndSetReference(newRef, ref); err != nil {
 		return nil, nil, fmt.Errorf("failed to update branch: %w", err)
 	}
 
@@ -171,6 +171,7 @@ func ListCheckpoints() ([]CheckpointInfo, error) {
 												info.CreatedAt = sessionMetadata.CreatedAt
 												info.IsTask = sessionMetadata.IsTask
 												info.ToolUseID = sessionMetadata.ToolUseID
+												info.Annotations = sessionMetadata.Annotations
 											}
 										}
 									}
@@ -380,6 +381,7 @@ func ReadCheckpointMetadata(tree *object.Tree, checkpointPath string) (*Checkpoi
 									info.CreatedAt = sessionMetadata.CreatedAt
 									info.IsTask = sessionMetadata.IsTask
 									info.ToolUseID = sessionMetadata.ToolUseID
+									info.Annotations = sessionMetadata.Annotations
 								}
 							}
 						}
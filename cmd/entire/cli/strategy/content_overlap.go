@@ -33,7 +33,10 @@ import (
 // content, using content-aware comparison to detect the "reverted and replaced" scenario.
 //
 // This is used in PostCommit to determine if a session has work in the commit.
-func filesOverlapWithContent(repo *git.Repository, shadowBranchName string, headCommit *object.Commit, filesTouched []string) bool {
+// fileHashes are the session's recorded file hashes (see SessionState.FileHashes).
+// When a new file has a recorded hash, it is compared against that instead of the
+// shadow branch, which concurrent sessions share.
+func filesOverlapWithContent(repo *git.Repository, shadowBranchName string, headCommit *object.Commit, filesTouched []string, fileHashes map[string]string) bool {
 	logCtx := logging.WithComponent(context.Background(), "checkpoint")
 
 	// Build set of filesTouched for quick lookup
@@ -122,7 +125,23 @@ func filesOverlapWithContent(repo *git.Repository, shadowBranchName string, head
 			return true
 		}
 
-		// For new files, check content against shadow branch
+		// For new files, prefer the session's own record of the content it wrote
+		if recorded, ok := fileHashes[filePath]; ok && recorded != "" {
+			if headFile.Hash.String() == recorded {
+				logging.Debug(logCtx, "filesOverlapWithContent: new file matches the session's recorded content",
+					slog.String("file", filePath),
+				)
+				return true
+			}
+			// The record can be stale (e.g. after a rewind), so fall back to the shadow branch
+			logging.Debug(logCtx, "filesOverlapWithContent: new file differs from the session's recorded content",
+				slog.String("file", filePath),
+				slog.String("head_hash", headFile.Hash.String()),
+				slog.String("recorded_hash", recorded),
+			)
+		}
+
+		// Otherwise check content against shadow branch
 		shadowFile, err := shadowTree.File(filePath)
 		if err != nil {
 			// File not in shadow branch - this shouldn't happen but skip it
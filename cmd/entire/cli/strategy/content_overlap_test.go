@@ -57,7 +57,7 @@ func TestFilesOverlapWithContent_ModifiedFile(t *testing.T) {
 
 	// Test: Modified file should count as overlap even with different content
 	shadowBranch := checkpoint.ShadowBranchNameForCommit("abc1234", "e3b0c4")
-	result := filesOverlapWithContent(repo, shadowBranch, commit, []string{"test.txt"})
+	result := filesOverlapWithContent(repo, shadowBranch, commit, []string{"test.txt"}, nil)
 	assert.True(t, result, "Modified file should count as overlap (user edited session's work)")
 }
 
@@ -94,10 +94,48 @@ func TestFilesOverlapWithContent_NewFile_ContentMatch(t *testing.T) {
 
 	// Test: New file with matching content should count as overlap
 	shadowBranch := checkpoint.ShadowBranchNameForCommit("def5678", "e3b0c4")
-	result := filesOverlapWithContent(repo, shadowBranch, commit, []string{"newfile.txt"})
+	result := filesOverlapWithContent(repo, shadowBranch, commit, []string{"newfile.txt"}, nil)
 	assert.True(t, result, "New file with matching content should count as overlap")
 }
 
+// TestFilesOverlapWithContent_NewFile_StaleRecordedHash tests that a new file whose
+// recorded hash no longer matches (e.g. after a rewind) falls back to the shadow branch.
+func TestFilesOverlapWithContent_NewFile_StaleRecordedHash(t *testing.T) {
+	t.Parallel()
+	dir := setupGitRepo(t)
+
+	repo, err := git.PlainOpen(dir)
+	require.NoError(t, err)
+
+	// Create shadow branch with the content the session was rewound to
+	rewoundContent := []byte("content from the rewound checkpoint")
+	createShadowBranchWithContent(t, repo, "aaa1111", "e3b0c4", map[string][]byte{
+		"newfile.txt": rewoundContent,
+	})
+
+	// Commit the rewound content
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "newfile.txt"), rewoundContent, 0o644))
+	wt, err := repo.Worktree()
+	require.NoError(t, err)
+	_, err = wt.Add("newfile.txt")
+	require.NoError(t, err)
+	headCommit, err := wt.Commit("Add new file", &git.CommitOptions{
+		Author: &object.Signature{Name: "Test", Email: "test@test.com", When: time.Now()},
+	})
+	require.NoError(t, err)
+
+	commit, err := repo.CommitObject(headCommit)
+	require.NoError(t, err)
+
+	// The session recorded the content of a later checkpoint
+	staleHashes := map[string]string{
+		"newfile.txt": plumbing.ComputeHash(plumbing.BlobObject, []byte("content from a later checkpoint")).String(),
+	}
+	shadowBranch := checkpoint.ShadowBranchNameForCommit("aaa1111", "e3b0c4")
+	result := filesOverlapWithContent(repo, shadowBranch, commit, []string{"newfile.txt"}, staleHashes)
+	assert.True(t, result, "Stale recorded hash should fall back to the shadow branch content")
+}
+
 // TestFilesOverlapWithContent_NewFile_ContentMismatch tests that a new file with
 // completely different content does NOT count as overlap (reverted & replaced scenario).
 func TestFilesOverlapWithContent_NewFile_ContentMismatch(t *testing.T) {
@@ -131,7 +169,7 @@ func TestFilesOverlapWithContent_NewFile_ContentMismatch(t *testing.T) {
 
 	// Test: New file with different content should NOT count as overlap
 	shadowBranch := checkpoint.ShadowBranchNameForCommit("ghi9012", "e3b0c4")
-	result := filesOverlapWithContent(repo, shadowBranch, commit, []string{"replaced.txt"})
+	result := filesOverlapWithContent(repo, shadowBranch, commit, []string{"replaced.txt"}, nil)
 	assert.False(t, result, "New file with different content should NOT count as overlap (reverted & replaced)")
 }
 
@@ -170,11 +208,11 @@ func TestFilesOverlapWithContent_FileNotInCommit(t *testing.T) {
 
 	// Test: Only fileB in filesTouched, which is not in commit
 	shadowBranch := checkpoint.ShadowBranchNameForCommit("jkl3456", "e3b0c4")
-	result := filesOverlapWithContent(repo, shadowBranch, commit, []string{"fileB.txt"})
+	result := filesOverlapWithContent(repo, shadowBranch, commit, []string{"fileB.txt"}, nil)
 	assert.False(t, result, "File not in commit should not count as overlap")
 
 	// Test: fileA in filesTouched and in commit - should overlap (new file with matching content)
-	result = filesOverlapWithContent(repo, shadowBranch, commit, []string{"fileA.txt"})
+	result = filesOverlapWithContent(repo, shadowBranch, commit, []string{"fileA.txt"}, nil)
 	assert.True(t, result, "File in commit with matching content should count as overlap")
 }
 
@@ -202,7 +240,7 @@ func TestFilesOverlapWithContent_NoShadowBranch(t *testing.T) {
 	require.NoError(t, err)
 
 	// Test: Non-existent shadow branch should fall back to assuming overlap
-	result := filesOverlapWithContent(repo, "entire/nonexistent-e3b0c4", commit, []string{"test.txt"})
+	result := filesOverlapWithContent(repo, "entire/nonexistent-e3b0c4", commit, []string{"test.txt"}, nil)
 	assert.True(t, result, "Missing shadow branch should fall back to assuming overlap")
 }
 
@@ -0,0 +1,179 @@
+package strategy
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/entireio/cli/cmd/entire/cli/logging"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// File ownership for attributing commits to concurrent sessions.
+//
+// Several sessions can be ACTIVE in the same worktree and share one shadow
+// branch, so the shadow tree alone can't tell whose edit a committed file is.
+// Each session therefore records, at every checkpoint, the blob hash of the
+// content it left each touched file in (SessionState.FileHashes). When a
+// commit is made, a session owns a committed file if it edited that file and
+// no other session's recorded content matches the commit better — i.e. if
+// some session's hash equals the committed blob exactly, only those sessions
+// own it.
+
+// recordFileHashes returns hashes updated with the current working-tree content
+// of the files a checkpoint recorded. Paths are relative to worktreeRoot.
+// Files that no longer exist are recorded with an empty hash.
+func recordFileHashes(hashes map[string]string, worktreeRoot string, fileLists ...[]string) map[string]string {
+	for _, files := range fileLists {
+		for _, f := range files {
+			if hashes == nil {
+				hashes = make(map[string]string)
+			}
+			hashes[f] = worktreeFileHash(filepath.Join(worktreeRoot, f))
+		}
+	}
+	return hashes
+}
+
+// worktreeFileHash returns the git blob hash of a file's content, or "" if it
+// can't be read (e.g. it was deleted).
+func worktreeFileHash(path string) string {
+	data, err := os.ReadFile(path) //nolint:gosec // path is a session-touched file inside the worktree
+	if err != nil {
+		return ""
+	}
+	return plumbing.ComputeHash(plumbing.BlobObject, data).String()
+}
+
+// pickFileHashes returns the recorded hashes of the given files only.
+func pickFileHashes(hashes map[string]string, files []string) map[string]string {
+	if len(hashes) == 0 {
+		return nil
+	}
+	picked := make(map[string]string)
+	for _, f := range files {
+		if h, ok := hashes[f]; ok {
+			picked[f] = h
+		}
+	}
+	if len(picked) == 0 {
+		return nil
+	}
+	return picked
+}
+
+// commitOwnership describes what a commit changed and which sessions' recorded
+// content it matches exactly.
+type commitOwnership struct {
+	// committed maps each file changed by the commit to its committed blob
+	// hash ("" if the commit deleted it).
+	committed map[string]string
+
+	// exactOwners maps a committed file to the sessions whose recorded hash
+	// matches the committed content.
+	exactOwners map[string]map[string]bool
+}
+
+// committedBlobHashes returns the committed blob hash of each file changed by
+// the commit ("" if the commit deleted it).
+func committedBlobHashes(commit *object.Commit, committedFiles map[string]struct{}) map[string]string {
+	committed := make(map[string]string, len(committedFiles))
+	tree, err := commit.Tree()
+	for f := range committedFiles {
+		committed[f] = ""
+		if err != nil {
+			continue
+		}
+		if file, fileErr := tree.File(f); fileErr == nil {
+			committed[f] = file.Hash.String()
+		}
+	}
+	return committed
+}
+
+// newCommitOwnership matches the committed content (see committedBlobHashes)
+// against each session's recorded file hashes.
+func newCommitOwnership(committed map[string]string, sessions []*SessionState) *commitOwnership {
+	o := &commitOwnership{
+		committed:   committed,
+		exactOwners: make(map[string]map[string]bool),
+	}
+
+	for _, state := range sessions {
+		for f, recorded := range state.FileHashes {
+			hash, ok := o.committed[f]
+			if !ok || hash != recorded {
+				continue
+			}
+			if o.exactOwners[f] == nil {
+				o.exactOwners[f] = make(map[string]bool)
+			}
+			o.exactOwners[f][state.SessionID] = true
+		}
+	}
+	return o
+}
+
+// ownedFiles returns the committed files among files that the session owns:
+// it edited them, and no other session left exactly the committed content.
+func (o *commitOwnership) ownedFiles(sessionID string, files []string) []string {
+	var owned []string
+	seen := make(map[string]bool, len(files))
+	for _, f := range files {
+		if seen[f] {
+			continue
+		}
+		seen[f] = true
+		if _, ok := o.committed[f]; !ok {
+			continue
+		}
+		if owners := o.exactOwners[f]; len(owners) > 0 && !owners[sessionID] {
+			continue // Another session's edit is what was committed
+		}
+		owned = append(owned, f)
+	}
+	return owned
+}
+
+// sessionEditedFiles returns every file the session is known to have edited
+// since its last condensation: files recorded at checkpoints and, for ACTIVE
+// sessions, files edited in the live transcript (including subagent
+// transcripts) that haven't been checkpointed yet.
+func (s *ManualCommitStrategy) sessionEditedFiles(state *SessionState) []string {
+	files := mergeFilesTouched(nil, state.FilesTouched)
+	for f := range state.FileHashes {
+		files = mergeFilesTouched(files, []string{f})
+	}
+	if state.Phase.IsActive() && state.TranscriptPath != "" {
+		files = mergeFilesTouched(files, s.extractModifiedFilesFromLiveTranscript(state, state.CheckpointTranscriptStart))
+	}
+	return files
+}
+
+// commitOwners returns the sessions whose edits are part of the commit.
+// Returns nil when no session's edits can be found in it (for example, a
+// subagent committed before its transcript was written); callers then fall
+// back to treating every ACTIVE session as related, because the commit's
+// trailer already says it is agent work.
+func (s *ManualCommitStrategy) commitOwners(ownership *commitOwnership, sessions []*SessionState) map[string]bool {
+	logCtx := logging.WithComponent(context.Background(), "checkpoint")
+
+	var owners map[string]bool
+	for _, state := range sessions {
+		owned := ownership.ownedFiles(state.SessionID, s.sessionEditedFiles(state))
+		logging.Debug(logCtx, "post-commit: session ownership",
+			slog.String("session_id", state.SessionID),
+			slog.Any("owned_files", owned),
+		)
+		if len(owned) == 0 {
+			continue
+		}
+		if owners == nil {
+			owners = make(map[string]bool)
+		}
+		owners[state.SessionID] = true
+	}
+	return owners
+}
@@ -0,0 +1,51 @@
+package strategy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordFileHashes(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.go"), []byte("package a\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	hashes := recordFileHashes(nil, dir, []string{"a.go"}, nil, []string{"gone.go"})
+	assert.Equal(t, plumbing.ComputeHash(plumbing.BlobObject, []byte("package a\n")).String(), hashes["a.go"])
+	assert.Contains(t, hashes, "gone.go")
+	assert.Empty(t, hashes["gone.go"], "deleted files are recorded with an empty hash")
+
+	// Later checkpoints overwrite the recorded content
+	if err := os.WriteFile(filepath.Join(dir, "a.go"), []byte("package a // edited\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	hashes = recordFileHashes(hashes, dir, []string{"a.go"})
+	assert.Equal(t, plumbing.ComputeHash(plumbing.BlobObject, []byte("package a // edited\n")).String(), hashes["a.go"])
+
+	assert.Equal(t, map[string]string{"a.go": hashes["a.go"]}, pickFileHashes(hashes, []string{"a.go", "missing.go"}))
+	assert.Nil(t, pickFileHashes(hashes, []string{"missing.go"}))
+}
+
+func TestCommitOwnership_OwnedFiles(t *testing.T) {
+	t.Parallel()
+
+	committedHash := "3333333333333333333333333333333333333333"
+	o := newCommitOwnership(map[string]string{
+		"shared.go": committedHash,
+		"a.go":      "4444444444444444444444444444444444444444",
+	}, []*SessionState{
+		{SessionID: "exact", FileHashes: map[string]string{"shared.go": committedHash}},
+		{SessionID: "stale", FileHashes: map[string]string{"shared.go": "5555555555555555555555555555555555555555"}},
+	})
+
+	assert.Equal(t, []string{"shared.go"}, o.ownedFiles("exact", []string{"shared.go", "shared.go", "untouched.go"}))
+	assert.Empty(t, o.ownedFiles("stale", []string{"shared.go"}), "another session's exact match wins")
+	assert.Equal(t, []string{"a.go"}, o.ownedFiles("stale", []string{"a.go"}), "unclaimed files are owned by name")
+}
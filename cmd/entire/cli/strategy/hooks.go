@@ -19,7 +19,11 @@ const backupSuffix = ".pre-entire"
 const chainComment = "# Chain: run pre-existing hook"
 
 // gitHookNames are the git hooks managed by Entire CLI
-var gitHookNames = []string{"prepare-commit-msg", "commit-msg", "post-commit", "pre-push"}
+var gitHookNames = []string{"prepare-commit-msg", "commit-msg", "post-commit", "post-rewrite", "pre-push"}
+
+// stdinHookVar is the shell variable hooks that read stdin buffer it in, so a
+// chained pre-existing hook receives the same input.
+const stdinHookVar = "_entire_stdin"
 
 // ManagedGitHookNames returns the list of git hooks managed by Entire CLI.
 // This is useful for tests that need to manipulate hooks.
@@ -121,6 +125,16 @@ func buildHookSpecs(cmdPrefix string) []hookSpec {
 # Post-commit hook: condense session data if commit has Entire-Checkpoint trailer
 %s hooks git post-commit 2>/dev/null || true
 `, entireHookMarker, cmdPrefix),
+		},
+		{
+			name: "post-rewrite",
+			content: fmt.Sprintf(`#!/bin/sh
+# %s
+# Post-rewrite hook: link rewritten commits (amend, rebase) to their checkpoints
+# $1 is "amend" or "rebase"; "<old-sha> <new-sha>" pairs arrive on stdin
+%s="$(cat)"
+printf '%%s\n' "$%s" | %s hooks git post-rewrite "$1" 2>/dev/null || true
+`, entireHookMarker, stdinHookVar, stdinHookVar, cmdPrefix),
 		},
 		{
 			name: "pre-push",
@@ -195,7 +209,7 @@ func InstallGitHook(silent bool) (int, error) {
 	}
 
 	if !silent {
-		fmt.Println("✓ Installed git hooks (prepare-commit-msg, commit-msg, post-commit, pre-push)")
+		fmt.Println("✓ Installed git hooks (prepare-commit-msg, commit-msg, post-commit, post-rewrite, pre-push)")
 		fmt.Println("  Hooks delegate to the current strategy at runtime")
 	}
 
@@ -270,12 +284,17 @@ func RemoveGitHook() (int, error) {
 // generateChainedContent appends a chain call to the base hook content,
 // so the pre-existing hook (backed up to .pre-entire) is called after our hook.
 func generateChainedContent(baseContent, hookName string) string {
+	// Hooks that consumed stdin replay the buffered copy to the chained hook
+	var stdinPipe string
+	if strings.Contains(baseContent, stdinHookVar+"=") {
+		stdinPipe = fmt.Sprintf(`printf '%%s\n' "$%s" | `, stdinHookVar)
+	}
 	return baseContent + fmt.Sprintf(`%s
 _entire_hook_dir="$(dirname "$0")"
 if [ -x "$_entire_hook_dir/%s%s" ]; then
-    "$_entire_hook_dir/%s%s" "$@"
+    %s"$_entire_hook_dir/%s%s" "$@"
 fi
-`, chainComment, hookName, backupSuffix, hookName, backupSuffix)
+`, chainComment, hookName, backupSuffix, stdinPipe, hookName, backupSuffix)
 }
 
 // isLocalDev reads the local_dev setting from .entire/settings.json
@@ -764,6 +764,46 @@ func TestGenerateChainedContent(t *testing.T) {
 	}
 }
 
+func TestGenerateChainedContent_PostRewriteReplaysStdin(t *testing.T) {
+	t.Parallel()
+
+	var base string
+	for _, spec := range buildHookSpecs("true") {
+		if spec.name == "post-rewrite" {
+			base = spec.content
+		}
+	}
+	if base == "" {
+		t.Fatal("post-rewrite hook spec not found")
+	}
+
+	// The chained hook must see the same old/new pairs Entire consumed.
+	dir := t.TempDir()
+	hookPath := filepath.Join(dir, "post-rewrite")
+	outPath := filepath.Join(dir, "chained-stdin")
+	if err := os.WriteFile(hookPath, []byte(generateChainedContent(base, "post-rewrite")), 0o755); err != nil {
+		t.Fatalf("failed to write hook: %v", err)
+	}
+	backup := "#!/bin/sh\ncat > \"" + outPath + "\"\n"
+	if err := os.WriteFile(hookPath+backupSuffix, []byte(backup), 0o755); err != nil {
+		t.Fatalf("failed to write backup hook: %v", err)
+	}
+
+	cmd := exec.CommandContext(context.Background(), "sh", hookPath, "rebase")
+	cmd.Stdin = strings.NewReader("aaa bbb\nccc ddd\n")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("hook failed: %v\n%s", err, out)
+	}
+
+	got, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("chained hook did not run: %v", err)
+	}
+	if string(got) != "aaa bbb\nccc ddd\n" {
+		t.Errorf("chained hook stdin = %q, want the original mappings", got)
+	}
+}
+
 func TestInstallGitHook_InstallRemoveReinstall(t *testing.T) {
 	_, hooksDir := initHooksTestRepo(t)
 
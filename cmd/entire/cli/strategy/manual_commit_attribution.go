@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/redact"
 	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/sergi/go-diff/diffmatchpatch"
 )
@@ -193,6 +194,7 @@ func CalculateAttributionWithAccumulated(
 	var totalAgentAndUserWork int
 	var postCheckpointUserAdded, postCheckpointUserRemoved int
 	postCheckpointUserRemovedPerFile := make(map[string]int)
+	files := make(map[string]checkpoint.FileAttribution)
 
 	for _, filePath := range filesTouched {
 		baseContent := getFileContent(baseTree, filePath)
@@ -212,6 +214,16 @@ func CalculateAttributionWithAccumulated(
 		if postUserRemoved > 0 {
 			postCheckpointUserRemovedPerFile[filePath] = postUserRemoved
 		}
+
+		// Per-file breakdown. Accumulated removals aren't tracked per file, so only
+		// post-checkpoint removals count against this file.
+		userAddedToFile := accumulatedUserAddedPerFile[filePath]
+		addFileAttribution(files, filePath,
+			max(0, workAdded-userAddedToFile),
+			userAddedToFile+postUserAdded,
+			postUserRemoved,
+			min(postUserRemoved, userAddedToFile),
+		)
 	}
 
 	// Calculate total user edits to non-agent files (files not in filesTouched)
@@ -225,8 +237,11 @@ func CalculateAttributionWithAccumulated(
 
 		baseContent := getFileContent(baseTree, filePath)
 		headContent := getFileContent(headTree, filePath)
-		_, userAdded, _ := diffLines(baseContent, headContent)
+		_, userAdded, userRemoved := diffLines(baseContent, headContent)
 		allUserEditsToNonAgentFiles += userAdded
+
+		// The agent never touched this file, so every change belongs to the user.
+		addFileAttribution(files, filePath, 0, userAdded, userRemoved, 0)
 	}
 
 	// Separate accumulated edits by file type using per-file tracking data.
@@ -302,7 +317,130 @@ func CalculateAttributionWithAccumulated(
 		HumanRemoved:    pureUserRemoved,
 		TotalCommitted:  totalCommitted,
 		AgentPercentage: agentPercentage,
+		Files:           files,
+		Prompts: calculatePromptBreakdown(
+			promptAttributions,
+			totalAgentAdded,
+			postCheckpointUserAdded+postToNonAgentFiles,
+			postCheckpointUserRemoved,
+		),
+	}
+}
+
+// calculatePromptBreakdown splits agent work across the prompts of a session.
+//
+// Each PromptAttribution records, at the start of its prompt, the cumulative
+// base → last checkpoint work and the user edits made since that checkpoint.
+// The agent lines for prompt k are the growth in cumulative work between the
+// start of prompt k and the start of prompt k+1, minus the user edits captured
+// at the start of prompt k (which are part of checkpoint k's snapshot). The
+// user edits captured at the start of prompt k+1 are the human edits made after
+// prompt k.
+//
+// The last prompt has no successor, so it takes whatever agent work remains
+// of totalAgentAdded and the post-checkpoint edits made before the commit.
+func calculatePromptBreakdown(
+	promptAttributions []PromptAttribution,
+	totalAgentAdded int,
+	finalUserAdded int,
+	finalUserRemoved int,
+) []checkpoint.PromptAttribution {
+	if len(promptAttributions) == 0 {
+		return nil
+	}
+
+	prompts := make([]checkpoint.PromptAttribution, len(promptAttributions))
+	var agentSoFar int
+	for i, pa := range promptAttributions {
+		prompts[i] = checkpoint.PromptAttribution{
+			Checkpoint: pa.CheckpointNumber,
+			// Session state saved by older versions holds the prompt unredacted.
+			Prompt: redact.String(pa.Prompt),
+		}
+		if i == len(promptAttributions)-1 {
+			prompts[i].AgentLines = max(0, totalAgentAdded-agentSoFar)
+			prompts[i].HumanAdded = finalUserAdded
+			prompts[i].HumanRemoved = finalUserRemoved
+			break
+		}
+
+		next := promptAttributions[i+1]
+		prompts[i].AgentLines = max(0, next.AgentLinesAdded-pa.AgentLinesAdded-pa.UserLinesAdded)
+		prompts[i].HumanAdded = next.UserLinesAdded
+		prompts[i].HumanRemoved = next.UserLinesRemoved
+		agentSoFar += prompts[i].AgentLines
+	}
+	return prompts
+}
+
+// addFileAttribution records the attribution for a single file, applying the same
+// modification estimate as the commit totals. Files with no line changes are skipped.
+func addFileAttribution(files map[string]checkpoint.FileAttribution, filePath string, agentAdded, userAdded, userRemoved, userSelfModified int) {
+	if agentAdded == 0 && userAdded == 0 && userRemoved == 0 {
+		return
 	}
+	modified := min(userAdded, userRemoved)
+	modifiedAgent := max(0, modified-userSelfModified)
+	pureRemoved := userRemoved - modified
+	files[filePath] = checkpoint.FileAttribution{
+		AgentLines:    max(0, agentAdded-pureRemoved-modifiedAgent),
+		HumanAdded:    userAdded - modified,
+		HumanModified: modified,
+		HumanRemoved:  pureRemoved,
+	}
+}
+
+// CalculateSubagentAttribution splits per-file agent lines between the subagents
+// that modified each file. The main agent may also have edited those files, but
+// transcripts don't tell us which lines, so a file a subagent modified counts
+// toward that subagent. A file modified by several subagents is split evenly
+// between them, with any remainder going to the first. Subagents whose files
+// contributed no agent lines are omitted.
+func CalculateSubagentAttribution(files map[string]checkpoint.FileAttribution, subagents []SubagentActivity) []checkpoint.SubagentAttribution {
+	if len(files) == 0 || len(subagents) == 0 {
+		return nil
+	}
+
+	owners := make(map[string][]int)
+	for i, sa := range subagents {
+		for _, path := range sa.Files {
+			if !slices.Contains(owners[path], i) {
+				owners[path] = append(owners[path], i)
+			}
+		}
+	}
+
+	result := make([]checkpoint.SubagentAttribution, len(subagents))
+	for i, sa := range subagents {
+		result[i] = checkpoint.SubagentAttribution{AgentID: sa.AgentID, SubagentType: sa.SubagentType}
+	}
+
+	paths := make([]string, 0, len(owners))
+	for path := range owners {
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+
+	for _, path := range paths {
+		lines := files[path].AgentLines
+		if lines == 0 {
+			continue
+		}
+		idx := owners[path]
+		share := lines / len(idx)
+		for n, i := range idx {
+			got := share
+			if n == 0 {
+				got += lines % len(idx)
+			}
+			result[i].AgentLines += got
+			result[i].Files = append(result[i].Files, path)
+		}
+	}
+
+	return slices.DeleteFunc(result, func(sa checkpoint.SubagentAttribution) bool {
+		return sa.AgentLines == 0
+	})
 }
 
 // estimateUserSelfModifications estimates how many removed lines were the user's own additions.
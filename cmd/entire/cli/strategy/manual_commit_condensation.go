@@ -1,12 +1,15 @@
 package strategy
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
+	"math"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/entireio/cli/cmd/entire/cli/agent"
@@ -142,6 +145,9 @@ func (s *ManualCommitStrategy) CondenseSession(repo *git.Repository, checkpointI
 		}
 	}
 
+	// Turns made while recording was paused don't belong in the checkpoint
+	transcriptStart := excludePausedTurns(sessionData, state)
+
 	// For 1:1 checkpoint model: filter files_touched to only include files actually
 	// committed in this specific commit. This ensures each checkpoint represents
 	// exactly the files in that commit, not all files mentioned in the transcript.
@@ -184,36 +190,6 @@ func (s *ManualCommitStrategy) CondenseSession(repo *git.Repository, checkpointI
 	// Get current branch name
 	branchName := GetCurrentBranchName(repo)
 
-	// Generate summary if enabled
-	var summary *cpkg.Summary
-	if settings.IsSummarizeEnabled() && len(sessionData.Transcript) > 0 {
-		logCtx := logging.WithComponent(context.Background(), "attribution")
-		summarizeCtx := logging.WithComponent(logCtx, "summarize")
-
-		// Scope transcript to this checkpoint's portion.
-		// For Claude Code (JSONL), CheckpointTranscriptStart is a line offset.
-		// For Gemini (JSON), CheckpointTranscriptStart is a message index.
-		var scopedTranscript []byte
-		if state.AgentType == agent.AgentTypeGemini {
-			scopedTranscript = geminicli.SliceFromMessage(sessionData.Transcript, state.CheckpointTranscriptStart)
-		} else {
-			scopedTranscript = transcript.SliceFromLine(sessionData.Transcript, state.CheckpointTranscriptStart)
-		}
-		if len(scopedTranscript) > 0 {
-			var err error
-			summary, err = summarize.GenerateFromTranscript(summarizeCtx, scopedTranscript, sessionData.FilesTouched, state.AgentType, nil)
-			if err != nil {
-				logging.Warn(summarizeCtx, "summary generation failed",
-					slog.String("session_id", state.SessionID),
-					slog.String("error", err.Error()))
-				// Continue without summary - non-blocking
-			} else {
-				logging.Info(summarizeCtx, "summary generated",
-					slog.String("session_id", state.SessionID))
-			}
-		}
-	}
-
 	// Write checkpoint metadata using the checkpoint store
 	if err := store.WriteCommitted(context.Background(), cpkg.WriteCommittedOptions{
 		CheckpointID:                checkpointID,
@@ -231,15 +207,20 @@ func (s *ManualCommitStrategy) CondenseSession(repo *git.Repository, checkpointI
 		Agent:                       state.AgentType,
 		TurnID:                      state.TurnID,
 		TranscriptIdentifierAtStart: state.TranscriptIdentifierAtStart,
-		CheckpointTranscriptStart:   state.CheckpointTranscriptStart,
+		CheckpointTranscriptStart:   transcriptStart,
 		TokenUsage:                  sessionData.TokenUsage,
 		InitialAttribution:          attribution,
-		Summary:                     summary,
 		SessionTranscriptPath:       homeRelativePath(state.TranscriptPath),
+		Annotations:                 state.Annotations,
 	}); err != nil {
 		return nil, fmt.Errorf("failed to write checkpoint metadata: %w", err)
 	}
 
+	// Summaries are generated in the background so the commit doesn't wait on an LLM call
+	if settings.IsSummarizeEnabled() && len(sessionData.Transcript) > 0 {
+		queueSummary(checkpointID, state.SessionID)
+	}
+
 	return &CondenseResult{
 		CheckpointID:         checkpointID,
 		SessionID:            state.SessionID,
@@ -324,6 +305,7 @@ func calculateSessionAttributions(repo *git.Repository, shadowRef *plumbing.Refe
 						)
 
 						if attribution != nil {
+							attribution.Subagents = CalculateSubagentAttribution(attribution.Files, state.Subagents)
 							logging.Info(logCtx, "attribution calculated",
 								slog.Int("agent_lines", attribution.AgentLines),
 								slog.Int("human_added", attribution.HumanAdded),
@@ -448,6 +430,89 @@ func (s *ManualCommitStrategy) extractSessionDataFromLiveTranscript(state *Sessi
 	return data, nil
 }
 
+// excludePausedTurns removes the turns made while recording was paused from the
+// condensed transcript and recomputes the prompts, context, and token usage
+// from what is left. FullTranscriptLines still counts the live transcript, since
+// the next checkpoint's offset refers to it. Returns the checkpoint's transcript
+// start within the trimmed transcript.
+func excludePausedTurns(data *ExtractedSessionData, state *SessionState) int {
+	start := state.CheckpointTranscriptStart
+	ranges := pausedTranscriptRanges(state)
+	if len(ranges) == 0 || len(data.Transcript) == 0 {
+		return start
+	}
+
+	trimmed, removedBeforeStart := removeTranscriptRanges(state.AgentType, data.Transcript, ranges, start)
+	start -= removedBeforeStart
+	data.Transcript = trimmed
+	data.Prompts = extractUserPrompts(state.AgentType, string(trimmed))
+	data.Context = generateContextFromPrompts(data.Prompts)
+	data.TokenUsage = calculateTokenUsage(state.AgentType, trimmed, start)
+	return start
+}
+
+// pausedTranscriptRanges returns the parts of the transcript written while
+// recording was paused. A current pause runs to the end of the transcript.
+func pausedTranscriptRanges(state *SessionState) []session.TranscriptRange {
+	ranges := state.PausedTranscriptRanges
+	if state.Phase.IsPaused() {
+		ranges = append(slices.Clip(ranges), session.TranscriptRange{Start: state.PausedTranscriptStart, End: math.MaxInt})
+	}
+	return ranges
+}
+
+// removeTranscriptRanges drops the lines (JSONL) or messages (Gemini) within
+// ranges from a transcript. It also returns how many of the dropped items came
+// before offset, so offsets into the transcript can be shifted to match.
+// A Gemini transcript that can't be parsed is returned unchanged.
+func removeTranscriptRanges(agentType agent.AgentType, data []byte, ranges []session.TranscriptRange, offset int) ([]byte, int) {
+	inRange := func(i int) bool {
+		return slices.ContainsFunc(ranges, func(r session.TranscriptRange) bool {
+			return i >= r.Start && i < r.End
+		})
+	}
+
+	removedBefore := 0
+	if agentType == agent.AgentTypeGemini {
+		var doc map[string]json.RawMessage
+		var messages []json.RawMessage
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return data, 0
+		}
+		if err := json.Unmarshal(doc["messages"], &messages); err != nil {
+			return data, 0
+		}
+		kept := make([]json.RawMessage, 0, len(messages))
+		for i, msg := range messages {
+			if !inRange(i) {
+				kept = append(kept, msg)
+			} else if i < offset {
+				removedBefore++
+			}
+		}
+		encoded, err := json.Marshal(kept)
+		if err != nil {
+			return data, 0
+		}
+		doc["messages"] = encoded
+		out, err := json.Marshal(doc)
+		if err != nil {
+			return data, 0
+		}
+		return out, removedBefore
+	}
+
+	var out []byte
+	for i, line := range bytes.SplitAfter(data, []byte("\n")) {
+		if !inRange(i) {
+			out = append(out, line...)
+		} else if i < offset {
+			removedBefore++
+		}
+	}
+	return out, removedBefore
+}
+
 // countTranscriptItems counts lines (JSONL) or messages (JSON) in a transcript.
 // For Claude Code and JSONL-based agents, this counts lines.
 // For Gemini CLI and JSON-based agents, this counts messages.
@@ -693,6 +758,8 @@ func (s *ManualCommitStrategy) CondenseSessionByID(sessionID string) error {
 	state.AttributionBaseCommit = state.BaseCommit
 	state.PromptAttributions = nil
 	state.PendingPromptAttribution = nil
+	state.Subagents = nil
+	state.FileHashes = nil
 
 	if err := s.saveSessionState(state); err != nil {
 		return fmt.Errorf("failed to save session state: %w", err)
@@ -740,3 +807,25 @@ func (s *ManualCommitStrategy) cleanupShadowBranchIfUnused(_ *git.Repository, sh
 	}
 	return nil
 }
+
+// queueSummary queues summary generation for a condensed session and starts a
+// background worker to process it. Failures are logged and never block the commit.
+func queueSummary(checkpointID id.CheckpointID, sessionID string) {
+	logCtx := logging.WithComponent(context.Background(), "summarize")
+
+	queue, err := summarize.NewQueue()
+	if err == nil {
+		err = queue.Enqueue(logCtx, &summarize.Job{CheckpointID: checkpointID, SessionID: sessionID})
+	}
+	if err != nil {
+		logging.Warn(logCtx, "failed to queue summary",
+			slog.String("session_id", sessionID),
+			slog.String("error", err.Error()))
+		return
+	}
+
+	logging.Info(logCtx, "summary queued",
+		slog.String("session_id", sessionID),
+		slog.String("checkpoint_id", checkpointID.String()))
+	summarize.SpawnWorker()
+}
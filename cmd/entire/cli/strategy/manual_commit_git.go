@@ -124,6 +124,9 @@ func (s *ManualCommitStrategy) SaveChanges(ctx SaveContext) error {
 
 	// Track touched files (modified, new, and deleted)
 	state.FilesTouched = mergeFilesTouched(state.FilesTouched, ctx.ModifiedFiles, ctx.NewFiles, ctx.DeletedFiles)
+	if worktreePath, wpErr := GetWorktreePath(); wpErr == nil {
+		state.FileHashes = recordFileHashes(state.FileHashes, worktreePath, ctx.ModifiedFiles, ctx.NewFiles, ctx.DeletedFiles)
+	}
 
 	// On first checkpoint, record the transcript identifier for this session
 	if state.StepCount == 1 {
@@ -251,6 +254,12 @@ func (s *ManualCommitStrategy) SaveTaskCheckpoint(ctx TaskCheckpointContext) err
 
 	// Track touched files (modified, new, and deleted)
 	state.FilesTouched = mergeFilesTouched(state.FilesTouched, ctx.ModifiedFiles, ctx.NewFiles, ctx.DeletedFiles)
+	if worktreePath, wpErr := GetWorktreePath(); wpErr == nil {
+		state.FileHashes = recordFileHashes(state.FileHashes, worktreePath, ctx.ModifiedFiles, ctx.NewFiles, ctx.DeletedFiles)
+	}
+
+	// Record which files this subagent wrote so attribution can be split by subagent
+	state.Subagents = recordSubagentActivity(state.Subagents, ctx)
 
 	// Save updated state
 	if err := s.saveSessionState(state); err != nil {
@@ -289,6 +298,33 @@ func (s *ManualCommitStrategy) SaveTaskCheckpoint(ctx TaskCheckpointContext) err
 	return nil
 }
 
+// recordSubagentActivity adds the task's modified and new files to the subagent's
+// activity, creating the entry on its first checkpoint. Deleted files are skipped
+// since they contribute no lines.
+func recordSubagentActivity(activities []SubagentActivity, ctx TaskCheckpointContext) []SubagentActivity {
+	if ctx.ToolUseID == "" || len(ctx.ModifiedFiles)+len(ctx.NewFiles) == 0 {
+		return activities
+	}
+	for i := range activities {
+		if activities[i].ToolUseID == ctx.ToolUseID {
+			activities[i].Files = mergeFilesTouched(activities[i].Files, ctx.ModifiedFiles, ctx.NewFiles)
+			if activities[i].AgentID == "" {
+				activities[i].AgentID = ctx.AgentID
+			}
+			if activities[i].SubagentType == "" {
+				activities[i].SubagentType = ctx.SubagentType
+			}
+			return activities
+		}
+	}
+	return append(activities, SubagentActivity{
+		ToolUseID:    ctx.ToolUseID,
+		AgentID:      ctx.AgentID,
+		SubagentType: ctx.SubagentType,
+		Files:        mergeFilesTouched(nil, ctx.ModifiedFiles, ctx.NewFiles),
+	})
+}
+
 // mergeFilesTouched merges multiple file lists into existing touched files, deduplicating.
 func mergeFilesTouched(existing []string, fileLists ...[]string) []string {
 	seen := make(map[string]bool)
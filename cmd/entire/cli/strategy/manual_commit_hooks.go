@@ -228,10 +228,16 @@ func (s *ManualCommitStrategy) PrepareCommitMsg(commitMsgFile string, source str
 		return nil
 	}
 
-	// Skip for merge and squash sources
+	// A squash merge drops the squashed commits' trailers into the message body;
+	// promote them to real trailers so the squash commit stays linked.
+	if source == "squash" {
+		return carrySquashTrailers(logCtx, commitMsgFile)
+	}
+
+	// Skip for merge sources
 	// These are auto-generated messages - not from Claude sessions
 	switch source {
-	case "merge", "squash":
+	case "merge":
 		logging.Debug(logCtx, "prepare-commit-msg: skipped for source",
 			slog.String("strategy", "manual-commit"),
 			slog.String("source", source),
@@ -528,6 +534,12 @@ func (s *ManualCommitStrategy) PostCommit() error {
 	newHead := head.Hash().String()
 	committedFileSet := filesChangedInCommit(commit)
 
+	// Attribute the commit by file ownership: which sessions' recorded edits
+	// it contains. Computed up front because a file is only owned by a session
+	// if no other session's recorded content matches the commit exactly.
+	ownership := newCommitOwnership(committedBlobHashes(commit, committedFileSet), sessions)
+	owners := s.commitOwners(ownership, sessions)
+
 	for _, state := range sessions {
 		shadowBranchName := getShadowBranchNameForCommit(state.BaseCommit, state.WorktreeID)
 
@@ -540,12 +552,20 @@ func (s *ManualCommitStrategy) PostCommit() error {
 		// The trailer is only added when either:
 		//   - No TTY (agent/subagent committing) — added unconditionally
 		//   - TTY (human committing) — added after content detection confirmed agent work
-		// In both cases, PrepareCommitMsg already validated this commit. We trust
-		// that decision here. Transcript-based re-validation is unreliable because
-		// subagent transcripts may not be available yet (subagent still running).
+		// In both cases, PrepareCommitMsg already validated this commit. With
+		// concurrent sessions, only those whose edits (checkpointed, or in the
+		// live main and subagent transcripts) are part of the commit are
+		// condensed. If no session's edits can be found — a subagent's
+		// transcript may not be written yet — every ACTIVE session is condensed
+		// rather than risk losing the one that made the commit.
 		var hasNew bool
 		if state.Phase.IsActive() {
-			hasNew = true
+			hasNew = owners == nil || owners[state.SessionID]
+			if !hasNew {
+				logging.Debug(logCtx, "post-commit: active session does not own committed changes, skipping",
+					slog.String("session_id", state.SessionID),
+				)
+			}
 		} else {
 			var contentErr error
 			hasNew, contentErr = s.sessionHasNewContent(repo, state)
@@ -568,6 +588,7 @@ func (s *ManualCommitStrategy) PostCommit() error {
 		// because no SaveChanges/Stop has been called yet. Extract files from transcript.
 		filesTouchedBefore := make([]string, len(state.FilesTouched))
 		copy(filesTouchedBefore, state.FilesTouched)
+		fileHashesBefore := state.FileHashes
 		if len(filesTouchedBefore) == 0 && state.Phase.IsActive() && state.TranscriptPath != "" {
 			filesTouchedBefore = s.extractFilesFromLiveTranscript(state)
 		}
@@ -590,14 +611,15 @@ func (s *ManualCommitStrategy) PostCommit() error {
 		for _, action := range remaining {
 			switch action {
 			case session.ActionCondense:
-				// For ACTIVE sessions, any commit during the turn is session-related.
+				// For ACTIVE sessions, ownership was already checked above.
 				// For IDLE/ENDED sessions (e.g., carry-forward), also require that the
 				// committed files overlap with the session's remaining files AND have
 				// matching content — otherwise an unrelated commit (or a commit with
 				// completely replaced content) would incorrectly get this session's checkpoint.
 				shouldCondense := hasNew
 				if shouldCondense && !state.Phase.IsActive() {
-					shouldCondense = filesOverlapWithContent(repo, shadowBranchName, commit, state.FilesTouched)
+					shouldCondense = owners[state.SessionID] &&
+						filesOverlapWithContent(repo, shadowBranchName, commit, state.FilesTouched, state.FileHashes)
 				}
 				if shouldCondense {
 					condensed = s.condenseAndUpdateState(logCtx, repo, checkpointID, state, head, shadowBranchName, shadowBranchesToDelete, committedFileSet)
@@ -611,7 +633,7 @@ func (s *ManualCommitStrategy) PostCommit() error {
 				// The state machine already gates this action on HasFilesTouched,
 				// but hasNew is an additional content-level check (transcript has
 				// new content beyond what was previously condensed).
-				if len(state.FilesTouched) > 0 && hasNew {
+				if len(state.FilesTouched) > 0 && hasNew && owners[state.SessionID] {
 					condensed = s.condenseAndUpdateState(logCtx, repo, checkpointID, state, head, shadowBranchName, shadowBranchesToDelete, committedFileSet)
 					// On failure, BaseCommit is preserved (same as ActionCondense).
 				} else {
@@ -656,6 +678,7 @@ func (s *ManualCommitStrategy) PostCommit() error {
 			)
 			if len(remainingFiles) > 0 {
 				s.carryForwardToNewShadowBranch(logCtx, repo, state, remainingFiles)
+				state.FileHashes = pickFileHashes(fileHashesBefore, remainingFiles)
 			}
 		}
 
@@ -730,7 +753,9 @@ func (s *ManualCommitStrategy) condenseAndUpdateState(
 	// Clear attribution tracking — condensation already used these values
 	state.PromptAttributions = nil
 	state.PendingPromptAttribution = nil
+	state.Subagents = nil
 	state.FilesTouched = nil
+	state.FileHashes = nil
 
 	// Save checkpoint ID so subsequent commits can reuse it (e.g., amend restores trailer)
 	state.LastCheckpointID = checkpointID
@@ -1184,6 +1209,71 @@ func addCheckpointTrailer(message string, checkpointID id.CheckpointID) string {
 	return strings.TrimRight(message, "\n") + "\n\n" + trailer + "\n"
 }
 
+// carrySquashTrailers rewrites a squash-merge commit message so every checkpoint
+// referenced by the squashed commits appears as an Entire-Checkpoint trailer.
+// git indents the squashed messages, so their trailers aren't trailers anymore.
+func carrySquashTrailers(logCtx context.Context, commitMsgFile string) error {
+	content, err := os.ReadFile(commitMsgFile) //nolint:gosec // commitMsgFile is provided by git hook
+	if err != nil {
+		return nil //nolint:nilerr // Hook must be silent on failure
+	}
+
+	message := string(content)
+	cpIDs := trailers.ParseAllCheckpoints(message)
+	if len(cpIDs) == 0 {
+		return nil
+	}
+
+	updated := addSquashTrailers(message, cpIDs)
+	if updated == message {
+		return nil
+	}
+	if err := os.WriteFile(commitMsgFile, []byte(updated), 0o600); err != nil {
+		return nil //nolint:nilerr // Hook must be silent on failure
+	}
+
+	logging.Info(logCtx, "prepare-commit-msg: carried checkpoint trailers into squash commit",
+		slog.String("strategy", "manual-commit"),
+		slog.Int("checkpoints", len(cpIDs)),
+	)
+	return nil
+}
+
+// addSquashTrailers appends a trailer paragraph for each checkpoint ID that isn't
+// already an unindented Entire-Checkpoint trailer. The paragraph goes above the
+// git comment block so it survives comment stripping.
+func addSquashTrailers(message string, cpIDs []id.CheckpointID) string {
+	lines := strings.Split(message, "\n")
+
+	existing := make(map[string]bool)
+	commentStart := len(lines)
+	for i, line := range lines {
+		if strings.HasPrefix(line, "#") {
+			commentStart = i
+			break
+		}
+		existing[strings.TrimRight(line, " \t")] = true
+	}
+
+	var trailerLines []string
+	for _, cpID := range cpIDs {
+		trailer := trailers.CheckpointTrailerKey + ": " + cpID.String()
+		if !existing[trailer] {
+			trailerLines = append(trailerLines, trailer)
+		}
+	}
+	if len(trailerLines) == 0 {
+		return message
+	}
+
+	body := strings.TrimRight(strings.Join(lines[:commentStart], "\n"), "\n")
+	result := body + "\n\n" + strings.Join(trailerLines, "\n") + "\n"
+	if commentStart < len(lines) {
+		result += "\n" + strings.Join(lines[commentStart:], "\n")
+	}
+	return result
+}
+
 // addCheckpointTrailerWithComment adds the Entire-Checkpoint trailer with an explanatory comment.
 // The trailer is placed above the git comment block but below the user's message area,
 // with a comment explaining that the user can remove it if they don't want to link the commit
@@ -1292,6 +1382,7 @@ func (s *ManualCommitStrategy) InitializeSession(sessionID string, agentType age
 		// user edits made before the first prompt. The inner CalculatePromptAttribution handles
 		// nil lastCheckpointTree by falling back to baseTree.
 		promptAttr := s.calculatePromptAttributionAtStart(repo, state)
+		promptAttr.Prompt = promptForAttribution(userPrompt)
 		state.PendingPromptAttribution = &promptAttr
 
 		// Check if HEAD has moved (user pulled/rebased or committed)
@@ -1320,6 +1411,7 @@ func (s *ManualCommitStrategy) InitializeSession(sessionID string, agentType age
 	// Calculate attribution for pre-prompt edits
 	// This captures any user edits made before the first prompt
 	promptAttr := s.calculatePromptAttributionAtStart(repo, state)
+	promptAttr.Prompt = promptForAttribution(userPrompt)
 	state.PendingPromptAttribution = &promptAttr
 	if err = s.saveSessionState(state); err != nil {
 		return fmt.Errorf("failed to save attribution: %w", err)
@@ -1557,6 +1649,16 @@ func (s *ManualCommitStrategy) finalizeAllTurnCheckpoints(state *SessionState) i
 		return 1 // Count as error - all checkpoints will be skipped
 	}
 
+	// The next checkpoint starts at the end of the live transcript
+	fullTranscriptLines := countTranscriptItems(state.AgentType, string(fullTranscript))
+
+	// Leave out turns made while recording was paused, as condensation does.
+	// The checkpoint's transcript start only moves for ranges before it, and
+	// condensation already accounted for those.
+	if ranges := pausedTranscriptRanges(state); len(ranges) > 0 {
+		fullTranscript, _ = removeTranscriptRanges(state.AgentType, fullTranscript, ranges, 0)
+	}
+
 	// Extract prompts and context from the full transcript
 	prompts := extractUserPrompts(state.AgentType, string(fullTranscript))
 	contextBytes := generateContextFromPrompts(prompts)
@@ -1625,7 +1727,6 @@ func (s *ManualCommitStrategy) finalizeAllTurnCheckpoints(state *SessionState) i
 	}
 
 	// Update transcript start and clear turn checkpoint IDs
-	fullTranscriptLines := countTranscriptItems(state.AgentType, string(fullTranscript))
 	state.CheckpointTranscriptStart = fullTranscriptLines
 	state.TurnCheckpointIDs = nil
 
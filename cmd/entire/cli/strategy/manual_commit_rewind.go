@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -79,6 +81,7 @@ func (s *ManualCommitStrategy) GetRewindPoints(limit int) ([]RewindPoint, error)
 				ToolUseID:        cp.ToolUseID,
 				SessionID:        cp.SessionID,
 				SessionPrompt:    sessionPrompt,
+				Annotations:      state.Annotations,
 				Agent:            state.AgentType,
 			})
 		}
@@ -230,6 +233,7 @@ func (s *ManualCommitStrategy) GetLogsOnlyRewindPoints(limit int) ([]RewindPoint
 			SessionCount:   cpInfo.SessionCount,
 			SessionIDs:     cpInfo.SessionIDs,
 			SessionPrompts: sessionPrompts,
+			Annotations:    cpInfo.Annotations,
 		})
 
 		return nil
@@ -276,10 +280,15 @@ func (s *ManualCommitStrategy) Rewind(point RewindPoint) error {
 
 	// Load session state to get untracked files that existed at session start
 	sessionID, hasSessionTrailer := trailers.ParseSession(commit.Message)
+	var state *SessionState
 	var preservedUntrackedFiles map[string]bool
 	if hasSessionTrailer {
-		state, stateErr := s.loadSessionState(sessionID)
-		if stateErr == nil && state != nil && len(state.UntrackedFilesAtStart) > 0 {
+		var stateErr error
+		state, stateErr = s.loadSessionState(sessionID)
+		if stateErr != nil {
+			state = nil
+		}
+		if state != nil && len(state.UntrackedFilesAtStart) > 0 {
 			preservedUntrackedFiles = make(map[string]bool)
 			for _, f := range state.UntrackedFilesAtStart {
 				preservedUntrackedFiles[f] = true
@@ -329,6 +338,7 @@ func (s *ManualCommitStrategy) Rewind(point RewindPoint) error {
 
 	// Find and delete untracked files that aren't in the checkpoint
 	// These are likely files created by the agent in later checkpoints
+	snapshotPolicy := cpkg.DefaultSnapshotPolicy()
 	err = filepath.Walk(repoRoot, func(path string, info os.FileInfo, walkErr error) error {
 		if walkErr != nil {
 			return nil //nolint:nilerr // Skip filesystem errors during walk
@@ -368,6 +378,12 @@ func (s *ManualCommitStrategy) Rewind(point RewindPoint) error {
 			return nil
 		}
 
+		// Secret files like .env are left out of snapshots, so their absence
+		// from the checkpoint doesn't mean the agent created them after it
+		if snapshotPolicy.IsSecretFile(relPath) {
+			return nil
+		}
+
 		// File is untracked and not in checkpoint - delete it (use absolute path)
 		if removeErr := os.Remove(path); removeErr == nil {
 			fmt.Fprintf(os.Stderr, "  Deleted: %s\n", relPath)
@@ -417,6 +433,14 @@ func (s *ManualCommitStrategy) Rewind(point RewindPoint) error {
 		return fmt.Errorf("failed to iterate tree files: %w", err)
 	}
 
+	// The session's recorded file content is now that of the rewound checkpoint
+	if state != nil && len(state.FileHashes) > 0 {
+		state.FileHashes = recordFileHashes(state.FileHashes, repoRoot, slices.Collect(maps.Keys(state.FileHashes)))
+		if err := s.saveSessionState(state); err != nil {
+			fmt.Fprintf(os.Stderr, "[entire] Warning: failed to update session state: %v\n", err)
+		}
+	}
+
 	fmt.Println()
 	if len(point.ID) >= 7 {
 		fmt.Printf("Restored files from shadow commit %s\n", point.ID[:7])
@@ -496,10 +520,15 @@ func (s *ManualCommitStrategy) PreviewRewind(point RewindPoint) (*RewindPreview,
 
 	// Load session state to get untracked files that existed at session start
 	sessionID, hasSessionTrailer := trailers.ParseSession(commit.Message)
+	var state *SessionState
 	var preservedUntrackedFiles map[string]bool
 	if hasSessionTrailer {
-		state, stateErr := s.loadSessionState(sessionID)
-		if stateErr == nil && state != nil && len(state.UntrackedFilesAtStart) > 0 {
+		var stateErr error
+		state, stateErr = s.loadSessionState(sessionID)
+		if stateErr != nil {
+			state = nil
+		}
+		if state != nil && len(state.UntrackedFilesAtStart) > 0 {
 			preservedUntrackedFiles = make(map[string]bool)
 			for _, f := range state.UntrackedFilesAtStart {
 				preservedUntrackedFiles[f] = true
@@ -550,6 +579,7 @@ func (s *ManualCommitStrategy) PreviewRewind(point RewindPoint) (*RewindPreview,
 	}
 
 	// Find untracked files that would be deleted
+	snapshotPolicy := cpkg.DefaultSnapshotPolicy()
 	var filesToDelete []string
 	err = filepath.Walk(repoRoot, func(path string, info os.FileInfo, walkErr error) error {
 		if walkErr != nil {
@@ -589,15 +619,24 @@ func (s *ManualCommitStrategy) PreviewRewind(point RewindPoint) (*RewindPreview,
 			return nil
 		}
 
+		// Secret files are left out of snapshots and are never deleted
+		if snapshotPolicy.IsSecretFile(relPath) {
+			return nil
+		}
+
 		// File is untracked and not in checkpoint - will be deleted
 		filesToDelete = append(filesToDelete, relPath)
 		return nil
 	})
+	// Files the snapshot stored with secrets redacted
+	redactedFiles, _ := cpkg.SnapshotRedactedFiles(tree) //nolint:errcheck // The warning is best-effort
+
 	if err != nil {
 		// Non-fatal, return what we have
 		return &RewindPreview{ //nolint:nilerr // Partial result is still useful
 			FilesToRestore: filesToRestore,
 			FilesToDelete:  filesToDelete,
+			RedactedFiles:  redactedFiles,
 		}, nil
 	}
 
@@ -608,6 +647,7 @@ func (s *ManualCommitStrategy) PreviewRewind(point RewindPoint) (*RewindPreview,
 	return &RewindPreview{
 		FilesToRestore: filesToRestore,
 		FilesToDelete:  filesToDelete,
+		RedactedFiles:  redactedFiles,
 	}, nil
 }
 
@@ -5,14 +5,17 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/entireio/cli/cmd/entire/cli/agent"
+	"github.com/entireio/cli/cmd/entire/cli/agent/geminicli"
 	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
 	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
 	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/session"
 	"github.com/entireio/cli/cmd/entire/cli/trailers"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
@@ -861,6 +864,49 @@ func TestShadowStrategy_PrepareCommitMsg_SkipSources(t *testing.T) {
 	}
 }
 
+func TestShadowStrategy_PrepareCommitMsg_SquashCarriesTrailers(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := git.PlainInit(dir, false); err != nil {
+		t.Fatalf("failed to init git repo: %v", err)
+	}
+	t.Chdir(dir)
+
+	commitMsgFile := filepath.Join(dir, "COMMIT_MSG")
+	squashMsg := "Squashed commit of the following:\n\n" +
+		"commit 1111111111111111111111111111111111111111\n\n    Add login\n\n    Entire-Checkpoint: a1b2c3d4e5f6\n\n" +
+		"commit 2222222222222222222222222222222222222222\n\n    Fix typo\n\n    Entire-Checkpoint: b1b2c3d4e5f6\n" +
+		"# Please enter the commit message for your changes.\n"
+	if err := os.WriteFile(commitMsgFile, []byte(squashMsg), 0o644); err != nil {
+		t.Fatalf("failed to write commit message file: %v", err)
+	}
+
+	s := &ManualCommitStrategy{}
+	if err := s.PrepareCommitMsg(commitMsgFile, "squash"); err != nil {
+		t.Fatalf("PrepareCommitMsg() error = %v", err)
+	}
+
+	content, err := os.ReadFile(commitMsgFile)
+	if err != nil {
+		t.Fatalf("failed to read commit message file: %v", err)
+	}
+	want := "    Entire-Checkpoint: b1b2c3d4e5f6\n\n" +
+		"Entire-Checkpoint: a1b2c3d4e5f6\nEntire-Checkpoint: b1b2c3d4e5f6\n\n" +
+		"# Please enter"
+	if !strings.Contains(string(content), want) {
+		t.Errorf("squash message missing carried trailers above comments:\n%s", content)
+	}
+}
+
+func TestAddSquashTrailers_SkipsExistingTrailers(t *testing.T) {
+	t.Parallel()
+
+	message := "Squash\n\n    Entire-Checkpoint: a1b2c3d4e5f6\n\nEntire-Checkpoint: a1b2c3d4e5f6\n"
+	cpIDs := trailers.ParseAllCheckpoints(message)
+	if got := addSquashTrailers(message, cpIDs); got != message {
+		t.Errorf("addSquashTrailers() = %q, want message unchanged", got)
+	}
+}
+
 func TestAddCheckpointTrailer_NoComment(t *testing.T) {
 	// Test that addCheckpointTrailer adds trailer without any comment lines
 	message := "Test commit message\n" //nolint:goconst // already present in codebase
@@ -2652,6 +2698,120 @@ func TestCondenseSession_PrefersLiveTranscript(t *testing.T) {
 	}
 }
 
+func TestCondenseSession_ExcludesPausedTurns(t *testing.T) {
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatalf("failed to init repo: %v", err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("failed to get worktree: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "file.txt"), []byte("content"), 0o644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if _, err := wt.Add("file.txt"); err != nil {
+		t.Fatalf("failed to stage: %v", err)
+	}
+	if _, err := wt.Commit("Initial commit", &git.CommitOptions{
+		Author: &object.Signature{Name: "Test", Email: "test@test.com", When: time.Now()},
+	}); err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+	t.Chdir(dir)
+
+	s := &ManualCommitStrategy{}
+	sessionID := "2025-01-15-test-paused-turns"
+	metadataDir := ".entire/metadata/" + sessionID
+	metadataDirAbs := filepath.Join(dir, metadataDir)
+	if err := os.MkdirAll(metadataDirAbs, 0o755); err != nil {
+		t.Fatalf("failed to create metadata dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(metadataDirAbs, paths.TranscriptFileName), []byte("{}\n"), 0o644); err != nil {
+		t.Fatalf("failed to write transcript: %v", err)
+	}
+	if err := s.SaveChanges(SaveContext{
+		SessionID:      sessionID,
+		MetadataDir:    metadataDir,
+		MetadataDirAbs: metadataDirAbs,
+		CommitMessage:  "Checkpoint 1",
+		AuthorName:     "Test",
+		AuthorEmail:    "test@test.com",
+	}); err != nil {
+		t.Fatalf("SaveChanges() error = %v", err)
+	}
+
+	// Lines 2-3 were written during an earlier pause; the session has been
+	// paused again since line 6.
+	liveTranscriptFile := filepath.Join(dir, "live-transcript.jsonl")
+	liveTranscript := `{"type":"human","message":{"content":"first prompt"}}
+{"type":"assistant","message":{"content":"first response"}}
+{"type":"human","message":{"content":"private prompt"}}
+{"type":"assistant","message":{"content":"private response"}}
+{"type":"human","message":{"content":"second prompt"}}
+{"type":"assistant","message":{"content":"second response"}}
+{"type":"human","message":{"content":"still paused prompt"}}
+{"type":"assistant","message":{"content":"still paused response"}}
+`
+	if err := os.WriteFile(liveTranscriptFile, []byte(liveTranscript), 0o644); err != nil {
+		t.Fatalf("failed to write live transcript: %v", err)
+	}
+
+	state, err := s.loadSessionState(sessionID)
+	if err != nil {
+		t.Fatalf("loadSessionState() error = %v", err)
+	}
+	state.TranscriptPath = liveTranscriptFile
+	state.Phase = session.PhasePaused
+	state.PausedTranscriptStart = 6
+	state.PausedTranscriptRanges = []session.TranscriptRange{{Start: 2, End: 4}}
+
+	checkpointID := id.MustCheckpointID("c3d4e5f6a1b2")
+	result, err := s.CondenseSession(repo, checkpointID, state, nil)
+	if err != nil {
+		t.Fatalf("CondenseSession() error = %v", err)
+	}
+	// The next checkpoint starts after everything in the live transcript
+	if result.TotalTranscriptLines != 8 {
+		t.Errorf("TotalTranscriptLines = %d, want 8", result.TotalTranscriptLines)
+	}
+
+	content, err := checkpoint.NewGitStore(repo).ReadLatestSessionContent(t.Context(), checkpointID)
+	if err != nil {
+		t.Fatalf("ReadLatestSessionContent() error = %v", err)
+	}
+	transcript := string(content.Transcript)
+	if !strings.Contains(transcript, "first prompt") || !strings.Contains(transcript, "second prompt") {
+		t.Errorf("condensed transcript lost recorded turns:\n%s", transcript)
+	}
+	for _, paused := range []string{"private", "still paused"} {
+		if strings.Contains(transcript, paused) || strings.Contains(content.Prompts, paused) {
+			t.Errorf("condensed checkpoint contains %q from a paused turn:\n%s\n%s", paused, transcript, content.Prompts)
+		}
+	}
+}
+
+func TestRemoveTranscriptRanges_Gemini(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`{"sessionId":"abc","messages":[{"type":"user","content":"a"},{"type":"gemini","content":"b"},{"type":"user","content":"c"},{"type":"gemini","content":"d"}]}`)
+	got, removedBefore := removeTranscriptRanges(agent.AgentTypeGemini, data, []session.TranscriptRange{{Start: 1, End: 3}}, 2)
+	if removedBefore != 1 {
+		t.Errorf("removedBefore = %d, want 1", removedBefore)
+	}
+	transcript, err := geminicli.ParseTranscript(got)
+	if err != nil {
+		t.Fatalf("trimmed transcript is invalid: %v", err)
+	}
+	if len(transcript.Messages) != 2 || transcript.Messages[0].Content != "a" || transcript.Messages[1].Content != "d" {
+		t.Errorf("messages = %+v, want a and d", transcript.Messages)
+	}
+	if !strings.Contains(string(got), `"sessionId":"abc"`) {
+		t.Errorf("trimmed transcript lost other fields: %s", got)
+	}
+}
+
 // TestCondenseSession_GeminiTranscript verifies that CondenseSession works correctly
 // with Gemini JSON format transcripts, including prompt extraction and format detection.
 func TestCondenseSession_GeminiTranscript(t *testing.T) {
@@ -3036,3 +3196,76 @@ func TestCondenseSession_GeminiMultiCheckpoint(t *testing.T) {
 		t.Error("Prompts should contain second prompt")
 	}
 }
+
+func TestRecordSubagentActivity(t *testing.T) {
+	t.Parallel()
+
+	activities := recordSubagentActivity(nil, TaskCheckpointContext{
+		ToolUseID:     "toolu_1",
+		SubagentType:  "dev",
+		ModifiedFiles: []string{"b.go"},
+		NewFiles:      []string{"a.go"},
+		DeletedFiles:  []string{"gone.go"},
+	})
+	// A later checkpoint from the same task merges into the same entry.
+	activities = recordSubagentActivity(activities, TaskCheckpointContext{
+		ToolUseID:     "toolu_1",
+		AgentID:       "agent-1",
+		ModifiedFiles: []string{"b.go", "c.go"},
+	})
+	// Tasks without file changes are not recorded.
+	activities = recordSubagentActivity(activities, TaskCheckpointContext{ToolUseID: "toolu_2", DeletedFiles: []string{"x.go"}})
+
+	want := []SubagentActivity{{
+		ToolUseID:    "toolu_1",
+		AgentID:      "agent-1",
+		SubagentType: "dev",
+		Files:        []string{"a.go", "b.go", "c.go"},
+	}}
+	if len(activities) != 1 || activities[0].ToolUseID != want[0].ToolUseID ||
+		activities[0].AgentID != want[0].AgentID || activities[0].SubagentType != want[0].SubagentType ||
+		!slices.Equal(activities[0].Files, want[0].Files) {
+		t.Errorf("recordSubagentActivity() = %+v, want %+v", activities, want)
+	}
+}
+
+func TestCondenseSessionByID_MovesSessionToIdle(t *testing.T) {
+	dir := setupGitRepo(t)
+	t.Chdir(dir)
+
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		t.Fatalf("failed to open repo: %v", err)
+	}
+
+	s := &ManualCommitStrategy{}
+	sessionID := "test-condense-by-id-ended"
+	setupSessionWithCheckpoint(t, s, repo, dir, sessionID)
+
+	// "entire doctor" salvages stuck sessions whatever their phase
+	state, err := s.loadSessionState(sessionID)
+	if err != nil {
+		t.Fatalf("loadSessionState() error = %v", err)
+	}
+	endedAt := time.Now()
+	state.Phase = session.PhaseEnded
+	state.EndedAt = &endedAt
+	if err := s.saveSessionState(state); err != nil {
+		t.Fatalf("saveSessionState() error = %v", err)
+	}
+
+	if err := s.CondenseSessionByID(sessionID); err != nil {
+		t.Fatalf("CondenseSessionByID() error = %v", err)
+	}
+
+	state, err = s.loadSessionState(sessionID)
+	if err != nil {
+		t.Fatalf("loadSessionState() error = %v", err)
+	}
+	if state.Phase != session.PhaseIdle {
+		t.Errorf("Phase = %q, want %q after condensing", state.Phase, session.PhaseIdle)
+	}
+	if state.StepCount != 0 || state.LastCheckpointID.IsEmpty() {
+		t.Errorf("StepCount = %d, LastCheckpointID = %q; want the session condensed", state.StepCount, state.LastCheckpointID)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
 	"github.com/entireio/cli/cmd/entire/cli/session"
 	"github.com/entireio/cli/cmd/entire/cli/stringutil"
+	"github.com/entireio/cli/redact"
 )
 
 const (
@@ -23,6 +24,13 @@ func truncatePromptForStorage(prompt string) string {
 	return stringutil.TruncateRunes(stringutil.CollapseWhitespace(prompt), maxFirstPromptRunes, "...")
 }
 
+// promptForAttribution redacts secrets from a user prompt before truncating it
+// for the per-prompt attribution, which is committed in checkpoint metadata.
+// Redacting first keeps a secret cut by truncation from slipping past detection.
+func promptForAttribution(prompt string) string {
+	return truncatePromptForStorage(redact.String(prompt))
+}
+
 // SessionState is an alias for session.State.
 // Previously this was a separate struct with manual conversion functions.
 type SessionState = session.State
@@ -30,6 +38,9 @@ type SessionState = session.State
 // PromptAttribution is an alias for session.PromptAttribution.
 type PromptAttribution = session.PromptAttribution
 
+// SubagentActivity is an alias for session.SubagentActivity.
+type SubagentActivity = session.SubagentActivity
+
 // CheckpointInfo represents checkpoint metadata stored on the sessions branch.
 // Metadata is stored at sharded path: <checkpoint_id[:2]>/<checkpoint_id[2:]>/
 type CheckpointInfo struct {
@@ -43,6 +54,9 @@ type CheckpointInfo struct {
 	ToolUseID        string          `json:"tool_use_id,omitempty"`
 	SessionCount     int             `json:"session_count,omitempty"` // Number of sessions (1 if omitted)
 	SessionIDs       []string        `json:"session_ids,omitempty"`   // All session IDs in this checkpoint
+
+	// Annotations are the user's title, tags, and notes for the session (SessionID)
+	session.Annotations
 }
 
 // CondenseResult contains the result of a session condensation operation.
@@ -172,12 +172,10 @@ func TestPostCommit_RebaseDuringActive_SkipsTransition(t *testing.T) {
 		"shadow branch should be preserved during rebase")
 }
 
-// TestPostCommit_ActiveSessionAlwaysCondenses verifies that an ACTIVE session
-// is always condensed on GitCommit, even when it has no checkpoints or tracked files.
-// This is because PrepareCommitMsg already validated the trailer, so PostCommit
-// trusts that decision rather than re-validating via transcript analysis (which is
-// unreliable when subagents are still running).
-func TestPostCommit_ActiveSessionAlwaysCondenses(t *testing.T) {
+// TestPostCommit_ActiveSessionWithoutEdits_NotCondensed verifies that an ACTIVE
+// session that didn't edit any committed file is not condensed when another
+// session's edits are in the commit.
+func TestPostCommit_ActiveSessionWithoutEdits_NotCondensed(t *testing.T) {
 	dir := setupGitRepo(t)
 	t.Chdir(dir)
 
@@ -205,9 +203,8 @@ func TestPostCommit_ActiveSessionAlwaysCondenses(t *testing.T) {
 	require.NoError(t, s.saveSessionState(idleState))
 
 	// Create a second session with the SAME base commit and worktree (concurrent session).
-	// This session is ACTIVE but has NO checkpoints (StepCount=0, no shadow branch content).
-	// Despite having no content, it WILL be condensed because ACTIVE sessions always
-	// condense — PrepareCommitMsg already validated the trailer.
+	// This session is ACTIVE but has NO checkpoints and no edits, so the commit
+	// (which contains the idle session's test.txt) is not its work.
 	now := time.Now()
 	activeState := &SessionState{
 		SessionID:           activeSessionID,
@@ -231,29 +228,56 @@ func TestPostCommit_ActiveSessionAlwaysCondenses(t *testing.T) {
 	err = s.PostCommit()
 	require.NoError(t, err)
 
-	// Verify the ACTIVE session stays ACTIVE (immediate condensation model)
+	// Verify the ACTIVE session stays ACTIVE and was not condensed
 	activeState, err = s.loadSessionState(activeSessionID)
 	require.NoError(t, err)
 	assert.Equal(t, session.PhaseActive, activeState.Phase,
 		"ACTIVE session should stay ACTIVE after GitCommit")
+	assert.True(t, activeState.LastCheckpointID.IsEmpty(),
+		"ACTIVE session without edits in the commit should not be condensed")
 
-	// Verify both sessions condensed (entire/checkpoints/v1 branch should exist)
+	// Verify the IDLE session owning test.txt was condensed
 	idleState, err = s.loadSessionState(idleSessionID)
 	require.NoError(t, err)
-	sessionsRef, err := repo.Reference(plumbing.NewBranchReferenceName(paths.MetadataBranchName), true)
-	require.NoError(t, err, "entire/checkpoints/v1 branch should exist after condensation")
-	require.NotNil(t, sessionsRef)
-
-	// Verify IDLE session's StepCount was reset by condensation
+	assert.Equal(t, "d4e5f6a1b2c3", idleState.LastCheckpointID.String())
 	assert.Equal(t, 0, idleState.StepCount,
 		"IDLE session StepCount should be reset after condensation")
 
-	// Verify shadow branch is cleaned up because ALL sessions condensed
-	// (both IDLE and ACTIVE were condensed on this commit)
+	// Verify shadow branch is preserved for the uncondensed ACTIVE session
 	refName := plumbing.NewBranchReferenceName(shadowBranch)
 	_, err = repo.Reference(refName, true)
-	assert.Error(t, err,
-		"shadow branch should be deleted when all sessions have been condensed")
+	assert.NoError(t, err,
+		"shadow branch should be preserved while an uncondensed ACTIVE session uses it")
+}
+
+// TestPostCommit_ActiveSession_CondensesWhenNoSessionOwnsCommit verifies the
+// fallback: when no session's edits can be found in the commit (e.g. a subagent
+// committed before its transcript was written), ACTIVE sessions are condensed.
+func TestPostCommit_ActiveSession_CondensesWhenNoSessionOwnsCommit(t *testing.T) {
+	dir := setupGitRepo(t)
+	t.Chdir(dir)
+
+	repo, err := git.PlainOpen(dir)
+	require.NoError(t, err)
+
+	s := &ManualCommitStrategy{}
+	sessionID := "test-postcommit-no-owner"
+	setupSessionWithCheckpoint(t, s, repo, dir, sessionID)
+
+	state, err := s.loadSessionState(sessionID)
+	require.NoError(t, err)
+	state.Phase = session.PhaseActive
+	state.FilesTouched = nil
+	state.FileHashes = nil
+	require.NoError(t, s.saveSessionState(state))
+
+	commitWithCheckpointTrailer(t, repo, dir, "e5f6a1b2c3d4")
+	require.NoError(t, s.PostCommit())
+
+	state, err = s.loadSessionState(sessionID)
+	require.NoError(t, err)
+	assert.Equal(t, "e5f6a1b2c3d4", state.LastCheckpointID.String(),
+		"ACTIVE session should be condensed when no session owns the commit")
 }
 
 // TestPostCommit_CondensationFailure_PreservesShadowBranch verifies that when
@@ -1135,6 +1159,66 @@ func TestPostCommit_ActiveSession_RecordsTurnCheckpointIDs(t *testing.T) {
 		"TurnCheckpointIDs should contain the checkpoint ID after condensation")
 }
 
+// TestPostCommit_ConcurrentActiveSessions_CondensesOnlyOwners verifies that when
+// several sessions are ACTIVE, a commit is attributed only to the sessions whose
+// recorded edits it contains.
+func TestPostCommit_ConcurrentActiveSessions_CondensesOnlyOwners(t *testing.T) {
+	dir := setupGitRepo(t)
+	t.Chdir(dir)
+
+	repo, err := git.PlainOpen(dir)
+	require.NoError(t, err)
+
+	s := &ManualCommitStrategy{}
+	ownerID := "test-postcommit-owner"
+	setupSessionWithCheckpoint(t, s, repo, dir, ownerID)
+
+	owner, err := s.loadSessionState(ownerID)
+	require.NoError(t, err)
+	require.Contains(t, owner.FileHashes, "test.txt", "SaveChanges should record the content it checkpointed")
+	owner.Phase = session.PhaseActive
+	require.NoError(t, s.saveSessionState(owner))
+
+	// Bystanders share the base commit: one edited an unrelated file, the
+	// other edited test.txt but left different content than what is committed.
+	now := time.Now()
+	for _, bystander := range []*SessionState{
+		{
+			SessionID:    "test-postcommit-unrelated",
+			FilesTouched: []string{"other.txt"},
+			FileHashes:   map[string]string{"other.txt": "1111111111111111111111111111111111111111"},
+		},
+		{
+			SessionID:    "test-postcommit-same-file",
+			FilesTouched: []string{"test.txt"},
+			FileHashes:   map[string]string{"test.txt": "2222222222222222222222222222222222222222"},
+		},
+	} {
+		bystander.BaseCommit = owner.BaseCommit
+		bystander.WorktreePath = owner.WorktreePath
+		bystander.WorktreeID = owner.WorktreeID
+		bystander.StartedAt = now
+		bystander.Phase = session.PhaseActive
+		bystander.LastInteractionTime = &now
+		require.NoError(t, s.saveSessionState(bystander))
+	}
+
+	commitWithCheckpointTrailer(t, repo, dir, "b1c2d3e4f5a6")
+	require.NoError(t, s.PostCommit())
+
+	owner, err = s.loadSessionState(ownerID)
+	require.NoError(t, err)
+	assert.Equal(t, "b1c2d3e4f5a6", owner.LastCheckpointID.String(), "owner should be condensed")
+	assert.Empty(t, owner.FileHashes, "condensation should reset the recorded hashes")
+
+	for _, sessionID := range []string{"test-postcommit-unrelated", "test-postcommit-same-file"} {
+		state, err := s.loadSessionState(sessionID)
+		require.NoError(t, err)
+		assert.True(t, state.LastCheckpointID.IsEmpty(), "%s should not be condensed", sessionID)
+		assert.NotEmpty(t, state.FilesTouched, "%s should keep its files", sessionID)
+	}
+}
+
 // TestPostCommit_IdleSession_DoesNotRecordTurnCheckpointIDs verifies that PostCommit
 // does NOT record TurnCheckpointIDs for IDLE sessions.
 func TestPostCommit_IdleSession_DoesNotRecordTurnCheckpointIDs(t *testing.T) {
@@ -194,8 +194,9 @@ func fetchAndMergeSessionsCommon(remote, branchName string) error {
 	}
 
 	// Update branch ref
+	// Fails rather than dropping checkpoints written since the branch was read
 	newRef := plumbing.NewHashReference(plumbing.NewBranchReferenceName(branchName), mergeCommitHash)
-	if err := repo.Storer.SetReference(newRef); err != nil {
+	if err := repo.Storer.CheckAndSetReference(newRef, localRef); err != nil {
 		return fmt.Errorf("failed to update branch ref: %w", err)
 	}
 
@@ -9,8 +9,10 @@ import (
 	"github.com/entireio/cli/cmd/entire/cli/agent"
 	_ "github.com/entireio/cli/cmd/entire/cli/agent/claudecode" // Register agent for ResolveAgentForRewind tests
 	_ "github.com/entireio/cli/cmd/entire/cli/agent/geminicli"  // Register agent for ResolveAgentForRewind tests
+	"github.com/entireio/cli/cmd/entire/cli/paths"
 
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
 )
 
@@ -105,6 +107,17 @@ func TestShadowStrategy_PreviewRewind(t *testing.T) {
 		t.Fatalf("failed to add app.js: %v", err)
 	}
 
+	// Record app.js as stored with secrets redacted
+	if err := os.MkdirAll(filepath.Join(dir, entireDir), 0o755); err != nil {
+		t.Fatalf("failed to create .entire dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, paths.SnapshotRedactionsFile), []byte("app.js\n"), 0o644); err != nil {
+		t.Fatalf("failed to write snapshot redactions: %v", err)
+	}
+	if _, err := worktree.Add(paths.SnapshotRedactionsFile); err != nil {
+		t.Fatalf("failed to add snapshot redactions: %v", err)
+	}
+
 	// Create metadata directory structure first
 	sessionID := "test-session-123"
 	metadataDir := filepath.Join(dir, entireDir, "metadata", sessionID)
@@ -160,6 +173,11 @@ func TestShadowStrategy_PreviewRewind(t *testing.T) {
 		t.Fatalf("failed to write existing-untracked.txt: %v", err)
 	}
 
+	// 3. A secret file, which snapshots leave out (should NOT be deleted)
+	if err := os.WriteFile(filepath.Join(dir, ".env"), []byte("API_KEY=abc\n"), 0o644); err != nil {
+		t.Fatalf("failed to write .env: %v", err)
+	}
+
 	// Create rewind point
 	point := RewindPoint{
 		ID:          checkpointHash.String(),
@@ -202,12 +220,17 @@ func TestShadowStrategy_PreviewRewind(t *testing.T) {
 		t.Errorf("FilesToDelete missing extra.js, got: %v", preview.FilesToDelete)
 	}
 
-	// Should NOT delete existing-untracked.txt (existed at session start)
+	// Should NOT delete existing-untracked.txt (existed at session start) or .env (secret file)
 	for _, f := range preview.FilesToDelete {
-		if f == "existing-untracked.txt" {
-			t.Errorf("FilesToDelete incorrectly includes existing-untracked.txt, got: %v", preview.FilesToDelete)
+		if f == "existing-untracked.txt" || f == ".env" {
+			t.Errorf("FilesToDelete incorrectly includes %s, got: %v", f, preview.FilesToDelete)
 		}
 	}
+
+	// Should warn that app.js is restored with secrets redacted
+	if len(preview.RedactedFiles) != 1 || preview.RedactedFiles[0] != "app.js" {
+		t.Errorf("RedactedFiles = %v, want [app.js]", preview.RedactedFiles)
+	}
 }
 
 func TestShadowStrategy_PreviewRewind_LogsOnly(t *testing.T) {
@@ -340,3 +363,52 @@ func TestResolveAgentForRewind(t *testing.T) {
 		}
 	})
 }
+
+func TestShadowStrategy_Rewind_RefreshesFileHashes(t *testing.T) {
+	dir := setupGitRepo(t)
+	t.Chdir(dir)
+
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		t.Fatalf("failed to open repo: %v", err)
+	}
+
+	s := &ManualCommitStrategy{}
+	sessionID := "test-rewind-file-hashes"
+	setupSessionWithCheckpoint(t, s, repo, dir, sessionID)
+	points, err := s.GetRewindPoints(10)
+	if err != nil || len(points) != 1 {
+		t.Fatalf("GetRewindPoints() = %v, %v; want one point", points, err)
+	}
+	firstPoint := points[0]
+
+	// A second checkpoint records later content for test.txt
+	if err := os.WriteFile(filepath.Join(dir, "test.txt"), []byte("later agent content"), 0o644); err != nil {
+		t.Fatalf("failed to write test.txt: %v", err)
+	}
+	metadataDir := ".entire/metadata/" + sessionID
+	if err := s.SaveChanges(SaveContext{
+		SessionID:      sessionID,
+		ModifiedFiles:  []string{"test.txt"},
+		MetadataDir:    metadataDir,
+		MetadataDirAbs: filepath.Join(dir, metadataDir),
+		CommitMessage:  "Checkpoint 2",
+		AuthorName:     "Test",
+		AuthorEmail:    "test@test.com",
+	}); err != nil {
+		t.Fatalf("SaveChanges() error = %v", err)
+	}
+
+	if err := s.Rewind(firstPoint); err != nil {
+		t.Fatalf("Rewind() error = %v", err)
+	}
+
+	state, err := s.loadSessionState(sessionID)
+	if err != nil {
+		t.Fatalf("loadSessionState() error = %v", err)
+	}
+	want := plumbing.ComputeHash(plumbing.BlobObject, []byte("agent modified content")).String()
+	if got := state.FileHashes["test.txt"]; got != want {
+		t.Errorf("FileHashes[test.txt] = %q, want the rewound content's hash %q", got, want)
+	}
+}
@@ -0,0 +1,338 @@
+package strategy
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/logging"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/trailers"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// patchIDScanLimit bounds how many commits per branch are checked when looking
+// for the original of a rewritten commit by patch-id.
+const patchIDScanLimit = 500
+
+// CommitRewrite is a single old → new commit mapping, as reported by git's
+// post-rewrite hook.
+type CommitRewrite struct {
+	OldSHA string
+	NewSHA string
+}
+
+// ParseRewriteMappings parses the post-rewrite hook's stdin.
+// Each line is "<old-sha> <new-sha> [<extra>]"; blank lines are ignored.
+func ParseRewriteMappings(r io.Reader) ([]CommitRewrite, error) {
+	var rewrites []CommitRewrite
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("invalid rewrite mapping: %q", scanner.Text())
+		}
+		rewrites = append(rewrites, CommitRewrite{OldSHA: fields[0], NewSHA: fields[1]})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rewrite mappings: %w", err)
+	}
+	return rewrites, nil
+}
+
+// CommitLinkIndex maps commit SHAs to the checkpoints they were linked to after
+// a rewrite dropped their Entire-Checkpoint trailer.
+type CommitLinkIndex map[string][]id.CheckpointID
+
+// LoadCommitLinkIndex builds the link index from all committed checkpoints.
+func LoadCommitLinkIndex(ctx context.Context, store *checkpoint.GitStore) (CommitLinkIndex, error) {
+	committed, err := store.ListCommitted(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list checkpoints: %w", err)
+	}
+	index := make(CommitLinkIndex)
+	for _, info := range committed {
+		for _, link := range info.LinkedCommits {
+			index[link.SHA] = append(index[link.SHA], info.CheckpointID)
+		}
+	}
+	return index, nil
+}
+
+// CheckpointsForCommit returns the checkpoints a commit belongs to: the IDs in its
+// Entire-Checkpoint trailers followed by any checkpoints it was linked to.
+func (idx CommitLinkIndex) CheckpointsForCommit(c *object.Commit) []id.CheckpointID {
+	cpIDs := trailers.ParseAllCheckpoints(c.Message)
+	for _, cpID := range idx[c.Hash.String()] {
+		if !slices.Contains(cpIDs, cpID) {
+			cpIDs = append(cpIDs, cpID)
+		}
+	}
+	return cpIDs
+}
+
+// LinkRewrittenCommits links each rewritten commit to the checkpoints of the commit
+// it replaced, unless the new commit still carries those trailers. reason is one of
+// the checkpoint.LinkReason* values. Returns the number of links recorded.
+//
+// Checkpoints that don't exist locally (e.g. not fetched yet) are skipped.
+func LinkRewrittenCommits(ctx context.Context, repo *git.Repository, reason string, rewrites []CommitRewrite) (int, error) {
+	store := checkpoint.NewGitStore(repo)
+	index, err := LoadCommitLinkIndex(ctx, store)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now().UTC()
+	pending := make(map[id.CheckpointID][]checkpoint.LinkedCommit)
+	var order []id.CheckpointID
+
+	for _, rw := range rewrites {
+		var oldIDs []id.CheckpointID
+		if oldCommit, err := repo.CommitObject(plumbing.NewHash(rw.OldSHA)); err == nil {
+			oldIDs = index.CheckpointsForCommit(oldCommit)
+		} else {
+			oldIDs = index[rw.OldSHA]
+		}
+		if len(oldIDs) == 0 {
+			continue
+		}
+
+		var kept []id.CheckpointID
+		if newCommit, err := repo.CommitObject(plumbing.NewHash(rw.NewSHA)); err == nil {
+			kept = index.CheckpointsForCommit(newCommit)
+		}
+
+		for _, cpID := range oldIDs {
+			if slices.Contains(kept, cpID) {
+				continue
+			}
+			if _, seen := pending[cpID]; !seen {
+				order = append(order, cpID)
+			}
+			pending[cpID] = append(pending[cpID], checkpoint.LinkedCommit{
+				SHA:         rw.NewSHA,
+				OriginalSHA: rw.OldSHA,
+				Reason:      reason,
+				LinkedAt:    now,
+			})
+		}
+	}
+
+	return linkCommits(ctx, store, order, pending)
+}
+
+// FindCheckpointsByPatchID looks for a commit with the same patch-id as c among
+// the checkpoint-linked commits on local and remote-tracking branches. This
+// recovers the checkpoints of commits rewritten without our hooks running, such
+// as a cherry-pick with an edited message or a server-side squash of a single
+// commit. It only reads the repository: matches are not recorded as links, and
+// a commit whose patch-id can't be computed simply has no match.
+func FindCheckpointsByPatchID(ctx context.Context, repo *git.Repository, c *object.Commit, index CommitLinkIndex) []id.CheckpointID {
+	refs, err := repo.References()
+	if err != nil {
+		return nil
+	}
+	var tips []plumbing.Hash
+	_ = refs.ForEach(func(ref *plumbing.Reference) error { //nolint:errcheck // callback never errors
+		name := ref.Name()
+		if !name.IsBranch() && !name.IsRemote() {
+			return nil
+		}
+		short := name.Short()
+		if strings.HasPrefix(name.String(), "refs/heads/"+shadowBranchPrefix) || strings.HasSuffix(short, paths.MetadataBranchName) {
+			return nil
+		}
+		if ref.Type() == plumbing.HashReference {
+			tips = append(tips, ref.Hash())
+		}
+		return nil
+	})
+
+	// Collect the checkpoint-linked candidates first, so all patch-ids are
+	// computed by a single pair of git processes
+	seen := map[plumbing.Hash]bool{c.Hash: true}
+	candidates := make(map[string][]id.CheckpointID)
+	shas := []string{c.Hash.String()}
+	for _, tip := range tips {
+		//nolint:errcheck // fn never errors; shallow clones end the walk early
+		_ = walkFirstParents(repo, tip, patchIDScanLimit, func(candidate *object.Commit) error {
+			if seen[candidate.Hash] {
+				return nil
+			}
+			seen[candidate.Hash] = true
+			if cpIDs := index.CheckpointsForCommit(candidate); len(cpIDs) > 0 {
+				candidates[candidate.Hash.String()] = cpIDs
+				shas = append(shas, candidate.Hash.String())
+			}
+			return nil
+		})
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	patchIDs, err := commitPatchIDs(ctx, shas)
+	if err != nil {
+		logging.Debug(logging.WithComponent(ctx, "checkpoint"), "patch-id lookup failed",
+			slog.String("commit", c.Hash.String()),
+			slog.String("error", err.Error()),
+		)
+		return nil
+	}
+	target := patchIDs[c.Hash.String()]
+	if target == "" {
+		return nil // No textual diff to match (e.g. merges, empty commits)
+	}
+
+	var matched []id.CheckpointID
+	for _, sha := range shas[1:] {
+		if patchIDs[sha] != target {
+			continue
+		}
+		for _, cpID := range candidates[sha] {
+			if !slices.Contains(matched, cpID) {
+				matched = append(matched, cpID)
+			}
+		}
+	}
+	return matched
+}
+
+// HandlePostRewrite implements the post-rewrite hook for all strategies.
+// rewriteType is "amend" or "rebase"; mappings is the hook's stdin.
+// Errors are logged rather than returned so history rewrites are never blocked.
+func HandlePostRewrite(rewriteType string, mappings io.Reader) error {
+	logCtx := logging.WithComponent(context.Background(), "checkpoint")
+
+	reason := checkpoint.LinkReasonRebase
+	if rewriteType == "amend" {
+		reason = checkpoint.LinkReasonAmend
+	}
+
+	rewrites, err := ParseRewriteMappings(mappings)
+	if err != nil || len(rewrites) == 0 {
+		return nil //nolint:nilerr // Hook must be silent on failure
+	}
+
+	repo, err := OpenRepository()
+	if err != nil {
+		return nil //nolint:nilerr // Hook must be silent on failure
+	}
+
+	linked, err := LinkRewrittenCommits(logCtx, repo, reason, rewrites)
+	if err != nil {
+		logging.Warn(logCtx, "post-rewrite: failed to link rewritten commits",
+			slog.String("type", rewriteType),
+			slog.String("error", err.Error()),
+		)
+		return nil
+	}
+	if linked > 0 {
+		logging.Info(logCtx, "post-rewrite: linked rewritten commits to checkpoints",
+			slog.String("type", rewriteType),
+			slog.Int("rewrites", len(rewrites)),
+			slog.Int("linked", linked),
+		)
+	}
+	return nil
+}
+
+// linkCommits writes pending links in order, skipping checkpoints that don't exist locally.
+func linkCommits(ctx context.Context, store *checkpoint.GitStore, order []id.CheckpointID, pending map[id.CheckpointID][]checkpoint.LinkedCommit) (int, error) {
+	total := 0
+	for _, cpID := range order {
+		added, err := store.LinkCommits(ctx, cpID, pending[cpID])
+		if errors.Is(err, checkpoint.ErrCheckpointNotFound) {
+			continue
+		}
+		if err != nil {
+			return total, fmt.Errorf("failed to link commits to checkpoint %s: %w", cpID, err)
+		}
+		total += added
+	}
+	return total, nil
+}
+
+// commitPatchIDs returns the stable patch-id of each commit's diff against its
+// first parent, keyed by commit SHA. Commits without a textual diff (e.g.
+// merges, empty commits) are missing from the result.
+func commitPatchIDs(ctx context.Context, shas []string) (map[string]string, error) {
+	diffCmd := exec.CommandContext(ctx, "git", "diff-tree", "--stdin", "-p", "--root", "--no-color")
+	diffCmd.Stdin = strings.NewReader(strings.Join(shas, "\n") + "\n")
+	diff, err := diffCmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff commits: %w", err)
+	}
+
+	patchIDs := make(map[string]string, len(shas))
+	if len(diff) == 0 {
+		return patchIDs, nil
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "patch-id", "--stable")
+	cmd.Stdin = bytes.NewReader(diff)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute patch-ids: %w", err)
+	}
+	// Each line is "<patch-id> <commit-sha>"
+	for _, line := range strings.Split(string(output), "\n") {
+		if fields := strings.Fields(line); len(fields) == 2 {
+			patchIDs[fields[1]] = fields[0]
+		}
+	}
+	return patchIDs, nil
+}
+
+// walkFirstParents calls fn for up to limit commits along the first-parent chain from start.
+func walkFirstParents(repo *git.Repository, start plumbing.Hash, limit int, fn func(*object.Commit) error) error {
+	hash := start
+	for range limit {
+		c, err := repo.CommitObject(hash)
+		if err != nil {
+			return nil //nolint:nilerr // Shallow clones end the walk early
+		}
+		if err := fn(c); err != nil {
+			return err
+		}
+		if len(c.ParentHashes) == 0 {
+			return nil
+		}
+		hash = c.ParentHashes[0]
+	}
+	return nil
+}
+
+// ResolveCommitCheckpoints returns the checkpoints a commit belongs to, following
+// rewrite links and falling back to a patch-id match for commits without either.
+// It is a read-only lookup for commands like "entire explain".
+func ResolveCommitCheckpoints(ctx context.Context, repo *git.Repository, c *object.Commit) ([]id.CheckpointID, error) {
+	if cpIDs := trailers.ParseAllCheckpoints(c.Message); len(cpIDs) > 0 {
+		return cpIDs, nil
+	}
+	index, err := LoadCommitLinkIndex(ctx, checkpoint.NewGitStore(repo))
+	if err != nil {
+		return nil, err
+	}
+	if cpIDs := index.CheckpointsForCommit(c); len(cpIDs) > 0 {
+		return cpIDs, nil
+	}
+	return FindCheckpointsByPatchID(ctx, repo, c, index), nil
+}
@@ -0,0 +1,242 @@
+package strategy
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/agent"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/trailers"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// runRewriteGit runs a git command in dir with a fixed identity and returns trimmed stdout.
+func runRewriteGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.CommandContext(context.Background(), "git", append([]string{"-c", "user.name=Test", "-c", "user.email=test@test.com"}, args...)...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s failed: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// setupRewriteTestRepo creates a repo with a commit linked to a stored checkpoint.
+// Returns the repo directory, the opened repository, and the linked commit's SHA.
+func setupRewriteTestRepo(t *testing.T, cpID id.CheckpointID) (string, *git.Repository, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	runRewriteGit(t, dir, "init", "-q", "-b", "main")
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Test\n"), 0o644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	runRewriteGit(t, dir, "add", ".")
+	runRewriteGit(t, dir, "commit", "-q", "-m", "Initial commit")
+
+	runRewriteGit(t, dir, "checkout", "-q", "-b", "feature")
+	if err := os.WriteFile(filepath.Join(dir, "login.go"), []byte("package login\n\nfunc Login() {}\n"), 0o644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	runRewriteGit(t, dir, "add", ".")
+	runRewriteGit(t, dir, "commit", "-q", "-m", "Add login\n\n"+trailers.CheckpointTrailerKey+": "+cpID.String())
+	sha := runRewriteGit(t, dir, "rev-parse", "HEAD")
+
+	t.Chdir(dir)
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		t.Fatalf("failed to open repo: %v", err)
+	}
+	if err := checkpoint.NewGitStore(repo).WriteCommitted(context.Background(), checkpoint.WriteCommittedOptions{
+		CheckpointID: cpID,
+		SessionID:    "2026-01-01-rewrite-session",
+		Strategy:     "manual-commit",
+		Agent:        agent.AgentTypeClaudeCode,
+		Transcript:   []byte(`{"type":"user","message":{"content":"add login"}}` + "\n"),
+		FilesTouched: []string{"login.go"},
+		AuthorName:   "Test",
+		AuthorEmail:  "test@test.com",
+		InitialAttribution: &checkpoint.InitialAttribution{
+			AgentLines:      3,
+			TotalCommitted:  3,
+			AgentPercentage: 100,
+		},
+	}); err != nil {
+		t.Fatalf("failed to write checkpoint: %v", err)
+	}
+	return dir, repo, sha
+}
+
+func TestParseRewriteMappings(t *testing.T) {
+	t.Parallel()
+
+	input := "aaa bbb\n\nccc ddd extra-info\n"
+	got, err := ParseRewriteMappings(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseRewriteMappings() error = %v", err)
+	}
+	want := []CommitRewrite{{OldSHA: "aaa", NewSHA: "bbb"}, {OldSHA: "ccc", NewSHA: "ddd"}}
+	if !slices.Equal(got, want) {
+		t.Errorf("ParseRewriteMappings() = %v, want %v", got, want)
+	}
+
+	if _, err := ParseRewriteMappings(strings.NewReader("only-one-field\n")); err == nil {
+		t.Error("expected error for a line without a new SHA")
+	}
+}
+
+func TestLinkRewrittenCommits_AmendDropsTrailer(t *testing.T) {
+	cpID := id.MustCheckpointID("a1b2c3d4e5f6")
+	dir, repo, original := setupRewriteTestRepo(t, cpID)
+	ctx := context.Background()
+
+	runRewriteGit(t, dir, "commit", "-q", "--amend", "-m", "Add login (reworded)")
+	amended := runRewriteGit(t, dir, "rev-parse", "HEAD")
+
+	linked, err := LinkRewrittenCommits(ctx, repo, checkpoint.LinkReasonAmend, []CommitRewrite{{OldSHA: original, NewSHA: amended}})
+	if err != nil {
+		t.Fatalf("LinkRewrittenCommits() error = %v", err)
+	}
+	if linked != 1 {
+		t.Errorf("linked = %d, want 1", linked)
+	}
+
+	store := checkpoint.NewGitStore(repo)
+	summary, err := store.ReadCommitted(ctx, cpID)
+	if err != nil {
+		t.Fatalf("ReadCommitted() error = %v", err)
+	}
+	if len(summary.LinkedCommits) != 1 {
+		t.Fatalf("LinkedCommits = %+v, want one link", summary.LinkedCommits)
+	}
+	link := summary.LinkedCommits[0]
+	if link.SHA != amended || link.OriginalSHA != original || link.Reason != checkpoint.LinkReasonAmend {
+		t.Errorf("link = %+v", link)
+	}
+
+	// The amended commit resolves to the checkpoint, whose attribution carries over.
+	index, err := LoadCommitLinkIndex(ctx, store)
+	if err != nil {
+		t.Fatalf("LoadCommitLinkIndex() error = %v", err)
+	}
+	commit, err := repo.CommitObject(plumbing.NewHash(amended))
+	if err != nil {
+		t.Fatalf("failed to read commit: %v", err)
+	}
+	if got := index.CheckpointsForCommit(commit); len(got) != 1 || got[0] != cpID {
+		t.Errorf("CheckpointsForCommit() = %v, want [%s]", got, cpID)
+	}
+	content, err := store.ReadLatestSessionContent(ctx, cpID)
+	if err != nil {
+		t.Fatalf("ReadLatestSessionContent() error = %v", err)
+	}
+	if content.Metadata.InitialAttribution == nil || content.Metadata.InitialAttribution.AgentLines != 3 {
+		t.Errorf("attribution lost after linking: %+v", content.Metadata.InitialAttribution)
+	}
+
+	// Linking again is a no-op.
+	if linked, err := LinkRewrittenCommits(ctx, repo, checkpoint.LinkReasonAmend, []CommitRewrite{{OldSHA: original, NewSHA: amended}}); err != nil || linked != 0 {
+		t.Errorf("second LinkRewrittenCommits() = %d, %v; want 0, nil", linked, err)
+	}
+
+	// A rebase of the already-linked commit follows the link.
+	runRewriteGit(t, dir, "commit", "-q", "--amend", "-m", "Add login (again)")
+	rebased := runRewriteGit(t, dir, "rev-parse", "HEAD")
+	if linked, err := LinkRewrittenCommits(ctx, repo, checkpoint.LinkReasonRebase, []CommitRewrite{{OldSHA: amended, NewSHA: rebased}}); err != nil || linked != 1 {
+		t.Errorf("chained LinkRewrittenCommits() = %d, %v; want 1, nil", linked, err)
+	}
+}
+
+func TestLinkRewrittenCommits_KeepsTrailer(t *testing.T) {
+	cpID := id.MustCheckpointID("a1b2c3d4e5f6")
+	dir, repo, original := setupRewriteTestRepo(t, cpID)
+
+	// A rebase that keeps the message keeps the trailer, so no link is needed.
+	runRewriteGit(t, dir, "commit", "-q", "--amend", "--no-edit", "--date=2026-01-02T00:00:00")
+	rebased := runRewriteGit(t, dir, "rev-parse", "HEAD")
+
+	linked, err := LinkRewrittenCommits(context.Background(), repo, checkpoint.LinkReasonRebase, []CommitRewrite{{OldSHA: original, NewSHA: rebased}})
+	if err != nil {
+		t.Fatalf("LinkRewrittenCommits() error = %v", err)
+	}
+	if linked != 0 {
+		t.Errorf("linked = %d, want 0 when the trailer survived", linked)
+	}
+}
+
+func TestFindCheckpointsByPatchID_CherryPick(t *testing.T) {
+	cpID := id.MustCheckpointID("a1b2c3d4e5f6")
+	dir, repo, original := setupRewriteTestRepo(t, cpID)
+	ctx := context.Background()
+
+	// Cherry-pick onto main and drop the trailer from the message.
+	runRewriteGit(t, dir, "checkout", "-q", "main")
+	runRewriteGit(t, dir, "cherry-pick", original)
+	runRewriteGit(t, dir, "commit", "-q", "--amend", "-m", "Login support")
+	picked := runRewriteGit(t, dir, "rev-parse", "HEAD")
+
+	store := checkpoint.NewGitStore(repo)
+	index, err := LoadCommitLinkIndex(ctx, store)
+	if err != nil {
+		t.Fatalf("LoadCommitLinkIndex() error = %v", err)
+	}
+	commit, err := repo.CommitObject(plumbing.NewHash(picked))
+	if err != nil {
+		t.Fatalf("failed to read commit: %v", err)
+	}
+	if got := index.CheckpointsForCommit(commit); len(got) != 0 {
+		t.Fatalf("cherry-picked commit unexpectedly linked: %v", got)
+	}
+
+	metaBefore, err := repo.Reference(plumbing.NewBranchReferenceName(paths.MetadataBranchName), true)
+	if err != nil {
+		t.Fatalf("failed to read metadata branch: %v", err)
+	}
+
+	matched := FindCheckpointsByPatchID(ctx, repo, commit, index)
+	if len(matched) != 1 || matched[0] != cpID {
+		t.Fatalf("FindCheckpointsByPatchID() = %v, want [%s]", matched, cpID)
+	}
+
+	// The lookup is read-only: no link is written to the metadata branch.
+	metaAfter, err := repo.Reference(plumbing.NewBranchReferenceName(paths.MetadataBranchName), true)
+	if err != nil {
+		t.Fatalf("failed to read metadata branch: %v", err)
+	}
+	if metaAfter.Hash() != metaBefore.Hash() {
+		t.Errorf("metadata branch moved from %s to %s during a lookup", metaBefore.Hash(), metaAfter.Hash())
+	}
+
+	// An unrelated commit has no match.
+	runRewriteGit(t, dir, "commit", "-q", "--allow-empty", "-m", "Unrelated")
+	unrelated, err := repo.CommitObject(plumbing.NewHash(runRewriteGit(t, dir, "rev-parse", "HEAD")))
+	if err != nil {
+		t.Fatalf("failed to read commit: %v", err)
+	}
+	if got := FindCheckpointsByPatchID(ctx, repo, unrelated, index); len(got) != 0 {
+		t.Errorf("FindCheckpointsByPatchID(unrelated) = %v, want none", got)
+	}
+}
+
+func TestResolveCommitCheckpoints_MissingCommitIsNoMatch(t *testing.T) {
+	cpID := id.MustCheckpointID("a1b2c3d4e5f6")
+	_, repo, _ := setupRewriteTestRepo(t, cpID)
+
+	// A commit git can't diff (not in the object store) resolves to no checkpoints
+	missing := &object.Commit{Hash: plumbing.NewHash("0123456789abcdef0123456789abcdef01234567"), Message: "Not ours"}
+	cpIDs, err := ResolveCommitCheckpoints(context.Background(), repo, missing)
+	if err != nil || len(cpIDs) != 0 {
+		t.Errorf("ResolveCommitCheckpoints() = %v, %v; want no checkpoints and no error", cpIDs, err)
+	}
+}
@@ -113,6 +113,10 @@ type RewindPoint struct {
 	// Used to help users identify which session a checkpoint belongs to.
 	SessionPrompt string
 
+	// Annotations are the user's title, tags, and notes for the session (SessionID).
+	// A title, when set, identifies the session better than SessionPrompt.
+	Annotations session.Annotations
+
 	// SessionCount is the number of sessions in this checkpoint (1 for single-session).
 	// Only populated for logs-only points with multi-session checkpoints.
 	SessionCount int
@@ -141,6 +145,10 @@ type RewindPreview struct {
 	// TrackedChanges are tracked files with uncommitted changes that will be reverted.
 	// These come from the existing CanRewind() warning.
 	TrackedChanges []string
+
+	// RedactedFiles are files that will be restored with secrets redacted,
+	// because the checkpoint snapshot stored them that way.
+	RedactedFiles []string
 }
 
 // SaveContext contains all information needed for saving changes.
@@ -507,8 +515,8 @@ type SessionResetter interface {
 }
 
 // SessionCondenser is an optional interface for strategies that support
-// force-condensing a session. This is used by "entire doctor" to
-// salvage stuck sessions by condensing their data to permanent storage.
+// force-condensing a session. This is used by "entire doctor" and the idle
+// timeout to salvage stuck sessions by condensing their data to permanent storage.
 type SessionCondenser interface {
 	// CondenseSessionByID force-condenses a session and cleans up.
 	// Generates a new checkpoint ID, condenses to entire/checkpoints/v1,
@@ -0,0 +1,184 @@
+package summarize
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"unicode/utf8"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/stringutil"
+)
+
+// DefaultChunkTokens bounds the estimated size of one summarization window.
+// It leaves room for the prompt and the response on 200K-context models.
+const DefaultChunkTokens = 150_000
+
+// minChunkTokens keeps windows useful when a tiny chunk size is configured.
+const minChunkTokens = 1_000
+
+// charsPerToken is the rough characters-per-token ratio used to estimate
+// transcript size without a tokenizer.
+const charsPerToken = 4
+
+// EstimateTokens returns a rough token count for s.
+func EstimateTokens(s string) int {
+	return (len(s) + charsPerToken - 1) / charsPerToken
+}
+
+// SplitTranscript splits entries into consecutive windows whose estimated size
+// stays within maxTokens. An entry larger than a whole window is truncated so
+// that it fits in a window of its own.
+func SplitTranscript(entries []Entry, maxTokens int) [][]Entry {
+	maxTokens = max(maxTokens, minChunkTokens)
+
+	var windows [][]Entry
+	var current []Entry
+	currentTokens := 0
+	for _, entry := range entries {
+		tokens := entryTokens(entry)
+		if tokens > maxTokens {
+			entry = truncateEntry(entry, maxTokens)
+			tokens = entryTokens(entry)
+		}
+		if len(current) > 0 && currentTokens+tokens > maxTokens {
+			windows = append(windows, current)
+			current, currentTokens = nil, 0
+		}
+		current = append(current, entry)
+		currentTokens += tokens
+	}
+	if len(current) > 0 {
+		windows = append(windows, current)
+	}
+	return windows
+}
+
+// MergeSummaries combines the summaries of consecutive transcript windows.
+// The intent comes from the first window that states one (where the session's
+// goal is set) and the outcome from the last (where it ended up). Learnings,
+// friction and open items are concatenated in order with duplicates removed.
+// Extension lists are combined the same way; other extension values take the
+// last window's non-empty value.
+func MergeSummaries(parts []*checkpoint.Summary) *checkpoint.Summary {
+	// Non-nil lists keep the JSON shape of a single generated summary.
+	merged := &checkpoint.Summary{
+		Learnings: checkpoint.LearningsSummary{Repo: []string{}, Code: []checkpoint.CodeLearning{}, Workflow: []string{}},
+		Friction:  []string{},
+		OpenItems: []string{},
+	}
+	for _, part := range parts {
+		if part == nil {
+			continue
+		}
+		if merged.Intent == "" {
+			merged.Intent = part.Intent
+		}
+		if part.Outcome != "" {
+			merged.Outcome = part.Outcome
+		}
+		merged.Learnings.Repo = appendUnique(merged.Learnings.Repo, part.Learnings.Repo...)
+		merged.Learnings.Code = appendUnique(merged.Learnings.Code, part.Learnings.Code...)
+		merged.Learnings.Workflow = appendUnique(merged.Learnings.Workflow, part.Learnings.Workflow...)
+		merged.Friction = appendUnique(merged.Friction, part.Friction...)
+		merged.OpenItems = appendUnique(merged.OpenItems, part.OpenItems...)
+		merged.Extensions = mergeExtensions(merged.Extensions, part.Extensions)
+	}
+	return merged
+}
+
+// mergeExtensions merges a window's extension fields into dst.
+func mergeExtensions(dst, src map[string]any) map[string]any {
+	for key, value := range src {
+		if dst == nil {
+			dst = make(map[string]any)
+		}
+		switch v := value.(type) {
+		case []any:
+			existing, _ := dst[key].([]any) //nolint:errcheck // Missing or mismatched values start a new list
+			dst[key] = appendUniqueStrings(existing, v...)
+		case string:
+			if v != "" || dst[key] == nil {
+				dst[key] = v
+			}
+		default:
+			if v != nil {
+				dst[key] = v
+			}
+		}
+	}
+	return dst
+}
+
+// chunkingGenerator summarizes transcripts too large for one prompt by
+// summarizing token-bounded windows separately and merging the results.
+type chunkingGenerator struct {
+	Generator
+
+	MaxTokens int
+}
+
+// Generate summarizes the transcript in one call if it fits, or window by window otherwise.
+func (g *chunkingGenerator) Generate(ctx context.Context, input Input) (*checkpoint.Summary, error) {
+	// Every window carries the files list, so it counts against each window's budget.
+	budget := g.MaxTokens - EstimateTokens(FormatCondensedTranscript(Input{FilesTouched: input.FilesTouched}))
+	windows := SplitTranscript(input.Transcript, budget)
+	if len(windows) <= 1 {
+		return g.Generator.Generate(ctx, input) //nolint:wrapcheck // passthrough wrapper
+	}
+
+	parts := make([]*checkpoint.Summary, 0, len(windows))
+	for i, window := range windows {
+		part, err := g.Generator.Generate(ctx, Input{Transcript: window, FilesTouched: input.FilesTouched})
+		if err != nil {
+			return nil, fmt.Errorf("failed to summarize part %d of %d: %w", i+1, len(windows), err)
+		}
+		parts = append(parts, part)
+	}
+	return MergeSummaries(parts), nil
+}
+
+// entryTokens estimates the tokens an entry takes up in the formatted transcript.
+func entryTokens(entry Entry) int {
+	return EstimateTokens(FormatCondensedTranscript(Input{Transcript: []Entry{entry}}))
+}
+
+// truncateEntry shortens an entry's text so it fits within maxTokens.
+func truncateEntry(entry Entry, maxTokens int) Entry {
+	// Leave room for the entry's label and tool name.
+	maxChars := max(0, maxTokens*charsPerToken-len(entry.ToolName)-32)
+
+	// Content and tool detail share the budget. Each is entitled to half, and
+	// whatever one doesn't need goes to the other.
+	contentLen := utf8.RuneCountInString(entry.Content)
+	detailLen := utf8.RuneCountInString(entry.ToolDetail)
+	if contentLen+detailLen <= maxChars {
+		return entry
+	}
+	detailChars := min(detailLen, max(maxChars/2, maxChars-contentLen))
+	entry.Content = stringutil.TruncateRunes(entry.Content, maxChars-detailChars, "...")
+	entry.ToolDetail = stringutil.TruncateRunes(entry.ToolDetail, detailChars, "...")
+	return entry
+}
+
+// appendUniqueStrings is appendUnique for extension lists, which template
+// normalization leaves as []any of strings. Other values are dropped, since
+// they may not be comparable.
+func appendUniqueStrings(dst []any, values ...any) []any {
+	for _, v := range values {
+		if s, ok := v.(string); ok && !slices.Contains(dst, any(s)) {
+			dst = append(dst, s)
+		}
+	}
+	return dst
+}
+
+// appendUnique appends the values not already in dst, preserving order.
+func appendUnique[T comparable](dst []T, values ...T) []T {
+	for _, v := range values {
+		if !slices.Contains(dst, v) {
+			dst = append(dst, v)
+		}
+	}
+	return dst
+}
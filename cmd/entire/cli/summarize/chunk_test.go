@@ -0,0 +1,217 @@
+package summarize
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+)
+
+func TestSplitTranscript(t *testing.T) {
+	t.Parallel()
+
+	// Each entry is just under 500 tokens, so two fit in a 1000-token window.
+	text := strings.Repeat("x", 1980)
+	entries := []Entry{
+		{Type: EntryTypeUser, Content: text},
+		{Type: EntryTypeAssistant, Content: text},
+		{Type: EntryTypeTool, ToolName: "Edit", ToolDetail: text},
+	}
+
+	windows := SplitTranscript(entries, 1_000)
+	if len(windows) != 2 || len(windows[0]) != 2 || len(windows[1]) != 1 {
+		t.Fatalf("windows = %d, want [2 1] entries", len(windows))
+	}
+
+	if got := SplitTranscript(entries, 100_000); len(got) != 1 {
+		t.Errorf("small transcript split into %d windows, want 1", len(got))
+	}
+	if got := SplitTranscript(nil, 1_000); len(got) != 0 {
+		t.Errorf("empty transcript split into %d windows, want 0", len(got))
+	}
+}
+
+func TestSplitTranscript_TruncatesOversizedEntry(t *testing.T) {
+	t.Parallel()
+
+	entries := []Entry{
+		{Type: EntryTypeUser, Content: strings.Repeat("short ", 20)},
+		{Type: EntryTypeAssistant, Content: strings.Repeat("y", 50_000)},
+	}
+	windows := SplitTranscript(entries, 1_000)
+	if len(windows) != 2 {
+		t.Fatalf("windows = %d, want 2", len(windows))
+	}
+	big := windows[1][0]
+	if entryTokens(big) > 1_000 || !strings.HasSuffix(big.Content, "...") {
+		t.Errorf("oversized entry not truncated: %d tokens", entryTokens(big))
+	}
+}
+
+func TestTruncateEntry_SharesBudget(t *testing.T) {
+	t.Parallel()
+
+	entry := Entry{
+		Type:       EntryTypeTool,
+		ToolName:   "Bash",
+		Content:    strings.Repeat("c", 20_000),
+		ToolDetail: strings.Repeat("d", 20_000),
+	}
+	got := truncateEntry(entry, 1_000)
+	if entryTokens(got) > 1_000 {
+		t.Errorf("truncated entry is %d tokens, want at most 1000", entryTokens(got))
+	}
+	if len(got.Content) != len(got.ToolDetail) {
+		t.Errorf("content %d chars, detail %d chars; want an even split", len(got.Content), len(got.ToolDetail))
+	}
+
+	// A short detail leaves the rest of the budget to the content.
+	entry.ToolDetail = "ls"
+	got = truncateEntry(entry, 1_000)
+	if got.ToolDetail != "ls" || len(got.Content) <= 2_000 {
+		t.Errorf("content %d chars, detail %q; want the content to use the unused budget", len(got.Content), got.ToolDetail)
+	}
+}
+
+func TestMergeSummaries(t *testing.T) {
+	t.Parallel()
+
+	first := &checkpoint.Summary{
+		Intent:  "Add OAuth login",
+		Outcome: "Provider wired up",
+		Learnings: checkpoint.LearningsSummary{
+			Repo: []string{"Config lives in config/"},
+			Code: []checkpoint.CodeLearning{{Path: "auth.go", Line: 10, Finding: "Tokens are cached"}},
+		},
+		Friction:  []string{"Flaky test"},
+		OpenItems: []string{"Add refresh tokens"},
+	}
+	second := &checkpoint.Summary{
+		Intent:  "Continue OAuth work",
+		Outcome: "Login works end to end",
+		Learnings: checkpoint.LearningsSummary{
+			Repo:     []string{"Config lives in config/", "Use make test"},
+			Code:     []checkpoint.CodeLearning{{Path: "auth.go", Line: 10, Finding: "Tokens are cached"}},
+			Workflow: []string{"Run the e2e suite"},
+		},
+		OpenItems: []string{"Add refresh tokens", "Document scopes"},
+	}
+
+	merged := MergeSummaries([]*checkpoint.Summary{first, nil, second})
+	if merged.Intent != "Add OAuth login" {
+		t.Errorf("Intent = %q, want the first window's", merged.Intent)
+	}
+	if merged.Outcome != "Login works end to end" {
+		t.Errorf("Outcome = %q, want the last window's", merged.Outcome)
+	}
+	if len(merged.Learnings.Repo) != 2 || len(merged.Learnings.Code) != 1 || len(merged.Learnings.Workflow) != 1 {
+		t.Errorf("Learnings = %+v, want duplicates removed", merged.Learnings)
+	}
+	if len(merged.Friction) != 1 || len(merged.OpenItems) != 2 || merged.OpenItems[1] != "Document scopes" {
+		t.Errorf("Friction = %v, OpenItems = %v", merged.Friction, merged.OpenItems)
+	}
+
+	if empty := MergeSummaries(nil); empty.Friction == nil || empty.Learnings.Code == nil {
+		t.Error("merged lists should be non-nil")
+	}
+}
+
+func TestMergeSummaries_Extensions(t *testing.T) {
+	t.Parallel()
+
+	first := &checkpoint.Summary{Extensions: map[string]any{
+		"risk":      "High",
+		"test_gaps": []any{"No timeout test"},
+	}}
+	second := &checkpoint.Summary{Extensions: map[string]any{
+		"risk":      "",
+		"test_gaps": []any{"No timeout test", "No retry test"},
+	}}
+
+	merged := MergeSummaries([]*checkpoint.Summary{first, second})
+	if merged.Extensions["risk"] != "High" {
+		t.Errorf("risk = %v, want the last non-empty value", merged.Extensions["risk"])
+	}
+	gaps, ok := merged.Extensions["test_gaps"].([]any)
+	if !ok || len(gaps) != 2 || gaps[1] != "No retry test" {
+		t.Errorf("test_gaps = %v, want lists combined without duplicates", merged.Extensions["test_gaps"])
+	}
+
+	// Lists from normalized templates only hold strings; anything else is dropped.
+	odd := MergeSummaries([]*checkpoint.Summary{{Extensions: map[string]any{"test_gaps": []any{map[string]any{"a": "b"}, "gap"}}}})
+	if gaps, ok := odd.Extensions["test_gaps"].([]any); !ok || len(gaps) != 1 || gaps[0] != "gap" {
+		t.Errorf("test_gaps = %v, want only the string item", odd.Extensions["test_gaps"])
+	}
+
+	if plain := MergeSummaries([]*checkpoint.Summary{{Intent: "x"}}); plain.Extensions != nil {
+		t.Errorf("Extensions = %v, want nil when no window has extensions", plain.Extensions)
+	}
+}
+
+// recordingGenerator returns one summary per call, recording the inputs it saw.
+type recordingGenerator struct {
+	inputs []Input
+	err    error
+}
+
+func (g *recordingGenerator) Generate(_ context.Context, input Input) (*checkpoint.Summary, error) {
+	g.inputs = append(g.inputs, input)
+	if g.err != nil {
+		return nil, g.err
+	}
+	n := len(g.inputs)
+	return &checkpoint.Summary{
+		Intent:    "intent " + string(rune('0'+n)),
+		Outcome:   "outcome " + string(rune('0'+n)),
+		OpenItems: []string{"item " + string(rune('0'+n))},
+	}, nil
+}
+
+func TestChunkingGenerator(t *testing.T) {
+	t.Parallel()
+
+	text := strings.Repeat("z", 1990)
+	input := Input{
+		Transcript: []Entry{
+			{Type: EntryTypeUser, Content: text},
+			{Type: EntryTypeAssistant, Content: text},
+			{Type: EntryTypeAssistant, Content: text},
+		},
+		FilesTouched: []string{"main.go"},
+	}
+
+	inner := &recordingGenerator{}
+	summary, err := (&chunkingGenerator{Generator: inner, MaxTokens: 1_100}).Generate(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if len(inner.inputs) != 2 {
+		t.Fatalf("generator called %d times, want 2 windows", len(inner.inputs))
+	}
+	for _, in := range inner.inputs {
+		if len(in.FilesTouched) != 1 {
+			t.Errorf("window missing files list: %+v", in.FilesTouched)
+		}
+	}
+	if summary.Intent != "intent 1" || summary.Outcome != "outcome 2" || len(summary.OpenItems) != 2 {
+		t.Errorf("summary = %+v", summary)
+	}
+
+	// A transcript that fits is passed through in a single call.
+	inner = &recordingGenerator{}
+	if _, err := (&chunkingGenerator{Generator: inner, MaxTokens: DefaultChunkTokens}).Generate(context.Background(), input); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if len(inner.inputs) != 1 || len(inner.inputs[0].Transcript) != 3 {
+		t.Errorf("small transcript should be summarized in one call, got %d calls", len(inner.inputs))
+	}
+
+	// A failed window fails the whole summary.
+	failing := &recordingGenerator{err: errors.New("rate limited")}
+	_, err = (&chunkingGenerator{Generator: failing, MaxTokens: 1_100}).Generate(context.Background(), input)
+	if err == nil || !strings.Contains(err.Error(), "part 1 of 2") {
+		t.Errorf("error = %v, want window context", err)
+	}
+}
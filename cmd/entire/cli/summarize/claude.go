@@ -13,39 +13,6 @@ import (
 	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
 )
 
-// summarizationPromptTemplate is the prompt used to generate summaries via the Claude CLI.
-//
-// Security note: The transcript is wrapped in <transcript> tags to provide clear boundary
-// markers. This helps contain any potentially malicious content within the transcript
-// (e.g., prompt injection attempts in user messages or file contents) by giving the LLM
-// a clear structural signal about where the untrusted content begins and ends.
-const summarizationPromptTemplate = `Analyze this development session transcript and generate a structured summary.
-
-<transcript>
-%s
-</transcript>
-
-Return a JSON object with this exact structure:
-{
-  "intent": "What the user was trying to accomplish (1-2 sentences)",
-  "outcome": "What was actually achieved (1-2 sentences)",
-  "learnings": {
-    "repo": ["Codebase-specific patterns, conventions, or gotchas discovered"],
-    "code": [{"path": "file/path.go", "line": 42, "end_line": 56, "finding": "What was learned"}],
-    "workflow": ["General development practices or tool usage insights"]
-  },
-  "friction": ["Problems, blockers, or annoyances encountered"],
-  "open_items": ["Tech debt, unfinished work, or things to revisit later"]
-}
-
-Guidelines:
-- Be concise but specific
-- Include line numbers for code learnings when the transcript references specific lines
-- Friction should capture both blockers and minor annoyances
-- Open items are things intentionally deferred, not failures
-- Empty arrays are fine if a category doesn't apply
-- Return ONLY the JSON object, no markdown formatting or explanation`
-
 // DefaultModel is the default model used for summarization.
 // Sonnet provides a good balance of quality and cost, with 1M context window
 // to handle long transcripts without truncation.
@@ -61,6 +28,10 @@ type ClaudeGenerator struct {
 	// If empty, defaults to DefaultModel ("sonnet").
 	Model string
 
+	// Template customizes the prompt and adds extension fields.
+	// If nil, the built-in prompt is used.
+	Template *Template
+
 	// CommandRunner allows injection of the command execution for testing.
 	// If nil, uses exec.CommandContext directly.
 	CommandRunner func(ctx context.Context, name string, args ...string) *exec.Cmd
@@ -77,7 +48,10 @@ func (g *ClaudeGenerator) Generate(ctx context.Context, input Input) (*checkpoin
 	transcriptText := FormatCondensedTranscript(input)
 
 	// Build the prompt
-	prompt := buildSummarizationPrompt(transcriptText)
+	prompt, err := g.Template.BuildPrompt(transcriptText)
+	if err != nil {
+		return nil, err
+	}
 
 	// Execute the Claude CLI
 	runner := g.CommandRunner
@@ -116,7 +90,7 @@ func (g *ClaudeGenerator) Generate(ctx context.Context, input Input) (*checkpoin
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	err := cmd.Run()
+	err = cmd.Run()
 	if err != nil {
 		// Check if the command was not found
 		var execErr *exec.Error
@@ -140,23 +114,7 @@ func (g *ClaudeGenerator) Generate(ctx context.Context, input Input) (*checkpoin
 	}
 
 	// The result field contains the actual JSON summary
-	resultJSON := cliResponse.Result
-
-	// Try to extract JSON if it's wrapped in markdown code blocks
-	resultJSON = extractJSONFromMarkdown(resultJSON)
-
-	// Parse the summary from the result
-	var summary checkpoint.Summary
-	if err := json.Unmarshal([]byte(resultJSON), &summary); err != nil {
-		return nil, fmt.Errorf("failed to parse summary JSON: %w (response: %s)", err, resultJSON)
-	}
-
-	return &summary, nil
-}
-
-// buildSummarizationPrompt creates the prompt for the Claude CLI.
-func buildSummarizationPrompt(transcriptText string) string {
-	return fmt.Sprintf(summarizationPromptTemplate, transcriptText)
+	return parseSummaryResult(cliResponse.Result, g.Template)
 }
 
 // stripGitEnv returns a copy of env with all GIT_* variables removed.
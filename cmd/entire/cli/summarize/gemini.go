@@ -0,0 +1,98 @@
+package summarize
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+)
+
+// DefaultGeminiModel is the default model used for summarization via the Gemini CLI.
+const DefaultGeminiModel = "gemini-2.5-flash"
+
+// GeminiGenerator generates summaries using the Gemini CLI.
+type GeminiGenerator struct {
+	// GeminiPath is the path to the gemini CLI executable.
+	// If empty, defaults to "gemini" (expects it to be in PATH).
+	GeminiPath string
+
+	// Model is the Gemini model to use for summarization.
+	// If empty, defaults to DefaultGeminiModel.
+	Model string
+
+	// Template customizes the prompt and adds extension fields.
+	// If nil, the built-in prompt is used.
+	Template *Template
+
+	// CommandRunner allows injection of the command execution for testing.
+	// If nil, uses exec.CommandContext directly.
+	CommandRunner func(ctx context.Context, name string, args ...string) *exec.Cmd
+}
+
+// geminiCLIResponse represents the JSON response from `gemini --output-format json`.
+type geminiCLIResponse struct {
+	Response string `json:"response"`
+}
+
+// Generate creates a summary from checkpoint data by calling the Gemini CLI.
+func (g *GeminiGenerator) Generate(ctx context.Context, input Input) (*checkpoint.Summary, error) {
+	prompt, err := g.Template.BuildPrompt(FormatCondensedTranscript(input))
+	if err != nil {
+		return nil, err
+	}
+
+	runner := g.CommandRunner
+	if runner == nil {
+		runner = exec.CommandContext
+	}
+
+	geminiPath := g.GeminiPath
+	if geminiPath == "" {
+		geminiPath = "gemini"
+	}
+
+	model := g.Model
+	if model == "" {
+		model = DefaultGeminiModel
+	}
+
+	// The prompt is read from stdin; non-interactive mode is implied when stdin isn't a TTY.
+	cmd := runner(ctx, geminiPath, "--model", model, "--output-format", "json")
+
+	// Isolate the subprocess from the user's git repo, as for the Claude CLI:
+	// Gemini CLI hooks would otherwise fire Entire's own hooks recursively.
+	cmd.Dir = os.TempDir()
+	cmd.Env = stripGitEnv(os.Environ())
+	cmd.Stdin = strings.NewReader(prompt)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		var execErr *exec.Error
+		if errors.As(err, &execErr) {
+			return nil, fmt.Errorf("gemini CLI not found: %w", err)
+		}
+
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil, fmt.Errorf("gemini CLI failed (exit %d): %s", exitErr.ExitCode(), stderr.String())
+		}
+
+		return nil, fmt.Errorf("failed to run gemini CLI: %w", err)
+	}
+
+	var cliResponse geminiCLIResponse
+	if err := json.Unmarshal(stdout.Bytes(), &cliResponse); err != nil {
+		return nil, fmt.Errorf("failed to parse gemini CLI response: %w", err)
+	}
+
+	return parseSummaryResult(cliResponse.Response, g.Template)
+}
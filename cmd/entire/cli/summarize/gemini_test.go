@@ -0,0 +1,66 @@
+package summarize
+
+import (
+	"context"
+	"os/exec"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestGeminiGenerator_Generate(t *testing.T) {
+	var gotName string
+	var gotArgs []string
+
+	// The summary JSON is wrapped in a markdown block inside the CLI's JSON response.
+	response := `{"response":"` + "```json\\n" + `{\"intent\":\"Add login\",\"outcome\":\"Done\",\"learnings\":{\"repo\":[],\"code\":[],\"workflow\":[]},\"friction\":[],\"open_items\":[]}` + "\\n```" + `","stats":{}}`
+
+	gen := &GeminiGenerator{
+		Model: "gemini-2.5-pro",
+		CommandRunner: func(ctx context.Context, name string, args ...string) *exec.Cmd {
+			gotName = name
+			gotArgs = args
+			return exec.CommandContext(ctx, "sh", "-c", "cat >/dev/null; printf '%s' '"+response+"'")
+		},
+	}
+	t.Setenv("GIT_DIR", "/some/repo/.git")
+
+	summary, err := gen.Generate(context.Background(), Input{Transcript: []Entry{{Type: EntryTypeUser, Content: "Add login"}}})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if summary.Intent != "Add login" || summary.Outcome != "Done" {
+		t.Errorf("summary = %+v", summary)
+	}
+	if gotName != "gemini" {
+		t.Errorf("command = %q, want gemini", gotName)
+	}
+	if !slices.Equal(gotArgs, []string{"--model", "gemini-2.5-pro", "--output-format", "json"}) {
+		t.Errorf("args = %v", gotArgs)
+	}
+}
+
+func TestGeminiGenerator_CommandFailure(t *testing.T) {
+	gen := &GeminiGenerator{
+		CommandRunner: func(ctx context.Context, _ string, _ ...string) *exec.Cmd {
+			return exec.CommandContext(ctx, "sh", "-c", "echo 'quota exceeded' >&2; exit 3")
+		},
+	}
+
+	_, err := gen.Generate(context.Background(), Input{})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(err.Error(), "exit 3") || !strings.Contains(err.Error(), "quota exceeded") {
+		t.Errorf("error = %v, want exit code and stderr", err)
+	}
+}
+
+func TestGeminiGenerator_CommandNotFound(t *testing.T) {
+	gen := &GeminiGenerator{GeminiPath: "entire-test-nonexistent-gemini"}
+
+	_, err := gen.Generate(context.Background(), Input{})
+	if err == nil || !strings.Contains(err.Error(), "gemini CLI not found") {
+		t.Errorf("error = %v, want not found", err)
+	}
+}
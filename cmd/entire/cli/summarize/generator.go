@@ -0,0 +1,78 @@
+package summarize
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/settings"
+)
+
+// DefaultAPIKeyEnv is the environment variable read for the OpenAI-compatible
+// provider's API key when api_key_env isn't configured.
+const DefaultAPIKeyEnv = "OPENAI_API_KEY"
+
+// NewGenerator builds the summary generator selected in strategy_options.summarize.
+// tmpl customizes the prompt and fields; nil uses the built-in prompt.
+func NewGenerator(opts settings.SummarizeOptions, tmpl *Template) Generator {
+	var generator Generator
+	switch opts.Provider {
+	case settings.SummarizeProviderGemini:
+		generator = &GeminiGenerator{Model: opts.Model, Template: tmpl}
+	case settings.SummarizeProviderOpenAI:
+		keyEnv := opts.APIKeyEnv
+		if keyEnv == "" {
+			keyEnv = DefaultAPIKeyEnv
+		}
+		generator = &OpenAIGenerator{
+			Endpoint: opts.Endpoint,
+			Model:    opts.Model,
+			APIKey:   os.Getenv(keyEnv),
+			Template: tmpl,
+		}
+	default:
+		generator = &ClaudeGenerator{Model: opts.Model, Template: tmpl}
+	}
+
+	// The timeout applies per call, so each window of a long transcript gets the full budget.
+	if opts.Timeout > 0 {
+		generator = &timeoutGenerator{Generator: generator, Timeout: opts.Timeout}
+	}
+
+	chunkTokens := opts.ChunkTokens
+	if chunkTokens == 0 {
+		chunkTokens = DefaultChunkTokens
+	}
+	return &chunkingGenerator{Generator: generator, MaxTokens: chunkTokens}
+}
+
+// ConfiguredGenerator returns the generator configured in settings, using the
+// repository's summary template if it has one. An invalid configuration or
+// template is returned as an error so callers can surface it rather than
+// silently falling back to the defaults.
+func ConfiguredGenerator() (Generator, error) {
+	opts, err := settings.GetSummarizeOptions()
+	if err != nil {
+		return nil, err //nolint:wrapcheck // settings errors are already descriptive
+	}
+	tmpl, err := LoadTemplate()
+	if err != nil {
+		return nil, err
+	}
+	return NewGenerator(opts, tmpl), nil
+}
+
+// timeoutGenerator bounds each generation call of the wrapped generator.
+type timeoutGenerator struct {
+	Generator
+
+	Timeout time.Duration
+}
+
+// Generate calls the wrapped generator with a deadline.
+func (g *timeoutGenerator) Generate(ctx context.Context, input Input) (*checkpoint.Summary, error) {
+	ctx, cancel := context.WithTimeout(ctx, g.Timeout)
+	defer cancel()
+	return g.Generator.Generate(ctx, input) //nolint:wrapcheck // passthrough wrapper
+}
@@ -0,0 +1,79 @@
+package summarize
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/settings"
+)
+
+// unwrapGenerator returns the provider generator inside the chunking and timeout wrappers.
+func unwrapGenerator(t *testing.T, g Generator) Generator {
+	t.Helper()
+	chunking, ok := g.(*chunkingGenerator)
+	if !ok {
+		t.Fatalf("generator %T is not wrapped for chunking", g)
+	}
+	if timeout, ok := chunking.Generator.(*timeoutGenerator); ok {
+		return timeout.Generator
+	}
+	return chunking.Generator
+}
+
+func TestNewGenerator(t *testing.T) {
+	t.Setenv("LOCAL_LLM_KEY", "secret")
+
+	if g, ok := unwrapGenerator(t, NewGenerator(settings.SummarizeOptions{Provider: settings.SummarizeProviderClaude, Model: "opus"}, nil)).(*ClaudeGenerator); !ok || g.Model != "opus" {
+		t.Errorf("claude provider = %#v", g)
+	}
+	if g, ok := unwrapGenerator(t, NewGenerator(settings.SummarizeOptions{Provider: settings.SummarizeProviderGemini}, nil)).(*GeminiGenerator); !ok || g.Model != "" {
+		t.Errorf("gemini provider = %#v", g)
+	}
+
+	g, ok := unwrapGenerator(t, NewGenerator(settings.SummarizeOptions{
+		Provider:  settings.SummarizeProviderOpenAI,
+		Model:     "llama3.1",
+		Endpoint:  "http://localhost:11434/v1",
+		APIKeyEnv: "LOCAL_LLM_KEY",
+	}, nil)).(*OpenAIGenerator)
+	if !ok || g.Endpoint != "http://localhost:11434/v1" || g.Model != "llama3.1" || g.APIKey != "secret" {
+		t.Errorf("openai provider = %#v", g)
+	}
+
+	chunking, ok := NewGenerator(settings.SummarizeOptions{Timeout: time.Minute, ChunkTokens: 8_000}, nil).(*chunkingGenerator)
+	if !ok || chunking.MaxTokens != 8_000 {
+		t.Fatalf("generator = %#v, want chunking with 8000 tokens", chunking)
+	}
+	if _, ok := chunking.Generator.(*timeoutGenerator); !ok {
+		t.Error("timeout should wrap each call inside the chunking generator")
+	}
+	if chunking, ok := NewGenerator(settings.SummarizeOptions{}, nil).(*chunkingGenerator); !ok || chunking.MaxTokens != DefaultChunkTokens {
+		t.Errorf("default chunk size = %#v", chunking)
+	}
+}
+
+type deadlineGenerator struct {
+	hadDeadline bool
+}
+
+func (g *deadlineGenerator) Generate(ctx context.Context, _ Input) (*checkpoint.Summary, error) {
+	_, g.hadDeadline = ctx.Deadline()
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestTimeoutGenerator(t *testing.T) {
+	t.Parallel()
+
+	inner := &deadlineGenerator{}
+	_, err := (&timeoutGenerator{Generator: inner, Timeout: 10 * time.Millisecond}).Generate(context.Background(), Input{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error = %v, want deadline exceeded", err)
+	}
+	if !inner.hadDeadline {
+		t.Error("wrapped generator should see a deadline")
+	}
+}
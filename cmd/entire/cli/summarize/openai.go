@@ -0,0 +1,120 @@
+package summarize
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+)
+
+// DefaultOpenAIEndpoint is the base URL used when no endpoint is configured.
+const DefaultOpenAIEndpoint = "https://api.openai.com/v1"
+
+// maxOpenAIErrorBody bounds how much of an error response is included in errors.
+const maxOpenAIErrorBody = 512
+
+// OpenAIGenerator generates summaries using any OpenAI-compatible chat completions
+// API, including local servers such as Ollama and llama.cpp.
+type OpenAIGenerator struct {
+	// Endpoint is the API base URL (e.g. "http://localhost:11434/v1").
+	// If empty, defaults to DefaultOpenAIEndpoint.
+	Endpoint string
+
+	// Model is the model name passed to the endpoint. Required.
+	Model string
+
+	// APIKey is sent as a bearer token. Local servers usually don't need one.
+	APIKey string
+
+	// Template customizes the prompt and adds extension fields.
+	// If nil, the built-in prompt is used.
+	Template *Template
+
+	// HTTPClient allows injection of the HTTP client for testing.
+	// If nil, uses http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+type openAIMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// openAIChatRequest leaves sampling parameters such as temperature unset so
+// the server's defaults apply; some reasoning models reject non-default values.
+type openAIChatRequest struct {
+	Model    string          `json:"model"`
+	Messages []openAIMessage `json:"messages"`
+}
+
+type openAIChatResponse struct {
+	Choices []struct {
+		Message openAIMessage `json:"message"`
+	} `json:"choices"`
+}
+
+// Generate creates a summary from checkpoint data by calling the chat completions endpoint.
+func (g *OpenAIGenerator) Generate(ctx context.Context, input Input) (*checkpoint.Summary, error) {
+	if g.Model == "" {
+		return nil, errors.New("no model configured for the OpenAI-compatible summary generator")
+	}
+
+	endpoint := g.Endpoint
+	if endpoint == "" {
+		endpoint = DefaultOpenAIEndpoint
+	}
+	url := strings.TrimSuffix(endpoint, "/") + "/chat/completions"
+
+	prompt, err := g.Template.BuildPrompt(FormatCondensedTranscript(input))
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(openAIChatRequest{
+		Model:    g.Model,
+		Messages: []openAIMessage{{Role: "user", Content: prompt}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if g.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+g.APIKey)
+	}
+
+	client := g.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, maxOpenAIErrorBody)) //nolint:errcheck // best-effort error detail
+		return nil, fmt.Errorf("summary endpoint returned %s: %s", resp.Status, strings.TrimSpace(string(snippet)))
+	}
+
+	var chatResp openAIChatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&chatResp); err != nil {
+		return nil, fmt.Errorf("failed to parse chat completions response: %w", err)
+	}
+	if len(chatResp.Choices) == 0 {
+		return nil, errors.New("chat completions response has no choices")
+	}
+
+	return parseSummaryResult(chatResp.Choices[0].Message.Content, g.Template)
+}
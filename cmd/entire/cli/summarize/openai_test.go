@@ -0,0 +1,89 @@
+package summarize
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestOpenAIGenerator_Generate(t *testing.T) {
+	var gotReq openAIChatRequest
+	var gotFields map[string]json.RawMessage
+	var gotAuth, gotPath string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotAuth = r.Header.Get("Authorization")
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("failed to read request body: %v", err)
+		}
+		if err := json.Unmarshal(body, &gotReq); err != nil {
+			t.Errorf("invalid request body: %v", err)
+		}
+		if err := json.Unmarshal(body, &gotFields); err != nil {
+			t.Errorf("invalid request body: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"{\"intent\":\"Fix bug\",\"outcome\":\"Fixed\",\"learnings\":{\"repo\":[],\"code\":[],\"workflow\":[]},\"friction\":[],\"open_items\":[]}"}}]}`))
+	}))
+	defer server.Close()
+
+	gen := &OpenAIGenerator{Endpoint: server.URL + "/v1/", Model: "llama3.1", APIKey: "sk-test"}
+	summary, err := gen.Generate(context.Background(), Input{Transcript: []Entry{{Type: EntryTypeUser, Content: "Fix the bug"}}})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if summary.Intent != "Fix bug" {
+		t.Errorf("summary = %+v", summary)
+	}
+
+	if gotPath != "/v1/chat/completions" {
+		t.Errorf("path = %q", gotPath)
+	}
+	if gotAuth != "Bearer sk-test" {
+		t.Errorf("Authorization = %q", gotAuth)
+	}
+	if gotReq.Model != "llama3.1" || len(gotReq.Messages) != 1 || !strings.Contains(gotReq.Messages[0].Content, "Fix the bug") {
+		t.Errorf("request = %+v", gotReq)
+	}
+	if _, ok := gotFields["temperature"]; ok {
+		t.Errorf("request sets temperature, want the server default: %v", gotFields)
+	}
+}
+
+func TestOpenAIGenerator_NoAPIKey(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if auth := r.Header.Get("Authorization"); auth != "" {
+			t.Errorf("unexpected Authorization header %q", auth)
+		}
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"content":"{\"intent\":\"x\",\"outcome\":\"y\"}"}}]}`))
+	}))
+	defer server.Close()
+
+	gen := &OpenAIGenerator{Endpoint: server.URL, Model: "qwen"}
+	if _, err := gen.Generate(context.Background(), Input{}); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+}
+
+func TestOpenAIGenerator_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "model not found", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	_, err := (&OpenAIGenerator{Endpoint: server.URL, Model: "missing"}).Generate(context.Background(), Input{})
+	if err == nil || !strings.Contains(err.Error(), "404") || !strings.Contains(err.Error(), "model not found") {
+		t.Errorf("error = %v, want status and body", err)
+	}
+
+	_, err = (&OpenAIGenerator{Endpoint: server.URL}).Generate(context.Background(), Input{})
+	if err == nil || !strings.Contains(err.Error(), "no model configured") {
+		t.Errorf("error = %v, want missing model error", err)
+	}
+}
@@ -0,0 +1,225 @@
+package summarize
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/jsonutil"
+	"github.com/entireio/cli/cmd/entire/cli/validation"
+)
+
+// QueueDirName is the directory under the git common dir holding queued summary jobs.
+const QueueDirName = "entire-summaries"
+
+// MaxAttempts is how many times the background worker tries a job before
+// leaving it for `entire summarize --pending`.
+const MaxAttempts = 3
+
+// retryBackoff is the delay before a failed job is retried, multiplied by the
+// number of attempts so far.
+const retryBackoff = time.Minute
+
+// workerLockName is the lock file that keeps a single worker draining the queue.
+const workerLockName = "worker.lock"
+
+// staleLockAge is how old a worker lock may get before it's assumed abandoned.
+const staleLockAge = 30 * time.Minute
+
+// ErrWorkerRunning is returned by Queue.Lock when another worker holds the lock.
+var ErrWorkerRunning = errors.New("a summary worker is already running")
+
+// Job is a summary waiting to be generated for a session of a committed checkpoint.
+type Job struct {
+	CheckpointID id.CheckpointID `json:"checkpoint_id"`
+	SessionID    string          `json:"session_id"`
+	EnqueuedAt   time.Time       `json:"enqueued_at"`
+
+	// Attempts is how many times generation has failed for this job.
+	Attempts int `json:"attempts,omitempty"`
+
+	// LastError is the error from the most recent failed attempt.
+	LastError string `json:"last_error,omitempty"`
+
+	// NextAttemptAt is when the background worker may retry the job.
+	NextAttemptAt time.Time `json:"next_attempt_at,omitempty"`
+}
+
+// Due reports whether the background worker should try the job at now.
+func (j *Job) Due(now time.Time) bool {
+	return j.Attempts < MaxAttempts && !now.Before(j.NextAttemptAt)
+}
+
+// RecordFailure notes a failed attempt and schedules the next retry.
+func (j *Job) RecordFailure(err error, now time.Time) {
+	j.Attempts++
+	j.LastError = err.Error()
+	j.NextAttemptAt = now.Add(time.Duration(j.Attempts) * retryBackoff)
+}
+
+// Queue stores summary jobs as one JSON file each in .git/entire-summaries/,
+// shared across worktrees like session state.
+type Queue struct {
+	dir string
+}
+
+// NewQueue opens the summary queue of the current repository.
+func NewQueue() (*Queue, error) {
+	commonDir, err := getGitCommonDir()
+	if err != nil {
+		return nil, err
+	}
+	return &Queue{dir: filepath.Join(commonDir, QueueDirName)}, nil
+}
+
+// NewQueueWithDir opens a summary queue stored in dir.
+// This is useful for testing.
+func NewQueueWithDir(dir string) *Queue {
+	return &Queue{dir: dir}
+}
+
+// Enqueue adds a job, replacing any queued job for the same checkpoint session.
+func (q *Queue) Enqueue(ctx context.Context, job *Job) error {
+	if job.EnqueuedAt.IsZero() {
+		job.EnqueuedAt = time.Now()
+	}
+	return q.Save(ctx, job)
+}
+
+// Save writes a job atomically.
+func (q *Queue) Save(ctx context.Context, job *Job) error {
+	_ = ctx // Reserved for future use
+
+	if err := validation.ValidateSessionID(job.SessionID); err != nil {
+		return fmt.Errorf("invalid session ID: %w", err)
+	}
+
+	if err := os.MkdirAll(q.dir, 0o750); err != nil {
+		return fmt.Errorf("failed to create summary queue directory: %w", err)
+	}
+
+	data, err := jsonutil.MarshalIndentWithNewline(job, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal summary job: %w", err)
+	}
+
+	jobFile := q.jobFilePath(job)
+	tmpFile := jobFile + ".tmp"
+	if err := os.WriteFile(tmpFile, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write summary job: %w", err)
+	}
+	if err := os.Rename(tmpFile, jobFile); err != nil {
+		return fmt.Errorf("failed to rename summary job file: %w", err)
+	}
+	return nil
+}
+
+// Remove deletes a job from the queue.
+func (q *Queue) Remove(ctx context.Context, job *Job) error {
+	_ = ctx // Reserved for future use
+
+	if err := os.Remove(q.jobFilePath(job)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove summary job: %w", err)
+	}
+	return nil
+}
+
+// List returns all queued jobs, oldest first.
+func (q *Queue) List(ctx context.Context) ([]*Job, error) {
+	_ = ctx // Reserved for future use
+
+	entries, err := os.ReadDir(q.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read summary queue directory: %w", err)
+	}
+
+	var jobs []*Job
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(q.dir, entry.Name())) //nolint:gosec // Path is within the queue directory
+		if err != nil {
+			continue
+		}
+		var job Job
+		if err := json.Unmarshal(data, &job); err != nil {
+			continue // Skip corrupted job files
+		}
+		jobs = append(jobs, &job)
+	}
+
+	sort.SliceStable(jobs, func(i, j int) bool {
+		return jobs[i].EnqueuedAt.Before(jobs[j].EnqueuedAt)
+	})
+	return jobs, nil
+}
+
+// Lock takes the worker lock so only one process drains the queue at a time.
+// A lock older than staleLockAge is treated as abandoned and taken over.
+// Returns ErrWorkerRunning if another worker holds it; call the returned
+// function to release the lock.
+func (q *Queue) Lock() (func(), error) {
+	if err := os.MkdirAll(q.dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create summary queue directory: %w", err)
+	}
+
+	lockFile := filepath.Join(q.dir, workerLockName)
+	for range 2 {
+		f, err := os.OpenFile(lockFile, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600) //nolint:gosec // Path is within the queue directory
+		if err == nil {
+			fmt.Fprintf(f, "%d\n", os.Getpid())
+			_ = f.Close()
+			return func() { _ = os.Remove(lockFile) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("failed to create worker lock: %w", err)
+		}
+
+		info, statErr := os.Stat(lockFile)
+		if statErr != nil || time.Since(info.ModTime()) < staleLockAge {
+			break
+		}
+		_ = os.Remove(lockFile)
+	}
+	return nil, ErrWorkerRunning
+}
+
+// jobFilePath returns the path of a job's file.
+func (q *Queue) jobFilePath(job *Job) string {
+	return filepath.Join(q.dir, job.CheckpointID.String()+"-"+job.SessionID+".json")
+}
+
+// getGitCommonDir returns the path to the shared git directory.
+// In a regular checkout, this is .git/
+// In a worktree, this is the main repo's .git/ (not .git/worktrees/<name>/)
+func getGitCommonDir() (string, error) {
+	ctx := context.Background()
+	cmd := exec.CommandContext(ctx, "git", "rev-parse", "--git-common-dir")
+	cmd.Dir = "."
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get git common dir: %w", err)
+	}
+
+	commonDir := strings.TrimSpace(string(output))
+
+	// git rev-parse --git-common-dir returns relative paths from the working directory,
+	// so we need to make it absolute if it isn't already
+	if !filepath.IsAbs(commonDir) {
+		commonDir = filepath.Join(".", commonDir)
+	}
+
+	return filepath.Clean(commonDir), nil
+}
@@ -0,0 +1,130 @@
+package summarize
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+)
+
+func TestQueue_EnqueueListRemove(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	queue := NewQueueWithDir(filepath.Join(t.TempDir(), QueueDirName))
+
+	jobs, err := queue.List(ctx)
+	if err != nil || len(jobs) != 0 {
+		t.Fatalf("List() on missing dir = %v, %v, want empty", jobs, err)
+	}
+
+	now := time.Now()
+	older := &Job{CheckpointID: id.MustCheckpointID("aaaaaaaaaaaa"), SessionID: "session-b", EnqueuedAt: now.Add(-time.Hour)}
+	newer := &Job{CheckpointID: id.MustCheckpointID("bbbbbbbbbbbb"), SessionID: "session-a", EnqueuedAt: now}
+	for _, job := range []*Job{newer, older} {
+		if err := queue.Enqueue(ctx, job); err != nil {
+			t.Fatalf("Enqueue() error = %v", err)
+		}
+	}
+
+	jobs, err = queue.List(ctx)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(jobs) != 2 || jobs[0].SessionID != "session-b" {
+		t.Fatalf("List() = %+v, want two jobs oldest first", jobs)
+	}
+
+	if err := queue.Remove(ctx, older); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if err := queue.Remove(ctx, older); err != nil {
+		t.Fatalf("Remove() of a removed job error = %v", err)
+	}
+	jobs, err = queue.List(ctx)
+	if err != nil || len(jobs) != 1 || jobs[0].SessionID != "session-a" {
+		t.Fatalf("List() after Remove = %+v, %v", jobs, err)
+	}
+}
+
+func TestQueue_EnqueueRejectsInvalidSessionID(t *testing.T) {
+	t.Parallel()
+
+	queue := NewQueueWithDir(t.TempDir())
+	err := queue.Enqueue(context.Background(), &Job{CheckpointID: id.MustCheckpointID("aaaaaaaaaaaa"), SessionID: "../escape"})
+	if err == nil {
+		t.Fatal("Enqueue() should reject a session ID with path separators")
+	}
+}
+
+func TestJob_RecordFailureAndDue(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	job := &Job{}
+	if !job.Due(now) {
+		t.Fatal("a new job should be due")
+	}
+
+	job.RecordFailure(errors.New("boom"), now)
+	if job.Attempts != 1 || job.LastError != "boom" {
+		t.Errorf("job = %+v, want one attempt recorded", job)
+	}
+	if job.Due(now) || !job.Due(now.Add(retryBackoff)) {
+		t.Errorf("job should be due after %v, NextAttemptAt = %v", retryBackoff, job.NextAttemptAt)
+	}
+
+	job.RecordFailure(errors.New("boom"), now)
+	if !job.NextAttemptAt.Equal(now.Add(2 * retryBackoff)) {
+		t.Errorf("NextAttemptAt = %v, want backoff growing with attempts", job.NextAttemptAt)
+	}
+
+	job.RecordFailure(errors.New("boom"), now)
+	if job.Due(now.Add(time.Hour)) {
+		t.Error("a job that used all attempts should not be due")
+	}
+}
+
+func TestQueue_Lock(t *testing.T) {
+	t.Parallel()
+
+	queue := NewQueueWithDir(t.TempDir())
+	unlock, err := queue.Lock()
+	if err != nil {
+		t.Fatalf("Lock() error = %v", err)
+	}
+	if _, err := queue.Lock(); !errors.Is(err, ErrWorkerRunning) {
+		t.Fatalf("second Lock() error = %v, want ErrWorkerRunning", err)
+	}
+
+	unlock()
+	unlock2, err := queue.Lock()
+	if err != nil {
+		t.Fatalf("Lock() after unlock error = %v", err)
+	}
+	unlock2()
+}
+
+func TestQueue_LockTakesOverStaleLock(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	lockFile := filepath.Join(dir, workerLockName)
+	if err := os.WriteFile(lockFile, []byte("12345\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	stale := time.Now().Add(-2 * staleLockAge)
+	if err := os.Chtimes(lockFile, stale, stale); err != nil {
+		t.Fatal(err)
+	}
+
+	unlock, err := NewQueueWithDir(dir).Lock()
+	if err != nil {
+		t.Fatalf("Lock() with stale lock error = %v", err)
+	}
+	unlock()
+}
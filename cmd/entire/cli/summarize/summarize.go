@@ -22,7 +22,7 @@ import (
 //   - transcriptBytes: raw transcript bytes (JSONL or JSON format depending on agent)
 //   - filesTouched: list of files modified during the session
 //   - agentType: the agent type to determine transcript format
-//   - generator: summary generator to use (if nil, uses default ClaudeGenerator)
+//   - generator: summary generator to use (if nil, uses the generator configured in settings)
 //
 // Returns nil, error if transcript is empty or cannot be parsed.
 func GenerateFromTranscript(ctx context.Context, transcriptBytes []byte, filesTouched []string, agentType agent.AgentType, generator Generator) (*checkpoint.Summary, error) {
@@ -44,9 +44,12 @@ func GenerateFromTranscript(ctx context.Context, transcriptBytes []byte, filesTo
 		FilesTouched: filesTouched,
 	}
 
-	// Use default generator if none provided
+	// Use the configured generator if none provided
 	if generator == nil {
-		generator = &ClaudeGenerator{}
+		generator, err = ConfiguredGenerator()
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	summary, err := generator.Generate(ctx, input)
@@ -686,7 +686,7 @@ func TestGenerateFromTranscript_EmptyTranscript(t *testing.T) {
 func TestGenerateFromTranscript_NilGenerator(t *testing.T) {
 	transcript := []byte(`{"type":"user","message":{"content":"Hello"}}`)
 
-	// With nil generator, should use default ClaudeGenerator
+	// With nil generator, should use the configured generator (Claude by default)
 	// This will fail because claude CLI isn't available in test, but tests the nil handling
 	_, err := GenerateFromTranscript(context.Background(), transcript, []string{}, "", nil)
 	// Error is expected (claude CLI not available), but function should not panic
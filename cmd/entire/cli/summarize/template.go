@@ -0,0 +1,287 @@
+package summarize
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+)
+
+// TemplateFile is the repo-relative path of the optional summary template.
+const TemplateFile = ".entire/summary-template.json"
+
+// summarizationPromptTemplate is the prompt used to generate summaries.
+// The placeholders are the transcript, the JSON schema, and extra guidelines.
+//
+// Security note: The transcript is wrapped in <transcript> tags to provide clear boundary
+// markers. This helps contain any potentially malicious content within the transcript
+// (e.g., prompt injection attempts in user messages or file contents) by giving the LLM
+// a clear structural signal about where the untrusted content begins and ends.
+const summarizationPromptTemplate = `Analyze this development session transcript and generate a structured summary.
+
+<transcript>
+%s
+</transcript>
+
+Return a JSON object with this exact structure:
+%s
+
+Guidelines:
+- Be concise but specific
+- Include line numbers for code learnings when the transcript references specific lines
+- Friction should capture both blockers and minor annoyances
+- Open items are things intentionally deferred, not failures
+- Empty arrays are fine if a category doesn't apply
+%s- Return ONLY the JSON object, no markdown formatting or explanation`
+
+// summarySchema is the JSON structure of checkpoint.Summary shown to the model.
+const summarySchema = `{
+  "intent": "What the user was trying to accomplish (1-2 sentences)",
+  "outcome": "What was actually achieved (1-2 sentences)",
+  "learnings": {
+    "repo": ["Codebase-specific patterns, conventions, or gotchas discovered"],
+    "code": [{"path": "file/path.go", "line": 42, "end_line": 56, "finding": "What was learned"}],
+    "workflow": ["General development practices or tool usage insights"]
+  },
+  "friction": ["Problems, blockers, or annoyances encountered"],
+  "open_items": ["Tech debt, unfinished work, or things to revisit later"]
+}`
+
+// extensionsGuideline is added to the built-in guidelines when a template defines fields.
+const extensionsGuideline = "- Fill in every extensions field; use an empty string or array if it doesn't apply\n"
+
+// fieldNamePattern restricts extension field names to snake_case identifiers.
+var fieldNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// Template customizes summary generation for a repository. It is loaded from
+// TemplateFile and can replace the prompt wording and add extension fields,
+// which the model returns under "extensions" and are stored in Summary.Extensions.
+type Template struct {
+	// Prompt replaces the built-in prompt. It is a Go text/template that must
+	// include {{.Transcript}} and {{.Schema}}. If empty, the built-in prompt is used.
+	Prompt string `json:"prompt,omitempty"`
+
+	// Fields are additional summary fields, in display order.
+	Fields []TemplateField `json:"fields,omitempty"`
+
+	prompt *template.Template
+}
+
+// TemplateField is an extension field requested from the model.
+type TemplateField struct {
+	// Name is the snake_case key under "extensions" (e.g. "risk_assessment").
+	Name string `json:"name"`
+
+	// Description tells the model what to put in the field.
+	Description string `json:"description"`
+
+	// List requests an array of strings instead of a single string.
+	List bool `json:"list,omitempty"`
+}
+
+// promptData is the data available to a custom prompt template.
+type promptData struct {
+	Transcript string
+	Schema     string
+}
+
+// LoadTemplate loads the repository's summary template.
+// Returns nil, nil if the repository doesn't define one.
+func LoadTemplate() (*Template, error) {
+	path, err := paths.AbsPath(TemplateFile)
+	if err != nil {
+		path = TemplateFile
+	}
+	data, err := os.ReadFile(path) //nolint:gosec // Path is the repo's template file
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil //nolint:nilnil // No template means the built-in prompt
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", TemplateFile, err)
+	}
+	return ParseTemplate(data)
+}
+
+// ParseTemplate parses and validates a summary template.
+func ParseTemplate(data []byte) (*Template, error) {
+	var tmpl Template
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&tmpl); err != nil {
+		return nil, fmt.Errorf("invalid summary template: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	for _, field := range tmpl.Fields {
+		if !fieldNamePattern.MatchString(field.Name) {
+			return nil, fmt.Errorf("invalid summary template: field name %q must be snake_case", field.Name)
+		}
+		if seen[field.Name] {
+			return nil, fmt.Errorf("invalid summary template: duplicate field %q", field.Name)
+		}
+		seen[field.Name] = true
+	}
+
+	if tmpl.Prompt != "" {
+		if !strings.Contains(tmpl.Prompt, "{{.Transcript}}") || !strings.Contains(tmpl.Prompt, "{{.Schema}}") {
+			return nil, errors.New("invalid summary template: prompt must include {{.Transcript}} and {{.Schema}}")
+		}
+		parsed, err := template.New("summary-prompt").Option("missingkey=error").Parse(tmpl.Prompt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid summary template prompt: %w", err)
+		}
+		tmpl.prompt = parsed
+	}
+
+	return &tmpl, nil
+}
+
+// BuildPrompt creates the summarization prompt for a formatted transcript.
+// A nil template builds the built-in prompt.
+func (t *Template) BuildPrompt(transcriptText string) (string, error) {
+	if t == nil {
+		return buildSummarizationPrompt(transcriptText), nil
+	}
+
+	schema := t.schema()
+	if t.prompt == nil {
+		guidelines := ""
+		if len(t.Fields) > 0 {
+			guidelines = extensionsGuideline
+		}
+		return fmt.Sprintf(summarizationPromptTemplate, transcriptText, schema, guidelines), nil
+	}
+
+	var buf bytes.Buffer
+	if err := t.prompt.Execute(&buf, promptData{Transcript: transcriptText, Schema: schema}); err != nil {
+		return "", fmt.Errorf("failed to render summary prompt: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// schema returns the JSON structure shown to the model, with the template's
+// fields under "extensions".
+func (t *Template) schema() string {
+	if len(t.Fields) == 0 {
+		return summarySchema
+	}
+
+	var sb strings.Builder
+	sb.WriteString(strings.TrimSuffix(summarySchema, "\n}"))
+	sb.WriteString(",\n  \"extensions\": {\n")
+	for i, field := range t.Fields {
+		desc, _ := json.Marshal(field.Description) //nolint:errcheck // Marshaling a string can't fail
+		if field.List {
+			fmt.Fprintf(&sb, "    %q: [%s]", field.Name, desc)
+		} else {
+			fmt.Fprintf(&sb, "    %q: %s", field.Name, desc)
+		}
+		if i < len(t.Fields)-1 {
+			sb.WriteString(",")
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("  }\n}")
+	return sb.String()
+}
+
+// buildSummarizationPrompt creates the built-in summarization prompt.
+func buildSummarizationPrompt(transcriptText string) string {
+	return fmt.Sprintf(summarizationPromptTemplate, transcriptText, summarySchema, "")
+}
+
+// parseSummaryResult parses the model's reply into a summary, tolerating
+// replies wrapped in markdown code blocks. Extensions are checked against the
+// template's fields (see normalizeExtensions); a nil template drops them all.
+func parseSummaryResult(result string, tmpl *Template) (*checkpoint.Summary, error) {
+	resultJSON := extractJSONFromMarkdown(result)
+
+	var summary checkpoint.Summary
+	if err := json.Unmarshal([]byte(resultJSON), &summary); err != nil {
+		return nil, fmt.Errorf("failed to parse summary JSON: %w (response: %s)", err, resultJSON)
+	}
+	summary.Extensions = tmpl.normalizeExtensions(summary.Extensions)
+
+	return &summary, nil
+}
+
+// normalizeExtensions keeps only the extensions the template defines, coerced
+// to the field's shape: a string, or a list of strings for List fields.
+// Numbers and booleans are converted to strings, a single string is wrapped
+// for a List field, and any other value (objects, nested lists, null) is
+// dropped, so whatever the model replies is safe to store and render.
+// Returns nil if no fields remain.
+func (t *Template) normalizeExtensions(extensions map[string]any) map[string]any {
+	if t == nil || len(extensions) == 0 {
+		return nil
+	}
+
+	var normalized map[string]any
+	for _, field := range t.Fields {
+		value, ok := extensions[field.Name]
+		if !ok {
+			continue
+		}
+		var coerced any
+		if field.List {
+			coerced, ok = extensionList(value)
+		} else {
+			coerced, ok = extensionString(value)
+		}
+		if !ok {
+			continue
+		}
+		if normalized == nil {
+			normalized = make(map[string]any)
+		}
+		normalized[field.Name] = coerced
+	}
+	return normalized
+}
+
+// extensionString converts a scalar JSON value to a string.
+// Returns false for null, objects and arrays.
+func extensionString(value any) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(v), true
+	default:
+		return "", false
+	}
+}
+
+// extensionList converts a JSON value to a list of strings, skipping items
+// that aren't scalars. A single scalar becomes a one-item list.
+func extensionList(value any) ([]any, bool) {
+	items, isList := value.([]any)
+	if !isList {
+		s, ok := extensionString(value)
+		if !ok {
+			return nil, false
+		}
+		if s == "" {
+			return []any{}, true
+		}
+		return []any{s}, true
+	}
+
+	list := make([]any, 0, len(items))
+	for _, item := range items {
+		if s, ok := extensionString(item); ok {
+			list = append(list, s)
+		}
+	}
+	return list, true
+}
@@ -0,0 +1,196 @@
+package summarize
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+	"github.com/entireio/cli/cmd/entire/cli/testutil"
+)
+
+func TestParseTemplate_Validation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{name: "valid fields", data: `{"fields": [{"name": "risk", "description": "Risk"}]}`},
+		{name: "valid prompt", data: `{"prompt": "Summarize {{.Transcript}} as {{.Schema}}"}`},
+		{name: "bad json", data: `{`, wantErr: "invalid summary template"},
+		{name: "unknown key", data: `{"promt": "x"}`, wantErr: "unknown field"},
+		{name: "bad name", data: `{"fields": [{"name": "Risk Level"}]}`, wantErr: "snake_case"},
+		{name: "duplicate name", data: `{"fields": [{"name": "risk"}, {"name": "risk"}]}`, wantErr: "duplicate"},
+		{name: "prompt without schema", data: `{"prompt": "Summarize {{.Transcript}}"}`, wantErr: "must include"},
+		{name: "prompt syntax", data: `{"prompt": "{{.Transcript}} {{.Schema}} {{if}}"}`, wantErr: "prompt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := ParseTemplate([]byte(tt.data))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("ParseTemplate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("ParseTemplate() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTemplate_BuildPrompt_Fields(t *testing.T) {
+	t.Parallel()
+
+	tmpl, err := ParseTemplate([]byte(`{"fields": [
+		{"name": "risk_assessment", "description": "Deployment risk"},
+		{"name": "test_gaps", "description": "Untested paths", "list": true}
+	]}`))
+	if err != nil {
+		t.Fatalf("ParseTemplate() error = %v", err)
+	}
+
+	prompt, err := tmpl.BuildPrompt("[User] hello")
+	if err != nil {
+		t.Fatalf("BuildPrompt() error = %v", err)
+	}
+	for _, want := range []string{
+		"[User] hello",
+		`"open_items"`,
+		`"extensions": {`,
+		`"risk_assessment": "Deployment risk",`,
+		`"test_gaps": ["Untested paths"]`,
+		"Fill in every extensions field",
+	} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt missing %q:\n%s", want, prompt)
+		}
+	}
+}
+
+func TestTemplate_BuildPrompt_CustomPrompt(t *testing.T) {
+	t.Parallel()
+
+	tmpl, err := ParseTemplate([]byte(`{"prompt": "Summarize for reviewers.\n{{.Transcript}}\nReply as {{.Schema}}"}`))
+	if err != nil {
+		t.Fatalf("ParseTemplate() error = %v", err)
+	}
+
+	prompt, err := tmpl.BuildPrompt("[User] hello")
+	if err != nil {
+		t.Fatalf("BuildPrompt() error = %v", err)
+	}
+	if !strings.HasPrefix(prompt, "Summarize for reviewers.\n[User] hello\nReply as {") {
+		t.Errorf("prompt = %q, want the custom prompt rendered", prompt)
+	}
+	if strings.Contains(prompt, `"extensions"`) {
+		t.Error("schema should not include extensions when no fields are defined")
+	}
+}
+
+func TestTemplate_BuildPrompt_NilUsesBuiltIn(t *testing.T) {
+	t.Parallel()
+
+	var tmpl *Template
+	prompt, err := tmpl.BuildPrompt("[User] hello")
+	if err != nil {
+		t.Fatalf("BuildPrompt() error = %v", err)
+	}
+	if prompt != buildSummarizationPrompt("[User] hello") {
+		t.Error("nil template should build the built-in prompt")
+	}
+}
+
+func TestParseSummaryResult_Extensions(t *testing.T) {
+	t.Parallel()
+
+	tmpl := &Template{Fields: []TemplateField{{Name: "risk"}, {Name: "gaps", List: true}}}
+	summary, err := parseSummaryResult(`{"intent": "x", "outcome": "y", "extensions": {"risk": "Low", "gaps": ["a"]}}`, tmpl)
+	if err != nil {
+		t.Fatalf("parseSummaryResult() error = %v", err)
+	}
+	if summary.Extensions["risk"] != "Low" {
+		t.Errorf("Extensions = %v, want risk preserved", summary.Extensions)
+	}
+	if gaps, ok := summary.Extensions["gaps"].([]any); !ok || len(gaps) != 1 {
+		t.Errorf("Extensions[gaps] = %v, want a list", summary.Extensions["gaps"])
+	}
+
+	// Without a template, the model has no fields to fill in
+	summary, err = parseSummaryResult(`{"intent": "x", "extensions": {"risk": "Low"}}`, nil)
+	if err != nil {
+		t.Fatalf("parseSummaryResult() error = %v", err)
+	}
+	if summary.Extensions != nil {
+		t.Errorf("Extensions = %v, want nil without a template", summary.Extensions)
+	}
+}
+
+func TestParseSummaryResult_MalformedExtensions(t *testing.T) {
+	t.Parallel()
+
+	tmpl := &Template{Fields: []TemplateField{
+		{Name: "risk"},
+		{Name: "score"},
+		{Name: "owner"},
+		{Name: "gaps", List: true},
+		{Name: "notes", List: true},
+		{Name: "steps", List: true},
+	}}
+	summary, err := parseSummaryResult(`{"intent": "x", "extensions": {
+		"risk": {"level": "high"},
+		"score": 7.5,
+		"owner": null,
+		"gaps": [{"path": "a.go"}, "No retry test", 3, ["nested"], null, true],
+		"notes": "single note",
+		"steps": {"first": "x"},
+		"unknown": "dropped"
+	}}`, tmpl)
+	if err != nil {
+		t.Fatalf("parseSummaryResult() error = %v", err)
+	}
+
+	want := map[string]any{
+		"score": "7.5",
+		"gaps":  []any{"No retry test", "3", "true"},
+		"notes": []any{"single note"},
+	}
+	if !reflect.DeepEqual(summary.Extensions, want) {
+		t.Errorf("Extensions = %#v, want %#v", summary.Extensions, want)
+	}
+}
+
+func TestLoadTemplate(t *testing.T) {
+	tmpDir := t.TempDir()
+	testutil.InitRepo(t, tmpDir)
+	t.Chdir(tmpDir)
+	paths.ClearRepoRootCache()
+
+	tmpl, err := LoadTemplate()
+	if err != nil || tmpl != nil {
+		t.Fatalf("LoadTemplate() = %v, %v, want nil, nil without a template file", tmpl, err)
+	}
+
+	if err := os.MkdirAll(filepath.Join(tmpDir, ".entire"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	data := `{"fields": [{"name": "risk", "description": "Risk"}]}`
+	if err := os.WriteFile(filepath.Join(tmpDir, TemplateFile), []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl, err = LoadTemplate()
+	if err != nil {
+		t.Fatalf("LoadTemplate() error = %v", err)
+	}
+	if tmpl == nil || len(tmpl.Fields) != 1 || tmpl.Fields[0].Name != "risk" {
+		t.Errorf("LoadTemplate() = %+v, want the file's fields", tmpl)
+	}
+}
@@ -0,0 +1,51 @@
+package summarize
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+
+	"github.com/entireio/cli/cmd/entire/cli/paths"
+)
+
+// WorkerCommand is the hidden command that drains the summary queue in the background.
+const WorkerCommand = "__summarize_worker"
+
+// SpawnWorker starts a detached `entire __summarize_worker` process that drains
+// the summary queue, so commits don't wait for the LLM call. It returns
+// immediately; failures to start are ignored and the jobs stay queued for the
+// next worker or `entire summarize --pending`.
+func SpawnWorker() {
+	executable, err := os.Executable()
+	if err != nil {
+		return
+	}
+
+	repoRoot, err := paths.RepoRoot()
+	if err != nil {
+		return
+	}
+
+	cmd := exec.CommandContext(context.Background(), executable, WorkerCommand)
+
+	// Survive the parent (usually the post-commit hook) exiting
+	detachProcess(cmd)
+
+	// The worker opens the repository from its working directory. Git variables
+	// set for the hook (GIT_DIR, GIT_INDEX_FILE) would be wrong for it.
+	cmd.Dir = repoRoot
+	cmd.Env = stripGitEnv(os.Environ())
+
+	// Discard stdout/stderr to prevent output leaking to parent's terminal
+	cmd.Stdout = io.Discard
+	cmd.Stderr = io.Discard
+
+	if err := cmd.Start(); err != nil {
+		return
+	}
+
+	// Release the process so it can run independently
+	//nolint:errcheck // Best effort - process should continue regardless
+	_ = cmd.Process.Release()
+}
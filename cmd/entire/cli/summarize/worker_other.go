@@ -0,0 +1,9 @@
+//go:build !unix
+
+package summarize
+
+import "os/exec"
+
+// detachProcess is a no-op on non-Unix platforms. The worker is still started,
+// but it isn't moved into its own process group.
+func detachProcess(*exec.Cmd) {}
@@ -0,0 +1,16 @@
+//go:build unix
+
+package summarize
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// detachProcess moves cmd into its own process group so it keeps running
+// after the parent exits.
+func detachProcess(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Setpgid: true,
+	}
+}
@@ -0,0 +1,300 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/logging"
+	"github.com/entireio/cli/cmd/entire/cli/summarize"
+	"github.com/spf13/cobra"
+)
+
+// errNothingToSummarize marks a queued job that can never succeed (the checkpoint
+// or its transcript is gone) or is no longer needed, so it's dropped, not retried.
+var errNothingToSummarize = errors.New("nothing to summarize")
+
+func newSummarizeCmd() *cobra.Command {
+	var pendingFlag bool
+
+	cmd := &cobra.Command{
+		Use:   "summarize",
+		Short: "Show or process queued checkpoint summaries",
+		Long: `With auto-summarize enabled, summaries are not generated during 'git commit'.
+The commit queues a job in .git/entire-summaries/ and a background worker
+generates the summary afterwards.
+
+A failed job is retried with backoff, up to 3 attempts. Jobs that still fail stay
+queued.
+
+Without flags, lists the queued jobs. With --pending, generates every queued
+summary now, including jobs the background worker gave up on.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if checkDisabledGuard(cmd.OutOrStdout()) {
+				return nil
+			}
+
+			queue, err := summarize.NewQueue()
+			if err != nil {
+				return fmt.Errorf("failed to open summary queue: %w", err)
+			}
+			if !pendingFlag {
+				return listSummaryQueue(cmd.Context(), cmd.OutOrStdout(), queue)
+			}
+			return runSummarizePending(cmd.Context(), cmd.OutOrStdout(), queue)
+		},
+	}
+
+	cmd.Flags().BoolVar(&pendingFlag, "pending", false, "Generate all queued summaries now")
+
+	return cmd
+}
+
+// newSummarizeWorkerCmd creates the hidden command run by the detached summary worker.
+// This command is spawned by summarize.SpawnWorker and should not be called directly by users.
+func newSummarizeWorkerCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:    summarize.WorkerCommand,
+		Hidden: true,
+		Args:   cobra.NoArgs,
+		Run: func(cmd *cobra.Command, _ []string) {
+			runSummarizeWorker(cmd.Context())
+		},
+	}
+}
+
+// listSummaryQueue prints the queued summary jobs.
+func listSummaryQueue(ctx context.Context, w io.Writer, queue *summarize.Queue) error {
+	jobs, err := queue.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list summary queue: %w", err)
+	}
+	if len(jobs) == 0 {
+		fmt.Fprintln(w, "No pending summaries.")
+		return nil
+	}
+
+	fmt.Fprintf(w, "%d pending summary job(s):\n", len(jobs))
+	for _, job := range jobs {
+		fmt.Fprintf(w, "  %s  session %s  queued %s", job.CheckpointID, job.SessionID, job.EnqueuedAt.Local().Format("2006-01-02 15:04"))
+		if job.Attempts > 0 {
+			fmt.Fprintf(w, "  (%d failed attempt(s): %s)", job.Attempts, job.LastError)
+		}
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintln(w, "\nRun 'entire summarize --pending' to generate them now.")
+	return nil
+}
+
+// runSummarizePending generates every queued summary in the foreground.
+func runSummarizePending(ctx context.Context, w io.Writer, queue *summarize.Queue) error {
+	logging.SetLogLevelGetter(GetLogLevel)
+	if err := logging.Init(""); err == nil {
+		defer logging.Close()
+	}
+
+	unlock, err := queue.Lock()
+	if errors.Is(err, summarize.ErrWorkerRunning) {
+		return errors.New("a background summary worker is running; try again when it finishes")
+	}
+	if err != nil {
+		return err //nolint:wrapcheck // Already descriptive
+	}
+	defer unlock()
+
+	jobs, err := queue.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list summary queue: %w", err)
+	}
+	if len(jobs) == 0 {
+		fmt.Fprintln(w, "No pending summaries.")
+		return nil
+	}
+
+	generator, err := summarize.ConfiguredGenerator()
+	if err != nil {
+		return fmt.Errorf("failed to configure summary generator: %w", err)
+	}
+	store, err := openCheckpointStore()
+	if err != nil {
+		return err
+	}
+
+	summarized := 0
+	for _, job := range jobs {
+		err := processSummaryJob(ctx, queue, store, generator, job)
+		switch {
+		case err == nil:
+			summarized++
+			fmt.Fprintf(w, "✓ %s (session %s)\n", job.CheckpointID, job.SessionID)
+		case errors.Is(err, errNothingToSummarize):
+			fmt.Fprintf(w, "- %s (session %s): skipped, %v\n", job.CheckpointID, job.SessionID, err)
+		default:
+			fmt.Fprintf(w, "✗ %s (session %s): %v\n", job.CheckpointID, job.SessionID, err)
+		}
+	}
+
+	fmt.Fprintf(w, "\nGenerated %d of %d queued summary job(s).\n", summarized, len(jobs))
+	return nil
+}
+
+// runSummarizeWorker drains the due jobs of the summary queue. It runs detached
+// from the commit that spawned it, so failures are only logged.
+func runSummarizeWorker(ctx context.Context) {
+	logging.SetLogLevelGetter(GetLogLevel)
+	if err := logging.Init(""); err == nil {
+		defer logging.Close()
+	}
+	logCtx := logging.WithComponent(ctx, "summarize")
+
+	queue, err := summarize.NewQueue()
+	if err != nil {
+		logging.Warn(logCtx, "summary worker: failed to open queue", slog.String("error", err.Error()))
+		return
+	}
+	unlock, err := queue.Lock()
+	if err != nil {
+		// Another worker is draining the queue and will pick up new jobs
+		logging.Debug(logCtx, "summary worker: not starting", slog.String("reason", err.Error()))
+		return
+	}
+	defer unlock()
+
+	generator, err := summarize.ConfiguredGenerator()
+	if err != nil {
+		logging.Warn(logCtx, "summary worker: invalid summarize configuration", slog.String("error", err.Error()))
+		return
+	}
+	store, err := openCheckpointStore()
+	if err != nil {
+		logging.Warn(logCtx, "summary worker: failed to open checkpoint store", slog.String("error", err.Error()))
+		return
+	}
+
+	drainSummaryQueue(logCtx, queue, store, generator, sleepContext)
+}
+
+// maxWorkerWait bounds how long the background worker waits for a failed job's
+// retry before exiting and leaving it to the next worker.
+const maxWorkerWait = 5 * time.Minute
+
+// drainSummaryQueue processes due jobs until none are left, waiting out the
+// backoff of failed jobs so they are retried in the same run. The queue is
+// re-read after each pass so jobs queued by commits made meanwhile are picked
+// up too.
+func drainSummaryQueue(ctx context.Context, queue *summarize.Queue, store *checkpoint.GitStore, generator summarize.Generator, wait func(context.Context, time.Duration) bool) {
+	// Bound the attempts per job in this run even if the queue can't record them
+	tried := make(map[string]int)
+	for {
+		jobs, err := queue.List(ctx)
+		if err != nil {
+			logging.Warn(ctx, "summary worker: failed to list queue", slog.String("error", err.Error()))
+			return
+		}
+
+		now := time.Now()
+		processed := 0
+		var nextRetry time.Time
+		for _, job := range jobs {
+			key := job.CheckpointID.String() + "/" + job.SessionID
+			if tried[key] >= summarize.MaxAttempts || job.Attempts >= summarize.MaxAttempts {
+				continue
+			}
+			if !job.Due(now) {
+				if nextRetry.IsZero() || job.NextAttemptAt.Before(nextRetry) {
+					nextRetry = job.NextAttemptAt
+				}
+				continue
+			}
+			tried[key]++
+			processed++
+			if err := processSummaryJob(ctx, queue, store, generator, job); err != nil {
+				logging.Warn(ctx, "summary worker: job failed",
+					slog.String("checkpoint_id", job.CheckpointID.String()),
+					slog.String("session_id", job.SessionID),
+					slog.Int("attempts", job.Attempts),
+					slog.String("error", err.Error()))
+			}
+		}
+		if processed > 0 {
+			continue
+		}
+		if nextRetry.IsZero() || nextRetry.Sub(now) > maxWorkerWait || !wait(ctx, nextRetry.Sub(now)) {
+			return
+		}
+	}
+}
+
+// sleepContext waits for d, returning false if ctx is cancelled first.
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// processSummaryJob generates and saves the summary for a queued job. The job is
+// removed when it succeeds or has nothing to summarize; otherwise the failure
+// is recorded on the job for a later retry.
+func processSummaryJob(ctx context.Context, queue *summarize.Queue, store *checkpoint.GitStore, generator summarize.Generator, job *summarize.Job) error {
+	err := generateQueuedSummary(ctx, store, generator, job)
+	if err == nil || errors.Is(err, errNothingToSummarize) {
+		if removeErr := queue.Remove(ctx, job); removeErr != nil {
+			return removeErr //nolint:wrapcheck // Already descriptive
+		}
+		return err
+	}
+
+	job.RecordFailure(err, time.Now())
+	if saveErr := queue.Save(ctx, job); saveErr != nil {
+		logging.Warn(ctx, "failed to record summary job failure", slog.String("error", saveErr.Error()))
+	}
+	return err
+}
+
+// generateQueuedSummary summarizes the job's session the same way
+// `entire explain --generate` does and saves it to that session's metadata.
+func generateQueuedSummary(ctx context.Context, store *checkpoint.GitStore, generator summarize.Generator, job *summarize.Job) error {
+	content, err := store.ReadSessionContentByID(ctx, job.CheckpointID, job.SessionID)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errNothingToSummarize, err)
+	}
+	if content.Metadata.Summary != nil {
+		return fmt.Errorf("%w: checkpoint already has a summary", errNothingToSummarize)
+	}
+	scoped := scopeTranscriptForCheckpoint(content.Transcript, content.Metadata.GetTranscriptStart(), content.Metadata.Agent)
+	if len(scoped) == 0 {
+		return fmt.Errorf("%w: checkpoint has no transcript", errNothingToSummarize)
+	}
+
+	logging.Info(ctx, "generating queued summary",
+		slog.String("checkpoint_id", job.CheckpointID.String()),
+		slog.String("session_id", job.SessionID))
+
+	summary, err := summarize.GenerateFromTranscript(ctx, scoped, content.Metadata.FilesTouched, content.Metadata.Agent, generator)
+	if err != nil {
+		return err //nolint:wrapcheck // Already wrapped by summarize
+	}
+	if err := store.UpdateSessionSummary(ctx, job.CheckpointID, job.SessionID, summary); err != nil {
+		return fmt.Errorf("failed to save summary: %w", err)
+	}
+	return nil
+}
+
+// openCheckpointStore opens the checkpoint store of the current repository.
+func openCheckpointStore() (*checkpoint.GitStore, error) {
+	repo, err := openRepository()
+	if err != nil {
+		return nil, fmt.Errorf("not a git repository: %w", err)
+	}
+	return checkpoint.NewGitStore(repo), nil
+}
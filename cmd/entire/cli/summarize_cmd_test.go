@@ -0,0 +1,184 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint"
+	"github.com/entireio/cli/cmd/entire/cli/checkpoint/id"
+	"github.com/entireio/cli/cmd/entire/cli/summarize"
+)
+
+// failingSummaryGenerator always fails and records how often it was called.
+type failingSummaryGenerator struct {
+	calls int
+}
+
+func (g *failingSummaryGenerator) Generate(_ context.Context, _ summarize.Input) (*checkpoint.Summary, error) {
+	g.calls++
+	return nil, errors.New("rate limited")
+}
+
+func TestProcessSummaryJob_SavesSummaryAndRemovesJob(t *testing.T) {
+	repo, _ := setupServeTestRepo(t)
+	cpID := id.MustCheckpointID("b2c3d4e5f6a1")
+	addUnsummarizedCheckpoint(t, repo, cpID)
+
+	store := checkpoint.NewGitStore(repo)
+	queue := summarize.NewQueueWithDir(t.TempDir())
+	job := &summarize.Job{CheckpointID: cpID, SessionID: "2026-01-02-second-session"}
+	if err := queue.Enqueue(context.Background(), job); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+
+	generator := &fakeSummaryGenerator{}
+	if err := processSummaryJob(context.Background(), queue, store, generator, job); err != nil {
+		t.Fatalf("processSummaryJob() error = %v", err)
+	}
+
+	content, err := store.ReadSessionContentByID(context.Background(), cpID, job.SessionID)
+	if err != nil {
+		t.Fatalf("ReadSessionContentByID() error = %v", err)
+	}
+	if content.Metadata.Summary == nil || content.Metadata.Summary.Intent != "Generated intent" {
+		t.Errorf("summary = %+v, want the generated summary", content.Metadata.Summary)
+	}
+	if jobs, _ := queue.List(context.Background()); len(jobs) != 0 { //nolint:errcheck // empty list on error fails the check
+		t.Errorf("queue has %d jobs, want the finished job removed", len(jobs))
+	}
+}
+
+func TestProcessSummaryJob_SkipsSummarizedCheckpoint(t *testing.T) {
+	repo, cpID := setupServeTestRepo(t)
+
+	store := checkpoint.NewGitStore(repo)
+	queue := summarize.NewQueueWithDir(t.TempDir())
+	job := &summarize.Job{CheckpointID: cpID, SessionID: "2026-01-01-serve-session"}
+	if err := queue.Enqueue(context.Background(), job); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+
+	generator := &fakeSummaryGenerator{}
+	err := processSummaryJob(context.Background(), queue, store, generator, job)
+	if !errors.Is(err, errNothingToSummarize) {
+		t.Fatalf("processSummaryJob() error = %v, want errNothingToSummarize", err)
+	}
+	if generator.calls != 0 {
+		t.Errorf("generator called %d times, want 0 for a summarized checkpoint", generator.calls)
+	}
+	if jobs, _ := queue.List(context.Background()); len(jobs) != 0 { //nolint:errcheck // empty list on error fails the check
+		t.Errorf("queue has %d jobs, want the skipped job removed", len(jobs))
+	}
+}
+
+func TestProcessSummaryJob_RecordsFailure(t *testing.T) {
+	repo, _ := setupServeTestRepo(t)
+	cpID := id.MustCheckpointID("b2c3d4e5f6a1")
+	addUnsummarizedCheckpoint(t, repo, cpID)
+
+	store := checkpoint.NewGitStore(repo)
+	queue := summarize.NewQueueWithDir(t.TempDir())
+	job := &summarize.Job{CheckpointID: cpID, SessionID: "2026-01-02-second-session"}
+	if err := queue.Enqueue(context.Background(), job); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+
+	if err := processSummaryJob(context.Background(), queue, store, &failingSummaryGenerator{}, job); err == nil {
+		t.Fatal("processSummaryJob() should fail when generation fails")
+	}
+
+	jobs, err := queue.List(context.Background())
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(jobs) != 1 {
+		t.Fatalf("queue has %d jobs, want the failed job kept", len(jobs))
+	}
+	if jobs[0].Attempts != 1 || !strings.Contains(jobs[0].LastError, "rate limited") {
+		t.Errorf("job = %+v, want one recorded attempt with the error", jobs[0])
+	}
+	if jobs[0].Due(time.Now()) {
+		t.Error("failed job should not be due before its backoff")
+	}
+}
+
+func TestDrainSummaryQueue_RetriesUntilMaxAttempts(t *testing.T) {
+	repo, _ := setupServeTestRepo(t)
+	cpID := id.MustCheckpointID("b2c3d4e5f6a1")
+	addUnsummarizedCheckpoint(t, repo, cpID)
+
+	store := checkpoint.NewGitStore(repo)
+	queue := summarize.NewQueueWithDir(filepath.Join(t.TempDir(), "queue"))
+	if err := queue.Enqueue(context.Background(), &summarize.Job{CheckpointID: cpID, SessionID: "2026-01-02-second-session"}); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+
+	// Skip the backoff wait by moving every job's retry time into the past
+	waits := 0
+	wait := func(ctx context.Context, _ time.Duration) bool {
+		waits++
+		jobs, err := queue.List(ctx)
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+		for _, job := range jobs {
+			job.NextAttemptAt = time.Time{}
+			if err := queue.Save(ctx, job); err != nil {
+				t.Fatalf("Save() error = %v", err)
+			}
+		}
+		return true
+	}
+
+	generator := &failingSummaryGenerator{}
+	drainSummaryQueue(context.Background(), queue, store, generator, wait)
+
+	if generator.calls != summarize.MaxAttempts {
+		t.Errorf("generator called %d times, want %d", generator.calls, summarize.MaxAttempts)
+	}
+	if waits != summarize.MaxAttempts-1 {
+		t.Errorf("waited %d times, want %d", waits, summarize.MaxAttempts-1)
+	}
+	jobs, err := queue.List(context.Background())
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(jobs) != 1 || jobs[0].Attempts != summarize.MaxAttempts {
+		t.Errorf("jobs = %+v, want the exhausted job left queued", jobs)
+	}
+}
+
+func TestListSummaryQueue(t *testing.T) {
+	t.Parallel()
+
+	queue := summarize.NewQueueWithDir(t.TempDir())
+	var buf bytes.Buffer
+	if err := listSummaryQueue(context.Background(), &buf, queue); err != nil {
+		t.Fatalf("listSummaryQueue() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "No pending summaries.") {
+		t.Errorf("output = %q, want the empty message", buf.String())
+	}
+
+	job := &summarize.Job{CheckpointID: id.MustCheckpointID("b2c3d4e5f6a1"), SessionID: "session-1"}
+	job.RecordFailure(errors.New("timed out"), time.Now())
+	if err := queue.Enqueue(context.Background(), job); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+
+	buf.Reset()
+	if err := listSummaryQueue(context.Background(), &buf, queue); err != nil {
+		t.Fatalf("listSummaryQueue() error = %v", err)
+	}
+	output := buf.String()
+	for _, want := range []string{"1 pending summary job(s)", "b2c3d4e5f6a1", "session session-1", "1 failed attempt(s): timed out", "entire summarize --pending"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+}
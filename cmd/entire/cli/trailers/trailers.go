@@ -138,6 +138,28 @@ func ParseCheckpoint(commitMessage string) (checkpointID.CheckpointID, bool) {
 	return checkpointID.EmptyCheckpointID, false
 }
 
+// ParseAllCheckpoints extracts all checkpoint IDs from a commit message.
+// Squash commits can carry one trailer per squashed commit, so unlike
+// ParseCheckpoint this returns every valid ID, deduplicated in order.
+func ParseAllCheckpoints(commitMessage string) []checkpointID.CheckpointID {
+	matches := checkpointTrailerRegex.FindAllStringSubmatch(commitMessage, -1)
+	if len(matches) == 0 {
+		return nil
+	}
+
+	seen := make(map[checkpointID.CheckpointID]bool)
+	cpIDs := make([]checkpointID.CheckpointID, 0, len(matches))
+	for _, match := range matches {
+		cpID, err := checkpointID.NewCheckpointID(strings.TrimSpace(match[1]))
+		if err != nil || seen[cpID] {
+			continue
+		}
+		seen[cpID] = true
+		cpIDs = append(cpIDs, cpID)
+	}
+	return cpIDs
+}
+
 // ParseAllSessions extracts all session IDs from a commit message.
 // Returns a slice of session IDs (may be empty if none found).
 // Duplicate session IDs are deduplicated while preserving order.
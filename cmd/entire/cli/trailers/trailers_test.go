@@ -255,6 +255,49 @@ func TestParseAllSessions(t *testing.T) {
 	}
 }
 
+func TestParseAllCheckpoints(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    []string
+	}{
+		{
+			name:    "single checkpoint trailer",
+			message: "Add feature\n\nEntire-Checkpoint: a1b2c3d4e5f6\n",
+			want:    []string{"a1b2c3d4e5f6"},
+		},
+		{
+			name:    "no trailer",
+			message: "Simple commit message",
+			want:    nil,
+		},
+		{
+			name:    "squash message with indented trailers",
+			message: "Squashed commit of the following:\n\n    First\n\n    Entire-Checkpoint: a1b2c3d4e5f6\n\n    Second\n\n    Entire-Checkpoint: b1b2c3d4e5f6\n",
+			want:    []string{"a1b2c3d4e5f6", "b1b2c3d4e5f6"},
+		},
+		{
+			name:    "duplicates and invalid IDs are dropped",
+			message: "Msg\n\nEntire-Checkpoint: a1b2c3d4e5f6\nEntire-Checkpoint: nothex\nEntire-Checkpoint: a1b2c3d4e5f6\n",
+			want:    []string{"a1b2c3d4e5f6"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseAllCheckpoints(tt.message)
+			if len(got) != len(tt.want) {
+				t.Fatalf("ParseAllCheckpoints() = %v, want %v", got, tt.want)
+			}
+			for i, wantID := range tt.want {
+				if got[i].String() != wantID {
+					t.Errorf("ParseAllCheckpoints()[%d] = %v, want %v", i, got[i], wantID)
+				}
+			}
+		})
+	}
+}
+
 func TestParseCheckpoint(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -1,7 +1,9 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/charmbracelet/huh"
@@ -32,6 +34,25 @@ func NewAccessibleForm(groups ...*huh.Group) *huh.Form {
 	return form
 }
 
+// writeOutput runs write against w, or against the file at path when an
+// --output path other than "-" is given. An error closing the file is
+// returned, so a report that wasn't fully written doesn't exit successfully.
+func writeOutput(w io.Writer, path string, write func(io.Writer) error) (err error) {
+	if path == "" || path == "-" {
+		return write(w)
+	}
+	f, err := os.Create(path) //nolint:gosec // User-specified output path
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	defer func() {
+		if closeErr := f.Close(); closeErr != nil {
+			err = errors.Join(err, fmt.Errorf("failed to write output file: %w", closeErr))
+		}
+	}()
+	return write(f)
+}
+
 // fileExists checks if a file exists
 func fileExists(path string) bool {
 	_, err := os.Stat(path)
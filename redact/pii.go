@@ -0,0 +1,223 @@
+package redact
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+)
+
+// PIICategory is a kind of personally identifiable information that can be
+// redacted. PII detection is off by default; enable categories with Config.PII.
+type PIICategory string
+
+const (
+	PIIEmail      PIICategory = "email"
+	PIIPhone      PIICategory = "phone"
+	PIIIPAddress  PIICategory = "ip_address"
+	PIICreditCard PIICategory = "credit_card"
+	PIINationalID PIICategory = "national_id"
+)
+
+// PIICategories lists every supported PII category.
+var PIICategories = []PIICategory{PIIEmail, PIIPhone, PIIIPAddress, PIICreditCard, PIINationalID}
+
+// piiDetectors finds the byte ranges of each category's matches in a string.
+var piiDetectors = map[PIICategory]func(s string) [][2]int{
+	PIIEmail:      findEmails,
+	PIIPhone:      findPhoneNumbers,
+	PIIIPAddress:  findIPAddresses,
+	PIICreditCard: findCreditCards,
+	PIINationalID: findNationalIDs,
+}
+
+// ParsePIICategory validates a PII category name.
+func ParsePIICategory(name string) (PIICategory, error) {
+	c := PIICategory(name)
+	if _, ok := piiDetectors[c]; !ok {
+		return "", fmt.Errorf("unknown PII category %q (valid: %s)", name, joinCategories(PIICategories))
+	}
+	return c, nil
+}
+
+func joinCategories(categories []PIICategory) string {
+	names := make([]string, len(categories))
+	for i, c := range categories {
+		names[i] = string(c)
+	}
+	return strings.Join(names, ", ")
+}
+
+// piiLabel is the default replacement for a PII category, e.g. "REDACTED:email".
+func piiLabel(c PIICategory) string {
+	return DefaultReplacement + ":" + string(c)
+}
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}`)
+
+	// intlPhonePattern matches numbers with a country code, e.g. +44 20 7946 0958.
+	intlPhonePattern = regexp.MustCompile(`\+\d{1,3}(?:[ .-]?\(?\d{1,4}\)?){2,5}`)
+
+	// nanpPhonePattern matches North American numbers, e.g. (415) 555-0132 or 415.555.0132.
+	// A separator is required so plain digit runs aren't mistaken for phone numbers.
+	nanpPhonePattern = regexp.MustCompile(`(?:\(\d{3}\) ?|\d{3}[.-])\d{3}[.-]\d{4}`)
+
+	ipv4Pattern = regexp.MustCompile(`\d{1,3}(?:\.\d{1,3}){3}`)
+
+	// ipv6Pattern finds candidates which are then validated with net.ParseIP.
+	ipv6Pattern = regexp.MustCompile(`[0-9A-Fa-f]{0,4}(?::[0-9A-Fa-f]{0,4}){2,7}`)
+
+	// cardPattern matches 13-19 digits, optionally grouped by spaces or dashes.
+	cardPattern = regexp.MustCompile(`\d(?:[ -]?\d){12,18}`)
+
+	// ssnPattern matches US Social Security numbers.
+	ssnPattern = regexp.MustCompile(`\d{3}-\d{2}-\d{4}`)
+
+	// ninoPattern matches UK National Insurance numbers, e.g. AB 12 34 56 C.
+	ninoPattern = regexp.MustCompile(`[A-CEGHJ-PR-TW-Z][A-CEGHJ-NPR-TW-Z] ?\d{2} ?\d{2} ?\d{2} ?[A-D]`)
+)
+
+func findEmails(s string) [][2]int {
+	return findValid(s, emailPattern, isWordBoundary, nil)
+}
+
+func findPhoneNumbers(s string) [][2]int {
+	intl := findValid(s, intlPhonePattern, isDigitBoundary, func(m string) bool {
+		n := countDigits(m)
+		return n >= 8 && n <= 15
+	})
+	return append(intl, findValid(s, nanpPhonePattern, isDigitBoundary, nil)...)
+}
+
+func findIPAddresses(s string) [][2]int {
+	valid := func(m string) bool {
+		ip := net.ParseIP(m)
+		// Loopback and unspecified addresses identify no one and are common in dev transcripts
+		return ip != nil && !ip.IsLoopback() && !ip.IsUnspecified()
+	}
+	v4 := findValid(s, ipv4Pattern, func(s string, start, end int) bool {
+		return isDigitBoundary(s, start, end) && !adjacentTo(s, start, end, '.')
+	}, valid)
+	v6 := findValid(s, ipv6Pattern, func(s string, start, end int) bool {
+		return !adjacentTo(s, start, end, ':') && isWordBoundary(s, start, end)
+	}, func(m string) bool {
+		// Require three hex groups so C++-style "a::b" isn't taken for an address
+		groups := 0
+		for _, g := range strings.Split(m, ":") {
+			if g != "" {
+				groups++
+			}
+		}
+		return groups >= 3 && valid(m)
+	})
+	return append(v4, v6...)
+}
+
+func findCreditCards(s string) [][2]int {
+	return findValid(s, cardPattern, isDigitBoundary, func(m string) bool {
+		return luhnValid(digitsOnly(m))
+	})
+}
+
+func findNationalIDs(s string) [][2]int {
+	ssn := findValid(s, ssnPattern, func(s string, start, end int) bool {
+		return isDigitBoundary(s, start, end) && !adjacentTo(s, start, end, '-')
+	}, validSSN)
+	nino := findValid(s, ninoPattern, isWordBoundary, func(m string) bool {
+		switch m[:2] {
+		case "BG", "GB", "NK", "KN", "TN", "NT", "ZZ":
+			return false
+		}
+		return true
+	})
+	return append(ssn, nino...)
+}
+
+// findValid returns the matches of re that sit on a boundary and pass valid.
+// Go's regexp has no lookaround, so boundaries are checked here instead.
+func findValid(s string, re *regexp.Regexp, boundary func(s string, start, end int) bool, valid func(string) bool) [][2]int {
+	var out [][2]int
+	for _, loc := range re.FindAllStringIndex(s, -1) {
+		start, end := loc[0], loc[1]
+		if boundary != nil && !boundary(s, start, end) {
+			continue
+		}
+		if valid != nil && !valid(s[start:end]) {
+			continue
+		}
+		out = append(out, [2]int{start, end})
+	}
+	return out
+}
+
+// isDigitBoundary reports whether s[start:end] isn't part of a longer run of digits.
+func isDigitBoundary(s string, start, end int) bool {
+	return (start == 0 || !isDigit(s[start-1])) && (end == len(s) || !isDigit(s[end]))
+}
+
+// isWordBoundary reports whether s[start:end] isn't part of a longer word.
+func isWordBoundary(s string, start, end int) bool {
+	return (start == 0 || !isWordChar(s[start-1])) && (end == len(s) || !isWordChar(s[end]))
+}
+
+// adjacentTo reports whether c directly precedes or follows s[start:end].
+func adjacentTo(s string, start, end int, c byte) bool {
+	return (start > 0 && s[start-1] == c) || (end < len(s) && s[end] == c)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isWordChar(c byte) bool {
+	return isDigit(c) || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c == '_'
+}
+
+func countDigits(s string) int {
+	n := 0
+	for i := range len(s) {
+		if isDigit(s[i]) {
+			n++
+		}
+	}
+	return n
+}
+
+func digitsOnly(s string) string {
+	var b strings.Builder
+	for i := range len(s) {
+		if isDigit(s[i]) {
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// luhnValid reports whether digits pass the Luhn checksum used by payment cards.
+func luhnValid(digits string) bool {
+	if len(digits) < 13 || len(digits) > 19 {
+		return false
+	}
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// validSSN rejects numbers the SSA never issues: area 000, 666 or 9xx,
+// group 00, or serial 0000.
+func validSSN(m string) bool {
+	area, group, serial := m[0:3], m[4:6], m[7:11]
+	return area != "000" && area != "666" && area[0] != '9' && group != "00" && serial != "0000"
+}
@@ -0,0 +1,115 @@
+package redact
+
+import (
+	"testing"
+)
+
+func TestRedactor_PII(t *testing.T) {
+	t.Parallel()
+
+	r := mustNew(t, Config{PII: PIICategories})
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"email", "mail jane.doe+work@example.co.uk today", "mail REDACTED:email today"},
+		{"international phone", "call +44 20 7946 0958 now", "call REDACTED:phone now"},
+		{"nanp phone", "call (415) 555-0132 or 415.555.0199", "call REDACTED:phone or REDACTED:phone"},
+		{"ipv4", "server at 203.0.113.42:8080", "server at REDACTED:ip_address:8080"},
+		{"ipv6", "host 2001:db8::8a2e:370:7334 is up", "host REDACTED:ip_address is up"},
+		{"loopback kept", "listening on 127.0.0.1 and ::1", "listening on 127.0.0.1 and ::1"},
+		{"credit card", "card 4111 1111 1111 1111 expires", "card REDACTED:credit_card expires"},
+		{"credit card failing luhn", "order 4111 1111 1111 1112 shipped", "order 4111 1111 1111 1112 shipped"},
+		{"ssn", "ssn 123-45-6789 on file", "ssn REDACTED:national_id on file"},
+		{"invalid ssn", "ssn 000-45-6789 on file", "ssn 000-45-6789 on file"},
+		{"nino", "NI number AB 12 34 56 C", "NI number REDACTED:national_id"},
+		{"date is not a phone", "released 2024-01-15 at 12:30:45", "released 2024-01-15 at 12:30:45"},
+		{"version is not an ip", "upgrade to v1.2.3.4.5", "upgrade to v1.2.3.4.5"},
+		{"code is not an ipv6", "use std::vector and a::b", "use std::vector and a::b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := r.String(tt.input); got != tt.want {
+				t.Errorf("String(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactor_PIIOffByDefault(t *testing.T) {
+	t.Parallel()
+
+	input := "mail jane@example.com or call (415) 555-0132"
+	if got := mustNew(t, Config{}).String(input); got != input {
+		t.Errorf("PII redacted without being enabled: %q", got)
+	}
+}
+
+func TestRedactor_PIIPerCategory(t *testing.T) {
+	t.Parallel()
+
+	r := mustNew(t, Config{
+		PII:    []PIICategory{PIIEmail},
+		Labels: map[string]string{"email": "[email]"},
+	})
+	got := r.String("mail jane@example.com or call (415) 555-0132")
+	if got != "mail [email] or call (415) 555-0132" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestRedactor_PIIAllowlist(t *testing.T) {
+	t.Parallel()
+
+	r := mustNew(t, Config{
+		PII:           []PIICategory{PIIEmail},
+		AllowPatterns: []string{`@example\.com$`},
+	})
+	got := r.String("noreply@example.com and jane@corp.io")
+	if got != "noreply@example.com and REDACTED:email" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestRedactor_PIIJSONLContent(t *testing.T) {
+	t.Parallel()
+
+	r := mustNew(t, Config{PII: []PIICategory{PIIEmail, PIIPhone}})
+	input := `{"type":"user","message":{"content":"I'm jane@example.com, +1 415 555 0132"},"uuid":"abc"}`
+	got, err := r.JSONLContent(input)
+	if err != nil {
+		t.Fatalf("JSONLContent() error = %v", err)
+	}
+	want := `{"type":"user","message":{"content":"I'm REDACTED:email, REDACTED:phone"},"uuid":"abc"}`
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestNew_UnknownPIICategory(t *testing.T) {
+	t.Parallel()
+
+	if _, err := New(Config{PII: []PIICategory{"passport"}}); err == nil {
+		t.Error("expected error for unknown PII category")
+	}
+}
+
+func TestLuhnValid(t *testing.T) {
+	t.Parallel()
+
+	for digits, want := range map[string]bool{
+		"4111111111111111":    true,
+		"5500005555555559":    true,
+		"378282246310005":     true,
+		"4111111111111112":    false,
+		"1234":                false,
+		"0000000000000000000": true,
+	} {
+		if got := luhnValid(digits); got != want {
+			t.Errorf("luhnValid(%q) = %v, want %v", digits, got, want)
+		}
+	}
+}
@@ -3,24 +3,44 @@ package redact
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
+	"os"
 	"regexp"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
 
+	"github.com/spf13/viper"
+	"github.com/zricethezav/gitleaks/v8/config"
 	"github.com/zricethezav/gitleaks/v8/detect"
 )
 
 // secretPattern matches high-entropy strings that may be secrets.
 var secretPattern = regexp.MustCompile(`[A-Za-z0-9/+_=-]{10,}`)
 
-// entropyThreshold is the minimum Shannon entropy for a string to be considered
-// a secret. 4.5 was chosen through trial and error: high enough to avoid false
-// positives on common words and identifiers, low enough to catch typical API keys
-// and tokens which tend to have entropy well above 5.0.
-const entropyThreshold = 4.5
+// DefaultEntropyThreshold is the minimum Shannon entropy for a string to be
+// considered a secret. 4.5 was chosen through trial and error: high enough to
+// avoid false positives on common words and identifiers, low enough to catch
+// typical API keys and tokens which tend to have entropy well above 5.0.
+const DefaultEntropyThreshold = 4.5
+
+// DefaultReplacement replaces secrets that have no label configured.
+const DefaultReplacement = "REDACTED"
+
+// EntropyRuleID is the rule ID of entropy-based detection, for use in Config.Labels.
+const EntropyRuleID = "entropy"
+
+// Detection sources, in increasing priority. When regions found by different
+// sources overlap, the label of the higher-priority source is used.
+const (
+	priorityEntropy = iota
+	priorityPII
+	priorityGitleaks
+	priorityCustom
+)
 
 var (
 	gitleaksDetector     *detect.Detector
@@ -38,26 +58,276 @@ func getDetector() *detect.Detector {
 	return gitleaksDetector
 }
 
+// Config customizes redaction. The zero value gives the built-in behavior.
+type Config struct {
+	// EntropyThreshold overrides DefaultEntropyThreshold when positive.
+	EntropyThreshold float64
+
+	// GitleaksConfig is the path of a gitleaks TOML config used instead of
+	// gitleaks' default rules. Use [extend] useDefault = true to add to them.
+	GitleaksConfig string
+
+	// Rules are additional regex rules, checked alongside gitleaks.
+	Rules []Rule
+
+	// AllowPatterns are regexes; a detected secret matching any of them is kept.
+	AllowPatterns []string
+
+	// AllowValues are literal values that are never redacted.
+	AllowValues []string
+
+	// PII enables detection of personal data by category. Matches are replaced
+	// with "REDACTED:<category>" unless Labels has an entry for the category.
+	PII []PIICategory
+
+	// Labels maps rule IDs (gitleaks rule IDs, custom rule IDs, PII categories,
+	// or EntropyRuleID) to the text that replaces their matches.
+	Labels map[string]string
+}
+
+// Rule is a custom secret pattern. If Pattern has a capture group, only the
+// first group is redacted (e.g. `token=(\w+)` keeps "token=").
+type Rule struct {
+	ID          string
+	Pattern     string
+	Replacement string
+}
+
+// Redactor replaces secrets in text according to a Config.
+type Redactor struct {
+	entropyThreshold float64
+	detector         *detect.Detector
+	rules            []compiledRule
+	allowPatterns    []*regexp.Regexp
+	allowValues      map[string]bool
+	pii              []PIICategory
+	labels           map[string]string
+
+	// counts, when set by WithCounts, tallies the redactions made.
+	counts Counts
+
+	// cache holds already-redacted JSONL lines. Copies made by WithCounts share it.
+	cache *lineCache
+}
+
+// Counts tallies redactions by rule ID.
+type Counts map[string]int
+
+// Total returns the number of redactions across all rules.
+func (c Counts) Total() int {
+	total := 0
+	for _, n := range c {
+		total += n
+	}
+	return total
+}
+
+// WithCounts returns a copy of r that adds every redaction it makes to counts.
+// The copy must not be used concurrently.
+func (r *Redactor) WithCounts(counts Counts) *Redactor {
+	cp := *r
+	cp.counts = counts
+	return &cp
+}
+
+// addCounts adds redactions made elsewhere, e.g. by another goroutine, to the
+// tally set by WithCounts.
+func (r *Redactor) addCounts(counts Counts) {
+	if r.counts == nil {
+		return
+	}
+	for rule, n := range counts {
+		r.counts[rule] += n
+	}
+}
+
+type compiledRule struct {
+	id          string
+	re          *regexp.Regexp
+	replacement string
+}
+
+// New builds a Redactor from cfg, validating its regexes and gitleaks config.
+func New(cfg Config) (*Redactor, error) {
+	r := &Redactor{
+		entropyThreshold: DefaultEntropyThreshold,
+		allowValues:      make(map[string]bool, len(cfg.AllowValues)),
+		labels:           cfg.Labels,
+		cache:            newLineCache(),
+	}
+	if cfg.EntropyThreshold > 0 {
+		r.entropyThreshold = cfg.EntropyThreshold
+	}
+
+	if cfg.GitleaksConfig != "" {
+		d, err := loadGitleaksConfig(cfg.GitleaksConfig)
+		if err != nil {
+			return nil, err
+		}
+		r.detector = d
+	} else {
+		r.detector = getDetector()
+	}
+
+	for _, rule := range cfg.Rules {
+		if rule.ID == "" {
+			return nil, errors.New("redaction rule is missing an id")
+		}
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex for redaction rule %q: %w", rule.ID, err)
+		}
+		r.rules = append(r.rules, compiledRule{id: rule.ID, re: re, replacement: rule.Replacement})
+	}
+
+	for _, pattern := range cfg.AllowPatterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction allowlist pattern %q: %w", pattern, err)
+		}
+		r.allowPatterns = append(r.allowPatterns, re)
+	}
+	for _, value := range cfg.AllowValues {
+		r.allowValues[value] = true
+	}
+
+	for _, c := range cfg.PII {
+		if _, err := ParsePIICategory(string(c)); err != nil {
+			return nil, err
+		}
+		if !slices.Contains(r.pii, c) {
+			r.pii = append(r.pii, c)
+		}
+	}
+
+	return r, nil
+}
+
+// loadGitleaksConfig builds a gitleaks detector from a TOML config file.
+func loadGitleaksConfig(path string) (*detect.Detector, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // Path comes from the repository's settings
+	if err != nil {
+		return nil, fmt.Errorf("failed to read gitleaks config: %w", err)
+	}
+	v := viper.New()
+	v.SetConfigType("toml")
+	if err := v.ReadConfig(bytes.NewReader(data)); err != nil {
+		return nil, fmt.Errorf("failed to parse gitleaks config %s: %w", path, err)
+	}
+	var vc config.ViperConfig
+	if err := v.Unmarshal(&vc); err != nil {
+		return nil, fmt.Errorf("failed to parse gitleaks config %s: %w", path, err)
+	}
+	cfg, err := vc.Translate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid gitleaks config %s: %w", path, err)
+	}
+	return detect.NewDetector(cfg), nil
+}
+
+var (
+	defaultMu       sync.Mutex
+	defaultRedactor *Redactor
+	defaultLoader   func() *Redactor
+)
+
+// SetDefaultLoader registers the function that builds the Redactor used by the
+// package-level functions. It is called once, on first use, so configuration is
+// only read when something is redacted. A nil result uses the built-in rules.
+func SetDefaultLoader(load func() *Redactor) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultLoader = load
+	defaultRedactor = nil
+}
+
+// SetDefault replaces the Redactor used by the package-level functions.
+// Passing nil restores lazy loading.
+func SetDefault(r *Redactor) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultRedactor = r
+}
+
+// Default returns the Redactor used by the package-level functions.
+func Default() *Redactor {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	if defaultRedactor == nil {
+		if defaultLoader != nil {
+			defaultRedactor = defaultLoader()
+		}
+		if defaultRedactor == nil {
+			defaultRedactor = &Redactor{entropyThreshold: DefaultEntropyThreshold, detector: getDetector(), cache: newLineCache()}
+		}
+	}
+	return defaultRedactor
+}
+
 // region represents a byte range to redact.
-type region struct{ start, end int }
+type region struct {
+	start, end int
+	ruleID     string
+	label      string
+	priority   int
+}
 
-// String replaces secrets in s with "REDACTED" using layered detection:
-// 1. Entropy-based: high-entropy alphanumeric sequences (threshold 4.5)
-// 2. Pattern-based: gitleaks regex rules (180+ known secret formats)
-// A string is redacted if EITHER method flags it.
+// String replaces secrets in s using the default Redactor.
 func String(s string) string {
+	return Default().String(s)
+}
+
+// String replaces secrets in s using layered detection:
+// 1. Entropy-based: high-entropy alphanumeric sequences (threshold 4.5 by default)
+// 2. Pattern-based: gitleaks regex rules (180+ known secret formats)
+// 3. PII detection for the categories enabled in the Config
+// 4. Custom regex rules from the Config
+// A string is redacted if ANY method flags it and the value isn't allowlisted.
+// Matches are replaced with their rule's label, or "REDACTED".
+func (r *Redactor) String(s string) string {
+	regions := r.detect(s)
+	if len(regions) == 0 {
+		return s
+	}
+
+	var b strings.Builder
+	prev := 0
+	for _, reg := range regions {
+		b.WriteString(s[prev:reg.start])
+		b.WriteString(reg.label)
+		prev = reg.end
+		if r.counts != nil {
+			r.counts[reg.ruleID]++
+		}
+	}
+	b.WriteString(s[prev:])
+	return b.String()
+}
+
+// detect returns the regions of s to redact, sorted and merged. Where regions
+// overlap, the rule of the highest-priority detection source is kept.
+func (r *Redactor) detect(s string) []region {
 	var regions []region
+	add := func(start, end int, ruleID, replacement string, priority int) {
+		if r.allowed(s[start:end]) {
+			return
+		}
+		if replacement == "" {
+			replacement = r.label(ruleID)
+		}
+		regions = append(regions, region{start: start, end: end, ruleID: ruleID, label: replacement, priority: priority})
+	}
 
 	// 1. Entropy-based detection.
 	for _, loc := range secretPattern.FindAllStringIndex(s, -1) {
-		if shannonEntropy(s[loc[0]:loc[1]]) > entropyThreshold {
-			regions = append(regions, region{loc[0], loc[1]})
+		if shannonEntropy(s[loc[0]:loc[1]]) > r.entropyThreshold {
+			add(loc[0], loc[1], EntropyRuleID, "", priorityEntropy)
 		}
 	}
 
 	// 2. Pattern-based detection via gitleaks.
-	if d := getDetector(); d != nil {
-		for _, f := range d.DetectString(s) {
+	if r.detector != nil {
+		for _, f := range r.detector.DetectString(s) {
 			if f.Secret == "" {
 				continue
 			}
@@ -68,140 +338,176 @@ func String(s string) string {
 					break
 				}
 				absIdx := searchFrom + idx
-				regions = append(regions, region{absIdx, absIdx + len(f.Secret)})
+				add(absIdx, absIdx+len(f.Secret), f.RuleID, "", priorityGitleaks)
 				searchFrom = absIdx + len(f.Secret)
 			}
 		}
 	}
 
+	// 3. PII detection.
+	for _, c := range r.pii {
+		replacement := r.labels[string(c)]
+		if replacement == "" {
+			replacement = piiLabel(c)
+		}
+		for _, loc := range piiDetectors[c](s) {
+			add(loc[0], loc[1], string(c), replacement, priorityPII)
+		}
+	}
+
+	// 4. Custom rules.
+	for _, rule := range r.rules {
+		for _, loc := range rule.re.FindAllStringSubmatchIndex(s, -1) {
+			start, end := loc[0], loc[1]
+			if len(loc) >= 4 && loc[2] >= 0 {
+				start, end = loc[2], loc[3]
+			}
+			if start < end {
+				add(start, end, rule.id, rule.replacement, priorityCustom)
+			}
+		}
+	}
+
 	if len(regions) == 0 {
-		return s
+		return nil
 	}
 
-	// Merge overlapping regions and build result.
+	// Merge overlapping regions.
 	sort.Slice(regions, func(i, j int) bool {
 		return regions[i].start < regions[j].start
 	})
 	merged := []region{regions[0]}
-	for _, r := range regions[1:] {
+	for _, reg := range regions[1:] {
 		last := &merged[len(merged)-1]
-		if r.start <= last.end {
-			if r.end > last.end {
-				last.end = r.end
+		if reg.start <= last.end {
+			if reg.end > last.end {
+				last.end = reg.end
+			}
+			if reg.priority > last.priority {
+				last.ruleID, last.label, last.priority = reg.ruleID, reg.label, reg.priority
 			}
 		} else {
-			merged = append(merged, r)
+			merged = append(merged, reg)
 		}
 	}
+	return merged
+}
 
-	var b strings.Builder
-	prev := 0
-	for _, r := range merged {
-		b.WriteString(s[prev:r.start])
-		b.WriteString("REDACTED")
-		prev = r.end
+// allowed reports whether a detected value is allowlisted.
+func (r *Redactor) allowed(value string) bool {
+	if r.allowValues[value] {
+		return true
 	}
-	b.WriteString(s[prev:])
-	return b.String()
+	for _, re := range r.allowPatterns {
+		if re.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// label returns the replacement text for a rule's matches.
+func (r *Redactor) label(ruleID string) string {
+	if label, ok := r.labels[ruleID]; ok && label != "" {
+		return label
+	}
+	return DefaultReplacement
 }
 
 // Bytes is a convenience wrapper around String for []byte content.
 func Bytes(b []byte) []byte {
+	return Default().Bytes(b)
+}
+
+// Bytes is a convenience wrapper around String for []byte content.
+func (r *Redactor) Bytes(b []byte) []byte {
 	s := string(b)
-	redacted := String(s)
+	redacted := r.String(s)
 	if redacted == s {
 		return b
 	}
 	return []byte(redacted)
 }
 
-// JSONLBytes is a convenience wrapper around JSONLContent for []byte content.
+// JSONLBytes redacts JSONL content using the default Redactor.
 func JSONLBytes(b []byte) ([]byte, error) {
-	s := string(b)
-	redacted, err := JSONLContent(s)
-	if err != nil {
+	return Default().JSONLBytes(b)
+}
+
+// JSONLBytes redacts JSONL content with JSONLStream. Large content is
+// redacted across several goroutines.
+func (r *Redactor) JSONLBytes(b []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Grow(len(b))
+	if err := r.JSONLStream(&buf, bytes.NewReader(b), StreamOptions{Workers: streamWorkers(len(b))}); err != nil {
 		return nil, err
 	}
-	if redacted == s {
+	if bytes.Equal(buf.Bytes(), b) {
 		return b, nil
 	}
-	return []byte(redacted), nil
+	return buf.Bytes(), nil
+}
+
+// JSONLContent redacts JSONL content using the default Redactor.
+func JSONLContent(content string) (string, error) {
+	return Default().JSONLContent(content)
 }
 
 // JSONLContent parses each line as JSON to determine which string values
 // need redaction, then performs targeted replacements on the raw JSON bytes.
 // Lines with no secrets are returned unchanged, preserving original formatting.
-func JSONLContent(content string) (string, error) {
-	lines := strings.Split(content, "\n")
+func (r *Redactor) JSONLContent(content string) (string, error) {
 	var b strings.Builder
-	for i, line := range lines {
+	for i, line := range strings.Split(content, "\n") {
 		if i > 0 {
 			b.WriteByte('\n')
 		}
-		trimmed := strings.TrimSpace(line)
-		if trimmed == "" {
-			b.WriteString(line)
-			continue
-		}
-		var parsed any
-		if err := json.Unmarshal([]byte(trimmed), &parsed); err != nil {
-			b.WriteString(String(line))
-			continue
-		}
-		repls := collectJSONLReplacements(parsed)
-		if len(repls) == 0 {
-			b.WriteString(line)
-			continue
-		}
-		result := line
-		for _, r := range repls {
-			origJSON, err := jsonEncodeString(r[0])
-			if err != nil {
-				return "", err
-			}
-			replJSON, err := jsonEncodeString(r[1])
-			if err != nil {
-				return "", err
-			}
-			result = strings.ReplaceAll(result, origJSON, replJSON)
+		res, err := r.jsonlLine(line)
+		if err != nil {
+			return "", err
 		}
-		b.WriteString(result)
+		r.addCounts(res.counts)
+		b.WriteString(res.line)
 	}
 	return b.String(), nil
 }
 
 // collectJSONLReplacements walks a parsed JSON value and collects unique
 // (original, redacted) string pairs for values that need redaction.
-func collectJSONLReplacements(v any) [][2]string {
+func (r *Redactor) collectJSONLReplacements(v any) [][2]string {
 	seen := make(map[string]bool)
 	var repls [][2]string
-	var walk func(v any)
-	walk = func(v any) {
-		switch val := v.(type) {
-		case map[string]any:
-			if shouldSkipJSONLObject(val) {
-				return
-			}
-			for k, child := range val {
-				if shouldSkipJSONLField(k) {
-					continue
-				}
-				walk(child)
-			}
-		case []any:
-			for _, child := range val {
-				walk(child)
-			}
-		case string:
-			redacted := String(val)
-			if redacted != val && !seen[val] {
-				seen[val] = true
-				repls = append(repls, [2]string{val, redacted})
+	walkJSONLStrings(v, func(val string) {
+		redacted := r.String(val)
+		if redacted != val && !seen[val] {
+			seen[val] = true
+			repls = append(repls, [2]string{val, redacted})
+		}
+	})
+	return repls
+}
+
+// walkJSONLStrings calls fn for every string value in a parsed JSON value,
+// skipping the fields and objects that are never redacted.
+func walkJSONLStrings(v any, fn func(string)) {
+	switch val := v.(type) {
+	case map[string]any:
+		if shouldSkipJSONLObject(val) {
+			return
+		}
+		for k, child := range val {
+			if shouldSkipJSONLField(k) {
+				continue
 			}
+			walkJSONLStrings(child, fn)
 		}
+	case []any:
+		for _, child := range val {
+			walkJSONLStrings(child, fn)
+		}
+	case string:
+		fn(val)
 	}
-	walk(v)
-	return repls
 }
 
 // shouldSkipJSONLField returns true if a JSON key should be excluded from scanning/redaction.
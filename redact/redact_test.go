@@ -97,7 +97,7 @@ func TestCollectJSONLReplacements_Succeeds(t *testing.T) {
 	obj := map[string]any{
 		"content": "token=" + highEntropySecret,
 	}
-	repls := collectJSONLReplacements(obj)
+	repls := Default().collectJSONLReplacements(obj)
 	// expect one replacement for high-entropy secret
 	want := [][2]string{{"token=" + highEntropySecret, "REDACTED"}}
 	if !slices.Equal(repls, want) {
@@ -149,7 +149,7 @@ func TestShouldSkipJSONLField_RedactionBehavior(t *testing.T) {
 		"session_id": highEntropySecret,
 		"content":    highEntropySecret,
 	}
-	repls := collectJSONLReplacements(obj)
+	repls := Default().collectJSONLReplacements(obj)
 	// Only "content" should produce a replacement; "session_id" should be skipped.
 	if len(repls) != 1 {
 		t.Fatalf("expected 1 replacement, got %d", len(repls))
@@ -188,8 +188,8 @@ func TestString_PatternDetection(t *testing.T) {
 			// Verify entropy is below threshold (proving entropy-only would miss this).
 			for _, loc := range secretPattern.FindAllStringIndex(tt.input, -1) {
 				e := shannonEntropy(tt.input[loc[0]:loc[1]])
-				if e > entropyThreshold {
-					t.Fatalf("test secret has entropy %.2f > %.1f; this test is meant for low-entropy secrets", e, entropyThreshold)
+				if e > DefaultEntropyThreshold {
+					t.Fatalf("test secret has entropy %.2f > %.1f; this test is meant for low-entropy secrets", e, DefaultEntropyThreshold)
 				}
 			}
 
@@ -254,7 +254,7 @@ func TestShouldSkipJSONLObject_RedactionBehavior(t *testing.T) {
 		"type": "image",
 		"data": highEntropySecret,
 	}
-	repls := collectJSONLReplacements(obj)
+	repls := Default().collectJSONLReplacements(obj)
 
 	// expect no replacements, it's an image which is skipped.
 	var wantRepls [][2]string
@@ -267,7 +267,7 @@ func TestShouldSkipJSONLObject_RedactionBehavior(t *testing.T) {
 		"type":    "text",
 		"content": highEntropySecret,
 	}
-	repls2 := collectJSONLReplacements(obj2)
+	repls2 := Default().collectJSONLReplacements(obj2)
 	wantRepls2 := [][2]string{{highEntropySecret, "REDACTED"}}
 	if !slices.Equal(repls2, wantRepls2) {
 		t.Errorf("got %q, want %q", repls2, wantRepls2)
@@ -0,0 +1,58 @@
+package redact
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// Finding is a value the redactor would replace. It records where the value
+// is and which rule matched, but never the value itself.
+type Finding struct {
+	RuleID string
+	Line   int // 1-based
+}
+
+// ScanText reports what String would redact in content, without changing it.
+func (r *Redactor) ScanText(content string) []Finding {
+	var findings []Finding
+	line, offset := 1, 0
+	for _, reg := range r.detect(content) {
+		line += strings.Count(content[offset:reg.start], "\n")
+		offset = reg.start
+		findings = append(findings, Finding{RuleID: reg.ruleID, Line: line})
+	}
+	return findings
+}
+
+// ScanJSONL reports what JSONLContent would redact in content, without changing
+// it. Like JSONLContent, only string values are scanned and ID fields and
+// image objects are skipped.
+func (r *Redactor) ScanJSONL(content string) []Finding {
+	var findings []Finding
+	for i, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+		var parsed any
+		if err := json.Unmarshal([]byte(trimmed), &parsed); err != nil {
+			for _, reg := range r.detect(line) {
+				findings = append(findings, Finding{RuleID: reg.ruleID, Line: i + 1})
+			}
+			continue
+		}
+		var lineFindings []Finding
+		walkJSONLStrings(parsed, func(val string) {
+			for _, reg := range r.detect(val) {
+				lineFindings = append(lineFindings, Finding{RuleID: reg.ruleID, Line: i + 1})
+			}
+		})
+		// Map iteration order is random; keep the output stable
+		sort.Slice(lineFindings, func(a, b int) bool {
+			return lineFindings[a].RuleID < lineFindings[b].RuleID
+		})
+		findings = append(findings, lineFindings...)
+	}
+	return findings
+}
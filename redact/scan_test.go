@@ -0,0 +1,68 @@
+package redact
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRedactor_ScanText(t *testing.T) {
+	t.Parallel()
+
+	r := mustNew(t, Config{Rules: []Rule{{ID: "ticket", Pattern: `TICKET-\d+`}}})
+	content := "first line\nkey " + highEntropySecret + "\n\nsee TICKET-9 and TICKET-10"
+
+	got := r.ScanText(content)
+	want := []Finding{
+		{RuleID: EntropyRuleID, Line: 2},
+		{RuleID: "ticket", Line: 4},
+		{RuleID: "ticket", Line: 4},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("ScanText() = %+v, want %+v", got, want)
+	}
+	if strings.Contains(content, "REDACTED") {
+		t.Error("ScanText must not modify content")
+	}
+}
+
+func TestRedactor_ScanJSONL(t *testing.T) {
+	t.Parallel()
+
+	r := mustNew(t, Config{Rules: []Rule{{ID: "ticket", Pattern: `TICKET-\d+`}}})
+	content := `{"type":"user","message":"fix TICKET-1"}
+{"type":"assistant","tool_use_id":"TICKET-2","message":"done"}
+not json TICKET-3
+`
+	got := r.ScanJSONL(content)
+	want := []Finding{
+		{RuleID: "ticket", Line: 1},
+		{RuleID: "ticket", Line: 3},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("ScanJSONL() = %+v, want %+v", got, want)
+	}
+}
+
+func TestRedactor_WithCounts(t *testing.T) {
+	t.Parallel()
+
+	base := mustNew(t, Config{Rules: []Rule{{ID: "ticket", Pattern: `TICKET-\d+`}}})
+	counts := Counts{}
+	r := base.WithCounts(counts)
+
+	r.String("TICKET-1 and " + highEntropySecret)
+	if _, err := r.JSONLContent(`{"a":"TICKET-2","b":"TICKET-3"}`); err != nil {
+		t.Fatal(err)
+	}
+
+	if counts["ticket"] != 3 || counts[EntropyRuleID] != 1 || counts.Total() != 4 {
+		t.Errorf("counts = %v", counts)
+	}
+
+	// The original redactor doesn't count
+	base.String("TICKET-4")
+	if counts.Total() != 4 {
+		t.Errorf("base redactor changed counts: %v", counts)
+	}
+}
@@ -0,0 +1,264 @@
+package redact
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// StreamOptions tunes JSONLStream.
+type StreamOptions struct {
+	// Workers is the number of lines redacted concurrently. Values below 2
+	// redact one line at a time.
+	Workers int
+}
+
+const (
+	// streamBatchLines and streamBatchBytes bound how much of the input is held
+	// in memory at once: lines are redacted in batches, then written in order.
+	streamBatchLines = 512
+	streamBatchBytes = 8 << 20
+
+	// parallelThreshold is the input size above which JSONLBytes redacts lines
+	// in parallel. Smaller inputs aren't worth the goroutines.
+	parallelThreshold = 1 << 20
+)
+
+// JSONLStream redacts JSONL from src to dst using the default Redactor.
+func JSONLStream(dst io.Writer, src io.Reader, opts StreamOptions) error {
+	return Default().JSONLStream(dst, src, opts)
+}
+
+// JSONLStream redacts JSONL line by line from src to dst, giving the same
+// output as JSONLContent without holding the whole content in memory.
+// Lines that were already redacted by this Redactor are served from its cache.
+func (r *Redactor) JSONLStream(dst io.Writer, src io.Reader, opts StreamOptions) error {
+	br := bufio.NewReaderSize(src, 64<<10)
+	bw := bufio.NewWriterSize(dst, 64<<10)
+
+	batch := make([]string, 0, streamBatchLines)
+	batchBytes := 0
+	flush := func() error {
+		results, err := r.redactJSONLLines(batch, opts.Workers)
+		if err != nil {
+			return err
+		}
+		for i, res := range results {
+			if _, err := bw.WriteString(res.line); err != nil {
+				return fmt.Errorf("failed to write redacted line: %w", err)
+			}
+			if strings.HasSuffix(batch[i], "\n") {
+				if err := bw.WriteByte('\n'); err != nil {
+					return fmt.Errorf("failed to write redacted line: %w", err)
+				}
+			}
+			r.addCounts(res.counts)
+		}
+		batch = batch[:0]
+		batchBytes = 0
+		return nil
+	}
+
+	for {
+		line, readErr := br.ReadString('\n')
+		if line != "" {
+			batch = append(batch, line)
+			batchBytes += len(line)
+			if len(batch) == streamBatchLines || batchBytes >= streamBatchBytes {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return fmt.Errorf("failed to read JSONL: %w", readErr)
+		}
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("failed to write redacted JSONL: %w", err)
+	}
+	return nil
+}
+
+// lineResult is a redacted line and the redactions made in it. The counts may
+// be shared with the cache and must not be modified.
+type lineResult struct {
+	line   string
+	counts Counts
+}
+
+// redactJSONLLines redacts a batch of lines, each possibly ending in a newline,
+// using up to workers goroutines. Results are returned in input order, without
+// the newlines.
+func (r *Redactor) redactJSONLLines(lines []string, workers int) ([]lineResult, error) {
+	results := make([]lineResult, len(lines))
+	if workers < 2 || len(lines) < 2 {
+		for i, line := range lines {
+			res, err := r.jsonlLine(strings.TrimSuffix(line, "\n"))
+			if err != nil {
+				return nil, err
+			}
+			results[i] = res
+		}
+		return results, nil
+	}
+
+	workers = min(workers, len(lines))
+	errs := make([]error, len(lines))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				results[i], errs[i] = r.jsonlLine(strings.TrimSuffix(lines[i], "\n"))
+			}
+		}()
+	}
+	for i := range lines {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+// jsonlLine redacts a single JSONL line. It is safe for concurrent use: the
+// redactions are returned rather than added to r.counts.
+func (r *Redactor) jsonlLine(line string) (lineResult, error) {
+	if strings.TrimSpace(line) == "" {
+		return lineResult{line: line}, nil
+	}
+
+	var key [sha256.Size]byte
+	if r.cache != nil {
+		key = sha256.Sum256([]byte(line))
+		if res, ok := r.cache.get(key, line); ok {
+			return res, nil
+		}
+	}
+
+	lr := *r
+	lr.counts = Counts{}
+	redacted, err := lr.redactJSONLLine(line)
+	if err != nil {
+		return lineResult{}, err
+	}
+	res := lineResult{line: redacted, counts: lr.counts}
+	if r.cache != nil {
+		r.cache.put(key, line, res)
+	}
+	return res, nil
+}
+
+// redactJSONLLine parses a line as JSON to determine which string values need
+// redaction, then performs targeted replacements on the raw JSON. Lines that
+// aren't JSON are redacted as plain text.
+func (r *Redactor) redactJSONLLine(line string) (string, error) {
+	var parsed any
+	if err := json.Unmarshal([]byte(strings.TrimSpace(line)), &parsed); err != nil {
+		return r.String(line), nil
+	}
+	repls := r.collectJSONLReplacements(parsed)
+	result := line
+	for _, repl := range repls {
+		origJSON, err := jsonEncodeString(repl[0])
+		if err != nil {
+			return "", err
+		}
+		replJSON, err := jsonEncodeString(repl[1])
+		if err != nil {
+			return "", err
+		}
+		result = strings.ReplaceAll(result, origJSON, replJSON)
+	}
+	return result, nil
+}
+
+// Cache bounds. The cache is cleared when either is exceeded, which keeps it
+// simple while still covering the repeated redaction of one session's transcript.
+const (
+	lineCacheMaxEntries = 1 << 18
+	lineCacheMaxBytes   = 64 << 20
+)
+
+// lineCache remembers the redaction of JSONL lines by content hash. Checkpoint
+// writes redact the same transcript several times: once when a session is
+// finalized and again for each checkpoint it updates.
+type lineCache struct {
+	mu      sync.Mutex
+	entries map[[sha256.Size]byte]lineCacheEntry
+	bytes   int
+}
+
+// lineCacheEntry holds the redacted line only when it differs from the input,
+// so clean lines cost just their key.
+type lineCacheEntry struct {
+	changed bool
+	line    string
+	counts  Counts
+}
+
+func newLineCache() *lineCache {
+	return &lineCache{entries: make(map[[sha256.Size]byte]lineCacheEntry)}
+}
+
+func (c *lineCache) get(key [sha256.Size]byte, line string) (lineResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok {
+		return lineResult{}, false
+	}
+	if !entry.changed {
+		return lineResult{line: line}, true
+	}
+	return lineResult{line: entry.line, counts: entry.counts}, true
+}
+
+func (c *lineCache) put(key [sha256.Size]byte, line string, res lineResult) {
+	entry := lineCacheEntry{}
+	size := sha256.Size
+	if res.line != line {
+		entry = lineCacheEntry{changed: true, line: res.line, counts: res.counts}
+		size += len(res.line)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) >= lineCacheMaxEntries || c.bytes+size > lineCacheMaxBytes {
+		clear(c.entries)
+		c.bytes = 0
+	}
+	if size > lineCacheMaxBytes {
+		return
+	}
+	c.entries[key] = entry
+	c.bytes += size
+}
+
+// streamWorkers returns the number of workers JSONLBytes uses for n bytes.
+func streamWorkers(n int) int {
+	if n < parallelThreshold {
+		return 1
+	}
+	return runtime.GOMAXPROCS(0)
+}
@@ -0,0 +1,87 @@
+package redact
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRedactor_JSONLStream(t *testing.T) {
+	t.Parallel()
+
+	r := mustNew(t, Config{})
+	input := strings.Join([]string{
+		`{"type":"text","content":"key=` + highEntropySecret + `"}`,
+		``,
+		`not json ` + highEntropySecret,
+		`{"type":"text","content":"hello"}`,
+		``,
+	}, "\n")
+	want, err := r.JSONLContent(input)
+	if err != nil {
+		t.Fatalf("JSONLContent() error = %v", err)
+	}
+
+	for _, workers := range []int{0, 4} {
+		var out bytes.Buffer
+		if err := r.JSONLStream(&out, strings.NewReader(input), StreamOptions{Workers: workers}); err != nil {
+			t.Fatalf("JSONLStream(workers=%d) error = %v", workers, err)
+		}
+		if out.String() != want {
+			t.Errorf("JSONLStream(workers=%d) = %q, want %q", workers, out.String(), want)
+		}
+	}
+}
+
+func TestRedactor_JSONLStreamParallelKeepsOrder(t *testing.T) {
+	t.Parallel()
+
+	r := mustNew(t, Config{Rules: []Rule{{ID: "ticket", Pattern: `TICKET-\d+`}}})
+	var in, want strings.Builder
+	for i := range 2000 {
+		if i%3 == 0 {
+			in.WriteString(`{"n":` + strings.Repeat("1", i%7+1) + `,"msg":"TICKET-` + strings.Repeat("9", i%5+1) + `"}` + "\n")
+			want.WriteString(`{"n":` + strings.Repeat("1", i%7+1) + `,"msg":"REDACTED"}` + "\n")
+		} else {
+			line := `{"n":` + strings.Repeat("2", i%11+1) + `,"msg":"line"}` + "\n"
+			in.WriteString(line)
+			want.WriteString(line)
+		}
+	}
+
+	counts := Counts{}
+	var out bytes.Buffer
+	if err := r.WithCounts(counts).JSONLStream(&out, strings.NewReader(in.String()), StreamOptions{Workers: 8}); err != nil {
+		t.Fatalf("JSONLStream() error = %v", err)
+	}
+	if out.String() != want.String() {
+		t.Error("parallel output differs from the expected line order or content")
+	}
+	if counts["ticket"] != 667 {
+		t.Errorf("counts[ticket] = %d, want 667", counts["ticket"])
+	}
+}
+
+func TestRedactor_JSONLCacheKeepsCounts(t *testing.T) {
+	t.Parallel()
+
+	r := mustNew(t, Config{Rules: []Rule{{ID: "ticket", Pattern: `TICKET-\d+`}}})
+	input := []byte(`{"msg":"see TICKET-1"}` + "\n" + `{"msg":"clean"}` + "\n")
+
+	for range 2 {
+		counts := Counts{}
+		got, err := r.WithCounts(counts).JSONLBytes(input)
+		if err != nil {
+			t.Fatalf("JSONLBytes() error = %v", err)
+		}
+		if string(got) != `{"msg":"see REDACTED"}`+"\n"+`{"msg":"clean"}`+"\n" {
+			t.Errorf("JSONLBytes() = %q", got)
+		}
+		if counts["ticket"] != 1 {
+			t.Errorf("counts[ticket] = %d, want 1", counts["ticket"])
+		}
+	}
+	if n := len(r.cache.entries); n != 2 {
+		t.Errorf("cache has %d entries, want 2", n)
+	}
+}